@@ -19,14 +19,23 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	_ "github.com/Sup-Film/fiber-ecommerce-api/docs" // docs is generated by Swag CLI, you have to import it.
 
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/carriers"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/http/handlers"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/http/routes"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/notifiers"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/payments"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/search"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/config"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	gatewayPorts "github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+	notifierPorts "github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/notifiers"
+	searchPorts "github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/search"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/services"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -46,13 +55,81 @@ func main() {
 
 	// เริ่มต้นตั่งค่า Repositories
 	userRepo := repositories.NewUserRepository(db)
+	roleRepo := repositories.NewRoleRepository(db)
+	productRepo := repositories.NewProductRepository(db)
+	categoryRepo := repositories.NewCategoryRepository(db)
+	variantRepo := repositories.NewProductVariantRepository(db)
+	cartRepo := repositories.NewCartRepository(db)
+	orderRepo := repositories.NewOrderRepository(db)
+	inventoryRepo := repositories.NewInventoryRepository(db)
+	transactionRepo := repositories.NewTransactionRepository(db)
+	refundRepo := repositories.NewRefundRepository(db)
+	exchangeRateRepo := repositories.NewExchangeRateRepository(db)
+	taxRuleRepo := repositories.NewTaxRuleRepository(db)
+	promotionRepo := repositories.NewPromotionRepository(db)
+	shippingMethodRepo := repositories.NewShippingMethodRepository(db)
+	addressRepo := repositories.NewAddressRepository(db)
+	shipmentRepo := repositories.NewShipmentRepository(db)
+	returnRepo := repositories.NewReturnRepository(db)
+	wishlistRepo := repositories.NewWishlistRepository(db)
+
+	// เริ่มต้นตั่งค่า Background jobs
+	// การเปลี่ยนสต็อกส่งไปทั้งการตรวจสต็อกต่ำและการแจ้งเตือนรายการที่อยากได้
+	wishlistMonitor := services.NewWishlistMonitor(wishlistRepo, newWishlistNotifier(cfg))
+	stockMonitor := services.NewStockMonitorGroup(services.NewStockMonitor(productRepo, newLowStockNotifier(cfg)), wishlistMonitor)
+	stockMonitor.Start(context.Background())
 
 	// เริ่มต้นตั่งค่า Services
-	authService := services.NewAuthService(userRepo)
+	authService := services.NewAuthService(userRepo, roleRepo)
+	userService := services.NewUserService(userRepo)
+	productService := services.NewProductService(productRepo, categoryRepo, stockMonitor, wishlistMonitor, newProductSearchIndex(cfg))
+	categoryService := services.NewCategoryService(categoryRepo, productService)
+	variantService := services.NewVariantService(variantRepo, stockMonitor, wishlistMonitor)
+	cartService := services.NewCartService(cartRepo, promotionRepo)
+	orderService := services.NewOrderService(orderRepo, stockMonitor)
+	inventoryService := services.NewInventoryService(inventoryRepo, productRepo, stockMonitor)
+	paymentGateways := newPaymentGateways(cfg)
+	paymentService := services.NewPaymentService(transactionRepo, paymentGateways, newPaymentWebhookProviders(cfg))
+	refundService := services.NewRefundService(refundRepo, transactionRepo, paymentGateways, stockMonitor)
+	currencyService := services.NewCurrencyService(exchangeRateRepo)
+	taxService := services.NewTaxService(taxRuleRepo)
+	promotionService := services.NewPromotionService(promotionRepo)
+	shippingService := services.NewShippingService(shippingMethodRepo, addressRepo, cartService)
+	addressService := services.NewAddressService(addressRepo)
+	shipmentService := services.NewShipmentService(shipmentRepo, orderRepo, newCarrierClients())
+	returnService := services.NewReturnService(returnRepo, orderRepo, transactionRepo, refundService, stockMonitor)
+	wishlistService := services.NewWishlistService(wishlistRepo, cartService)
+
+	// search index ในหน่วยความจำต้องสร้างใหม่จากฐานข้อมูลทุกครั้งที่เริ่มระบบ
+	if indexed, err := productService.RebuildSearchIndex(context.Background()); err != nil {
+		log.Fatalf("Error building search index: %v\n", err)
+	} else if indexed > 0 {
+		log.Printf("Indexed %d products for search\n", indexed)
+	}
+
+	// ติดตามสถานะพัสดุกับผู้ให้บริการขนส่งเป็นระยะ
+	shipmentTracker := services.NewShipmentTracker(shipmentService, cfg.ShipmentPollInterval)
+	shipmentTracker.Start(context.Background())
 
 	// เริ่มต้นตั่งค่า Handlers
-	authHandler := handlers.NewAuthHandler(authService)
+	authHandler := handlers.NewAuthHandler(authService, userService)
 	adminHandler := handlers.NewAdminHandler(authService)
+	productHandler := handlers.NewProductHandler(productService, currencyService)
+	variantHandler := handlers.NewVariantHandler(variantService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	cartHandler := handlers.NewCartHandler(cartService)
+	orderHandler := handlers.NewOrderHandler(orderService)
+	inventoryHandler := handlers.NewInventoryHandler(inventoryService)
+	paymentHandler := handlers.NewPaymentHandler(paymentService, orderService)
+	refundHandler := handlers.NewRefundHandler(refundService)
+	currencyHandler := handlers.NewCurrencyHandler(currencyService)
+	taxHandler := handlers.NewTaxHandler(taxService)
+	promotionHandler := handlers.NewPromotionHandler(promotionService)
+	shippingHandler := handlers.NewShippingHandler(shippingService)
+	addressHandler := handlers.NewAddressHandler(addressService)
+	shipmentHandler := handlers.NewShipmentHandler(shipmentService)
+	returnHandler := handlers.NewReturnHandler(returnService)
+	wishlistHandler := handlers.NewWishlistHandler(wishlistService)
 
 	// สร้างแอปพลิเคชัน Fiber
 	app := fiber.New(fiber.Config{
@@ -68,7 +145,7 @@ func main() {
 	app.Use(cors.New())
 
 	// Setup Routes
-	routes.SetupRoutes(app, authHandler, adminHandler)
+	routes.SetupRoutes(app, authHandler, adminHandler, productHandler, cartHandler, orderHandler, inventoryHandler, paymentHandler, refundHandler, currencyHandler, taxHandler, promotionHandler, shippingHandler, addressHandler, shipmentHandler, returnHandler, wishlistHandler, variantHandler, categoryHandler)
 
 	// Start the server
 	if err := app.Listen(":3000"); err != nil {
@@ -76,3 +153,80 @@ func main() {
 	}
 	log.Printf("Server is running on port: %s\n", cfg.APPPort)
 }
+
+// newLowStockNotifier เลือก adapter สำหรับแจ้งเตือนสินค้าสต็อกต่ำตาม config
+func newLowStockNotifier(cfg *config.Config) notifierPorts.LowStockNotifier {
+	switch cfg.LowStockNotifier {
+	case "webhook":
+		return notifiers.NewWebhookNotifier(cfg.LowStockWebhookURL)
+	case "email":
+		return notifiers.NewEmailNotifier(cfg.LowStockEmailTo)
+	default:
+		return notifiers.NewLogNotifier()
+	}
+}
+
+// newWishlistNotifier เลือกช่องทางแจ้งผู้ใช้เมื่อสินค้าในรายการที่อยากได้กลับมามีสต็อกหรือลดราคา
+func newWishlistNotifier(cfg *config.Config) notifierPorts.WishlistNotifier {
+	switch cfg.WishlistNotifier {
+	case "webhook":
+		return notifiers.NewWebhookWishlistNotifier(cfg.WishlistWebhookURL)
+	case "email":
+		return notifiers.NewEmailWishlistNotifier()
+	default:
+		return notifiers.NewLogWishlistNotifier()
+	}
+}
+
+// newPaymentGateways กำหนด gateway ที่ใช้กับแต่ละช่องทางการชำระเงิน
+// PromptPay จะเปิดใช้เมื่อตั้งค่า PROMPTPAY_ID ไว้เท่านั้น
+func newPaymentGateways(cfg *config.Config) map[string]gatewayPorts.PaymentGateway {
+	fakeGateway := payments.NewFakeGateway()
+
+	paymentGateways := map[string]gatewayPorts.PaymentGateway{
+		entities.PaymentMethodCreditCard:   fakeGateway,
+		entities.PaymentMethodBankTransfer: fakeGateway,
+	}
+	if cfg.PromptPayID != "" {
+		paymentGateways[entities.PaymentMethodPromptPay] = payments.NewPromptPayGateway(cfg.PromptPayID)
+	}
+
+	return paymentGateways
+}
+
+// newPaymentWebhookProviders กำหนด secret สำหรับตรวจลายเซ็น webhook ตามชื่อผู้ให้บริการใน URL
+// และช่องทางการชำระเงินที่ผู้ให้บริการนั้นแจ้งผลได้ ต้องตรงกับ gateway ใน newPaymentGateways
+func newPaymentWebhookProviders(cfg *config.Config) map[string]entities.PaymentWebhookProvider {
+	providers := map[string]entities.PaymentWebhookProvider{}
+	if cfg.FakePaymentWebhookSecret != "" {
+		providers["fake"] = entities.PaymentWebhookProvider{
+			Secret:         cfg.FakePaymentWebhookSecret,
+			PaymentMethods: []string{entities.PaymentMethodCreditCard, entities.PaymentMethodBankTransfer},
+		}
+	}
+	if cfg.PromptPayID != "" && cfg.PromptPayWebhookSecret != "" {
+		providers["promptpay"] = entities.PaymentWebhookProvider{
+			Secret:         cfg.PromptPayWebhookSecret,
+			PaymentMethods: []string{entities.PaymentMethodPromptPay},
+		}
+	}
+
+	return providers
+}
+
+// newProductSearchIndex เลือกตัวจับคู่คำค้นสินค้าตาม config คืน nil เมื่อใช้ full-text search ของฐานข้อมูล
+func newProductSearchIndex(cfg *config.Config) searchPorts.ProductSearchIndex {
+	switch cfg.SearchIndex {
+	case "memory":
+		return search.NewMemoryIndex()
+	default:
+		return nil
+	}
+}
+
+// newCarrierClients กำหนด client ที่ใช้สร้างและติดตามพัสดุของแต่ละผู้ให้บริการขนส่ง
+func newCarrierClients() map[string]gatewayPorts.CarrierClient {
+	return map[string]gatewayPorts.CarrierClient{
+		entities.CarrierFake: carriers.NewFakeCarrier(),
+	}
+}
@@ -0,0 +1,87 @@
+// package carriers รวม adapter ของผู้ให้บริการขนส่งที่ implement gateways.CarrierClient
+package carriers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+)
+
+const fakeTrackingPrefix = "FAKE-"
+
+// fakeCheckpoints ลำดับเหตุการณ์ของพัสดุจำลอง นับจากเวลาที่สร้างพัสดุ
+var fakeCheckpoints = []struct {
+	after       time.Duration
+	status      entities.ShippingStatus
+	location    string
+	description string
+}{
+	{0, entities.ShippingStatusShipped, "คลังสินค้าของร้าน", "รับพัสดุเข้าระบบแล้ว"},
+	{2 * time.Hour, entities.ShippingStatusInTransit, "ศูนย์คัดแยกสินค้า", "พัสดุออกจากศูนย์คัดแยกสินค้า"},
+	{20 * time.Hour, entities.ShippingStatusInTransit, "ศูนย์กระจายสินค้าปลายทาง", "พัสดุถึงศูนย์กระจายสินค้าปลายทาง"},
+	{24 * time.Hour, entities.ShippingStatusDelivered, "ปลายทาง", "จัดส่งสำเร็จ"},
+}
+
+type fakeCarrier struct{}
+
+// NewFakeCarrier สร้างผู้ให้บริการขนส่งจำลองสำหรับการทดสอบและการพัฒนาในเครื่อง
+// ไม่เก็บ state ใดๆ เหตุการณ์ของพัสดุขึ้นกับเวลาที่ผ่านไปนับจากเวลาที่ฝังไว้ในเลขพัสดุเท่านั้น
+func NewFakeCarrier() gateways.CarrierClient {
+	return &fakeCarrier{}
+}
+
+func (c *fakeCarrier) CreateShipment(ctx context.Context, req *entities.CarrierShipmentRequest) (*entities.CarrierShipment, error) {
+	reference := strings.ToUpper(strings.ReplaceAll(req.Reference, "-", ""))
+	if len(reference) > 8 {
+		reference = reference[:8]
+	}
+
+	// เก็บเวลาที่สร้างไว้ในเลขพัสดุ เพื่อให้ Track คำนวณเหตุการณ์ได้โดยไม่ต้องมี state
+	return &entities.CarrierShipment{
+		TrackingNumber: fmt.Sprintf("%s%d-%s", fakeTrackingPrefix, time.Now().UnixMilli(), reference),
+	}, nil
+}
+
+func (c *fakeCarrier) Track(ctx context.Context, trackingNumber string) ([]entities.CarrierEvent, error) {
+	createdAt, err := parseFakeTrackingNumber(trackingNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var events []entities.CarrierEvent
+	for i, checkpoint := range fakeCheckpoints {
+		occurredAt := createdAt.Add(checkpoint.after)
+		if occurredAt.After(now) {
+			break
+		}
+		events = append(events, entities.CarrierEvent{
+			EventID:     fmt.Sprintf("%s-%d", trackingNumber, i),
+			Status:      checkpoint.status,
+			Location:    checkpoint.location,
+			Description: checkpoint.description,
+			OccurredAt:  occurredAt,
+		})
+	}
+
+	return events, nil
+}
+
+// parseFakeTrackingNumber อ่านเวลาที่สร้างพัสดุจากเลขพัสดุของ fake carrier (รูปแบบ FAKE-<unix milli>-<reference>)
+func parseFakeTrackingNumber(trackingNumber string) (time.Time, error) {
+	parts := strings.Split(strings.TrimPrefix(trackingNumber, fakeTrackingPrefix), "-")
+	if !strings.HasPrefix(trackingNumber, fakeTrackingPrefix) || len(parts) != 2 {
+		return time.Time{}, fmt.Errorf("unknown tracking number %q", trackingNumber)
+	}
+
+	millis, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unknown tracking number %q", trackingNumber)
+	}
+	return time.UnixMilli(millis), nil
+}
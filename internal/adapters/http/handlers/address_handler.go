@@ -0,0 +1,260 @@
+// ไฟล์นี้ใช้สำหรับจัดการ HTTP requests ที่เกี่ยวกับสมุดที่อยู่ของผู้ใช้ที่เข้าสู่ระบบแล้ว
+// ทุก endpoint ใช้ userID จาก AuthMiddleware เพื่อให้ผู้ใช้เข้าถึงได้เฉพาะที่อยู่ของตัวเอง
+
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AddressHandler struct {
+	addressService services.AddressService
+}
+
+// NewAddressHandler สร้าง AddressHandler ใหม่
+func NewAddressHandler(addressService services.AddressService) *AddressHandler {
+	return &AddressHandler{
+		addressService: addressService,
+	}
+}
+
+// GetAddresses godoc
+// @Summary List my addresses
+// @Description Get the current user's address book, default address first
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Address}
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/user/addresses [get]
+func (h *AddressHandler) GetAddresses(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	addresses, err := h.addressService.GetAddresses(c.UserContext(), userID)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get addresses", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Addresses retrieved successfully",
+		Data:    addresses,
+	})
+}
+
+// GetAddress godoc
+// @Summary Get my address
+// @Description Get one of the current user's addresses by ID
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Address ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.Address}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Router /api/user/addresses/{id} [get]
+func (h *AddressHandler) GetAddress(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid address ID", err)
+	}
+
+	address, err := h.addressService.GetAddress(c.UserContext(), userID, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrAddressNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Address not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get address", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Address retrieved successfully",
+		Data:    address,
+	})
+}
+
+// CreateAddress godoc
+// @Summary Add address
+// @Description Add an address to the current user's address book. The first address becomes the default
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.CreateAddressRequest true "Address data"
+// @Success 201 {object} entities.ApiResponse{data=entities.Address}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/user/addresses [post]
+func (h *AddressHandler) CreateAddress(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	var req entities.CreateAddressRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	address, err := h.addressService.CreateAddress(c.UserContext(), userID, &req)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to create address", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Address created successfully",
+		Data:    address,
+	})
+}
+
+// UpdateAddress godoc
+// @Summary Update address
+// @Description Replace one of the current user's addresses. Orders placed with this address keep their copy
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Address ID"
+// @Param request body entities.UpdateAddressRequest true "Address data"
+// @Success 200 {object} entities.ApiResponse{data=entities.Address}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/user/addresses/{id} [put]
+func (h *AddressHandler) UpdateAddress(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid address ID", err)
+	}
+
+	var req entities.UpdateAddressRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	address, err := h.addressService.UpdateAddress(c.UserContext(), userID, id, &req)
+	if err != nil {
+		if errors.Is(err, entities.ErrAddressNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Address not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to update address", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Address updated successfully",
+		Data:    address,
+	})
+}
+
+// SetDefaultAddress godoc
+// @Summary Set default address
+// @Description Make one of the current user's addresses the default
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Address ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.Address}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/user/addresses/{id}/default [put]
+func (h *AddressHandler) SetDefaultAddress(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid address ID", err)
+	}
+
+	address, err := h.addressService.SetDefaultAddress(c.UserContext(), userID, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrAddressNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Address not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to set default address", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Default address updated successfully",
+		Data:    address,
+	})
+}
+
+// DeleteAddress godoc
+// @Summary Delete address
+// @Description Remove an address from the current user's address book. If it was the default, the most recently added address becomes the default
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Address ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/user/addresses/{id} [delete]
+func (h *AddressHandler) DeleteAddress(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid address ID", err)
+	}
+
+	if err := h.addressService.DeleteAddress(c.UserContext(), userID, id); err != nil {
+		if errors.Is(err, entities.ErrAddressNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Address not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to delete address", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Address deleted successfully",
+	})
+}
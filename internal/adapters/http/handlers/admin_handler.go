@@ -65,7 +65,7 @@ func (h *AdminHandler) AdminRegister(c *fiber.Ctx) error {
 		})
 	}
 
-	user, err := h.authService.AdminRegister(req)
+	user, err := h.authService.AdminRegister(c.UserContext(), &req)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to register admin: " + err.Error(),
@@ -5,9 +5,6 @@
 package handlers
 
 import (
-	"fmt"
-	"strconv"
-
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
 	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
@@ -16,14 +13,17 @@ import (
 
 type AuthHandler struct {
 	authService services.AuthService
+	userService services.UserService
 }
 
 // NewAuthHandler สร้าง AuthHandler ใหม่
 // รับพารามิเตอร์ authService ซึ่งเป็นบริการที่ใช้ในการจัดการการยืนยันตัวตน
+// และ userService สำหรับดึงข้อมูลโปรไฟล์ของผู้ใช้
 // คืนค่า AuthHandler ที่พร้อมใช้งาน
-func NewAuthHandler(authService services.AuthService) *AuthHandler {
+func NewAuthHandler(authService services.AuthService, userService services.UserService) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
+		userService: userService,
 	}
 }
 
@@ -59,7 +59,7 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 
 	// เรียกใช้ authService เพื่อทำการลงทะเบียนผู้ใช้ใหม่
 	// ถ้ามีข้อผิดพลาดเกิดขึ้น จะส่งกลับสถานะ 409 Conflict
-	user, err := h.authService.Register(req)
+	user, err := h.authService.Register(c.UserContext(), &req)
 	if err != nil {
 		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
 			"error": err.Error(),
@@ -96,7 +96,7 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		})
 	}
 
-	response, err := h.authService.Login(req)
+	response, err := h.authService.Login(c.UserContext(), &req)
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"error": err.Error(),
@@ -119,16 +119,14 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 // @Failure 404 {object} map[string]string
 // @Router /api/user/profile [get]
 func (h *AuthHandler) GetUserProfile(c *fiber.Ctx) error {
-	fmt.Println("User ID from context:", c.Locals("userID"))
-	userID := c.Locals("userID").(string)
-	id, err := strconv.ParseUint(userID, 10, 32)
+	id, err := getUserID(c)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid user ID",
 		})
 	}
 
-	user, err := h.authService.GetUserByID(uint(id))
+	user, err := h.userService.GetUserByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "User not found",
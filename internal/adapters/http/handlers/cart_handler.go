@@ -0,0 +1,295 @@
+// ไฟล์นี้ใช้สำหรับจัดการ HTTP requests ที่เกี่ยวกับตะกร้าสินค้าของผู้ใช้ที่เข้าสู่ระบบแล้ว
+// ทุก endpoint ใช้ userID จาก AuthMiddleware เพื่อให้ผู้ใช้เข้าถึงได้เฉพาะตะกร้าของตัวเอง
+
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CartHandler struct {
+	cartService services.CartService
+}
+
+// NewCartHandler สร้าง CartHandler ใหม่
+func NewCartHandler(cartService services.CartService) *CartHandler {
+	return &CartHandler{
+		cartService: cartService,
+	}
+}
+
+// GetCart godoc
+// @Summary Get cart
+// @Description Get the current user's cart
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} entities.ApiResponse{data=entities.Cart}
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/user/cart [get]
+func (h *CartHandler) GetCart(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	cart, err := h.cartService.GetCart(c.UserContext(), userID)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get cart", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Cart retrieved successfully",
+		Data:    cart,
+	})
+}
+
+// AddToCart godoc
+// @Summary Add item to cart
+// @Description Add a product to the current user's cart
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.AddToCartRequest true "Cart item data"
+// @Success 200 {object} entities.ApiResponse{data=entities.Cart}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/user/cart/items [post]
+func (h *CartHandler) AddToCart(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	var req entities.AddToCartRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	if err := h.cartService.AddToCart(c.UserContext(), userID, &req); err != nil {
+		switch {
+		case errors.Is(err, entities.ErrOutOfStock):
+			return errorResponse(c, fiber.StatusConflict, "Insufficient stock", err)
+		case errors.Is(err, entities.ErrVariantNotFound):
+			return errorResponse(c, fiber.StatusNotFound, "Variant not found", err)
+		}
+		return errorResponse(c, fiber.StatusBadRequest, "Failed to add item to cart", err)
+	}
+
+	return h.respondWithCart(c, userID, "Item added to cart successfully")
+}
+
+// UpdateCartItem godoc
+// @Summary Update cart item
+// @Description Update the quantity of an item in the current user's cart
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cart item ID"
+// @Param request body entities.UpdateCartItemRequest true "Quantity"
+// @Success 200 {object} entities.ApiResponse{data=entities.Cart}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/user/cart/items/{id} [put]
+func (h *CartHandler) UpdateCartItem(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	cartItemID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid cart item ID", err)
+	}
+
+	var req entities.UpdateCartItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	if err := h.cartService.UpdateCartItem(c.UserContext(), userID, cartItemID, &req); err != nil {
+		if errors.Is(err, entities.ErrCartItemNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Cart item not found", err)
+		}
+		if errors.Is(err, entities.ErrOutOfStock) {
+			return errorResponse(c, fiber.StatusConflict, "Insufficient stock", err)
+		}
+		return errorResponse(c, fiber.StatusBadRequest, "Failed to update cart item", err)
+	}
+
+	return h.respondWithCart(c, userID, "Cart item updated successfully")
+}
+
+// RemoveFromCart godoc
+// @Summary Remove cart item
+// @Description Remove an item from the current user's cart
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Cart item ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.Cart}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Router /api/user/cart/items/{id} [delete]
+func (h *CartHandler) RemoveFromCart(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	cartItemID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid cart item ID", err)
+	}
+
+	if err := h.cartService.RemoveFromCart(c.UserContext(), userID, cartItemID); err != nil {
+		if errors.Is(err, entities.ErrCartItemNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Cart item not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to remove cart item", err)
+	}
+
+	return h.respondWithCart(c, userID, "Cart item removed successfully")
+}
+
+// ClearCart godoc
+// @Summary Clear cart
+// @Description Remove all items from the current user's cart
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/user/cart [delete]
+func (h *CartHandler) ClearCart(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	if err := h.cartService.ClearCart(c.UserContext(), userID); err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to clear cart", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Cart cleared successfully",
+	})
+}
+
+// ApplyCoupon godoc
+// @Summary Apply coupon to cart
+// @Description Validate a coupon against the current cart and keep it on the cart. The discount is checked again at checkout
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.ApplyCouponRequest true "Coupon code"
+// @Success 200 {object} entities.ApiResponse{data=entities.Cart}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/user/cart/coupon [post]
+func (h *CartHandler) ApplyCoupon(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	var req entities.ApplyCouponRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	cart, err := h.cartService.ApplyCoupon(c.UserContext(), userID, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrPromotionNotFound):
+			return errorResponse(c, fiber.StatusNotFound, "Coupon not found", err)
+		case errors.Is(err, entities.ErrPromotionNotApplicable):
+			return errorResponse(c, fiber.StatusConflict, "Coupon cannot be applied", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to apply coupon", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Coupon applied successfully",
+		Data:    cart,
+	})
+}
+
+// RemoveCoupon godoc
+// @Summary Remove coupon from cart
+// @Description Remove the coupon from the current user's cart
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} entities.ApiResponse{data=entities.Cart}
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/user/cart/coupon [delete]
+func (h *CartHandler) RemoveCoupon(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	cart, err := h.cartService.RemoveCoupon(c.UserContext(), userID)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to remove coupon", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Coupon removed successfully",
+		Data:    cart,
+	})
+}
+
+// respondWithCart ดึงตะกร้าล่าสุดของผู้ใช้แล้วส่งกลับพร้อมข้อความที่กำหนด
+func (h *CartHandler) respondWithCart(c *fiber.Ctx, userID uuid.UUID, message string) error {
+	cart, err := h.cartService.GetCart(c.UserContext(), userID)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get cart", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: message,
+		Data:    cart,
+	})
+}
@@ -0,0 +1,279 @@
+// ไฟล์นี้ใช้สำหรับจัดการ HTTP requests ที่เกี่ยวกับหมวดหมู่สินค้า
+// ลูกค้าดูหมวดหมู่เป็นรายการหรือเป็นโครงสร้างต้นไม้ พร้อมจำนวนสินค้าที่รวมหมวดหมู่ย่อย
+// ส่วนผู้ดูแลระบบสร้าง แก้ไข ย้าย และลบหมวดหมู่ โดยลบหมวดหมู่ที่ยังมีสินค้าได้เมื่อระบุหมวดหมู่ที่จะย้ายสินค้าไป
+
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CategoryHandler struct {
+	categoryService services.CategoryService
+}
+
+// NewCategoryHandler สร้าง CategoryHandler ใหม่
+func NewCategoryHandler(categoryService services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+	}
+}
+
+// GetCategories godoc
+// @Summary List categories
+// @Description Get a paginated flat list of categories ordered by sort order and name; product_count includes products in all subcategories
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Category}
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/categories [get]
+func (h *CategoryHandler) GetCategories(c *fiber.Ctx) error {
+	page, limit := getPaginationParams(c)
+
+	categories, pagination, err := h.categoryService.GetCategories(c.UserContext(), page, limit)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get categories", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success:    true,
+		Message:    "Categories retrieved successfully",
+		Data:       categories,
+		Pagination: pagination,
+	})
+}
+
+// GetCategoryTree godoc
+// @Summary Get category tree
+// @Description Get all top-level categories with their subcategories nested in children; product_count includes products in all subcategories
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Category}
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/categories/tree [get]
+func (h *CategoryHandler) GetCategoryTree(c *fiber.Ctx) error {
+	categories, err := h.categoryService.GetCategoryTree(c.UserContext())
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get categories", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Categories retrieved successfully",
+		Data:    categories,
+	})
+}
+
+// GetCategory godoc
+// @Summary Get category
+// @Description Get a category with its breadcrumb path and product count
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.Category}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/categories/{id} [get]
+func (h *CategoryHandler) GetCategory(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid category ID", err)
+	}
+
+	category, err := h.categoryService.GetCategoryByID(c.UserContext(), id)
+	if err != nil {
+		if errors.Is(err, entities.ErrCategoryNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Category not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get category", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Category retrieved successfully",
+		Data:    category,
+	})
+}
+
+// GetCategoryBySlug godoc
+// @Summary Get category by slug
+// @Description Get a category by its URL slug with its breadcrumb path and product count
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Success 200 {object} entities.ApiResponse{data=entities.Category}
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/categories/slug/{slug} [get]
+func (h *CategoryHandler) GetCategoryBySlug(c *fiber.Ctx) error {
+	category, err := h.categoryService.GetCategoryBySlug(c.UserContext(), c.Params("slug"))
+	if err != nil {
+		if errors.Is(err, entities.ErrCategoryNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Category not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get category", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Category retrieved successfully",
+		Data:    category,
+	})
+}
+
+// CreateCategory godoc
+// @Summary Create category
+// @Description Create a category, optionally under a parent; the slug is generated from the name when omitted (admin only)
+// @Tags Admin Categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.CreateCategoryRequest true "Category data"
+// @Success 201 {object} entities.ApiResponse{data=entities.Category}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/categories [post]
+func (h *CategoryHandler) CreateCategory(c *fiber.Ctx) error {
+	var req entities.CreateCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	category, err := h.categoryService.CreateCategory(c.UserContext(), &req)
+	if err != nil {
+		return categoryError(c, err, "Failed to create category")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Category created successfully",
+		Data:    category,
+	})
+}
+
+// UpdateCategory godoc
+// @Summary Update category
+// @Description Update a category; send parent_id to move it with its subcategories and products, or an empty UUID to make it top-level (admin only)
+// @Tags Admin Categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Category ID"
+// @Param request body entities.UpdateCategoryRequest true "Category data"
+// @Success 200 {object} entities.ApiResponse{data=entities.Category}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid category ID", err)
+	}
+
+	var req entities.UpdateCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	if err := h.categoryService.UpdateCategory(c.UserContext(), id, &req); err != nil {
+		return categoryError(c, err, "Failed to update category")
+	}
+
+	category, err := h.categoryService.GetCategoryByID(c.UserContext(), id)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get updated category", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Category updated successfully",
+		Data:    category,
+	})
+}
+
+// DeleteCategory godoc
+// @Summary Delete category
+// @Description Delete a category; its subcategories move up to its parent. A category that still has products is refused unless reassign_to names the category to move them to (admin only)
+// @Tags Admin Categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Category ID"
+// @Param reassign_to query string false "Category ID to move the category's products to"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid category ID", err)
+	}
+
+	var reassignTo *uuid.UUID
+	if value := c.Query("reassign_to"); value != "" {
+		target, err := uuid.Parse(value)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "Invalid reassign_to category ID", err)
+		}
+		reassignTo = &target
+	}
+
+	if err := h.categoryService.DeleteCategory(c.UserContext(), id, reassignTo); err != nil {
+		return categoryError(c, err, "Failed to delete category")
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Category deleted successfully",
+	})
+}
+
+// categoryError แปลง error ของหมวดหมู่เป็น HTTP status
+func categoryError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, entities.ErrCategoryNotFound):
+		return errorResponse(c, fiber.StatusNotFound, "Category not found", err)
+	case errors.Is(err, entities.ErrCategorySlugExists),
+		errors.Is(err, entities.ErrInvalidCategoryParent),
+		errors.Is(err, entities.ErrCategoryHasProducts):
+		return errorResponse(c, fiber.StatusConflict, message, err)
+	case errors.Is(err, entities.ErrParentCategoryNotFound),
+		errors.Is(err, entities.ErrInvalidCategorySlug),
+		errors.Is(err, entities.ErrInvalidCategoryReassign):
+		return errorResponse(c, fiber.StatusBadRequest, message, err)
+	}
+	return errorResponse(c, fiber.StatusInternalServerError, message, err)
+}
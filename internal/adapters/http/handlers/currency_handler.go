@@ -0,0 +1,115 @@
+// ไฟล์นี้ใช้สำหรับจัดการ HTTP requests ที่เกี่ยวกับสกุลเงินและอัตราแลกเปลี่ยน
+// ประกอบด้วย endpoint สาธารณะสำหรับดูสกุลเงินที่รองรับ
+// และ endpoint สำหรับผู้ดูแลระบบในการกำหนดและลบอัตราแลกเปลี่ยน
+
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CurrencyHandler struct {
+	currencyService services.CurrencyService
+}
+
+// NewCurrencyHandler สร้าง CurrencyHandler ใหม่
+func NewCurrencyHandler(currencyService services.CurrencyService) *CurrencyHandler {
+	return &CurrencyHandler{
+		currencyService: currencyService,
+	}
+}
+
+// GetExchangeRates godoc
+// @Summary List exchange rates
+// @Description Get the supported display currencies and their rates against the base currency (THB)
+// @Tags Currencies
+// @Accept json
+// @Produce json
+// @Success 200 {object} entities.ApiResponse{data=[]entities.ExchangeRate}
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/exchange-rates [get]
+func (h *CurrencyHandler) GetExchangeRates(c *fiber.Ctx) error {
+	rates, err := h.currencyService.ListRates(c.UserContext())
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get exchange rates", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Exchange rates retrieved successfully",
+		Data:    rates,
+	})
+}
+
+// SetExchangeRate godoc
+// @Summary Set exchange rate
+// @Description Create or update the rate of a currency, in units of the currency per 1 THB (admin only). Existing orders keep the rate used at checkout
+// @Tags Admin Currencies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param currency path string true "Currency code (ISO 4217)"
+// @Param request body entities.SetExchangeRateRequest true "Exchange rate"
+// @Success 200 {object} entities.ApiResponse{data=entities.ExchangeRate}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/exchange-rates/{currency} [put]
+func (h *CurrencyHandler) SetExchangeRate(c *fiber.Ctx) error {
+	var req entities.SetExchangeRateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	rate, err := h.currencyService.SetRate(c.UserContext(), c.Params("currency"), &req)
+	if err != nil {
+		if errors.Is(err, entities.ErrUnsupportedCurrency) {
+			return errorResponse(c, fiber.StatusBadRequest, "Invalid currency", err)
+		}
+		return errorResponse(c, fiber.StatusBadRequest, "Failed to set exchange rate", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Exchange rate updated successfully",
+		Data:    rate,
+	})
+}
+
+// DeleteExchangeRate godoc
+// @Summary Delete exchange rate
+// @Description Stop supporting a currency (admin only). Existing orders in this currency are not affected
+// @Tags Admin Currencies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param currency path string true "Currency code (ISO 4217)"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/exchange-rates/{currency} [delete]
+func (h *CurrencyHandler) DeleteExchangeRate(c *fiber.Ctx) error {
+	if err := h.currencyService.DeleteRate(c.UserContext(), c.Params("currency")); err != nil {
+		if errors.Is(err, entities.ErrUnsupportedCurrency) {
+			return errorResponse(c, fiber.StatusNotFound, "Exchange rate not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to delete exchange rate", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Exchange rate deleted successfully",
+	})
+}
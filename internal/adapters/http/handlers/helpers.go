@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPage  = 1
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+func init() {
+	// ให้ tag อย่าง required, gt=0 ของฟิลด์ Money ตรวจกับจำนวนเงินในหน่วยย่อย
+	utils.RegisterCustomType(moneyAmount, entities.Money{})
+}
+
+// moneyAmount คืนจำนวนเงินในหน่วยย่อยของ Money เพื่อใช้ตรวจสอบด้วย validator
+func moneyAmount(field reflect.Value) interface{} {
+	if money, ok := field.Interface().(entities.Money); ok {
+		return money.Amount
+	}
+	return nil
+}
+
+// getPaginationParams อ่านค่า page และ limit จาก query string
+// ถ้าไม่ได้ส่งมาหรือค่าไม่ถูกต้องจะใช้ค่า default แทน
+func getPaginationParams(c *fiber.Ctx) (int, int) {
+	page := c.QueryInt("page", defaultPage)
+	if page < 1 {
+		page = defaultPage
+	}
+
+	limit := c.QueryInt("limit", defaultLimit)
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	return page, limit
+}
+
+// parseUUIDParam แปลง route parameter ที่ระบุเป็น uuid.UUID
+func parseUUIDParam(c *fiber.Ctx, name string) (uuid.UUID, error) {
+	return uuid.Parse(c.Params(name))
+}
+
+// getUserID ดึง userID ที่ AuthMiddleware เก็บไว้ใน context แล้วแปลงเป็น uuid.UUID
+func getUserID(c *fiber.Ctx) (uuid.UUID, error) {
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return uuid.Nil, errors.New("user ID not found in context")
+	}
+	return uuid.Parse(userID)
+}
+
+// getDisplayCurrency อ่านสกุลเงินที่ลูกค้าเลือกให้แสดงราคา จาก query currency หรือ header X-Currency
+// query มีลำดับความสำคัญมากกว่า header และคืนค่าว่างถ้าไม่ได้เลือก
+func getDisplayCurrency(c *fiber.Ctx) string {
+	currency := c.Query("currency")
+	if currency == "" {
+		currency = c.Get("X-Currency")
+	}
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+// errorResponse ส่ง ErrorResponse กลับไปพร้อม status code ที่กำหนด
+func errorResponse(c *fiber.Ctx, status int, message string, err error) error {
+	resp := entities.ErrorResponse{
+		Success: false,
+		Message: message,
+	}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	return c.Status(status).JSON(resp)
+}
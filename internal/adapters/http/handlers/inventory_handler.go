@@ -0,0 +1,196 @@
+// ไฟล์นี้ใช้สำหรับจัดการ HTTP requests ที่เกี่ยวกับสต็อกสินค้า (สำหรับผู้ดูแลระบบ)
+// ประกอบด้วยการดูประวัติการเคลื่อนไหวของสต็อก การปรับสต็อกด้วยมือ
+// การคำนวณสต็อกปัจจุบันใหม่จาก inventory ledger และรายการสินค้าที่ต่ำกว่าจุดสั่งซื้อเพิ่ม
+
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type InventoryHandler struct {
+	inventoryService services.InventoryService
+}
+
+// NewInventoryHandler สร้าง InventoryHandler ใหม่
+func NewInventoryHandler(inventoryService services.InventoryService) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryService: inventoryService,
+	}
+}
+
+// GetMovements godoc
+// @Summary List inventory movements
+// @Description Get the stock movement history of a product, newest first (admin only)
+// @Tags Admin Inventory
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} entities.ApiResponse{data=[]entities.InventoryMovement}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/products/{id}/inventory [get]
+func (h *InventoryHandler) GetMovements(c *fiber.Ctx) error {
+	productID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid product ID", err)
+	}
+
+	page, limit := getPaginationParams(c)
+
+	movements, pagination, err := h.inventoryService.GetMovements(c.UserContext(), productID, page, limit)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get inventory movements", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success:    true,
+		Message:    "Inventory movements retrieved successfully",
+		Data:       movements,
+		Pagination: pagination,
+	})
+}
+
+// AdjustStock godoc
+// @Summary Adjust stock
+// @Description Manually add or remove stock; the change is recorded in the inventory ledger (admin only)
+// @Tags Admin Inventory
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Product ID"
+// @Param request body entities.AdjustStockRequest true "Stock adjustment"
+// @Success 201 {object} entities.ApiResponse{data=entities.InventoryMovement}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/admin/products/{id}/inventory/adjustments [post]
+func (h *InventoryHandler) AdjustStock(c *fiber.Ctx) error {
+	actorID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	productID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid product ID", err)
+	}
+
+	var req entities.AdjustStockRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	movement, err := h.inventoryService.AdjustStock(c.UserContext(), productID, actorID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrProductNotFound):
+			return errorResponse(c, fiber.StatusNotFound, "Product not found", err)
+		case errors.Is(err, entities.ErrVariantNotFound):
+			return errorResponse(c, fiber.StatusNotFound, "Variant not found", err)
+		case errors.Is(err, entities.ErrOutOfStock):
+			return errorResponse(c, fiber.StatusConflict, "Insufficient stock", err)
+		}
+		return errorResponse(c, fiber.StatusBadRequest, "Failed to adjust stock", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Stock adjusted successfully",
+		Data:    movement,
+	})
+}
+
+// RebuildStock godoc
+// @Summary Rebuild stock from ledger
+// @Description Recalculate a product's current stock as the sum of its inventory movements (admin only)
+// @Tags Admin Inventory
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Product ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.StockRebuildResult}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/products/{id}/inventory/rebuild [post]
+func (h *InventoryHandler) RebuildStock(c *fiber.Ctx) error {
+	productID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid product ID", err)
+	}
+
+	result, err := h.inventoryService.RebuildStock(c.UserContext(), productID)
+	if err != nil {
+		if errors.Is(err, entities.ErrProductNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Product not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to rebuild stock", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Stock rebuilt from inventory ledger",
+		Data:    result,
+	})
+}
+
+// GetLowStockProducts godoc
+// @Summary List low-stock products
+// @Description List products whose stock is at or below their reorder threshold (product, then category, then default) (admin only)
+// @Tags Admin Inventory
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category_id query string false "Filter by category ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} entities.ApiResponse{data=[]entities.LowStockProduct}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/products/low-stock [get]
+func (h *InventoryHandler) GetLowStockProducts(c *fiber.Ctx) error {
+	page, limit := getPaginationParams(c)
+	filter := &entities.LowStockFilter{Page: page, Limit: limit}
+
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		id, err := uuid.Parse(categoryID)
+		if err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "Invalid category ID", err)
+		}
+		filter.CategoryID = id
+	}
+
+	products, pagination, err := h.inventoryService.GetLowStockProducts(c.UserContext(), filter)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get low-stock products", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success:    true,
+		Message:    "Low-stock products retrieved successfully",
+		Data:       products,
+		Pagination: pagination,
+	})
+}
@@ -0,0 +1,486 @@
+// ไฟล์นี้ใช้สำหรับจัดการ HTTP requests ที่เกี่ยวกับคำสั่งซื้อ
+// ผู้ใช้สามารถสร้างคำสั่งซื้อจากตะกร้า ดูคำสั่งซื้อของตัวเอง และยกเลิกคำสั่งซื้อได้
+// ผู้ดูแลระบบสามารถค้นหาคำสั่งซื้อทั้งหมดและอัพเดทสถานะคำสั่งซื้อ การชำระเงิน และการจัดส่งได้
+
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type OrderHandler struct {
+	orderService services.OrderService
+}
+
+// NewOrderHandler สร้าง OrderHandler ใหม่
+func NewOrderHandler(orderService services.OrderService) *OrderHandler {
+	return &OrderHandler{
+		orderService: orderService,
+	}
+}
+
+// CreateOrder godoc
+// @Summary Create order
+// @Description Create an order from the current user's cart. Prices are converted to the requested currency and the exchange rate is stored on the order. The cart coupon (or coupon_code) is validated again before the discount is applied, and the fee of shipping_method is added to the total
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.CreateOrderRequest true "Order data"
+// @Param X-Currency header string false "Checkout currency when the body does not specify one"
+// @Success 201 {object} entities.ApiResponse{data=entities.Order}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Failure 422 {object} entities.ErrorResponse
+// @Router /api/user/orders [post]
+func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	var req entities.CreateOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	// ถ้าไม่ได้ระบุสกุลเงินที่จะชำระ ใช้สกุลเงินที่ลูกค้าเลือกแสดงราคา
+	if req.Currency == "" {
+		req.Currency = getDisplayCurrency(c)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	order, err := h.orderService.CreateOrder(c.UserContext(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrOutOfStock):
+			return errorResponse(c, fiber.StatusConflict, "Insufficient stock", err)
+		case errors.Is(err, entities.ErrPromotionNotApplicable):
+			return errorResponse(c, fiber.StatusConflict, "Coupon cannot be applied", err)
+		case errors.Is(err, entities.ErrAddressNotFound):
+			return errorResponse(c, fiber.StatusNotFound, "Address not found", err)
+		case errors.Is(err, entities.ErrShippingNotAvailable):
+			return errorResponse(c, fiber.StatusUnprocessableEntity, "Shipping method not available", err)
+		}
+		return errorResponse(c, fiber.StatusBadRequest, "Failed to create order", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Order created successfully",
+		Data:    order,
+	})
+}
+
+// GetOrders godoc
+// @Summary List my orders
+// @Description Get a paginated list of the current user's orders
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Order}
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/user/orders [get]
+func (h *OrderHandler) GetOrders(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	page, limit := getPaginationParams(c)
+
+	orders, pagination, err := h.orderService.GetOrders(c.UserContext(), userID, page, limit)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get orders", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success:    true,
+		Message:    "Orders retrieved successfully",
+		Data:       orders,
+		Pagination: pagination,
+	})
+}
+
+// GetOrder godoc
+// @Summary Get my order
+// @Description Get one of the current user's orders by ID
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.Order}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Router /api/user/orders/{id} [get]
+func (h *OrderHandler) GetOrder(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	orderID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid order ID", err)
+	}
+
+	order, err := h.orderService.GetUserOrderByID(c.UserContext(), userID, orderID)
+	if err != nil {
+		return errorResponse(c, fiber.StatusNotFound, "Order not found", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Order retrieved successfully",
+		Data:    order,
+	})
+}
+
+// CancelOrder godoc
+// @Summary Cancel my order
+// @Description Cancel one of the current user's pending orders. Paid orders must be fully refunded before they can be cancelled
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/user/orders/{id}/cancel [post]
+func (h *OrderHandler) CancelOrder(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	orderID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid order ID", err)
+	}
+
+	if err := h.orderService.CancelOrder(c.UserContext(), userID, orderID); err != nil {
+		if errors.Is(err, entities.ErrOrderNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Order not found", err)
+		}
+		return statusUpdateError(c, "Failed to cancel order", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Order cancelled successfully",
+	})
+}
+
+// GetAllOrders godoc
+// @Summary List all orders
+// @Description Get a paginated list of all orders with optional filters (admin only)
+// @Tags Admin Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Order status"
+// @Param payment_status query string false "Payment status"
+// @Param shipping_status query string false "Shipping status"
+// @Param user_id query string false "User ID"
+// @Param date_from query string false "Created at or after (YYYY-MM-DD or RFC3339)"
+// @Param date_to query string false "Created on or before (YYYY-MM-DD) or before (RFC3339)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Order}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/orders [get]
+func (h *OrderHandler) GetAllOrders(c *fiber.Ctx) error {
+	filter, err := parseOrderFilter(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid filter parameters", err)
+	}
+
+	orders, pagination, err := h.orderService.GetAllOrders(c.UserContext(), filter)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get orders", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success:    true,
+		Message:    "Orders retrieved successfully",
+		Data:       orders,
+		Pagination: pagination,
+	})
+}
+
+// AdminGetOrder godoc
+// @Summary Get order
+// @Description Get any order by ID, including its status timeline (admin only)
+// @Tags Admin Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.Order}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Router /api/admin/orders/{id} [get]
+func (h *OrderHandler) AdminGetOrder(c *fiber.Ctx) error {
+	orderID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid order ID", err)
+	}
+
+	order, err := h.orderService.GetOrderByID(c.UserContext(), orderID)
+	if err != nil {
+		return errorResponse(c, fiber.StatusNotFound, "Order not found", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Order retrieved successfully",
+		Data:    order,
+	})
+}
+
+// UpdateOrderStatus godoc
+// @Summary Update order status
+// @Description Update the status of an order (admin only). A paid order can only be cancelled after it has been fully refunded
+// @Tags Admin Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body entities.UpdateOrderStatusRequest true "Order status"
+// @Success 200 {object} entities.ApiResponse{data=entities.Order}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/admin/orders/{id}/status [put]
+func (h *OrderHandler) UpdateOrderStatus(c *fiber.Ctx) error {
+	actorID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	orderID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid order ID", err)
+	}
+
+	var req entities.UpdateOrderStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	if _, err := h.orderService.GetOrderByID(c.UserContext(), orderID); err != nil {
+		return errorResponse(c, fiber.StatusNotFound, "Order not found", err)
+	}
+
+	if err := h.orderService.UpdateOrderStatus(c.UserContext(), orderID, actorID, &req); err != nil {
+		return statusUpdateError(c, "Failed to update order status", err)
+	}
+
+	return h.respondWithOrder(c, orderID, "Order status updated successfully")
+}
+
+// UpdatePaymentStatus godoc
+// @Summary Update payment status
+// @Description Update the payment status of an order (admin only). refunded and partially_refunded are set by refunds and cannot be set here
+// @Tags Admin Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body entities.UpdatePaymentStatusRequest true "Payment status"
+// @Success 200 {object} entities.ApiResponse{data=entities.Order}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/admin/orders/{id}/payment-status [put]
+func (h *OrderHandler) UpdatePaymentStatus(c *fiber.Ctx) error {
+	actorID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	orderID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid order ID", err)
+	}
+
+	var req entities.UpdatePaymentStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	if _, err := h.orderService.GetOrderByID(c.UserContext(), orderID); err != nil {
+		return errorResponse(c, fiber.StatusNotFound, "Order not found", err)
+	}
+
+	if err := h.orderService.UpdatePaymentStatus(c.UserContext(), orderID, actorID, &req); err != nil {
+		return statusUpdateError(c, "Failed to update payment status", err)
+	}
+
+	return h.respondWithOrder(c, orderID, "Payment status updated successfully")
+}
+
+// UpdateShippingStatus godoc
+// @Summary Update shipping status
+// @Description Update the shipping status and tracking number of an order (admin only)
+// @Tags Admin Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body entities.UpdateShippingStatusRequest true "Shipping status"
+// @Success 200 {object} entities.ApiResponse{data=entities.Order}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/admin/orders/{id}/shipping-status [put]
+func (h *OrderHandler) UpdateShippingStatus(c *fiber.Ctx) error {
+	actorID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	orderID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid order ID", err)
+	}
+
+	var req entities.UpdateShippingStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	if _, err := h.orderService.GetOrderByID(c.UserContext(), orderID); err != nil {
+		return errorResponse(c, fiber.StatusNotFound, "Order not found", err)
+	}
+
+	if err := h.orderService.UpdateShippingStatus(c.UserContext(), orderID, actorID, &req); err != nil {
+		return statusUpdateError(c, "Failed to update shipping status", err)
+	}
+
+	return h.respondWithOrder(c, orderID, "Shipping status updated successfully")
+}
+
+// statusUpdateError ตอบ 409 ถ้าเป็นการเปลี่ยนสถานะที่ไม่อนุญาต นอกนั้นตอบ 400
+func statusUpdateError(c *fiber.Ctx, message string, err error) error {
+	var transitionErr *entities.StatusTransitionError
+	if errors.As(err, &transitionErr) {
+		return errorResponse(c, fiber.StatusConflict, message, err)
+	}
+	return errorResponse(c, fiber.StatusBadRequest, message, err)
+}
+
+// respondWithOrder ดึงคำสั่งซื้อล่าสุดแล้วส่งกลับพร้อมข้อความที่กำหนด
+func (h *OrderHandler) respondWithOrder(c *fiber.Ctx, orderID uuid.UUID, message string) error {
+	order, err := h.orderService.GetOrderByID(c.UserContext(), orderID)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get order", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: message,
+		Data:    order,
+	})
+}
+
+// parseOrderFilter แปลง query string เป็น OrderFilter
+func parseOrderFilter(c *fiber.Ctx) (*entities.OrderFilter, error) {
+	page, limit := getPaginationParams(c)
+
+	filter := &entities.OrderFilter{
+		Status:         entities.OrderStatus(c.Query("status")),
+		PaymentStatus:  entities.PaymentStatus(c.Query("payment_status")),
+		ShippingStatus: entities.ShippingStatus(c.Query("shipping_status")),
+		Page:           page,
+		Limit:          limit,
+	}
+
+	if userID := c.Query("user_id"); userID != "" {
+		id, err := uuid.Parse(userID)
+		if err != nil {
+			return nil, err
+		}
+		filter.UserID = id
+	}
+
+	if dateFrom := c.Query("date_from"); dateFrom != "" {
+		value, _, err := parseDateQuery(dateFrom)
+		if err != nil {
+			return nil, err
+		}
+		filter.DateFrom = value
+	}
+
+	if dateTo := c.Query("date_to"); dateTo != "" {
+		value, dateOnly, err := parseDateQuery(dateTo)
+		if err != nil {
+			return nil, err
+		}
+		// ถ้าส่งมาเป็นวันที่อย่างเดียว ให้รวมทั้งวันนั้นด้วย
+		if dateOnly {
+			value = value.AddDate(0, 0, 1)
+		}
+		filter.DateTo = value
+	}
+
+	return filter, nil
+}
+
+// parseDateQuery รองรับทั้งรูปแบบ YYYY-MM-DD และ RFC3339
+// คืนค่า dateOnly = true ถ้าเป็นรูปแบบวันที่อย่างเดียว
+func parseDateQuery(value string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, true, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, false, nil
+}
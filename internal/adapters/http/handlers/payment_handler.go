@@ -0,0 +1,261 @@
+// ไฟล์นี้ใช้สำหรับจัดการ HTTP requests ที่เกี่ยวกับการชำระเงินของผู้ใช้
+// ประกอบด้วยการเริ่มชำระเงินผ่าน payment gateway การดูสถานะ การตรวจสอบผลการชำระเงิน และการยกเลิก
+
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PaymentHandler struct {
+	paymentService services.PaymentService
+	orderService   services.OrderService
+}
+
+// NewPaymentHandler สร้าง PaymentHandler ใหม่
+func NewPaymentHandler(paymentService services.PaymentService, orderService services.OrderService) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+		orderService:   orderService,
+	}
+}
+
+// CreatePayment godoc
+// @Summary Start a payment
+// @Description Create a transaction for one of the current user's orders and start the payment with the gateway of the chosen payment method
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.CreatePaymentRequest true "Payment details"
+// @Success 201 {object} entities.ApiResponse{data=entities.Transaction}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Failure 502 {object} entities.ErrorResponse
+// @Router /api/user/payments [post]
+func (h *PaymentHandler) CreatePayment(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	var req entities.CreatePaymentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	order, err := h.orderService.GetUserOrderByID(c.UserContext(), userID, req.OrderID)
+	if err != nil {
+		return errorResponse(c, fiber.StatusNotFound, "Order not found", err)
+	}
+
+	// ชำระเงินได้เฉพาะคำสั่งซื้อที่ยังไม่ถูกยกเลิก และยังรอชำระหรือชำระไม่สำเร็จ
+	payable := order.PaymentStatus == entities.PaymentStatusPending || order.PaymentStatus == entities.PaymentStatusFailed
+	if order.Status == entities.OrderStatusCancelled || !payable {
+		return errorResponse(c, fiber.StatusConflict, "Order cannot be paid", nil)
+	}
+
+	transaction, err := h.paymentService.CreatePayment(c.UserContext(), &req)
+	if err != nil {
+		if errors.Is(err, entities.ErrUnsupportedPaymentMethod) {
+			return errorResponse(c, fiber.StatusBadRequest, "Unsupported payment method", err)
+		}
+		return errorResponse(c, fiber.StatusBadGateway, "Failed to create payment", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Payment created successfully",
+		Data:    transaction,
+	})
+}
+
+// GetPayment godoc
+// @Summary Get a payment
+// @Description Get one of the current user's payment transactions
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.Transaction}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Router /api/user/payments/{id} [get]
+func (h *PaymentHandler) GetPayment(c *fiber.Ctx) error {
+	transaction, fiberErr := h.userTransaction(c)
+	if fiberErr != nil {
+		return errorResponse(c, fiberErr.Code, fiberErr.Message, nil)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Payment retrieved successfully",
+		Data:    transaction,
+	})
+}
+
+// VerifyPayment godoc
+// @Summary Verify a payment
+// @Description Check the payment with the gateway and update the transaction and order payment status
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Param request body entities.VerifyPaymentRequest true "Verification details"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/user/payments/{id}/verify [post]
+func (h *PaymentHandler) VerifyPayment(c *fiber.Ctx) error {
+	transaction, fiberErr := h.userTransaction(c)
+	if fiberErr != nil {
+		return errorResponse(c, fiberErr.Code, fiberErr.Message, nil)
+	}
+
+	var req entities.VerifyPaymentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	if err := h.paymentService.VerifyPayment(c.UserContext(), transaction.ID, &req); err != nil {
+		switch {
+		case errors.Is(err, entities.ErrPaymentNotFound):
+			return errorResponse(c, fiber.StatusNotFound, "Payment not found", err)
+		case errors.Is(err, entities.ErrPaymentPending):
+			return errorResponse(c, fiber.StatusConflict, "Payment is still pending", err)
+		case errors.Is(err, entities.ErrTransactionFinalized):
+			return errorResponse(c, fiber.StatusConflict, "Payment is already finalized", err)
+		}
+		return errorResponse(c, fiber.StatusBadRequest, "Failed to verify payment", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Payment verified successfully",
+	})
+}
+
+// CancelPayment godoc
+// @Summary Cancel a payment
+// @Description Cancel one of the current user's pending payment transactions
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/user/payments/{id}/cancel [post]
+func (h *PaymentHandler) CancelPayment(c *fiber.Ctx) error {
+	transaction, fiberErr := h.userTransaction(c)
+	if fiberErr != nil {
+		return errorResponse(c, fiberErr.Code, fiberErr.Message, nil)
+	}
+
+	if transaction.Status != entities.TransactionStatusPending {
+		return errorResponse(c, fiber.StatusConflict, "Only pending payments can be cancelled", nil)
+	}
+
+	if err := h.paymentService.CancelPayment(c.UserContext(), transaction.ID); err != nil {
+		if errors.Is(err, entities.ErrTransactionFinalized) {
+			return errorResponse(c, fiber.StatusConflict, "Only pending payments can be cancelled", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to cancel payment", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Payment cancelled successfully",
+	})
+}
+
+// HandleWebhook godoc
+// @Summary Payment webhook
+// @Description Receive a payment result from a provider. The raw body must be signed with HMAC-SHA256 using the provider's webhook secret and the hex digest sent in the X-Webhook-Signature header. Redelivered and stale events are acknowledged without changes. A provider can only report on transactions paid through the payment methods it handles.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param provider path string true "Payment provider (fake, promptpay)"
+// @Param X-Webhook-Signature header string true "HMAC-SHA256 of the raw body, hex encoded, optionally prefixed with sha256="
+// @Param request body entities.PaymentWebhookEvent true "Webhook event"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 403 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/webhooks/payments/{provider} [post]
+func (h *PaymentHandler) HandleWebhook(c *fiber.Ctx) error {
+	provider := c.Params("provider")
+	signature := c.Get("X-Webhook-Signature")
+
+	if err := h.paymentService.HandleWebhook(c.UserContext(), provider, c.Body(), signature); err != nil {
+		switch {
+		case errors.Is(err, entities.ErrUnknownWebhookProvider):
+			return errorResponse(c, fiber.StatusNotFound, "Unknown payment provider", err)
+		case errors.Is(err, entities.ErrInvalidWebhookSignature):
+			return errorResponse(c, fiber.StatusUnauthorized, "Invalid webhook signature", err)
+		case errors.Is(err, entities.ErrWebhookProviderMismatch):
+			return errorResponse(c, fiber.StatusForbidden, "Payment provider does not handle this payment", err)
+		case errors.Is(err, entities.ErrInvalidWebhookPayload):
+			return errorResponse(c, fiber.StatusBadRequest, "Invalid webhook payload", err)
+		case errors.Is(err, entities.ErrPaymentNotFound):
+			return errorResponse(c, fiber.StatusNotFound, "Payment not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to process webhook", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Webhook processed successfully",
+	})
+}
+
+// userTransaction ดึงธุรกรรมจาก path parameter "id" โดยตรวจสอบว่าเป็นของคำสั่งซื้อของผู้ใช้คนนี้
+// ถ้าไม่ผ่านจะคืน fiber.Error ที่มี status code และข้อความสำหรับตอบกลับ
+func (h *PaymentHandler) userTransaction(c *fiber.Ctx) (*entities.Transaction, *fiber.Error) {
+	userID, err := getUserID(c)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid user")
+	}
+
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid payment ID")
+	}
+
+	transaction, err := h.paymentService.GetPaymentByID(c.UserContext(), id)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Payment not found")
+	}
+
+	// ธุรกรรมของคำสั่งซื้อคนอื่นตอบเหมือนไม่พบ เพื่อไม่ให้รู้ว่ามีธุรกรรมนี้อยู่
+	if _, err := h.orderService.GetUserOrderByID(c.UserContext(), userID, transaction.OrderID); err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Payment not found")
+	}
+
+	return transaction, nil
+}
@@ -0,0 +1,429 @@
+// ไฟล์นี้ใช้สำหรับจัดการ HTTP requests ที่เกี่ยวกับสินค้า
+// ประกอบด้วย endpoint สาธารณะสำหรับดูรายการ/รายละเอียด/ค้นหาสินค้า
+// และ endpoint สำหรับผู้ดูแลระบบในการสร้าง แก้ไข และลบสินค้า
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ProductHandler struct {
+	productService  services.ProductService
+	currencyService services.CurrencyService
+}
+
+// NewProductHandler สร้าง ProductHandler ใหม่
+func NewProductHandler(productService services.ProductService, currencyService services.CurrencyService) *ProductHandler {
+	return &ProductHandler{
+		productService:  productService,
+		currencyService: currencyService,
+	}
+}
+
+// GetProducts godoc
+// @Summary List products
+// @Description Get a paginated list of products
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param currency query string false "Display currency (ISO 4217), can also be sent as X-Currency header"
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Product}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/products [get]
+func (h *ProductHandler) GetProducts(c *fiber.Ctx) error {
+	page, limit := getPaginationParams(c)
+
+	products, pagination, err := h.productService.GetProducts(c.UserContext(), page, limit)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get products", err)
+	}
+
+	if err := h.currencyService.LocalizeProducts(c.UserContext(), getDisplayCurrency(c), products...); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Unsupported currency", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success:    true,
+		Message:    "Products retrieved successfully",
+		Data:       products,
+		Pagination: pagination,
+	})
+}
+
+// GetProduct godoc
+// @Summary Get product
+// @Description Get product details by ID
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param currency query string false "Display currency (ISO 4217), can also be sent as X-Currency header"
+// @Success 200 {object} entities.ApiResponse{data=entities.Product}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Router /api/products/{id} [get]
+func (h *ProductHandler) GetProduct(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid product ID", err)
+	}
+
+	product, err := h.productService.GetProductByID(c.UserContext(), id)
+	if err != nil {
+		return errorResponse(c, fiber.StatusNotFound, "Product not found", err)
+	}
+
+	if err := h.currencyService.LocalizeProducts(c.UserContext(), getDisplayCurrency(c), product); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Unsupported currency", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Product retrieved successfully",
+		Data:    product,
+	})
+}
+
+// SearchProducts godoc
+// @Summary Search products
+// @Description Full-text search products by keyword, category, price range and stock, with facet counts by category and price range (facet prices are in the catalog currency)
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param query query string false "Keyword to search in name and description"
+// @Param category_id query string false "Category ID"
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Param in_stock query bool false "Only products that are in stock"
+// @Param sort query string false "Sort order" Enums(relevance, newest, price_asc, price_desc) default(relevance)
+// @Param currency query string false "Display currency (ISO 4217), can also be sent as X-Currency header. min_price/max_price are in this currency"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Product}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/products/search [get]
+func (h *ProductHandler) SearchProducts(c *fiber.Ctx) error {
+	req, err := parseProductSearchRequest(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid search parameters", err)
+	}
+
+	if err := h.toCatalogCurrency(c, req.MinPrice, req.MaxPrice); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Unsupported currency", err)
+	}
+
+	products, facets, pagination, err := h.productService.SearchProducts(c.UserContext(), req)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to search products", err)
+	}
+
+	if err := h.currencyService.LocalizeProducts(c.UserContext(), getDisplayCurrency(c), products...); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Unsupported currency", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success:    true,
+		Message:    "Products retrieved successfully",
+		Data:       products,
+		Pagination: pagination,
+		Facets:     facets,
+	})
+}
+
+// SuggestProducts godoc
+// @Summary Suggest search keywords
+// @Description Autocomplete the keyword being typed from product and category names, or suggest a corrected spelling; returns an empty list when the search index is not enabled
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param query query string true "Keyword being typed"
+// @Param limit query int false "Maximum number of suggestions (1-10)" default(5)
+// @Success 200 {object} entities.ApiResponse{data=[]string}
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/products/suggest [get]
+func (h *ProductHandler) SuggestProducts(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 5)
+	if limit < 1 || limit > 10 {
+		limit = 5
+	}
+
+	suggestions, err := h.productService.SuggestProducts(c.UserContext(), c.Query("query"), limit)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to suggest keywords", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Suggestions retrieved successfully",
+		Data:    suggestions,
+	})
+}
+
+// GetProductsByCategory godoc
+// @Summary List products by category
+// @Description Get a paginated list of products in a category and all of its subcategories, with the breadcrumb path of the category
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param categoryId path string true "Category ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param currency query string false "Display currency (ISO 4217), can also be sent as X-Currency header"
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Product}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/products/category/{categoryId} [get]
+func (h *ProductHandler) GetProductsByCategory(c *fiber.Ctx) error {
+	categoryID, err := parseUUIDParam(c, "categoryId")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid category ID", err)
+	}
+
+	page, limit := getPaginationParams(c)
+
+	products, category, pagination, err := h.productService.GetProductsByCategory(c.UserContext(), categoryID, page, limit)
+	if err != nil {
+		if errors.Is(err, entities.ErrCategoryNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Category not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get products", err)
+	}
+
+	if err := h.currencyService.LocalizeProducts(c.UserContext(), getDisplayCurrency(c), products...); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Unsupported currency", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success:     true,
+		Message:     "Products retrieved successfully",
+		Data:        products,
+		Pagination:  pagination,
+		Breadcrumbs: category.Breadcrumbs,
+	})
+}
+
+// CreateProduct godoc
+// @Summary Create product
+// @Description Create a new product (admin only)
+// @Tags Admin Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.CreateProductRequest true "Product data"
+// @Success 201 {object} entities.ApiResponse{data=entities.Product}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/products [post]
+func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
+	actorID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	var req entities.CreateProductRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	product, err := h.productService.CreateProduct(c.UserContext(), actorID, &req)
+	if err != nil {
+		if errors.Is(err, entities.ErrCategoryNotFound) {
+			return errorResponse(c, fiber.StatusBadRequest, "Category not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to create product", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Product created successfully",
+		Data:    product,
+	})
+}
+
+// UpdateProduct godoc
+// @Summary Update product
+// @Description Update an existing product (admin only)
+// @Tags Admin Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Product ID"
+// @Param request body entities.UpdateProductRequest true "Product data"
+// @Success 200 {object} entities.ApiResponse{data=entities.Product}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/products/{id} [put]
+func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
+	actorID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid product ID", err)
+	}
+
+	var req entities.UpdateProductRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	// ตรวจสอบว่าสินค้ามีอยู่จริงก่อนอัพเดท
+	if _, err := h.productService.GetProductByID(c.UserContext(), id); err != nil {
+		return errorResponse(c, fiber.StatusNotFound, "Product not found", err)
+	}
+
+	if err := h.productService.UpdateProduct(c.UserContext(), id, actorID, &req); err != nil {
+		if errors.Is(err, entities.ErrCategoryNotFound) {
+			return errorResponse(c, fiber.StatusBadRequest, "Category not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to update product", err)
+	}
+
+	product, err := h.productService.GetProductByID(c.UserContext(), id)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get updated product", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Product updated successfully",
+		Data:    product,
+	})
+}
+
+// DeleteProduct godoc
+// @Summary Delete product
+// @Description Delete a product (admin only)
+// @Tags Admin Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Product ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/products/{id} [delete]
+func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid product ID", err)
+	}
+
+	if _, err := h.productService.GetProductByID(c.UserContext(), id); err != nil {
+		return errorResponse(c, fiber.StatusNotFound, "Product not found", err)
+	}
+
+	if err := h.productService.DeleteProduct(c.UserContext(), id); err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to delete product", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Product deleted successfully",
+	})
+}
+
+// parseProductSearchRequest แปลง query string เป็น ProductSearchRequest
+func parseProductSearchRequest(c *fiber.Ctx) (*entities.ProductSearchRequest, error) {
+	page, limit := getPaginationParams(c)
+
+	req := &entities.ProductSearchRequest{
+		Query:   c.Query("query"),
+		InStock: c.QueryBool("in_stock"),
+		Sort:    entities.ProductSort(c.Query("sort")),
+		Page:    page,
+		Limit:   limit,
+	}
+
+	if req.Sort != "" && !req.Sort.IsValid() {
+		return nil, fmt.Errorf("ไม่รองรับการเรียงลำดับ %q", req.Sort)
+	}
+
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		id, err := uuid.Parse(categoryID)
+		if err != nil {
+			return nil, err
+		}
+		req.CategoryID = id
+	}
+
+	// ราคากรองเป็นทศนิยมในสกุลเงินที่ลูกค้าเลือก (ค่าเริ่มต้น THB) เช่น min_price=99.50
+	currency := getDisplayCurrency(c)
+	if currency == "" {
+		currency = entities.DefaultCurrency
+	}
+
+	if minPrice := c.Query("min_price"); minPrice != "" {
+		value, err := entities.ParseMoney(minPrice, currency)
+		if err != nil {
+			return nil, err
+		}
+		req.MinPrice = &value
+	}
+
+	if maxPrice := c.Query("max_price"); maxPrice != "" {
+		value, err := entities.ParseMoney(maxPrice, currency)
+		if err != nil {
+			return nil, err
+		}
+		req.MaxPrice = &value
+	}
+
+	return req, nil
+}
+
+// toCatalogCurrency แปลงราคาที่ลูกค้าระบุในสกุลเงินที่เลือกเป็นสกุลเงินหลักของแคตตาล็อก
+// เพื่อใช้กรองกับราคาสินค้าที่เก็บไว้
+func (h *ProductHandler) toCatalogCurrency(c *fiber.Ctx, prices ...*entities.Money) error {
+	var rates entities.ExchangeRateTable
+	for _, price := range prices {
+		if price == nil || price.Currency == entities.DefaultCurrency {
+			continue
+		}
+
+		if rates == nil {
+			var err error
+			if rates, err = h.currencyService.GetRateTable(c.UserContext()); err != nil {
+				return err
+			}
+		}
+		converted, err := rates.Convert(*price, entities.DefaultCurrency)
+		if err != nil {
+			return err
+		}
+		*price = converted
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+// ไฟล์นี้ใช้สำหรับจัดการ HTTP requests ที่เกี่ยวกับโปรโมชันและคูปองส่วนลด (สำหรับผู้ดูแลระบบ)
+// ลูกค้าใช้คูปองผ่าน /api/user/cart/coupon และส่วนลดถูกตรวจอีกครั้งตอนสร้างคำสั่งซื้อ
+
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PromotionHandler struct {
+	promotionService services.PromotionService
+}
+
+// NewPromotionHandler สร้าง PromotionHandler ใหม่
+func NewPromotionHandler(promotionService services.PromotionService) *PromotionHandler {
+	return &PromotionHandler{
+		promotionService: promotionService,
+	}
+}
+
+// GetPromotions godoc
+// @Summary List promotions
+// @Description Get a paginated list of promotions with their usage count (admin only)
+// @Tags Admin Promotions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Promotion}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/promotions [get]
+func (h *PromotionHandler) GetPromotions(c *fiber.Ctx) error {
+	page, limit := getPaginationParams(c)
+
+	promotions, pagination, err := h.promotionService.GetPromotions(c.UserContext(), page, limit)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get promotions", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success:    true,
+		Message:    "Promotions retrieved successfully",
+		Data:       promotions,
+		Pagination: pagination,
+	})
+}
+
+// GetPromotion godoc
+// @Summary Get promotion
+// @Description Get a promotion by ID (admin only)
+// @Tags Admin Promotions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.Promotion}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Router /api/admin/promotions/{id} [get]
+func (h *PromotionHandler) GetPromotion(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid promotion ID", err)
+	}
+
+	promotion, err := h.promotionService.GetPromotionByID(c.UserContext(), id)
+	if err != nil {
+		if errors.Is(err, entities.ErrPromotionNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Promotion not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get promotion", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Promotion retrieved successfully",
+		Data:    promotion,
+	})
+}
+
+// CreatePromotion godoc
+// @Summary Create promotion
+// @Description Create a coupon. type is percentage (rate in basis points, 1000 = 10%), fixed (amount) or free_shipping. Leave product_ids and category_ids empty to apply to every product (admin only)
+// @Tags Admin Promotions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.CreatePromotionRequest true "Promotion data"
+// @Success 201 {object} entities.ApiResponse{data=entities.Promotion}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/promotions [post]
+func (h *PromotionHandler) CreatePromotion(c *fiber.Ctx) error {
+	var req entities.CreatePromotionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	promotion, err := h.promotionService.CreatePromotion(c.UserContext(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrInvalidPromotion):
+			return errorResponse(c, fiber.StatusBadRequest, "Invalid promotion", err)
+		case errors.Is(err, entities.ErrPromotionCodeExists):
+			return errorResponse(c, fiber.StatusConflict, "Coupon code already exists", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to create promotion", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Promotion created successfully",
+		Data:    promotion,
+	})
+}
+
+// UpdatePromotion godoc
+// @Summary Update promotion
+// @Description Update a promotion. Orders that already used the coupon keep their discount (admin only)
+// @Tags Admin Promotions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Promotion ID"
+// @Param request body entities.UpdatePromotionRequest true "Promotion data"
+// @Success 200 {object} entities.ApiResponse{data=entities.Promotion}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/promotions/{id} [put]
+func (h *PromotionHandler) UpdatePromotion(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid promotion ID", err)
+	}
+
+	var req entities.UpdatePromotionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	promotion, err := h.promotionService.UpdatePromotion(c.UserContext(), id, &req)
+	if err != nil {
+		if errors.Is(err, entities.ErrPromotionNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Promotion not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to update promotion", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Promotion updated successfully",
+		Data:    promotion,
+	})
+}
+
+// DeletePromotion godoc
+// @Summary Delete promotion
+// @Description Delete a promotion. The coupon can no longer be applied but past order discounts are kept (admin only)
+// @Tags Admin Promotions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/promotions/{id} [delete]
+func (h *PromotionHandler) DeletePromotion(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid promotion ID", err)
+	}
+
+	if err := h.promotionService.DeletePromotion(c.UserContext(), id); err != nil {
+		if errors.Is(err, entities.ErrPromotionNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Promotion not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to delete promotion", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Promotion deleted successfully",
+	})
+}
@@ -0,0 +1,118 @@
+// ไฟล์นี้ใช้สำหรับจัดการ HTTP requests ที่เกี่ยวกับการคืนเงิน (สำหรับผู้ดูแลระบบ)
+// ประกอบด้วยการคืนเงินเต็มจำนวนหรือบางส่วนของธุรกรรม และการดูประวัติการคืนเงิน
+
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type RefundHandler struct {
+	refundService services.RefundService
+}
+
+// NewRefundHandler สร้าง RefundHandler ใหม่
+func NewRefundHandler(refundService services.RefundService) *RefundHandler {
+	return &RefundHandler{
+		refundService: refundService,
+	}
+}
+
+// CreateRefund godoc
+// @Summary Refund a payment
+// @Description Refund all or part of a completed payment, optionally per order item and with restocking. Restocking is refused for cancelled orders, which were restocked when cancelled. The order payment status becomes refunded or partially_refunded (admin only)
+// @Tags Admin Refunds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Param request body entities.CreateRefundRequest true "Refund details"
+// @Success 201 {object} entities.ApiResponse{data=entities.Refund}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Failure 502 {object} entities.ErrorResponse
+// @Router /api/admin/payments/{id}/refunds [post]
+func (h *RefundHandler) CreateRefund(c *fiber.Ctx) error {
+	actorID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	transactionID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid payment ID", err)
+	}
+
+	var req entities.CreateRefundRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	refund, err := h.refundService.CreateRefund(c.UserContext(), transactionID, actorID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrPaymentNotFound):
+			return errorResponse(c, fiber.StatusNotFound, "Payment not found", err)
+		case errors.Is(err, entities.ErrRefundNotAllowed), errors.Is(err, entities.ErrRefundExceedsCaptured),
+			errors.Is(err, entities.ErrRestockCancelledOrder):
+			return errorResponse(c, fiber.StatusConflict, "Refund not allowed", err)
+		case errors.Is(err, entities.ErrRefundFailed):
+			return errorResponse(c, fiber.StatusBadGateway, "Payment provider failed to refund", err)
+		}
+		return errorResponse(c, fiber.StatusBadRequest, "Failed to create refund", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Refund created successfully",
+		Data:    refund,
+	})
+}
+
+// GetRefunds godoc
+// @Summary List refunds of a payment
+// @Description Get all refunds of a payment, newest first (admin only)
+// @Tags Admin Refunds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Refund}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/payments/{id}/refunds [get]
+func (h *RefundHandler) GetRefunds(c *fiber.Ctx) error {
+	transactionID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid payment ID", err)
+	}
+
+	refunds, err := h.refundService.GetRefunds(c.UserContext(), transactionID)
+	if err != nil {
+		if errors.Is(err, entities.ErrPaymentNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Payment not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get refunds", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Refunds retrieved successfully",
+		Data:    refunds,
+	})
+}
@@ -0,0 +1,442 @@
+// ไฟล์นี้ใช้สำหรับจัดการ HTTP requests ที่เกี่ยวกับคำขอคืนสินค้า (RMA)
+// ลูกค้ายื่นและยกเลิกคำขอของตัวเอง ผู้ดูแลระบบอนุมัติ รับสินค้า ตรวจสินค้า และคืนเงิน
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ReturnHandler struct {
+	returnService services.ReturnService
+}
+
+// NewReturnHandler สร้าง ReturnHandler ใหม่
+func NewReturnHandler(returnService services.ReturnService) *ReturnHandler {
+	return &ReturnHandler{
+		returnService: returnService,
+	}
+}
+
+// CreateReturn godoc
+// @Summary Request a return
+// @Description Request a return of some items of a delivered and paid order. Each item can be returned up to the purchased quantity minus quantities already refunded or in other open returns
+// @Tags Returns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.CreateReturnRequest true "Return details"
+// @Success 201 {object} entities.ApiResponse{data=entities.Return}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/user/returns [post]
+func (h *ReturnHandler) CreateReturn(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	var req entities.CreateReturnRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	ret, err := h.returnService.CreateReturn(c.UserContext(), userID, &req)
+	if err != nil {
+		return returnError(c, "Failed to create return", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Return requested successfully",
+		Data:    ret,
+	})
+}
+
+// GetReturns godoc
+// @Summary List my returns
+// @Description Get a paginated list of the current user's return requests, newest first
+// @Tags Returns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Return}
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/user/returns [get]
+func (h *ReturnHandler) GetReturns(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	page, limit := getPaginationParams(c)
+
+	returns, pagination, err := h.returnService.GetUserReturns(c.UserContext(), userID, page, limit)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get returns", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success:    true,
+		Message:    "Returns retrieved successfully",
+		Data:       returns,
+		Pagination: pagination,
+	})
+}
+
+// GetReturn godoc
+// @Summary Get my return
+// @Description Get one of the current user's return requests by ID, including its status timeline
+// @Tags Returns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Return ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.Return}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Router /api/user/returns/{id} [get]
+func (h *ReturnHandler) GetReturn(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid return ID", err)
+	}
+
+	ret, err := h.returnService.GetUserReturn(c.UserContext(), userID, id)
+	if err != nil {
+		return returnError(c, "Failed to get return", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Return retrieved successfully",
+		Data:    ret,
+	})
+}
+
+// CancelReturn godoc
+// @Summary Cancel my return
+// @Description Cancel one of the current user's return requests. Only requested or approved returns can be cancelled
+// @Tags Returns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Return ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.Return}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/user/returns/{id}/cancel [post]
+func (h *ReturnHandler) CancelReturn(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid return ID", err)
+	}
+
+	ret, err := h.returnService.CancelReturn(c.UserContext(), userID, id)
+	if err != nil {
+		return returnError(c, "Failed to cancel return", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Return cancelled successfully",
+		Data:    ret,
+	})
+}
+
+// GetAllReturns godoc
+// @Summary List all returns
+// @Description Get a paginated list of all return requests, optionally filtered by status, newest first (admin only)
+// @Tags Admin Returns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Return status"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} entities.ApiResponse{data=[]entities.Return}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/returns [get]
+func (h *ReturnHandler) GetAllReturns(c *fiber.Ctx) error {
+	status := entities.ReturnStatus(c.Query("status"))
+	if status != "" && !status.IsValid() {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid filter parameters", fmt.Errorf("ไม่รู้จักสถานะ %s", status))
+	}
+
+	page, limit := getPaginationParams(c)
+
+	returns, pagination, err := h.returnService.GetReturns(c.UserContext(), status, page, limit)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get returns", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success:    true,
+		Message:    "Returns retrieved successfully",
+		Data:       returns,
+		Pagination: pagination,
+	})
+}
+
+// AdminGetReturn godoc
+// @Summary Get return
+// @Description Get any return request by ID, including its status timeline (admin only)
+// @Tags Admin Returns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Return ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.Return}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Router /api/admin/returns/{id} [get]
+func (h *ReturnHandler) AdminGetReturn(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid return ID", err)
+	}
+
+	ret, err := h.returnService.GetReturn(c.UserContext(), id)
+	if err != nil {
+		return returnError(c, "Failed to get return", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Return retrieved successfully",
+		Data:    ret,
+	})
+}
+
+// ApproveReturn godoc
+// @Summary Approve a return
+// @Description Approve a requested return so the customer can send the items back (admin only)
+// @Tags Admin Returns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Return ID"
+// @Param request body entities.ReturnActionRequest false "Note"
+// @Success 200 {object} entities.ApiResponse{data=entities.Return}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/admin/returns/{id}/approve [post]
+func (h *ReturnHandler) ApproveReturn(c *fiber.Ctx) error {
+	return h.returnAction(c, "Return approved successfully", h.returnService.ApproveReturn)
+}
+
+// RejectReturn godoc
+// @Summary Reject a return
+// @Description Reject a requested return (admin only)
+// @Tags Admin Returns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Return ID"
+// @Param request body entities.ReturnActionRequest false "Reason for rejecting"
+// @Success 200 {object} entities.ApiResponse{data=entities.Return}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/admin/returns/{id}/reject [post]
+func (h *ReturnHandler) RejectReturn(c *fiber.Ctx) error {
+	return h.returnAction(c, "Return rejected successfully", h.returnService.RejectReturn)
+}
+
+// ReceiveReturn godoc
+// @Summary Mark return as received
+// @Description Record that the items of an approved return arrived at the store and are waiting for inspection (admin only)
+// @Tags Admin Returns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Return ID"
+// @Param request body entities.ReturnActionRequest false "Note"
+// @Success 200 {object} entities.ApiResponse{data=entities.Return}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/admin/returns/{id}/receive [post]
+func (h *ReturnHandler) ReceiveReturn(c *fiber.Ctx) error {
+	return h.returnAction(c, "Return received successfully", h.returnService.ReceiveReturn)
+}
+
+// InspectReturn godoc
+// @Summary Inspect a return
+// @Description Record the accepted quantity of each returned item and whether it goes back to stock, then refund the accepted items, restock them and complete the return. Items left out are not accepted. If the refund fails the return stays inspected and can be retried (admin only)
+// @Tags Admin Returns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Return ID"
+// @Param request body entities.InspectReturnRequest true "Inspection result"
+// @Success 200 {object} entities.ApiResponse{data=entities.Return}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Failure 502 {object} entities.ErrorResponse
+// @Router /api/admin/returns/{id}/inspect [post]
+func (h *ReturnHandler) InspectReturn(c *fiber.Ctx) error {
+	actorID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid return ID", err)
+	}
+
+	var req entities.InspectReturnRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	ret, err := h.returnService.InspectReturn(c.UserContext(), id, actorID, &req)
+	if err != nil {
+		return returnError(c, "Failed to inspect return", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Return inspected and completed successfully",
+		Data:    ret,
+	})
+}
+
+// RefundReturn godoc
+// @Summary Retry a return refund
+// @Description Refund the accepted items of an inspected return and complete it, after an earlier refund attempt failed. A return is never refunded twice (admin only)
+// @Tags Admin Returns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Return ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.Return}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Failure 502 {object} entities.ErrorResponse
+// @Router /api/admin/returns/{id}/refund [post]
+func (h *ReturnHandler) RefundReturn(c *fiber.Ctx) error {
+	actorID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid return ID", err)
+	}
+
+	ret, err := h.returnService.RefundReturn(c.UserContext(), id, actorID)
+	if err != nil {
+		return returnError(c, "Failed to refund return", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Return refunded and completed successfully",
+		Data:    ret,
+	})
+}
+
+// returnAction ใช้ร่วมกันระหว่างการอนุมัติ ปฏิเสธ และรับสินค้าคืน ซึ่งรับเฉพาะหมายเหตุที่ไม่บังคับ
+func (h *ReturnHandler) returnAction(c *fiber.Ctx, message string, action func(ctx context.Context, id, actorID uuid.UUID, note string) (*entities.Return, error)) error {
+	actorID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid return ID", err)
+	}
+
+	var req entities.ReturnActionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+		}
+	}
+
+	ret, err := action(c.UserContext(), id, actorID, req.Note)
+	if err != nil {
+		return returnError(c, "Failed to update return", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: message,
+		Data:    ret,
+	})
+}
+
+// returnError แปลง error ของคำขอคืนสินค้าเป็น HTTP status
+func returnError(c *fiber.Ctx, message string, err error) error {
+	switch {
+	case errors.Is(err, entities.ErrReturnNotFound):
+		return errorResponse(c, fiber.StatusNotFound, "Return not found", err)
+	case errors.Is(err, entities.ErrOrderNotFound):
+		return errorResponse(c, fiber.StatusNotFound, "Order not found", err)
+	case errors.Is(err, entities.ErrInvalidReturnItem):
+		return errorResponse(c, fiber.StatusBadRequest, message, err)
+	case errors.Is(err, entities.ErrReturnNotAllowed), errors.Is(err, entities.ErrReturnStatusConflict):
+		return errorResponse(c, fiber.StatusConflict, message, err)
+	case errors.Is(err, entities.ErrRefundNotAllowed), errors.Is(err, entities.ErrRefundExceedsCaptured):
+		return errorResponse(c, fiber.StatusConflict, "Refund not allowed", err)
+	case errors.Is(err, entities.ErrRefundFailed):
+		return errorResponse(c, fiber.StatusBadGateway, "Payment provider failed to refund", err)
+	}
+	return errorResponse(c, fiber.StatusInternalServerError, message, err)
+}
@@ -0,0 +1,125 @@
+// ไฟล์นี้ใช้สำหรับจัดการ HTTP requests ที่เกี่ยวกับพัสดุของคำสั่งซื้อ (เฉพาะผู้ดูแลระบบ)
+// ลูกค้าดูพัสดุและจุดติดตามได้จากรายละเอียดคำสั่งซื้อของตัวเอง
+
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ShipmentHandler struct {
+	shipmentService services.ShipmentService
+}
+
+// NewShipmentHandler สร้าง ShipmentHandler ใหม่
+func NewShipmentHandler(shipmentService services.ShipmentService) *ShipmentHandler {
+	return &ShipmentHandler{
+		shipmentService: shipmentService,
+	}
+}
+
+// CreateShipment godoc
+// @Summary Ship an order
+// @Description Create a shipment for all or part of an order. Without a tracking number the carrier client creates one. The first shipment moves the order to shipped, and the order becomes delivered once every item is shipped and every shipment is delivered (admin only)
+// @Tags Admin Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body entities.CreateShipmentRequest true "Shipment details"
+// @Success 201 {object} entities.ApiResponse{data=entities.Shipment}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Failure 502 {object} entities.ErrorResponse
+// @Router /api/admin/orders/{id}/shipments [post]
+func (h *ShipmentHandler) CreateShipment(c *fiber.Ctx) error {
+	actorID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	orderID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid order ID", err)
+	}
+
+	var req entities.CreateShipmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	shipment, err := h.shipmentService.CreateShipment(c.UserContext(), orderID, actorID, &req)
+	if err != nil {
+		return shipmentError(c, "Failed to create shipment", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Shipment created successfully",
+		Data:    shipment,
+	})
+}
+
+// SyncShipment godoc
+// @Summary Sync shipment tracking
+// @Description Fetch the latest tracking events of a shipment from its carrier now instead of waiting for the background poller (admin only)
+// @Tags Admin Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Shipment ID"
+// @Success 200 {object} entities.ApiResponse{data=entities.Shipment}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Failure 502 {object} entities.ErrorResponse
+// @Router /api/admin/shipments/{id}/sync [post]
+func (h *ShipmentHandler) SyncShipment(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid shipment ID", err)
+	}
+
+	shipment, err := h.shipmentService.SyncShipment(c.UserContext(), id)
+	if err != nil {
+		return shipmentError(c, "Failed to sync shipment", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Shipment synced successfully",
+		Data:    shipment,
+	})
+}
+
+// shipmentError แปลง error ของการจัดการพัสดุเป็น HTTP status
+func shipmentError(c *fiber.Ctx, message string, err error) error {
+	var transitionErr *entities.StatusTransitionError
+	switch {
+	case errors.Is(err, entities.ErrOrderNotFound):
+		return errorResponse(c, fiber.StatusNotFound, "Order not found", err)
+	case errors.Is(err, entities.ErrShipmentNotFound):
+		return errorResponse(c, fiber.StatusNotFound, "Shipment not found", err)
+	case errors.As(err, &transitionErr):
+		return errorResponse(c, fiber.StatusConflict, message, err)
+	case errors.Is(err, entities.ErrUnknownCarrier), errors.Is(err, entities.ErrInvalidShipmentItem):
+		return errorResponse(c, fiber.StatusBadRequest, message, err)
+	case errors.Is(err, entities.ErrCarrierFailed):
+		return errorResponse(c, fiber.StatusBadGateway, "Carrier request failed", err)
+	}
+	return errorResponse(c, fiber.StatusInternalServerError, message, err)
+}
@@ -0,0 +1,248 @@
+// ไฟล์นี้ใช้สำหรับจัดการ HTTP requests ที่เกี่ยวกับวิธีจัดส่งและค่าจัดส่ง
+// ประกอบด้วย endpoint สาธารณะสำหรับดูวิธีจัดส่ง endpoint สำหรับคำนวณค่าจัดส่งของตะกร้า
+// และ endpoint สำหรับผู้ดูแลระบบในการจัดการวิธีจัดส่ง
+
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ShippingHandler struct {
+	shippingService services.ShippingService
+}
+
+// NewShippingHandler สร้าง ShippingHandler ใหม่
+func NewShippingHandler(shippingService services.ShippingService) *ShippingHandler {
+	return &ShippingHandler{
+		shippingService: shippingService,
+	}
+}
+
+// GetShippingMethods godoc
+// @Summary List shipping methods
+// @Description Get the shipping methods customers can choose at checkout
+// @Tags Shipping
+// @Accept json
+// @Produce json
+// @Success 200 {object} entities.ApiResponse{data=[]entities.ShippingMethod}
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/shipping-methods [get]
+func (h *ShippingHandler) GetShippingMethods(c *fiber.Ctx) error {
+	methods, err := h.shippingService.GetMethods(c.UserContext(), true)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get shipping methods", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Shipping methods retrieved successfully",
+		Data:    methods,
+	})
+}
+
+// QuoteShipping godoc
+// @Summary Quote shipping for cart
+// @Description Price the current cart for every shipping method available to an address book entry or a province. Free-shipping thresholds use the total after coupon discounts
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.ShippingQuoteRequest true "Destination"
+// @Success 200 {object} entities.ApiResponse{data=[]entities.ShippingQuote}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 422 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/user/cart/shipping-quote [post]
+func (h *ShippingHandler) QuoteShipping(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	var req entities.ShippingQuoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	quotes, err := h.shippingService.QuoteCart(c.UserContext(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrCartEmpty):
+			return errorResponse(c, fiber.StatusBadRequest, "Cart is empty", err)
+		case errors.Is(err, entities.ErrAddressNotFound):
+			return errorResponse(c, fiber.StatusNotFound, "Address not found", err)
+		case errors.Is(err, entities.ErrShippingNotAvailable):
+			return errorResponse(c, fiber.StatusUnprocessableEntity, "Shipping method not available", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to quote shipping", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Shipping quoted successfully",
+		Data:    quotes,
+	})
+}
+
+// GetAllShippingMethods godoc
+// @Summary List all shipping methods
+// @Description Get every shipping method including inactive ones (admin only)
+// @Tags Admin Shipping
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} entities.ApiResponse{data=[]entities.ShippingMethod}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/shipping-methods [get]
+func (h *ShippingHandler) GetAllShippingMethods(c *fiber.Ctx) error {
+	methods, err := h.shippingService.GetMethods(c.UserContext(), false)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get shipping methods", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Shipping methods retrieved successfully",
+		Data:    methods,
+	})
+}
+
+// CreateShippingMethod godoc
+// @Summary Create shipping method
+// @Description Create a shipping method. rate_type is flat or weight_based (fee + fee_per_kg for every started kg). free_over > 0 waives the fee above that amount and regions override the fee per province (admin only)
+// @Tags Admin Shipping
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.CreateShippingMethodRequest true "Shipping method data"
+// @Success 201 {object} entities.ApiResponse{data=entities.ShippingMethod}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/shipping-methods [post]
+func (h *ShippingHandler) CreateShippingMethod(c *fiber.Ctx) error {
+	var req entities.CreateShippingMethodRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	method, err := h.shippingService.CreateMethod(c.UserContext(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrInvalidShippingMethod):
+			return errorResponse(c, fiber.StatusBadRequest, "Invalid shipping method", err)
+		case errors.Is(err, entities.ErrShippingCodeExists):
+			return errorResponse(c, fiber.StatusConflict, "Shipping method code already exists", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to create shipping method", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Shipping method created successfully",
+		Data:    method,
+	})
+}
+
+// UpdateShippingMethod godoc
+// @Summary Update shipping method
+// @Description Update a shipping method. Existing orders keep the fee they were charged (admin only)
+// @Tags Admin Shipping
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Shipping method ID"
+// @Param request body entities.UpdateShippingMethodRequest true "Shipping method data"
+// @Success 200 {object} entities.ApiResponse{data=entities.ShippingMethod}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/shipping-methods/{id} [put]
+func (h *ShippingHandler) UpdateShippingMethod(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid shipping method ID", err)
+	}
+
+	var req entities.UpdateShippingMethodRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	method, err := h.shippingService.UpdateMethod(c.UserContext(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrShippingMethodNotFound):
+			return errorResponse(c, fiber.StatusNotFound, "Shipping method not found", err)
+		case errors.Is(err, entities.ErrInvalidShippingMethod):
+			return errorResponse(c, fiber.StatusBadRequest, "Invalid shipping method", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to update shipping method", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Shipping method updated successfully",
+		Data:    method,
+	})
+}
+
+// DeleteShippingMethod godoc
+// @Summary Delete shipping method
+// @Description Delete a shipping method so it can no longer be chosen at checkout (admin only)
+// @Tags Admin Shipping
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Shipping method ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/shipping-methods/{id} [delete]
+func (h *ShippingHandler) DeleteShippingMethod(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid shipping method ID", err)
+	}
+
+	if err := h.shippingService.DeleteMethod(c.UserContext(), id); err != nil {
+		if errors.Is(err, entities.ErrShippingMethodNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Shipping method not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to delete shipping method", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Shipping method deleted successfully",
+	})
+}
@@ -0,0 +1,169 @@
+// ไฟล์นี้ใช้สำหรับจัดการ HTTP requests ที่เกี่ยวกับกฎภาษีมูลค่าเพิ่ม (สำหรับผู้ดูแลระบบ)
+// กฎภาษีผูกกับสินค้าหรือหมวดหมู่ผ่าน tax_rule_id และถูกนำไปคำนวณตอนสร้างคำสั่งซื้อ
+
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TaxHandler struct {
+	taxService services.TaxService
+}
+
+// NewTaxHandler สร้าง TaxHandler ใหม่
+func NewTaxHandler(taxService services.TaxService) *TaxHandler {
+	return &TaxHandler{
+		taxService: taxService,
+	}
+}
+
+// GetTaxRules godoc
+// @Summary List tax rules
+// @Description Get all tax rules. Products and categories without a rule use VAT 7% inclusive (admin only)
+// @Tags Admin Tax
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} entities.ApiResponse{data=[]entities.TaxRule}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/tax-rules [get]
+func (h *TaxHandler) GetTaxRules(c *fiber.Ctx) error {
+	taxRules, err := h.taxService.GetTaxRules(c.UserContext())
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get tax rules", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Tax rules retrieved successfully",
+		Data:    taxRules,
+	})
+}
+
+// CreateTaxRule godoc
+// @Summary Create tax rule
+// @Description Create a tax rule. Rate is in basis points (700 = 7%) (admin only)
+// @Tags Admin Tax
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.CreateTaxRuleRequest true "Tax rule data"
+// @Success 201 {object} entities.ApiResponse{data=entities.TaxRule}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/tax-rules [post]
+func (h *TaxHandler) CreateTaxRule(c *fiber.Ctx) error {
+	var req entities.CreateTaxRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	taxRule, err := h.taxService.CreateTaxRule(c.UserContext(), &req)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to create tax rule", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Tax rule created successfully",
+		Data:    taxRule,
+	})
+}
+
+// UpdateTaxRule godoc
+// @Summary Update tax rule
+// @Description Update a tax rule. Existing orders keep the tax they were charged (admin only)
+// @Tags Admin Tax
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tax rule ID"
+// @Param request body entities.UpdateTaxRuleRequest true "Tax rule data"
+// @Success 200 {object} entities.ApiResponse{data=entities.TaxRule}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/tax-rules/{id} [put]
+func (h *TaxHandler) UpdateTaxRule(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid tax rule ID", err)
+	}
+
+	var req entities.UpdateTaxRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	taxRule, err := h.taxService.UpdateTaxRule(c.UserContext(), id, &req)
+	if err != nil {
+		if errors.Is(err, entities.ErrTaxRuleNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Tax rule not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to update tax rule", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Tax rule updated successfully",
+		Data:    taxRule,
+	})
+}
+
+// DeleteTaxRule godoc
+// @Summary Delete tax rule
+// @Description Delete a tax rule that is no longer assigned to any product or category (admin only)
+// @Tags Admin Tax
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tax rule ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/tax-rules/{id} [delete]
+func (h *TaxHandler) DeleteTaxRule(c *fiber.Ctx) error {
+	id, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid tax rule ID", err)
+	}
+
+	if err := h.taxService.DeleteTaxRule(c.UserContext(), id); err != nil {
+		switch {
+		case errors.Is(err, entities.ErrTaxRuleNotFound):
+			return errorResponse(c, fiber.StatusNotFound, "Tax rule not found", err)
+		case errors.Is(err, entities.ErrTaxRuleInUse):
+			return errorResponse(c, fiber.StatusConflict, "Tax rule is in use", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to delete tax rule", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Tax rule deleted successfully",
+	})
+}
@@ -0,0 +1,295 @@
+// ไฟล์นี้ใช้สำหรับจัดการ HTTP requests ที่เกี่ยวกับตัวเลือกและ variant ของสินค้า
+// ลูกค้าดู variant ของสินค้าได้ ส่วนผู้ดูแลระบบเพิ่ม/ลบตัวเลือก (ขนาด สี) และเพิ่ม/แก้ไข/ลบ variant
+// สต็อกของ variant ปรับผ่าน inventory adjustments โดยระบุ variant_id
+
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type VariantHandler struct {
+	variantService services.VariantService
+}
+
+// NewVariantHandler สร้าง VariantHandler ใหม่
+func NewVariantHandler(variantService services.VariantService) *VariantHandler {
+	return &VariantHandler{
+		variantService: variantService,
+	}
+}
+
+// GetVariants godoc
+// @Summary List product variants
+// @Description Get the variants of a product with their SKU, price, stock and option values
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} entities.ApiResponse{data=[]entities.ProductVariant}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/products/{id}/variants [get]
+func (h *VariantHandler) GetVariants(c *fiber.Ctx) error {
+	productID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid product ID", err)
+	}
+
+	variants, err := h.variantService.GetVariants(c.UserContext(), productID)
+	if err != nil {
+		if errors.Is(err, entities.ErrProductNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Product not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get variants", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Variants retrieved successfully",
+		Data:    variants,
+	})
+}
+
+// CreateOption godoc
+// @Summary Create product option
+// @Description Add an option type such as size or color with its values; only allowed before the product has variants (admin only)
+// @Tags Admin Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Product ID"
+// @Param request body entities.CreateProductOptionRequest true "Option data"
+// @Success 201 {object} entities.ApiResponse{data=entities.ProductOption}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/admin/products/{id}/options [post]
+func (h *VariantHandler) CreateOption(c *fiber.Ctx) error {
+	productID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid product ID", err)
+	}
+
+	var req entities.CreateProductOptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	option, err := h.variantService.CreateOption(c.UserContext(), productID, &req)
+	if err != nil {
+		return variantError(c, err, "Failed to create option")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Option created successfully",
+		Data:    option,
+	})
+}
+
+// DeleteOption godoc
+// @Summary Delete product option
+// @Description Delete an option type and its values; refused while any variant uses them (admin only)
+// @Tags Admin Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Product ID"
+// @Param optionId path string true "Option ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/admin/products/{id}/options/{optionId} [delete]
+func (h *VariantHandler) DeleteOption(c *fiber.Ctx) error {
+	productID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid product ID", err)
+	}
+
+	optionID, err := parseUUIDParam(c, "optionId")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid option ID", err)
+	}
+
+	if err := h.variantService.DeleteOption(c.UserContext(), productID, optionID); err != nil {
+		return variantError(c, err, "Failed to delete option")
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Option deleted successfully",
+	})
+}
+
+// CreateVariant godoc
+// @Summary Create product variant
+// @Description Add a variant with one value per product option; initial stock is recorded in the inventory ledger (admin only)
+// @Tags Admin Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Product ID"
+// @Param request body entities.CreateVariantRequest true "Variant data"
+// @Success 201 {object} entities.ApiResponse{data=entities.ProductVariant}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/admin/products/{id}/variants [post]
+func (h *VariantHandler) CreateVariant(c *fiber.Ctx) error {
+	actorID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	productID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid product ID", err)
+	}
+
+	var req entities.CreateVariantRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	variant, err := h.variantService.CreateVariant(c.UserContext(), productID, actorID, &req)
+	if err != nil {
+		return variantError(c, err, "Failed to create variant")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Variant created successfully",
+		Data:    variant,
+	})
+}
+
+// UpdateVariant godoc
+// @Summary Update product variant
+// @Description Update the SKU, price override or image of a variant (admin only)
+// @Tags Admin Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Product ID"
+// @Param variantId path string true "Variant ID"
+// @Param request body entities.UpdateVariantRequest true "Variant data"
+// @Success 200 {object} entities.ApiResponse{data=entities.ProductVariant}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/admin/products/{id}/variants/{variantId} [put]
+func (h *VariantHandler) UpdateVariant(c *fiber.Ctx) error {
+	productID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid product ID", err)
+	}
+
+	variantID, err := parseUUIDParam(c, "variantId")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid variant ID", err)
+	}
+
+	var req entities.UpdateVariantRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	variant, err := h.variantService.UpdateVariant(c.UserContext(), productID, variantID, &req)
+	if err != nil {
+		return variantError(c, err, "Failed to update variant")
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Variant updated successfully",
+		Data:    variant,
+	})
+}
+
+// DeleteVariant godoc
+// @Summary Delete product variant
+// @Description Delete a variant; its remaining stock is written off in the inventory ledger and it is removed from carts (admin only)
+// @Tags Admin Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Product ID"
+// @Param variantId path string true "Variant ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/admin/products/{id}/variants/{variantId} [delete]
+func (h *VariantHandler) DeleteVariant(c *fiber.Ctx) error {
+	actorID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	productID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid product ID", err)
+	}
+
+	variantID, err := parseUUIDParam(c, "variantId")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid variant ID", err)
+	}
+
+	if err := h.variantService.DeleteVariant(c.UserContext(), productID, variantID, actorID); err != nil {
+		return variantError(c, err, "Failed to delete variant")
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Variant deleted successfully",
+	})
+}
+
+// variantError แปลง error ของตัวเลือกและ variant เป็น HTTP status
+func variantError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, entities.ErrProductNotFound):
+		return errorResponse(c, fiber.StatusNotFound, "Product not found", err)
+	case errors.Is(err, entities.ErrVariantNotFound):
+		return errorResponse(c, fiber.StatusNotFound, "Variant not found", err)
+	case errors.Is(err, entities.ErrProductOptionNotFound):
+		return errorResponse(c, fiber.StatusNotFound, "Option not found", err)
+	case errors.Is(err, entities.ErrDuplicateSKU),
+		errors.Is(err, entities.ErrVariantStockConflict),
+		errors.Is(err, entities.ErrProductOptionInUse),
+		errors.Is(err, entities.ErrProductHasVariants):
+		return errorResponse(c, fiber.StatusConflict, message, err)
+	}
+	return errorResponse(c, fiber.StatusBadRequest, message, err)
+}
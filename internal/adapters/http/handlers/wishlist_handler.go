@@ -0,0 +1,193 @@
+// ไฟล์นี้ใช้สำหรับจัดการ HTTP requests ที่เกี่ยวกับรายการสินค้าที่อยากได้ของผู้ใช้ที่เข้าสู่ระบบแล้ว
+// สินค้าในรายการระบุด้วย Product ID และย้ายไปตะกร้าได้ผ่านเงื่อนไขเดียวกับการเพิ่มสินค้าลงตะกร้า
+
+package handlers
+
+import (
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type WishlistHandler struct {
+	wishlistService services.WishlistService
+}
+
+// NewWishlistHandler สร้าง WishlistHandler ใหม่
+func NewWishlistHandler(wishlistService services.WishlistService) *WishlistHandler {
+	return &WishlistHandler{
+		wishlistService: wishlistService,
+	}
+}
+
+// GetWishlist godoc
+// @Summary List my wishlist
+// @Description Get a paginated list of the current user's wishlisted products, most recently added first
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} entities.ApiResponse{data=[]entities.WishlistItem}
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 500 {object} entities.ErrorResponse
+// @Router /api/user/wishlist [get]
+func (h *WishlistHandler) GetWishlist(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	page, limit := getPaginationParams(c)
+
+	items, pagination, err := h.wishlistService.GetWishlist(c.UserContext(), userID, page, limit)
+	if err != nil {
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to get wishlist", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success:    true,
+		Message:    "Wishlist retrieved successfully",
+		Data:       items,
+		Pagination: pagination,
+	})
+}
+
+// AddToWishlist godoc
+// @Summary Add product to wishlist
+// @Description Add a product to the current user's wishlist and choose whether to be notified when it is back in stock or its price drops. Adding a product that is already in the wishlist updates its notification settings
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.AddToWishlistRequest true "Product and notification settings"
+// @Success 200 {object} entities.ApiResponse{data=entities.WishlistItem}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Router /api/user/wishlist [post]
+func (h *WishlistHandler) AddToWishlist(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	var req entities.AddToWishlistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	item, err := h.wishlistService.AddToWishlist(c.UserContext(), userID, &req)
+	if err != nil {
+		if errors.Is(err, entities.ErrProductNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Product not found", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to add product to wishlist", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Product added to wishlist successfully",
+		Data:    item,
+	})
+}
+
+// RemoveFromWishlist godoc
+// @Summary Remove product from wishlist
+// @Description Remove a product from the current user's wishlist
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Product ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Router /api/user/wishlist/{id} [delete]
+func (h *WishlistHandler) RemoveFromWishlist(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	productID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid product ID", err)
+	}
+
+	if err := h.wishlistService.RemoveFromWishlist(c.UserContext(), userID, productID); err != nil {
+		if errors.Is(err, entities.ErrWishlistItemNotFound) {
+			return errorResponse(c, fiber.StatusNotFound, "Product not in wishlist", err)
+		}
+		return errorResponse(c, fiber.StatusInternalServerError, "Failed to remove product from wishlist", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Product removed from wishlist successfully",
+	})
+}
+
+// MoveToCart godoc
+// @Summary Move wishlist product to cart
+// @Description Add a wishlisted product to the current user's cart (1 item unless a quantity is given) and remove it from the wishlist. The product stays in the wishlist if it cannot be added to the cart
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Product ID"
+// @Param request body entities.MoveToCartRequest false "Quantity"
+// @Success 200 {object} entities.ApiResponse{data=entities.Cart}
+// @Failure 400 {object} entities.ErrorResponse
+// @Failure 401 {object} entities.ErrorResponse
+// @Failure 404 {object} entities.ErrorResponse
+// @Failure 409 {object} entities.ErrorResponse
+// @Router /api/user/wishlist/{id}/move-to-cart [post]
+func (h *WishlistHandler) MoveToCart(c *fiber.Ctx) error {
+	userID, err := getUserID(c)
+	if err != nil {
+		return errorResponse(c, fiber.StatusUnauthorized, "Invalid user", err)
+	}
+
+	productID, err := parseUUIDParam(c, "id")
+	if err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Invalid product ID", err)
+	}
+
+	var req entities.MoveToCartRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return errorResponse(c, fiber.StatusBadRequest, "Invalid request body", err)
+		}
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		return errorResponse(c, fiber.StatusBadRequest, "Validation failed", err)
+	}
+
+	cart, err := h.wishlistService.MoveToCart(c.UserContext(), userID, productID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrWishlistItemNotFound):
+			return errorResponse(c, fiber.StatusNotFound, "Product not in wishlist", err)
+		case errors.Is(err, entities.ErrOutOfStock):
+			return errorResponse(c, fiber.StatusConflict, "Insufficient stock", err)
+		}
+		return errorResponse(c, fiber.StatusBadRequest, "Failed to move product to cart", err)
+	}
+
+	return c.JSON(entities.ApiResponse{
+		Success: true,
+		Message: "Product moved to cart successfully",
+		Data:    cart,
+	})
+}
@@ -3,12 +3,13 @@ package routes
 import (
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/http/handlers"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/http/middleware"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
 	"github.com/gofiber/fiber/v2"
 	fiberSwagger "github.com/gofiber/swagger"
 )
 
 // SetupRoutes กำหนดเส้นทาง (routes) สำหรับแอปพลิเคชัน
-func SetupRoutes(app *fiber.App, authHandler *handlers.AuthHandler, adminHandler *handlers.AdminHandler) {
+func SetupRoutes(app *fiber.App, authHandler *handlers.AuthHandler, adminHandler *handlers.AdminHandler, productHandler *handlers.ProductHandler, cartHandler *handlers.CartHandler, orderHandler *handlers.OrderHandler, inventoryHandler *handlers.InventoryHandler, paymentHandler *handlers.PaymentHandler, refundHandler *handlers.RefundHandler, currencyHandler *handlers.CurrencyHandler, taxHandler *handlers.TaxHandler, promotionHandler *handlers.PromotionHandler, shippingHandler *handlers.ShippingHandler, addressHandler *handlers.AddressHandler, shipmentHandler *handlers.ShipmentHandler, returnHandler *handlers.ReturnHandler, wishlistHandler *handlers.WishlistHandler, variantHandler *handlers.VariantHandler, categoryHandler *handlers.CategoryHandler) {
 
 	// Swagger
 	app.Get("/swagger/*", fiberSwagger.HandlerDefault)
@@ -21,16 +22,170 @@ func SetupRoutes(app *fiber.App, authHandler *handlers.AuthHandler, adminHandler
 	auth.Post("/register", authHandler.Register)
 	auth.Post("/login", authHandler.Login)
 
+	// Payment Webhook Routes (ไม่ใช้ JWT แต่ตรวจลายเซ็น HMAC ของ payload แทน)
+	api.Post("/webhooks/payments/:provider", paymentHandler.HandleWebhook)
+
+	// Public Product Routes
+	// ลำดับสำคัญ: /search และ /category ต้องมาก่อน /:id
+	products := api.Group("/products")
+	products.Get("/", productHandler.GetProducts)
+	products.Get("/search", productHandler.SearchProducts)
+	products.Get("/suggest", productHandler.SuggestProducts)
+	products.Get("/category/:categoryId", productHandler.GetProductsByCategory)
+	products.Get("/:id", productHandler.GetProduct)
+	products.Get("/:id/variants", variantHandler.GetVariants)
+
+	// Public Category Routes
+	// ลำดับสำคัญ: /tree และ /slug ต้องมาก่อน /:id
+	categories := api.Group("/categories")
+	categories.Get("/", categoryHandler.GetCategories)
+	categories.Get("/tree", categoryHandler.GetCategoryTree)
+	categories.Get("/slug/:slug", categoryHandler.GetCategoryBySlug)
+	categories.Get("/:id", categoryHandler.GetCategory)
+
+	// Public Currency Routes (สกุลเงินที่ใช้แสดงราคาได้)
+	api.Get("/exchange-rates", currencyHandler.GetExchangeRates)
+
+	// Public Shipping Routes (วิธีจัดส่งที่ลูกค้าเลือกได้)
+	api.Get("/shipping-methods", shippingHandler.GetShippingMethods)
+
 	// Protect Routes
 	user := api.Group("/user")
 	user.Use(middleware.AuthMiddleware())
 	user.Get("/profile", authHandler.GetUserProfile)
 
+	// Address Routes (สมุดที่อยู่ของผู้ใช้)
+	addresses := user.Group("/addresses")
+	addresses.Get("/", addressHandler.GetAddresses)
+	addresses.Post("/", addressHandler.CreateAddress)
+	addresses.Get("/:id", addressHandler.GetAddress)
+	addresses.Put("/:id", addressHandler.UpdateAddress)
+	addresses.Put("/:id/default", addressHandler.SetDefaultAddress)
+	addresses.Delete("/:id", addressHandler.DeleteAddress)
+
+	// Wishlist Routes (รายการสินค้าที่อยากได้ของผู้ใช้)
+	wishlist := user.Group("/wishlist")
+	wishlist.Get("/", wishlistHandler.GetWishlist)
+	wishlist.Post("/", wishlistHandler.AddToWishlist)
+	wishlist.Delete("/:id", wishlistHandler.RemoveFromWishlist)
+	wishlist.Post("/:id/move-to-cart", wishlistHandler.MoveToCart)
+
+	// Cart Routes (ตะกร้าสินค้าของผู้ใช้ที่เข้าสู่ระบบ)
+	cart := user.Group("/cart")
+	cart.Get("/", cartHandler.GetCart)
+	cart.Delete("/", cartHandler.ClearCart)
+	cart.Post("/items", cartHandler.AddToCart)
+	cart.Put("/items/:id", cartHandler.UpdateCartItem)
+	cart.Delete("/items/:id", cartHandler.RemoveFromCart)
+	cart.Post("/coupon", cartHandler.ApplyCoupon)
+	cart.Delete("/coupon", cartHandler.RemoveCoupon)
+	cart.Post("/shipping-quote", shippingHandler.QuoteShipping)
+
+	// Order Routes (คำสั่งซื้อของผู้ใช้ที่เข้าสู่ระบบ)
+	orders := user.Group("/orders")
+	orders.Post("/", orderHandler.CreateOrder)
+	orders.Get("/", orderHandler.GetOrders)
+	orders.Get("/:id", orderHandler.GetOrder)
+	orders.Post("/:id/cancel", orderHandler.CancelOrder)
+
+	// Payment Routes (การชำระเงินของคำสั่งซื้อ)
+	payments := user.Group("/payments")
+	payments.Post("/", paymentHandler.CreatePayment)
+	payments.Get("/:id", paymentHandler.GetPayment)
+	payments.Post("/:id/verify", paymentHandler.VerifyPayment)
+	payments.Post("/:id/cancel", paymentHandler.CancelPayment)
+
+	// Return Routes (คำขอคืนสินค้าของคำสั่งซื้อที่จัดส่งแล้ว)
+	returns := user.Group("/returns")
+	returns.Post("/", returnHandler.CreateReturn)
+	returns.Get("/", returnHandler.GetReturns)
+	returns.Get("/:id", returnHandler.GetReturn)
+	returns.Post("/:id/cancel", returnHandler.CancelReturn)
+
 	// Admin Only Routes
 	// กำหนด middleware สำหรับเส้นทางที่ต้องการการยืนยันตัวตนและสิทธิ์
 	// ใช้ middleware สำหรับการตรวจสอบสิทธิ์ที่เขียนไว้ในไฟล์ middleware/auth_middleware.go
 	admin := api.Group("/admin")
-	admin.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware("admin"))
+	admin.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware(entities.RoleAdmin))
 	admin.Get("/dashboard", adminHandler.GetDashboard)
 	admin.Post("/register", adminHandler.AdminRegister)
+
+	// Admin Product Routes
+	adminProducts := admin.Group("/products")
+	adminProducts.Post("/", productHandler.CreateProduct)
+	adminProducts.Put("/:id", productHandler.UpdateProduct)
+	adminProducts.Delete("/:id", productHandler.DeleteProduct)
+
+	// Admin Variant Routes (ตัวเลือกและ variant ของสินค้า)
+	adminProducts.Post("/:id/options", variantHandler.CreateOption)
+	adminProducts.Delete("/:id/options/:optionId", variantHandler.DeleteOption)
+	adminProducts.Post("/:id/variants", variantHandler.CreateVariant)
+	adminProducts.Put("/:id/variants/:variantId", variantHandler.UpdateVariant)
+	adminProducts.Delete("/:id/variants/:variantId", variantHandler.DeleteVariant)
+
+	// Admin Inventory Routes (ประวัติและการปรับสต็อกของสินค้า)
+	adminProducts.Get("/low-stock", inventoryHandler.GetLowStockProducts)
+	adminProducts.Get("/:id/inventory", inventoryHandler.GetMovements)
+	adminProducts.Post("/:id/inventory/adjustments", inventoryHandler.AdjustStock)
+	adminProducts.Post("/:id/inventory/rebuild", inventoryHandler.RebuildStock)
+
+	// Admin Category Routes (หมวดหมู่สินค้าแบบซ้อนกันได้)
+	adminCategories := admin.Group("/categories")
+	adminCategories.Post("/", categoryHandler.CreateCategory)
+	adminCategories.Put("/:id", categoryHandler.UpdateCategory)
+	adminCategories.Delete("/:id", categoryHandler.DeleteCategory)
+
+	// Admin Order Routes
+	adminOrders := admin.Group("/orders")
+	adminOrders.Get("/", orderHandler.GetAllOrders)
+	adminOrders.Get("/:id", orderHandler.AdminGetOrder)
+	adminOrders.Put("/:id/status", orderHandler.UpdateOrderStatus)
+	adminOrders.Put("/:id/payment-status", orderHandler.UpdatePaymentStatus)
+	adminOrders.Put("/:id/shipping-status", orderHandler.UpdateShippingStatus)
+	adminOrders.Post("/:id/shipments", shipmentHandler.CreateShipment)
+
+	// Admin Shipment Routes (ดึงสถานะพัสดุจากผู้ให้บริการขนส่งทันที)
+	admin.Post("/shipments/:id/sync", shipmentHandler.SyncShipment)
+
+	// Admin Refund Routes (คืนเงินของธุรกรรมที่ชำระแล้ว)
+	adminPayments := admin.Group("/payments")
+	adminPayments.Get("/:id/refunds", refundHandler.GetRefunds)
+	adminPayments.Post("/:id/refunds", refundHandler.CreateRefund)
+
+	// Admin Return Routes (อนุมัติ รับ ตรวจสินค้าคืน แล้วคืนสต็อกและคืนเงิน)
+	adminReturns := admin.Group("/returns")
+	adminReturns.Get("/", returnHandler.GetAllReturns)
+	adminReturns.Get("/:id", returnHandler.AdminGetReturn)
+	adminReturns.Post("/:id/approve", returnHandler.ApproveReturn)
+	adminReturns.Post("/:id/reject", returnHandler.RejectReturn)
+	adminReturns.Post("/:id/receive", returnHandler.ReceiveReturn)
+	adminReturns.Post("/:id/inspect", returnHandler.InspectReturn)
+	adminReturns.Post("/:id/refund", returnHandler.RefundReturn)
+
+	// Admin Currency Routes (อัตราแลกเปลี่ยนเทียบกับสกุลเงินหลัก)
+	adminExchangeRates := admin.Group("/exchange-rates")
+	adminExchangeRates.Put("/:currency", currencyHandler.SetExchangeRate)
+	adminExchangeRates.Delete("/:currency", currencyHandler.DeleteExchangeRate)
+
+	// Admin Tax Routes (กฎภาษีมูลค่าเพิ่มของสินค้าและหมวดหมู่)
+	adminTaxRules := admin.Group("/tax-rules")
+	adminTaxRules.Get("/", taxHandler.GetTaxRules)
+	adminTaxRules.Post("/", taxHandler.CreateTaxRule)
+	adminTaxRules.Put("/:id", taxHandler.UpdateTaxRule)
+	adminTaxRules.Delete("/:id", taxHandler.DeleteTaxRule)
+
+	// Admin Promotion Routes (โปรโมชันและคูปองส่วนลด)
+	adminPromotions := admin.Group("/promotions")
+	adminPromotions.Get("/", promotionHandler.GetPromotions)
+	adminPromotions.Post("/", promotionHandler.CreatePromotion)
+	adminPromotions.Get("/:id", promotionHandler.GetPromotion)
+	adminPromotions.Put("/:id", promotionHandler.UpdatePromotion)
+	adminPromotions.Delete("/:id", promotionHandler.DeletePromotion)
+
+	// Admin Shipping Routes (วิธีจัดส่งและกฎการคิดค่าจัดส่ง)
+	adminShipping := admin.Group("/shipping-methods")
+	adminShipping.Get("/", shippingHandler.GetAllShippingMethods)
+	adminShipping.Post("/", shippingHandler.CreateShippingMethod)
+	adminShipping.Put("/:id", shippingHandler.UpdateShippingMethod)
+	adminShipping.Delete("/:id", shippingHandler.DeleteShippingMethod)
 }
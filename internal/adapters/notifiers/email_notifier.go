@@ -0,0 +1,63 @@
+package notifiers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/notifiers"
+)
+
+type emailNotifier struct {
+	to string
+}
+
+// NewEmailNotifier สร้าง notifier สำหรับอีเมล
+// ตอนนี้ยังไม่มีระบบส่งอีเมลจริง จึงประกอบเนื้อหาอีเมลแล้วเขียนลง log แทน
+func NewEmailNotifier(to string) notifiers.LowStockNotifier {
+	return &emailNotifier{to: to}
+}
+
+func (n *emailNotifier) NotifyLowStock(ctx context.Context, products []*entities.LowStockProduct) error {
+	var body strings.Builder
+	for _, product := range products {
+		fmt.Fprintf(&body, "- %s: เหลือ %d ชิ้น (จุดสั่งซื้อ %d)\n", product.Name, product.Stock, product.Threshold)
+	}
+
+	log.Printf("[email] to=%s subject=%q\n%s", n.to, fmt.Sprintf("สินค้าสต็อกต่ำ %d รายการ", len(products)), body.String())
+	return nil
+}
+
+// NewEmailWishlistNotifier สร้าง notifier ที่ส่งอีเมลถึงเจ้าของรายการที่อยากได้แต่ละคน
+func NewEmailWishlistNotifier() notifiers.WishlistNotifier {
+	return &emailNotifier{}
+}
+
+// NotifyWishlist รวมการแจ้งเตือนของผู้ใช้คนเดียวกันไว้ในอีเมลฉบับเดียว
+func (n *emailNotifier) NotifyWishlist(ctx context.Context, notifications []*entities.WishlistNotification) error {
+	var recipients []string
+	bodies := make(map[string]*strings.Builder)
+	for _, notification := range notifications {
+		body, ok := bodies[notification.Email]
+		if !ok {
+			body = &strings.Builder{}
+			fmt.Fprintf(body, "สวัสดีคุณ %s\n", notification.FirstName)
+			bodies[notification.Email] = body
+			recipients = append(recipients, notification.Email)
+		}
+
+		switch notification.Kind {
+		case entities.WishlistNotificationBackInStock:
+			fmt.Fprintf(body, "- %s กลับมามีสินค้าแล้ว (เหลือ %d ชิ้น)\n", notification.ProductName, notification.Stock)
+		case entities.WishlistNotificationPriceDrop:
+			fmt.Fprintf(body, "- %s ลดราคาจาก %s เหลือ %s\n", notification.ProductName, notification.PreviousPrice, notification.Price)
+		}
+	}
+
+	for _, to := range recipients {
+		log.Printf("[email] to=%s subject=%q\n%s", to, "สินค้าที่คุณอยากได้มีความเคลื่อนไหว", bodies[to].String())
+	}
+	return nil
+}
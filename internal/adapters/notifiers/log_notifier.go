@@ -0,0 +1,37 @@
+// package notifiers รวม adapter สำหรับส่งการแจ้งเตือนออกไปนอกระบบ
+package notifiers
+
+import (
+	"context"
+	"log"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/notifiers"
+)
+
+type logNotifier struct{}
+
+// NewLogNotifier สร้าง notifier ที่เขียนการแจ้งเตือนลง log ของแอปพลิเคชัน
+func NewLogNotifier() notifiers.LowStockNotifier {
+	return &logNotifier{}
+}
+
+func (n *logNotifier) NotifyLowStock(ctx context.Context, products []*entities.LowStockProduct) error {
+	for _, product := range products {
+		log.Printf("[low-stock] %s (%s): stock %d, threshold %d\n", product.Name, product.ProductID, product.Stock, product.Threshold)
+	}
+	return nil
+}
+
+// NewLogWishlistNotifier สร้าง notifier ที่เขียนการแจ้งเตือนรายการที่อยากได้ลง log ของแอปพลิเคชัน
+func NewLogWishlistNotifier() notifiers.WishlistNotifier {
+	return &logNotifier{}
+}
+
+func (n *logNotifier) NotifyWishlist(ctx context.Context, notifications []*entities.WishlistNotification) error {
+	for _, notification := range notifications {
+		log.Printf("[wishlist] %s user=%s product=%s (%s): price %s, stock %d\n", notification.Kind, notification.UserID,
+			notification.ProductName, notification.ProductID, notification.Price, notification.Stock)
+	}
+	return nil
+}
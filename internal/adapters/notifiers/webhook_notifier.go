@@ -0,0 +1,88 @@
+package notifiers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/notifiers"
+)
+
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier สร้าง notifier ที่ POST รายการสินค้าสต็อกต่ำเป็น JSON ไปยัง url ที่กำหนด
+func NewWebhookNotifier(url string) notifiers.LowStockNotifier {
+	return &webhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type lowStockWebhookPayload struct {
+	Event    string                      `json:"event"`
+	Products []*entities.LowStockProduct `json:"products"`
+	SentAt   time.Time                   `json:"sent_at"`
+}
+
+func (n *webhookNotifier) NotifyLowStock(ctx context.Context, products []*entities.LowStockProduct) error {
+	return n.post(ctx, lowStockWebhookPayload{
+		Event:    "inventory.low_stock",
+		Products: products,
+		SentAt:   time.Now(),
+	})
+}
+
+// NewWebhookWishlistNotifier สร้าง notifier ที่ POST การแจ้งเตือนรายการที่อยากได้เป็น JSON ไปยัง url ที่กำหนด
+// ระบบภายนอก (เช่นบริการส่งอีเมลหรือ push) เป็นผู้ส่งต่อให้ผู้ใช้
+func NewWebhookWishlistNotifier(url string) notifiers.WishlistNotifier {
+	return &webhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type wishlistWebhookPayload struct {
+	Event         string                           `json:"event"`
+	Notifications []*entities.WishlistNotification `json:"notifications"`
+	SentAt        time.Time                        `json:"sent_at"`
+}
+
+func (n *webhookNotifier) NotifyWishlist(ctx context.Context, notifications []*entities.WishlistNotification) error {
+	return n.post(ctx, wishlistWebhookPayload{
+		Event:         "wishlist.notification",
+		Notifications: notifications,
+		SentAt:        time.Now(),
+	})
+}
+
+// post ส่ง payload เป็น JSON ไปยัง url ของ webhook status ที่ไม่ใช่ 2xx ถือว่าส่งไม่สำเร็จ
+func (n *webhookNotifier) post(ctx context.Context, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status %d", n.url, resp.StatusCode)
+	}
+	return nil
+}
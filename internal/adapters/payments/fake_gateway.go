@@ -0,0 +1,109 @@
+// package payments รวม adapter ของผู้ให้บริการรับชำระเงินที่ implement gateways.PaymentGateway
+package payments
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+)
+
+const fakeRefPrefix = "fake_"
+
+// เศษหน่วยย่อยของยอดเงินที่ใช้กำหนดผลลัพธ์ของ fake gateway (คล้ายบัตรทดสอบของผู้ให้บริการจริง)
+// เช่น ยอด 100.51 จะชำระไม่สำเร็จเสมอ และ 100.52 จะค้างเป็น pending เสมอ
+const (
+	fakeFailSatang    = 51
+	fakePendingSatang = 52
+)
+
+type fakeGateway struct{}
+
+// NewFakeGateway สร้าง gateway จำลองสำหรับการทดสอบและการพัฒนาในเครื่อง
+// ไม่เก็บ state ใดๆ ผลลัพธ์ขึ้นกับยอดเงินเท่านั้น จึงให้ผลเหมือนเดิมทุกครั้ง
+func NewFakeGateway() gateways.PaymentGateway {
+	return &fakeGateway{}
+}
+
+func (g *fakeGateway) CreateIntent(ctx context.Context, req *entities.PaymentIntentRequest) (*entities.PaymentIntent, error) {
+	if !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("invalid amount %s", req.Amount)
+	}
+
+	// เก็บยอดเงินและสกุลเงินไว้ใน providerRef เพื่อให้ Verify คำนวณผลได้โดยไม่ต้องมี state
+	return &entities.PaymentIntent{
+		ProviderRef: fmt.Sprintf("%s%s_%s_%d", fakeRefPrefix, req.Reference, req.Amount.Currency, req.Amount.Amount),
+		Status:      entities.GatewayStatusPending,
+	}, nil
+}
+
+func (g *fakeGateway) Verify(ctx context.Context, providerRef string) (*entities.PaymentResult, error) {
+	amount, err := parseFakeRef(providerRef)
+	if err != nil {
+		return nil, err
+	}
+
+	status := entities.GatewayStatusAuthorized
+	switch amount.Amount % 100 {
+	case fakeFailSatang:
+		status = entities.GatewayStatusFailed
+	case fakePendingSatang:
+		status = entities.GatewayStatusPending
+	}
+
+	return &entities.PaymentResult{
+		ProviderRef: providerRef,
+		Status:      status,
+		Amount:      amount,
+	}, nil
+}
+
+func (g *fakeGateway) Capture(ctx context.Context, providerRef string, amount entities.Money) (*entities.PaymentResult, error) {
+	result, err := g.Verify(ctx, providerRef)
+	if err != nil {
+		return nil, err
+	}
+	if result.Status != entities.GatewayStatusAuthorized {
+		return nil, fmt.Errorf("cannot capture payment in status %s", result.Status)
+	}
+	if amount.Currency != result.Amount.Currency || amount.Amount > result.Amount.Amount {
+		return nil, fmt.Errorf("capture amount %s exceeds authorized amount %s", amount, result.Amount)
+	}
+
+	result.Status = entities.GatewayStatusCaptured
+	result.Amount = amount
+	return result, nil
+}
+
+func (g *fakeGateway) Refund(ctx context.Context, providerRef string, amount entities.Money) (*entities.PaymentResult, error) {
+	captured, err := parseFakeRef(providerRef)
+	if err != nil {
+		return nil, err
+	}
+	if !amount.IsPositive() || amount.Currency != captured.Currency || amount.Amount > captured.Amount {
+		return nil, fmt.Errorf("invalid refund amount %s", amount)
+	}
+
+	return &entities.PaymentResult{
+		ProviderRef: providerRef,
+		Status:      entities.GatewayStatusRefunded,
+		Amount:      amount,
+	}, nil
+}
+
+// parseFakeRef อ่านยอดเงินที่ฝังไว้ใน providerRef ของ fake gateway (รูปแบบ fake_<reference>_<currency>_<amount>)
+func parseFakeRef(providerRef string) (entities.Money, error) {
+	parts := strings.Split(strings.TrimPrefix(providerRef, fakeRefPrefix), "_")
+	if !strings.HasPrefix(providerRef, fakeRefPrefix) || len(parts) < 3 {
+		return entities.Money{}, fmt.Errorf("unknown provider reference %q", providerRef)
+	}
+
+	amount, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
+	if err != nil {
+		return entities.Money{}, fmt.Errorf("unknown provider reference %q", providerRef)
+	}
+	return entities.NewMoney(amount, parts[len(parts)-2]), nil
+}
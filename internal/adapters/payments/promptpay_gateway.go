@@ -0,0 +1,151 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+)
+
+// ค่าคงที่ตามมาตรฐาน EMVCo QR Code ที่ใช้กับ Thai QR Payment (PromptPay)
+const (
+	emvPayloadFormat       = "00"
+	emvPointOfInitiation   = "01"
+	emvMerchantPromptPay   = "29"
+	emvCurrency            = "53"
+	emvAmount              = "54"
+	emvCountryCode         = "58"
+	emvCRC                 = "63"
+	promptPayAID           = "A000000677010111"
+	promptPayTagAID        = "00"
+	promptPayTagPhone      = "01"
+	promptPayTagNationalID = "02"
+	promptPayTagEWallet    = "03"
+	currencyTHB            = "764"
+	promptPayRefPrefix     = "promptpay_"
+	promptPayIntentTTL     = 15 * time.Minute
+)
+
+var nonDigits = regexp.MustCompile(`[^0-9]`)
+
+// ErrPromptPayManualRefund PromptPay ไม่มี API สำหรับคืนเงิน ต้องโอนคืนด้วยมือ
+var ErrPromptPayManualRefund = errors.New("PromptPay ไม่รองรับการคืนเงินอัตโนมัติ ต้องโอนคืนด้วยมือ")
+
+type promptPayGateway struct {
+	target string
+}
+
+// NewPromptPayGateway สร้าง gateway สำหรับ PromptPay QR
+// promptPayID คือเบอร์โทรศัพท์ เลขประจำตัวประชาชน/เลขผู้เสียภาษี หรือเลข e-Wallet ของร้านค้า
+func NewPromptPayGateway(promptPayID string) gateways.PaymentGateway {
+	return &promptPayGateway{target: promptPayID}
+}
+
+// CreateIntent สร้าง EMVCo payload สำหรับแสดงเป็น QR ให้ผู้ซื้อสแกนจ่าย
+func (g *promptPayGateway) CreateIntent(ctx context.Context, req *entities.PaymentIntentRequest) (*entities.PaymentIntent, error) {
+	payload, err := PromptPayPayload(g.target, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(promptPayIntentTTL)
+	return &entities.PaymentIntent{
+		ProviderRef: promptPayRefPrefix + req.Reference,
+		Status:      entities.GatewayStatusPending,
+		Payload:     payload,
+		ExpiresAt:   &expiresAt,
+	}, nil
+}
+
+// Verify PromptPay ไม่มี API ให้ตรวจสอบสถานะ การยืนยันการชำระเงินจะมาจากธนาคารผ่าน webhook
+// จึงคืนสถานะ pending เสมอ
+func (g *promptPayGateway) Verify(ctx context.Context, providerRef string) (*entities.PaymentResult, error) {
+	return &entities.PaymentResult{
+		ProviderRef: providerRef,
+		Status:      entities.GatewayStatusPending,
+	}, nil
+}
+
+// Capture การโอนผ่าน PromptPay เข้าบัญชีทันทีที่จ่าย จึงไม่มีขั้นตอน capture แยก
+func (g *promptPayGateway) Capture(ctx context.Context, providerRef string, amount entities.Money) (*entities.PaymentResult, error) {
+	return &entities.PaymentResult{
+		ProviderRef: providerRef,
+		Status:      entities.GatewayStatusCaptured,
+		Amount:      amount,
+	}, nil
+}
+
+func (g *promptPayGateway) Refund(ctx context.Context, providerRef string, amount entities.Money) (*entities.PaymentResult, error) {
+	return nil, ErrPromptPayManualRefund
+}
+
+// PromptPayPayload สร้าง EMVCo payload ของ PromptPay
+// ถ้า amount มากกว่า 0 จะเป็น QR แบบใช้ครั้งเดียว (dynamic) พร้อมยอดเงิน
+// PromptPay รับเฉพาะเงินบาท
+func PromptPayPayload(target string, amount entities.Money) (string, error) {
+	if !amount.IsZero() && amount.Currency != "THB" {
+		return "", fmt.Errorf("PromptPay accepts THB only (got %s)", amount.Currency)
+	}
+
+	id := nonDigits.ReplaceAllString(target, "")
+
+	var account string
+	switch {
+	case len(id) >= 15:
+		account = emvField(promptPayTagEWallet, id)
+	case len(id) >= 13:
+		account = emvField(promptPayTagNationalID, id)
+	case len(id) == 10 && strings.HasPrefix(id, "0"):
+		// เบอร์โทรศัพท์: แทน 0 นำหน้าด้วยรหัสประเทศ 66 แล้วเติม 0 ด้านหน้าให้ครบ 13 หลัก
+		account = emvField(promptPayTagPhone, fmt.Sprintf("%013s", "66"+id[1:]))
+	default:
+		return "", fmt.Errorf("invalid PromptPay ID %q", target)
+	}
+
+	initiation := "11"
+	if amount.IsPositive() {
+		initiation = "12"
+	}
+
+	var b strings.Builder
+	b.WriteString(emvField(emvPayloadFormat, "01"))
+	b.WriteString(emvField(emvPointOfInitiation, initiation))
+	b.WriteString(emvField(emvMerchantPromptPay, emvField(promptPayTagAID, promptPayAID)+account))
+	b.WriteString(emvField(emvCountryCode, "TH"))
+	b.WriteString(emvField(emvCurrency, currencyTHB))
+	if amount.IsPositive() {
+		b.WriteString(emvField(emvAmount, amount.Decimal()))
+	}
+
+	// CRC คำนวณรวม tag และความยาวของตัวมันเองด้วย
+	b.WriteString(emvCRC + "04")
+	b.WriteString(fmt.Sprintf("%04X", crc16CCITT([]byte(b.String()))))
+
+	return b.String(), nil
+}
+
+// emvField ประกอบ field แบบ ID + ความยาว 2 หลัก + ค่า
+func emvField(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// crc16CCITT คำนวณ CRC-16/CCITT-FALSE (poly 0x1021, init 0xFFFF) ตามที่ EMVCo กำหนด
+func crc16CCITT(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
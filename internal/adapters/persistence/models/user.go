@@ -8,6 +8,9 @@
 // - ใช้ FromEntity เพื่อแปลง Entity → Model ก่อนบันทึกลง DB
 // - ใช้ ToEntity เพื่อแปลง Model → Entity ก่อนนำไปใช้ใน business logic
 // การแยกแบบนี้ช่วยให้โค้ดมีความยืดหยุ่นและแยกความรับผิดชอบตามหลัก Clean Architecture
+//
+// จำนวนเงินทุกคอลัมน์เก็บเป็นจำนวนเต็มในหน่วยย่อยของสกุลเงิน (เช่น สตางค์) ส่วนสกุลเงินเก็บในคอลัมน์ currency
+// ของเอกสารนั้น (OrderItem ใช้สกุลเงินของ Order, Refund ใช้สกุลเงินของ Transaction)
 package models
 
 import (
@@ -62,28 +65,52 @@ type User struct {
 	ResetTokenExpiry time.Time `json:"-"`
 }
 
+// TaxRule สำหรับเก็บกฎภาษีมูลค่าเพิ่ม (อัตราเป็น basis point) ที่ผูกกับสินค้าหรือหมวดหมู่
+// คำสั่งซื้อเก็บอัตราที่ใช้ไว้ในแต่ละ OrderItem การแก้กฎภายหลังจึงไม่กระทบคำสั่งซื้อเก่า
+type TaxRule struct {
+	BaseModel
+	Name      string `gorm:"type:varchar(100)" json:"name"`
+	Rate      int    `gorm:"type:int" json:"rate"`
+	Inclusive bool   `gorm:"default:false" json:"inclusive"`
+	Exempt    bool   `gorm:"default:false" json:"exempt"`
+}
+
 // Category สำหรับเก็บข้อมูลหมวดหมู่สินค้า
 type Category struct {
 	BaseModel
-	Name        string    `gorm:"type:varchar(100);unique_index" json:"name" validate:"required"`
-	Description string    `gorm:"type:text" json:"description"`
-	Image       string    `gorm:"type:varchar(255)" json:"image"`
-	Products    []Product `gorm:"foreignKey:CategoryID" json:"products,omitempty"`
+	ParentID         *uuid.UUID `gorm:"type:uuid;index" json:"parent_id"`
+	Parent           *Category  `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
+	Name             string     `gorm:"type:varchar(100);unique_index" json:"name" validate:"required"`
+	Slug             string     `gorm:"type:varchar(120);not null;uniqueIndex:idx_categories_slug,where:deleted_at IS NULL" json:"slug"`
+	Description      string     `gorm:"type:text" json:"description"`
+	Image            string     `gorm:"type:varchar(255)" json:"image"`
+	SortOrder        int        `gorm:"not null;default:0" json:"sort_order"`
+	ReorderThreshold *int       `gorm:"type:int" json:"reorder_threshold"`
+	TaxRuleID        *uuid.UUID `gorm:"type:uuid;index" json:"tax_rule_id"`
+	TaxRule          *TaxRule   `gorm:"foreignKey:TaxRuleID" json:"tax_rule,omitempty"`
+	Products         []Product  `gorm:"foreignKey:CategoryID" json:"products,omitempty"`
 }
 
 // Product สำหรับเก็บข้อมูลสินค้า
 type Product struct {
 	BaseModel
-	Name        string         `gorm:"type:varchar(100)" json:"name" validate:"required"`
-	Description string         `gorm:"type:text" json:"description"`
-	Price       float64        `gorm:"type:decimal(10,2)" json:"price" validate:"required,min=0"`
-	Stock       int            `gorm:"type:int" json:"stock" validate:"min=0"`
-	Image       string         `gorm:"type:varchar(255)" json:"image"`
-	Images      []ProductImage `gorm:"foreignKey:ProductID" json:"images,omitempty"`
-	CategoryID  uuid.UUID      `json:"category_id" validate:"required"`
-	Category    Category       `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
-	OrderItems  []OrderItem    `gorm:"foreignKey:ProductID" json:"order_items,omitempty"`
-	CartItems   []CartItem     `gorm:"foreignKey:ProductID" json:"cart_items,omitempty"`
+	Name             string           `gorm:"type:varchar(100)" json:"name" validate:"required"`
+	Description      string           `gorm:"type:text" json:"description"`
+	Price            int64            `gorm:"type:bigint" json:"price" validate:"required,min=0"`
+	Currency         string           `gorm:"type:varchar(3);default:'THB'" json:"currency"`
+	Stock            int              `gorm:"type:int" json:"stock" validate:"min=0"`
+	Weight           int              `gorm:"type:int;default:0" json:"weight"`
+	ReorderThreshold *int             `gorm:"type:int" json:"reorder_threshold"`
+	TaxRuleID        *uuid.UUID       `gorm:"type:uuid;index" json:"tax_rule_id"`
+	TaxRule          *TaxRule         `gorm:"foreignKey:TaxRuleID" json:"tax_rule,omitempty"`
+	Image            string           `gorm:"type:varchar(255)" json:"image"`
+	Images           []ProductImage   `gorm:"foreignKey:ProductID" json:"images,omitempty"`
+	Options          []ProductOption  `gorm:"foreignKey:ProductID" json:"options,omitempty"`
+	Variants         []ProductVariant `gorm:"foreignKey:ProductID" json:"variants,omitempty"`
+	CategoryID       uuid.UUID        `json:"category_id" validate:"required"`
+	Category         Category         `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	OrderItems       []OrderItem      `gorm:"foreignKey:ProductID" json:"order_items,omitempty"`
+	CartItems        []CartItem       `gorm:"foreignKey:ProductID" json:"cart_items,omitempty"`
 }
 
 // ProductImage สำหรับเก็บรูปภาพของสินค้า
@@ -93,63 +120,383 @@ type ProductImage struct {
 	ImageURL  string    `gorm:"type:varchar(255)" json:"image_url" validate:"required"`
 }
 
+// ProductOption สำหรับเก็บประเภทตัวเลือกของสินค้า เช่น ขนาด สี
+type ProductOption struct {
+	BaseModel
+	ProductID uuid.UUID            `gorm:"type:uuid;index" json:"product_id"`
+	Name      string               `gorm:"type:varchar(50)" json:"name"`
+	Position  int                  `gorm:"type:int;default:0" json:"position"`
+	Values    []ProductOptionValue `gorm:"foreignKey:OptionID" json:"values,omitempty"`
+}
+
+// ProductOptionValue สำหรับเก็บค่าของตัวเลือก เช่น S, M, L
+type ProductOptionValue struct {
+	BaseModel
+	OptionID uuid.UUID      `gorm:"type:uuid;index" json:"option_id"`
+	Option   *ProductOption `gorm:"foreignKey:OptionID" json:"option,omitempty"`
+	Value    string         `gorm:"type:varchar(50)" json:"value"`
+	Position int            `gorm:"type:int;default:0" json:"position"`
+}
+
+// ProductVariant สำหรับเก็บสินค้าย่อยตามชุดค่าตัวเลือก แต่ละตัวมี SKU และสต็อกของตัวเอง
+// Price เป็น nil เมื่อใช้ราคาของสินค้าหลัก สต็อกของสินค้าหลักคือผลรวมสต็อกของทุก variant
+// SKU ไม่ซ้ำกันเฉพาะ variant ที่ยังไม่ถูกลบ จึงนำ SKU ของ variant ที่ลบแล้วกลับมาใช้ได้
+type ProductVariant struct {
+	BaseModel
+	ProductID    uuid.UUID            `gorm:"type:uuid;index" json:"product_id"`
+	Product      Product              `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	SKU          string               `gorm:"type:varchar(64);uniqueIndex:idx_product_variants_sku,where:deleted_at IS NULL" json:"sku"`
+	Price        *int64               `gorm:"type:bigint" json:"price"`
+	Stock        int                  `gorm:"type:int;default:0" json:"stock"`
+	Image        string               `gorm:"type:varchar(255)" json:"image"`
+	OptionValues []ProductOptionValue `gorm:"many2many:product_variant_option_values" json:"option_values,omitempty"`
+}
+
+// InventoryMovement สำหรับเก็บประวัติการเปลี่ยนแปลงสต็อกสินค้า (ledger)
+// ReferenceID ชี้ไปยังเอกสารที่ทำให้สต็อกเปลี่ยน เช่น คำสั่งซื้อ
+type InventoryMovement struct {
+	BaseModel
+	ProductID   uuid.UUID  `gorm:"type:uuid;index" json:"product_id"`
+	Product     Product    `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	VariantID   *uuid.UUID `gorm:"type:uuid;index" json:"variant_id"`
+	Delta       int        `gorm:"type:int" json:"delta"`
+	Reason      string     `gorm:"type:varchar(50)" json:"reason"`
+	ReferenceID *uuid.UUID `gorm:"type:uuid;index" json:"reference_id"`
+	ActorID     *uuid.UUID `gorm:"type:uuid" json:"actor_id"`
+	Actor       *User      `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	Note        string     `gorm:"type:text" json:"note"`
+}
+
 // Cart สำหรับเก็บข้อมูลตะกร้าสินค้า
 type Cart struct {
 	BaseModel
 	UserID     uuid.UUID  `json:"user_id"`
 	User       User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
 	CartItems  []CartItem `gorm:"foreignKey:CartID" json:"cart_items,omitempty"`
-	TotalPrice float64    `gorm:"type:decimal(10,2)" json:"total_price"`
+	TotalPrice int64      `gorm:"type:bigint" json:"total_price"`
+	CouponCode string     `gorm:"type:varchar(50)" json:"coupon_code"`
 }
 
 // CartItem สำหรับเก็บรายการสินค้าในตะกร้า
 type CartItem struct {
 	BaseModel
-	CartID    uuid.UUID `json:"cart_id"`
-	Cart      Cart      `gorm:"foreignKey:CartID" json:"cart,omitempty"`
-	ProductID uuid.UUID `json:"product_id"`
-	Product   Product   `gorm:"foreignKey:ProductID" json:"product,omitempty"`
-	Quantity  int       `gorm:"type:int" json:"quantity" validate:"required,min=1"`
-	Price     float64   `gorm:"type:decimal(10,2)" json:"price"`
+	CartID    uuid.UUID       `json:"cart_id"`
+	Cart      Cart            `gorm:"foreignKey:CartID" json:"cart,omitempty"`
+	ProductID uuid.UUID       `json:"product_id"`
+	Product   Product         `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	VariantID *uuid.UUID      `gorm:"type:uuid;index" json:"variant_id"`
+	Variant   *ProductVariant `gorm:"foreignKey:VariantID" json:"variant,omitempty"`
+	Quantity  int             `gorm:"type:int" json:"quantity" validate:"required,min=1"`
+	Price     int64           `gorm:"type:bigint" json:"price"`
+	Currency  string          `gorm:"type:varchar(3);default:'THB'" json:"currency"`
 }
 
 // Order สำหรับเก็บข้อมูลการสั่งซื้อ
 type Order struct {
 	BaseModel
-	UserID          uuid.UUID     `json:"user_id"`
-	User            User          `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	OrderItems      []OrderItem   `gorm:"foreignKey:OrderID" json:"order_items,omitempty"`
-	TotalPrice      float64       `gorm:"type:decimal(10,2)" json:"total_price"`
-	Status          string        `gorm:"type:varchar(50);default:'pending'" json:"status"`
-	PaymentMethod   string        `gorm:"type:varchar(50)" json:"payment_method"`
-	PaymentStatus   string        `gorm:"type:varchar(50);default:'pending'" json:"payment_status"`
-	ShippingMethod  string        `gorm:"type:varchar(50)" json:"shipping_method"`
-	ShippingStatus  string        `gorm:"type:varchar(50);default:'pending'" json:"shipping_status"`
-	ShippingAddress string        `gorm:"type:text" json:"shipping_address"`
-	TrackingNumber  string        `gorm:"type:varchar(100)" json:"tracking_number"`
-	Notes           string        `gorm:"type:text" json:"notes"`
-	Transactions    []Transaction `gorm:"foreignKey:OrderID" json:"transactions,omitempty"`
+	UserID                uuid.UUID          `json:"user_id"`
+	User                  User               `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	OrderItems            []OrderItem        `gorm:"foreignKey:OrderID" json:"order_items,omitempty"`
+	Subtotal              int64              `gorm:"type:bigint;default:0" json:"subtotal"`
+	DiscountTotal         int64              `gorm:"type:bigint;default:0" json:"discount_total"`
+	Discounts             []OrderDiscount    `gorm:"foreignKey:OrderID" json:"discounts,omitempty"`
+	TaxTotal              int64              `gorm:"type:bigint;default:0" json:"tax_total"`
+	ShippingFee           int64              `gorm:"type:bigint;default:0" json:"shipping_fee"`
+	TotalPrice            int64              `gorm:"type:bigint" json:"total_price"`
+	Currency              string             `gorm:"type:varchar(3);default:'THB'" json:"currency"`
+	BaseCurrency          string             `gorm:"type:varchar(3);default:'THB'" json:"base_currency"`
+	ExchangeRate          float64            `gorm:"type:decimal(18,8);default:1" json:"exchange_rate"`
+	Status                string             `gorm:"type:varchar(50);default:'pending'" json:"status"`
+	PaymentMethod         string             `gorm:"type:varchar(50)" json:"payment_method"`
+	PaymentStatus         string             `gorm:"type:varchar(50);default:'pending'" json:"payment_status"`
+	ShippingMethod        string             `gorm:"type:varchar(50)" json:"shipping_method"`
+	ShippingMethodID      *uuid.UUID         `gorm:"type:uuid;index" json:"shipping_method_id"`
+	ShippingStatus        string             `gorm:"type:varchar(50);default:'pending'" json:"shipping_status"`
+	ShippingAddress       string             `gorm:"type:text" json:"shipping_address"`
+	ShippingAddressDetail PostalAddress      `gorm:"embedded;embeddedPrefix:shipping_" json:"shipping_address_detail"`
+	TrackingNumber        string             `gorm:"type:varchar(100)" json:"tracking_number"`
+	Shipments             []Shipment         `gorm:"foreignKey:OrderID" json:"shipments,omitempty"`
+	Notes                 string             `gorm:"type:text" json:"notes"`
+	Transactions          []Transaction      `gorm:"foreignKey:OrderID" json:"transactions,omitempty"`
+	StatusEvents          []OrderStatusEvent `gorm:"foreignKey:OrderID" json:"status_events,omitempty"`
+}
+
+// OrderStatusEvent สำหรับเก็บประวัติการเปลี่ยนสถานะของคำสั่งซื้อ
+// Axis คือชื่อคอลัมน์สถานะที่เปลี่ยน (status, payment_status, shipping_status)
+type OrderStatusEvent struct {
+	BaseModel
+	OrderID    uuid.UUID  `gorm:"type:uuid;index" json:"order_id"`
+	Axis       string     `gorm:"type:varchar(50)" json:"axis"`
+	FromStatus string     `gorm:"type:varchar(50)" json:"from_status"`
+	ToStatus   string     `gorm:"type:varchar(50)" json:"to_status"`
+	ActorID    *uuid.UUID `gorm:"type:uuid" json:"actor_id"`
+	Actor      *User      `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	Note       string     `gorm:"type:text" json:"note"`
 }
 
 // OrderItem สำหรับเก็บรายการสินค้าในคำสั่งซื้อ
 type OrderItem struct {
 	BaseModel
-	OrderID   uuid.UUID `json:"order_id"`
-	Order     Order     `gorm:"foreignKey:OrderID" json:"order,omitempty"`
-	ProductID uuid.UUID `json:"product_id"`
-	Product   Product   `gorm:"foreignKey:ProductID" json:"product,omitempty"`
-	Quantity  int       `gorm:"type:int" json:"quantity" validate:"required,min=1"`
-	Price     float64   `gorm:"type:decimal(10,2)" json:"price"`
+	OrderID      uuid.UUID  `json:"order_id"`
+	Order        Order      `gorm:"foreignKey:OrderID" json:"order,omitempty"`
+	ProductID    uuid.UUID  `json:"product_id"`
+	Product      Product    `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	VariantID    *uuid.UUID `gorm:"type:uuid;index" json:"variant_id"`
+	SKU          string     `gorm:"type:varchar(64)" json:"sku"`
+	VariantName  string     `gorm:"type:varchar(255)" json:"variant_name"`
+	Quantity     int        `gorm:"type:int" json:"quantity" validate:"required,min=1"`
+	Price        int64      `gorm:"type:bigint" json:"price"`
+	Discount     int64      `gorm:"type:bigint;default:0" json:"discount"`
+	Subtotal     int64      `gorm:"type:bigint;default:0" json:"subtotal"`
+	Tax          int64      `gorm:"type:bigint;default:0" json:"tax"`
+	Total        int64      `gorm:"type:bigint;default:0" json:"total"`
+	TaxRate      int        `gorm:"type:int;default:0" json:"tax_rate"`
+	TaxInclusive bool       `gorm:"default:false" json:"tax_inclusive"`
+	TaxExempt    bool       `gorm:"default:false" json:"tax_exempt"`
 }
 
 // Transaction สำหรับเก็บข้อมูลธุรกรรมการชำระเงิน
 type Transaction struct {
 	BaseModel
-	OrderID       uuid.UUID `json:"order_id"`
-	Order         Order     `gorm:"foreignKey:OrderID" json:"order,omitempty"`
-	Amount        float64   `gorm:"type:decimal(10,2)" json:"amount"`
-	PaymentMethod string    `gorm:"type:varchar(50)" json:"payment_method"`
-	Status        string    `gorm:"type:varchar(50);default:'pending'" json:"status"`
-	TransactionID string    `gorm:"type:varchar(100)" json:"transaction_id"`
-	PaymentData   string    `gorm:"type:text" json:"payment_data"`
+	OrderID        uuid.UUID `json:"order_id"`
+	Order          Order     `gorm:"foreignKey:OrderID" json:"order,omitempty"`
+	Amount         int64     `gorm:"type:bigint" json:"amount"`
+	Currency       string    `gorm:"type:varchar(3);default:'THB'" json:"currency"`
+	PaymentMethod  string    `gorm:"type:varchar(50)" json:"payment_method"`
+	Status         string    `gorm:"type:varchar(50);default:'pending'" json:"status"`
+	TransactionID  string    `gorm:"type:varchar(100)" json:"transaction_id"`
+	ProviderRef    string    `gorm:"type:varchar(100);index" json:"provider_ref"`
+	PaymentPayload string    `gorm:"type:text" json:"payment_payload"`
+	PaymentData    string    `gorm:"type:text" json:"payment_data"`
+}
+
+// Refund สำหรับเก็บการคืนเงินของธุรกรรม
+type Refund struct {
+	BaseModel
+	TransactionID uuid.UUID    `gorm:"type:uuid;index" json:"transaction_id"`
+	Transaction   Transaction  `gorm:"foreignKey:TransactionID" json:"transaction,omitempty"`
+	OrderID       uuid.UUID    `gorm:"type:uuid;index" json:"order_id"`
+	Amount        int64        `gorm:"type:bigint" json:"amount"`
+	Reason        string       `gorm:"type:text" json:"reason"`
+	Restock       bool         `gorm:"default:false" json:"restock"`
+	Status        string       `gorm:"type:varchar(50);default:'pending'" json:"status"`
+	ProviderRef   string       `gorm:"type:varchar(100)" json:"provider_ref"`
+	FailureReason string       `gorm:"type:text" json:"failure_reason"`
+	ActorID       *uuid.UUID   `gorm:"type:uuid" json:"actor_id"`
+	Items         []RefundItem `gorm:"foreignKey:RefundID" json:"items,omitempty"`
+}
+
+// RefundItem สำหรับเก็บจำนวนสินค้าของแต่ละ OrderItem ที่คืนเงิน
+type RefundItem struct {
+	BaseModel
+	RefundID    uuid.UUID  `gorm:"type:uuid;index" json:"refund_id"`
+	OrderItemID uuid.UUID  `gorm:"type:uuid;index" json:"order_item_id"`
+	ProductID   uuid.UUID  `gorm:"type:uuid" json:"product_id"`
+	VariantID   *uuid.UUID `gorm:"type:uuid" json:"variant_id"`
+	Quantity    int        `gorm:"type:int" json:"quantity"`
+	Amount      int64      `gorm:"type:bigint" json:"amount"`
+}
+
+// PaymentEvent สำหรับเก็บ webhook event ที่ได้รับจาก payment gateway
+// unique index บน (provider, event_id) ทำให้ event ที่ถูกส่งซ้ำไม่ถูกประมวลผลอีก
+type PaymentEvent struct {
+	BaseModel
+	Provider      string    `gorm:"type:varchar(50);uniqueIndex:idx_payment_events_provider_event_id" json:"provider"`
+	EventID       string    `gorm:"type:varchar(100);uniqueIndex:idx_payment_events_provider_event_id" json:"event_id"`
+	TransactionID uuid.UUID `gorm:"type:uuid;index" json:"transaction_id"`
+	Status        string    `gorm:"type:varchar(50)" json:"status"`
+	Applied       bool      `gorm:"default:false" json:"applied"`
+	Payload       string    `gorm:"type:text" json:"payload"`
+}
+
+// ExchangeRate สำหรับเก็บอัตราแลกเปลี่ยนจากสกุลเงินหลักของร้านเป็นสกุลเงินอื่น
+// คำสั่งซื้อเก็บอัตราที่ใช้ตอน checkout ไว้เอง การแก้อัตราในตารางนี้จึงไม่กระทบคำสั่งซื้อเก่า
+type ExchangeRate struct {
+	Currency  string    `gorm:"type:varchar(3);primaryKey" json:"currency"`
+	Rate      float64   `gorm:"type:decimal(18,8)" json:"rate"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Promotion สำหรับเก็บโปรโมชันที่ใช้ผ่านรหัสคูปอง จำนวนเงินเป็นหน่วยย่อยของ Currency
+// ขอบเขตสินค้าและหมวดหมู่เก็บในตาราง promotion_products และ promotion_categories
+type Promotion struct {
+	BaseModel
+	Code         string     `gorm:"type:varchar(50);uniqueIndex" json:"code"`
+	Name         string     `gorm:"type:varchar(100)" json:"name"`
+	Type         string     `gorm:"type:varchar(20)" json:"type"`
+	Rate         int        `gorm:"type:int;default:0" json:"rate"`
+	Amount       int64      `gorm:"type:bigint;default:0" json:"amount"`
+	MinSpend     int64      `gorm:"type:bigint;default:0" json:"min_spend"`
+	Currency     string     `gorm:"type:varchar(3);default:'THB'" json:"currency"`
+	UsageLimit   *int       `gorm:"type:int" json:"usage_limit"`
+	PerUserLimit *int       `gorm:"type:int" json:"per_user_limit"`
+	StartsAt     *time.Time `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	Active       bool       `json:"active"`
+	Products     []Product  `gorm:"many2many:promotion_products;" json:"products,omitempty"`
+	Categories   []Category `gorm:"many2many:promotion_categories;" json:"categories,omitempty"`
+}
+
+// PromotionRedemption สำหรับเก็บการใช้คูปองหนึ่งครั้งต่อหนึ่งคำสั่งซื้อ ใช้นับจำนวนครั้งที่ใช้ไปแล้ว
+// จะถูกลบเมื่อคำสั่งซื้อถูกยกเลิก เพื่อคืนสิทธิ์การใช้คูปอง
+type PromotionRedemption struct {
+	BaseModel
+	PromotionID uuid.UUID `gorm:"type:uuid;index" json:"promotion_id"`
+	UserID      uuid.UUID `gorm:"type:uuid;index" json:"user_id"`
+	OrderID     uuid.UUID `gorm:"type:uuid;index" json:"order_id"`
+}
+
+// OrderDiscount สำหรับเก็บส่วนลดแต่ละบรรทัดของคำสั่งซื้อ (ข้อมูลคูปองถูกคัดลอกไว้ ไม่ขึ้นกับโปรโมชันภายหลัง)
+type OrderDiscount struct {
+	BaseModel
+	OrderID     uuid.UUID  `gorm:"type:uuid;index" json:"order_id"`
+	PromotionID *uuid.UUID `gorm:"type:uuid" json:"promotion_id"`
+	Code        string     `gorm:"type:varchar(50)" json:"code"`
+	Type        string     `gorm:"type:varchar(20)" json:"type"`
+	Description string     `gorm:"type:varchar(255)" json:"description"`
+	Amount      int64      `gorm:"type:bigint" json:"amount"`
+}
+
+// ShippingMethod สำหรับเก็บวิธีจัดส่งและกฎการคิดค่าจัดส่ง (จำนวนเงินเป็นหน่วยย่อยของ Currency)
+type ShippingMethod struct {
+	BaseModel
+	Code          string               `gorm:"type:varchar(50);uniqueIndex" json:"code"`
+	Name          string               `gorm:"type:varchar(100)" json:"name"`
+	Description   string               `gorm:"type:text" json:"description"`
+	RateType      string               `gorm:"type:varchar(20)" json:"rate_type"`
+	Fee           int64                `gorm:"type:bigint;default:0" json:"fee"`
+	FeePerKg      int64                `gorm:"type:bigint;default:0" json:"fee_per_kg"`
+	FreeOver      int64                `gorm:"type:bigint;default:0" json:"free_over"`
+	Currency      string               `gorm:"type:varchar(3);default:'THB'" json:"currency"`
+	RegionsOnly   bool                 `json:"regions_only"`
+	EstimatedDays int                  `gorm:"type:int;default:0" json:"estimated_days"`
+	Active        bool                 `json:"active"`
+	Regions       []ShippingRegionRate `gorm:"foreignKey:ShippingMethodID" json:"regions,omitempty"`
+}
+
+// ShippingRegionRate สำหรับเก็บค่าจัดส่งพื้นฐานเฉพาะจังหวัดของวิธีจัดส่ง
+type ShippingRegionRate struct {
+	BaseModel
+	ShippingMethodID uuid.UUID `gorm:"type:uuid;index" json:"shipping_method_id"`
+	Province         string    `gorm:"type:varchar(100)" json:"province"`
+	Fee              int64     `gorm:"type:bigint" json:"fee"`
+}
+
+// PostalAddress สำหรับเก็บที่อยู่แบบแยกช่อง ใช้ทั้งในสมุดที่อยู่และสำเนาที่อยู่จัดส่งของคำสั่งซื้อ
+type PostalAddress struct {
+	RecipientName string `gorm:"type:varchar(100)" json:"recipient_name"`
+	Phone         string `gorm:"type:varchar(20)" json:"phone"`
+	Line1         string `gorm:"type:varchar(255)" json:"line1"`
+	Line2         string `gorm:"type:varchar(255)" json:"line2"`
+	Subdistrict   string `gorm:"type:varchar(100)" json:"subdistrict"`
+	District      string `gorm:"type:varchar(100)" json:"district"`
+	Province      string `gorm:"type:varchar(100)" json:"province"`
+	PostalCode    string `gorm:"type:varchar(10)" json:"postal_code"`
+	Country       string `gorm:"type:varchar(2)" json:"country"`
+}
+
+// Address สำหรับเก็บที่อยู่ในสมุดที่อยู่ของผู้ใช้
+type Address struct {
+	BaseModel
+	UserID        uuid.UUID     `gorm:"type:uuid;index" json:"user_id"`
+	Label         string        `gorm:"type:varchar(50)" json:"label"`
+	PostalAddress PostalAddress `gorm:"embedded" json:"postal_address"`
+	IsDefault     bool          `json:"is_default"`
+}
+
+// Shipment สำหรับเก็บพัสดุที่ส่งออกไปของคำสั่งซื้อ คำสั่งซื้อหนึ่งอาจแยกส่งหลายพัสดุ
+type Shipment struct {
+	BaseModel
+	OrderID        uuid.UUID            `gorm:"type:uuid;index" json:"order_id"`
+	Carrier        string               `gorm:"type:varchar(50)" json:"carrier"`
+	TrackingNumber string               `gorm:"type:varchar(100);index" json:"tracking_number"`
+	Status         string               `gorm:"type:varchar(50);default:'shipped';index" json:"status"`
+	ShippedAt      time.Time            `json:"shipped_at"`
+	DeliveredAt    *time.Time           `json:"delivered_at"`
+	Items          []ShipmentItem       `gorm:"foreignKey:ShipmentID" json:"items,omitempty"`
+	Checkpoints    []ShipmentCheckpoint `gorm:"foreignKey:ShipmentID" json:"checkpoints,omitempty"`
+}
+
+// ShipmentItem สำหรับเก็บจำนวนสินค้าของแต่ละ OrderItem ที่อยู่ในพัสดุ
+type ShipmentItem struct {
+	BaseModel
+	ShipmentID  uuid.UUID `gorm:"type:uuid;index" json:"shipment_id"`
+	OrderItemID uuid.UUID `gorm:"type:uuid;index" json:"order_item_id"`
+	ProductID   uuid.UUID `gorm:"type:uuid" json:"product_id"`
+	Quantity    int       `gorm:"type:int" json:"quantity"`
+}
+
+// ShipmentCheckpoint สำหรับเก็บจุดติดตามพัสดุจากผู้ให้บริการขนส่ง
+// EventID ของพัสดุเดียวกันจะถูกบันทึกได้เพียงครั้งเดียว
+type ShipmentCheckpoint struct {
+	BaseModel
+	ShipmentID  uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_shipment_checkpoints_shipment_event_id" json:"shipment_id"`
+	EventID     string    `gorm:"type:varchar(100);uniqueIndex:idx_shipment_checkpoints_shipment_event_id" json:"event_id"`
+	Status      string    `gorm:"type:varchar(50)" json:"status"`
+	Location    string    `gorm:"type:varchar(255)" json:"location"`
+	Description string    `gorm:"type:text" json:"description"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// Return สำหรับเก็บคำขอคืนสินค้า (RMA) ของคำสั่งซื้อ
+type Return struct {
+	BaseModel
+	OrderID  uuid.UUID  `gorm:"type:uuid;index" json:"order_id"`
+	UserID   uuid.UUID  `gorm:"type:uuid;index" json:"user_id"`
+	Status   string     `gorm:"type:varchar(50);default:'requested';index" json:"status"`
+	Reason   string     `gorm:"type:text" json:"reason"`
+	Note     string     `gorm:"type:text" json:"note"`
+	RefundID *uuid.UUID `gorm:"type:uuid" json:"refund_id"`
+	// RefundClaimedAt เวลาที่เริ่มคืนเงินผ่าน gateway ใช้กันไม่ให้คืนเงินของคำขอเดียวกันพร้อมกันสองครั้ง
+	RefundClaimedAt *time.Time    `json:"refund_claimed_at"`
+	Items           []ReturnItem  `gorm:"foreignKey:ReturnID" json:"items,omitempty"`
+	Events          []ReturnEvent `gorm:"foreignKey:ReturnID" json:"events,omitempty"`
+}
+
+// ReturnItem สำหรับเก็บจำนวนสินค้าของแต่ละ OrderItem ที่ขอคืนและผลการตรวจ
+type ReturnItem struct {
+	BaseModel
+	ReturnID         uuid.UUID  `gorm:"type:uuid;index" json:"return_id"`
+	OrderItemID      uuid.UUID  `gorm:"type:uuid;index" json:"order_item_id"`
+	ProductID        uuid.UUID  `gorm:"type:uuid" json:"product_id"`
+	VariantID        *uuid.UUID `gorm:"type:uuid" json:"variant_id"`
+	Quantity         int        `gorm:"type:int" json:"quantity"`
+	Reason           string     `gorm:"type:text" json:"reason"`
+	AcceptedQuantity int        `gorm:"type:int;default:0" json:"accepted_quantity"`
+	Restock          bool       `gorm:"default:false" json:"restock"`
+}
+
+// ReturnEvent สำหรับเก็บประวัติการเปลี่ยนสถานะของคำขอคืนสินค้า
+type ReturnEvent struct {
+	BaseModel
+	ReturnID   uuid.UUID  `gorm:"type:uuid;index" json:"return_id"`
+	FromStatus string     `gorm:"type:varchar(50)" json:"from_status"`
+	ToStatus   string     `gorm:"type:varchar(50)" json:"to_status"`
+	ActorID    *uuid.UUID `gorm:"type:uuid" json:"actor_id"`
+	Note       string     `gorm:"type:text" json:"note"`
+}
+
+// UserWishlist ตาราง join ของ User.WishList พร้อมการแจ้งเตือนที่ผู้ใช้เลือกรับสำหรับสินค้าแต่ละรายการ
+// AwaitingStock คือสินค้าหมดสต็อกและรอแจ้งเมื่อกลับมามีสต็อก LastPrice คือราคาล่าสุดที่ระบบเห็น ใช้ตรวจการลดราคา
+type UserWishlist struct {
+	UserID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	ProductID         uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"product_id"`
+	Product           Product   `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	NotifyBackInStock bool      `gorm:"default:false" json:"notify_back_in_stock"`
+	NotifyPriceDrop   bool      `gorm:"default:false" json:"notify_price_drop"`
+	AwaitingStock     bool      `gorm:"default:false" json:"awaiting_stock"`
+	LastPrice         int64     `gorm:"type:bigint;default:0" json:"last_price"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// TableName ใช้ตารางเดียวกับ many2many:user_wishlist ของ User.WishList
+func (UserWishlist) TableName() string {
+	return "user_wishlist"
+}
+
+// DataMigration สำหรับบันทึกการเติมข้อมูลที่ต้องทำเพียงครั้งเดียว เพื่อไม่ให้รันซ้ำทุกครั้งที่เริ่มแอป
+type DataMigration struct {
+	Name      string    `gorm:"type:varchar(100);primaryKey" json:"name"`
+	AppliedAt time.Time `json:"applied_at"`
 }
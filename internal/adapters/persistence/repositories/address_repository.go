@@ -0,0 +1,252 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultCountry ประเทศของที่อยู่ที่ไม่ได้ระบุ (ISO 3166-1 alpha-2)
+const defaultCountry = "TH"
+
+type addressRepository struct {
+	db *gorm.DB
+}
+
+func NewAddressRepository(db *gorm.DB) repositories.AddressRepository {
+	return &addressRepository{db: db}
+}
+
+func (r *addressRepository) Create(ctx context.Context, userID uuid.UUID, req *entities.CreateAddressRequest) (*entities.Address, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	addresses, err := lockAddresses(tx, userID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// ที่อยู่แรกเป็นที่อยู่เริ่มต้นเสมอ
+	address := &models.Address{
+		UserID:        userID,
+		Label:         req.Label,
+		PostalAddress: postalAddressModel(req.PostalAddress),
+		IsDefault:     req.IsDefault || len(addresses) == 0,
+	}
+
+	if address.IsDefault {
+		if err := clearDefaultAddress(tx, userID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Create(address).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return addressModelToEntity(address), nil
+}
+
+func (r *addressRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Address, error) {
+	var addresses []models.Address
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("is_default DESC, created_at DESC").Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+
+	var result []*entities.Address
+	for _, address := range addresses {
+		result = append(result, addressModelToEntity(&address))
+	}
+
+	return result, nil
+}
+
+func (r *addressRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*entities.Address, error) {
+	address, err := findAddress(r.db.WithContext(ctx), userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return addressModelToEntity(address), nil
+}
+
+func (r *addressRepository) Update(ctx context.Context, userID, id uuid.UUID, req *entities.UpdateAddressRequest) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	if _, err := lockAddresses(tx, userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	address, err := findAddress(tx, userID, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	address.Label = req.Label
+	address.PostalAddress = postalAddressModel(req.PostalAddress)
+
+	// ยกเลิกที่อยู่เริ่มต้นด้วยการส่ง false ไม่ได้ ต้องตั้งที่อยู่อื่นเป็นค่าเริ่มต้นแทน
+	if req.IsDefault != nil && *req.IsDefault && !address.IsDefault {
+		if err := clearDefaultAddress(tx, userID); err != nil {
+			tx.Rollback()
+			return err
+		}
+		address.IsDefault = true
+	}
+
+	if err := tx.Save(address).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Delete ลบที่อยู่ ถ้าเป็นที่อยู่เริ่มต้นจะตั้งที่อยู่ที่เพิ่มล่าสุดเป็นค่าเริ่มต้นแทน
+// คำสั่งซื้อที่เคยใช้ที่อยู่นี้ไม่ได้รับผลกระทบเพราะเก็บสำเนาไว้แล้ว
+func (r *addressRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	if _, err := lockAddresses(tx, userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	address, err := findAddress(tx, userID, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Delete(address).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if address.IsDefault {
+		var next models.Address
+		err := tx.Where("user_id = ?", userID).Order("created_at DESC").First(&next).Error
+		switch {
+		case err == nil:
+			if err := tx.Model(&next).Update("is_default", true).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (r *addressRepository) SetDefault(ctx context.Context, userID, id uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	if _, err := lockAddresses(tx, userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	address, err := findAddress(tx, userID, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := clearDefaultAddress(tx, userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Model(address).Update("is_default", true).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// lockAddresses ล็อคที่อยู่ทั้งหมดของผู้ใช้ กันการตั้งที่อยู่เริ่มต้นพร้อมกันจนมีมากกว่าหนึ่งรายการ
+func lockAddresses(tx *gorm.DB, userID uuid.UUID) ([]models.Address, error) {
+	var addresses []models.Address
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+func clearDefaultAddress(tx *gorm.DB, userID uuid.UUID) error {
+	return tx.Model(&models.Address{}).Where("user_id = ? AND is_default = ?", userID, true).Update("is_default", false).Error
+}
+
+// findAddress หาที่อยู่ของผู้ใช้ ที่อยู่ของผู้ใช้คนอื่นจะได้ ErrAddressNotFound เหมือนไม่มีอยู่
+func findAddress(db *gorm.DB, userID, id uuid.UUID) (*models.Address, error) {
+	var address models.Address
+	if err := db.Where("id = ? AND user_id = ?", id, userID).First(&address).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrAddressNotFound
+		}
+		return nil, err
+	}
+	return &address, nil
+}
+
+func postalAddressModel(address entities.PostalAddress) models.PostalAddress {
+	if address.Country == "" {
+		address.Country = defaultCountry
+	}
+	return models.PostalAddress{
+		RecipientName: address.RecipientName,
+		Phone:         address.Phone,
+		Line1:         address.Line1,
+		Line2:         address.Line2,
+		Subdistrict:   address.Subdistrict,
+		District:      address.District,
+		Province:      address.Province,
+		PostalCode:    address.PostalCode,
+		Country:       strings.ToUpper(address.Country),
+	}
+}
+
+func postalAddressEntity(address models.PostalAddress) entities.PostalAddress {
+	return entities.PostalAddress{
+		RecipientName: address.RecipientName,
+		Phone:         address.Phone,
+		Line1:         address.Line1,
+		Line2:         address.Line2,
+		Subdistrict:   address.Subdistrict,
+		District:      address.District,
+		Province:      address.Province,
+		PostalCode:    address.PostalCode,
+		Country:       address.Country,
+	}
+}
+
+func addressModelToEntity(address *models.Address) *entities.Address {
+	return &entities.Address{
+		ID:            address.ID,
+		UserID:        address.UserID,
+		Label:         address.Label,
+		PostalAddress: postalAddressEntity(address.PostalAddress),
+		IsDefault:     address.IsDefault,
+		CreatedAt:     address.CreatedAt,
+		UpdatedAt:     address.UpdatedAt,
+	}
+}
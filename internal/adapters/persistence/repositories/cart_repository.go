@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
@@ -22,7 +24,7 @@ func (r *cartRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*en
 	var cart models.Cart
 
 	// หาตะกร้าของผู้ใช้ หากไม่มีให้สร้างใหม่
-	if err := r.db.WithContext(ctx).Preload("CartItems.Product").Where("user_id = ?", userID).First(&cart).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("CartItems.Product").Preload("CartItems.Variant.OptionValues.Option").Where("user_id = ?", userID).First(&cart).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			// สร้างตะกร้าใหม่
 			newCart := &models.Cart{
@@ -53,22 +55,46 @@ func (r *cartRepository) AddItem(ctx context.Context, userID uuid.UUID, item *en
 		return err
 	}
 
-	// ตรวจสอบสต็อก
-	if product.Stock < item.Quantity {
-		return gorm.ErrInvalidData
+	// สินค้าที่มี variant ใช้ราคาและสต็อกของ variant ที่เลือก
+	variant, err := r.findVariant(ctx, &product, item.VariantID)
+	if err != nil {
+		return err
+	}
+	price, stock := product.Price, product.Stock
+	if variant != nil {
+		price, stock = variantModelToEntity(variant, &product).Price.Amount, variant.Stock
+	}
+
+	// ตรวจสอบสต็อกเบื้องต้น การตัดสต็อกจริงจะตรวจอีกครั้งตอน checkout
+	if stock < item.Quantity {
+		return cartOutOfStockError(&product, variant, item.Quantity)
 	}
 
-	// ตรวจสอบว่าสินค้านี้มีในตะกร้าแล้วหรือไม่
+	// ตะกร้าหนึ่งใบมีสินค้าได้สกุลเงินเดียว เพื่อให้รวมยอดได้โดยไม่ต้องแปลงสกุลเงิน
+	for _, cartItem := range cart.CartItems {
+		if cartItem.ProductID != item.ProductID && cartItem.Price.Currency != product.Currency {
+			return fmt.Errorf("%w: ตะกร้ามีสินค้าสกุลเงิน %s อยู่แล้ว", entities.ErrCurrencyMismatch, cartItem.Price.Currency)
+		}
+	}
+
+	// ตรวจสอบว่าสินค้า (และ variant) นี้มีในตะกร้าแล้วหรือไม่
+	query := r.db.WithContext(ctx).Where("cart_id = ? AND product_id = ?", cart.ID, item.ProductID)
+	if variant != nil {
+		query = query.Where("variant_id = ?", variant.ID)
+	} else {
+		query = query.Where("variant_id IS NULL")
+	}
 	var existingItem models.CartItem
-	if err := r.db.WithContext(ctx).Where("cart_id = ? AND product_id = ?", cart.ID, item.ProductID).First(&existingItem).Error; err == nil {
+	if err := query.First(&existingItem).Error; err == nil {
 		// อัพเดทจำนวน
 		newQuantity := existingItem.Quantity + item.Quantity
-		if product.Stock < newQuantity {
-			return gorm.ErrInvalidData
+		if stock < newQuantity {
+			return cartOutOfStockError(&product, variant, newQuantity)
 		}
 		return r.db.WithContext(ctx).Model(&existingItem).Updates(map[string]interface{}{
 			"quantity": newQuantity,
-			"price":    product.Price,
+			"price":    price,
+			"currency": product.Currency,
 		}).Error
 	}
 
@@ -77,22 +103,47 @@ func (r *cartRepository) AddItem(ctx context.Context, userID uuid.UUID, item *en
 		CartID:    cart.ID,
 		ProductID: item.ProductID,
 		Quantity:  item.Quantity,
-		Price:     product.Price,
+		Price:     price,
+		Currency:  product.Currency,
+	}
+	if variant != nil {
+		cartItem.VariantID = &variant.ID
 	}
 
 	return r.db.WithContext(ctx).Create(cartItem).Error
 }
 
+// findVariant หา variant ที่เลือกของสินค้า คืนค่า nil เมื่อสินค้าไม่มี variant
+// สินค้าที่มี variant ต้องระบุ variant และสินค้าที่ไม่มี variant ต้องไม่ระบุ
+func (r *cartRepository) findVariant(ctx context.Context, product *models.Product, variantID *uuid.UUID) (*models.ProductVariant, error) {
+	if variantID == nil {
+		return nil, requireVariant(r.db.WithContext(ctx), product.ID, nil)
+	}
+
+	var variant models.ProductVariant
+	if err := r.db.WithContext(ctx).Preload("OptionValues.Option").First(&variant, "id = ? AND product_id = ?", *variantID, product.ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrVariantNotFound
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
+
 func (r *cartRepository) UpdateItem(ctx context.Context, cartItemID uuid.UUID, quantity int) error {
 	// หา cart item
 	var cartItem models.CartItem
-	if err := r.db.WithContext(ctx).Preload("Product").First(&cartItem, "id = ?", cartItemID).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Product").Preload("Variant.OptionValues.Option").First(&cartItem, "id = ?", cartItemID).Error; err != nil {
 		return err
 	}
 
-	// ตรวจสอบสต็อก
-	if cartItem.Product.Stock < quantity {
-		return gorm.ErrInvalidData
+	// ตรวจสอบสต็อก (ของ variant ถ้ารายการนี้ระบุ variant)
+	stock := cartItem.Product.Stock
+	if cartItem.Variant != nil {
+		stock = cartItem.Variant.Stock
+	}
+	if stock < quantity {
+		return cartOutOfStockError(&cartItem.Product, cartItem.Variant, quantity)
 	}
 
 	return r.db.WithContext(ctx).Model(&cartItem).Update("quantity", quantity).Error
@@ -109,35 +160,67 @@ func (r *cartRepository) ClearCart(ctx context.Context, userID uuid.UUID) error
 		return err
 	}
 
-	// ลบรายการทั้งหมดในตะกร้า
-	return r.db.WithContext(ctx).Where("cart_id = ?", cart.ID).Delete(&models.CartItem{}).Error
+	// ลบรายการทั้งหมดในตะกร้า พร้อมยกเลิกคูปองที่ใช้อยู่
+	if err := r.db.WithContext(ctx).Where("cart_id = ?", cart.ID).Delete(&models.CartItem{}).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Model(&cart).Update("coupon_code", "").Error
+}
+
+// SetCouponCode บันทึกรหัสคูปองของตะกร้า ส่งค่าว่างเพื่อยกเลิกคูปอง
+func (r *cartRepository) SetCouponCode(ctx context.Context, userID uuid.UUID, code string) error {
+	cart, err := r.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Model(&models.Cart{}).Where("id = ?", cart.ID).Update("coupon_code", code).Error
 }
 
 func (r *cartRepository) GetCartItem(ctx context.Context, cartItemID uuid.UUID) (*entities.CartItem, error) {
 	var cartItem models.CartItem
-	if err := r.db.WithContext(ctx).Preload("Product").First(&cartItem, "id = ?", cartItemID).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Product").Preload("Variant.OptionValues.Option").First(&cartItem, "id = ?", cartItemID).Error; err != nil {
 		return nil, err
 	}
 
 	return r.cartItemModelToEntity(&cartItem), nil
 }
 
+// outOfStockError สร้าง OutOfStockError สำหรับสินค้าตัวเดียว
+func outOfStockError(product *models.Product, requested int) error {
+	return &entities.OutOfStockError{Items: []entities.OutOfStockItem{{
+		ProductID: product.ID,
+		Name:      product.Name,
+		Requested: requested,
+		Available: product.Stock,
+	}}}
+}
+
+// cartOutOfStockError สร้าง OutOfStockError ของสินค้าหรือ variant ที่อยู่ในตะกร้า
+func cartOutOfStockError(product *models.Product, variant *models.ProductVariant, requested int) error {
+	if variant != nil {
+		return variantOutOfStockError(product, variant, requested)
+	}
+	return outOfStockError(product, requested)
+}
+
 func (r *cartRepository) modelToEntity(cart *models.Cart) *entities.Cart {
 	cartEntity := &entities.Cart{
 		ID:         cart.ID,
 		UserID:     cart.UserID,
-		TotalPrice: cart.TotalPrice,
+		TotalPrice: entities.NewMoney(0, ""),
 		CreatedAt:  cart.CreatedAt,
 		UpdatedAt:  cart.UpdatedAt,
+		CouponCode: cart.CouponCode,
 	}
 
-	// คำนวณราคารวม
-	var totalPrice float64
+	// คำนวณราคารวม (AddItem ทำให้สินค้าในตะกร้าเป็นสกุลเงินเดียวกันเสมอ)
 	for _, item := range cart.CartItems {
-		cartEntity.CartItems = append(cartEntity.CartItems, *r.cartItemModelToEntity(&item))
-		totalPrice += item.Price * float64(item.Quantity)
+		cartItem := r.cartItemModelToEntity(&item)
+		cartEntity.CartItems = append(cartEntity.CartItems, *cartItem)
+		cartEntity.TotalPrice.Currency = cartItem.Price.Currency
+		cartEntity.TotalPrice.Amount += cartItem.Price.Mul(cartItem.Quantity).Amount
 	}
-	cartEntity.TotalPrice = totalPrice
 
 	return cartEntity
 }
@@ -147,8 +230,9 @@ func (r *cartRepository) cartItemModelToEntity(cartItem *models.CartItem) *entit
 		ID:        cartItem.ID,
 		CartID:    cartItem.CartID,
 		ProductID: cartItem.ProductID,
+		VariantID: cartItem.VariantID,
 		Quantity:  cartItem.Quantity,
-		Price:     cartItem.Price,
+		Price:     entities.NewMoney(cartItem.Price, cartItem.Currency),
 		CreatedAt: cartItem.CreatedAt,
 		UpdatedAt: cartItem.UpdatedAt,
 	}
@@ -158,8 +242,9 @@ func (r *cartRepository) cartItemModelToEntity(cartItem *models.CartItem) *entit
 			ID:          cartItem.Product.ID,
 			Name:        cartItem.Product.Name,
 			Description: cartItem.Product.Description,
-			Price:       cartItem.Product.Price,
+			Price:       entities.NewMoney(cartItem.Product.Price, cartItem.Product.Currency),
 			Stock:       cartItem.Product.Stock,
+			Weight:      cartItem.Product.Weight,
 			Image:       cartItem.Product.Image,
 			CategoryID:  cartItem.Product.CategoryID,
 			CreatedAt:   cartItem.Product.CreatedAt,
@@ -167,5 +252,10 @@ func (r *cartRepository) cartItemModelToEntity(cartItem *models.CartItem) *entit
 		}
 	}
 
+	if cartItem.Variant != nil && cartItem.Variant.ID != uuid.Nil {
+		variant := variantModelToEntity(cartItem.Variant, &cartItem.Product)
+		item.Variant = &variant
+	}
+
 	return item
 }
@@ -2,14 +2,47 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// categorySubtreeSQL subquery ที่คืน id ของหมวดหมู่และหมวดหมู่ย่อยทุกระดับ
+// ใช้ UNION แทน UNION ALL เพื่อให้หยุดได้แม้ข้อมูลจะวนกันเป็นรอบ
+const categorySubtreeSQL = `WITH RECURSIVE subtree AS (
+	SELECT id FROM categories WHERE id = ? AND deleted_at IS NULL
+	UNION
+	SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id WHERE c.deleted_at IS NULL
+) SELECT id FROM subtree`
+
+// categoryAncestorsSQL คืนหมวดหมู่จากบนสุดลงมาถึงหมวดหมู่ที่ระบุ
+const categoryAncestorsSQL = `WITH RECURSIVE ancestors AS (
+	SELECT id, name, slug, parent_id, 0 AS depth FROM categories WHERE id = ? AND deleted_at IS NULL
+	UNION ALL
+	SELECT c.id, c.name, c.slug, c.parent_id, a.depth + 1 FROM categories c JOIN ancestors a ON c.id = a.parent_id
+	WHERE c.deleted_at IS NULL AND a.depth < 1000
+) SELECT id, name, slug FROM ancestors ORDER BY depth DESC`
+
+// categoryProductCountsSQL นับสินค้าของแต่ละหมวดหมู่ที่ระบุ รวมสินค้าในหมวดหมู่ย่อยทุกระดับ
+const categoryProductCountsSQL = `WITH RECURSIVE tree AS (
+	SELECT id AS root_id, id FROM categories WHERE id IN ? AND deleted_at IS NULL
+	UNION
+	SELECT t.root_id, c.id FROM categories c JOIN tree t ON c.parent_id = t.id WHERE c.deleted_at IS NULL
+)
+SELECT tree.root_id AS category_id, COUNT(products.id) AS count
+FROM tree JOIN products ON products.category_id = tree.id AND products.deleted_at IS NULL
+GROUP BY tree.root_id`
+
+// categoryTreeLockKey key ของ advisory lock ที่ใช้เมื่อสร้าง ย้าย ลบ หรือเปลี่ยน slug ของหมวดหมู่
+// การตรวจว่าย้ายแล้วไม่วนเป็นรอบและการหา slug ที่ว่างต้องเห็นข้อมูลทั้งตาราง จึงให้ทำทีละรายการ
+const categoryTreeLockKey = 7342001
+
 type categoryRepository struct {
 	db *gorm.DB
 }
@@ -19,26 +52,70 @@ func NewCategoryRepository(db *gorm.DB) repositories.CategoryRepository {
 }
 
 func (r *categoryRepository) Create(ctx context.Context, req *entities.CreateCategoryRequest) (*entities.Category, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	if err := lockCategoryTree(tx); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if req.ParentID != nil {
+		if err := findParentCategory(tx, *req.ParentID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	slug, err := categorySlug(tx, req.Slug, req.Name, uuid.Nil)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	categoryModel := &models.Category{
-		Name:        req.Name,
-		Description: req.Description,
-		Image:       req.Image,
+		ParentID:         req.ParentID,
+		Name:             req.Name,
+		Slug:             slug,
+		Description:      req.Description,
+		Image:            req.Image,
+		SortOrder:        req.SortOrder,
+		ReorderThreshold: req.ReorderThreshold,
+		TaxRuleID:        req.TaxRuleID,
+	}
+
+	if err := tx.Create(categoryModel).Error; err != nil {
+		tx.Rollback()
+		return nil, err
 	}
 
-	if err := r.db.WithContext(ctx).Create(categoryModel).Error; err != nil {
+	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
 
-	return r.modelToEntity(categoryModel), nil
+	return r.GetByID(ctx, categoryModel.ID)
 }
 
+// GetByID คืนหมวดหมู่พร้อม breadcrumb จากหมวดหมู่บนสุด
 func (r *categoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Category, error) {
 	var categoryModel models.Category
-	if err := r.db.WithContext(ctx).First(&categoryModel, "id = ?", id).Error; err != nil {
+	if err := findCategory(r.db.WithContext(ctx), id, &categoryModel); err != nil {
+		return nil, err
+	}
+
+	return r.withBreadcrumbs(ctx, &categoryModel)
+}
+
+// GetBySlug คืนหมวดหมู่จาก slug ใน URL พร้อม breadcrumb
+func (r *categoryRepository) GetBySlug(ctx context.Context, slug string) (*entities.Category, error) {
+	var categoryModel models.Category
+	if err := r.db.WithContext(ctx).First(&categoryModel, "slug = ?", slug).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrCategoryNotFound
+		}
 		return nil, err
 	}
 
-	return r.modelToEntity(&categoryModel), nil
+	return r.withBreadcrumbs(ctx, &categoryModel)
 }
 
 func (r *categoryRepository) GetAll(ctx context.Context, page, limit int) ([]*entities.Category, int, error) {
@@ -51,7 +128,7 @@ func (r *categoryRepository) GetAll(ctx context.Context, page, limit int) ([]*en
 		return nil, 0, err
 	}
 
-	if err := r.db.WithContext(ctx).Offset(offset).Limit(limit).Find(&categories).Error; err != nil {
+	if err := r.db.WithContext(ctx).Order("sort_order ASC, name ASC").Offset(offset).Limit(limit).Find(&categories).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -60,36 +137,306 @@ func (r *categoryRepository) GetAll(ctx context.Context, page, limit int) ([]*en
 		result = append(result, r.modelToEntity(&category))
 	}
 
+	if err := r.countProducts(ctx, result); err != nil {
+		return nil, 0, err
+	}
+
 	return result, int(total), nil
 }
 
+// GetTree คืนหมวดหมู่บนสุดทั้งหมดพร้อมหมวดหมู่ย่อยทุกระดับใน Children เรียงตาม sort_order แล้วตามชื่อ
+func (r *categoryRepository) GetTree(ctx context.Context) ([]*entities.Category, error) {
+	var categories []models.Category
+	if err := r.db.WithContext(ctx).Order("sort_order ASC, name ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[uuid.UUID]*entities.Category, len(categories))
+	all := make([]*entities.Category, 0, len(categories))
+	for i := range categories {
+		nodes[categories[i].ID] = r.modelToEntity(&categories[i])
+		all = append(all, nodes[categories[i].ID])
+	}
+	if err := r.countProducts(ctx, all); err != nil {
+		return nil, err
+	}
+
+	roots := []*entities.Category{}
+	for i := range categories {
+		node := nodes[categories[i].ID]
+		if node.ParentID != nil {
+			if parent, ok := nodes[*node.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	return roots, nil
+}
+
 func (r *categoryRepository) Update(ctx context.Context, id uuid.UUID, req *entities.UpdateCategoryRequest) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	if req.ParentID != nil || req.Slug != "" {
+		if err := lockCategoryTree(tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	var category models.Category
+	if err := findCategory(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id, &category); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	updates := map[string]interface{}{}
 
 	if req.Name != "" {
 		updates["name"] = req.Name
 	}
+	if req.Slug != "" {
+		slug, err := categorySlug(tx, req.Slug, "", id)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		updates["slug"] = slug
+	}
 	if req.Description != "" {
 		updates["description"] = req.Description
 	}
 	if req.Image != "" {
 		updates["image"] = req.Image
 	}
+	if req.SortOrder != nil {
+		updates["sort_order"] = *req.SortOrder
+	}
+	if req.ReorderThreshold != nil {
+		updates["reorder_threshold"] = *req.ReorderThreshold
+	}
+	// ส่ง uuid ว่างเพื่อยกเลิกกฎภาษีที่กำหนดไว้ แล้วกลับไปใช้กฎภาษีเริ่มต้น (VAT 7%)
+	if req.TaxRuleID != nil {
+		if *req.TaxRuleID == uuid.Nil {
+			updates["tax_rule_id"] = nil
+		} else {
+			updates["tax_rule_id"] = *req.TaxRuleID
+		}
+	}
+	// ย้ายหมวดหมู่ หมวดหมู่ย่อยและสินค้าย้ายตามไปด้วยเพราะยังอ้างถึงหมวดหมู่เดิม
+	if req.ParentID != nil {
+		if *req.ParentID == uuid.Nil {
+			updates["parent_id"] = nil
+		} else {
+			if err := findParentCategory(tx, *req.ParentID); err != nil {
+				tx.Rollback()
+				return err
+			}
+
+			var descendant int64
+			if err := tx.Model(&models.Category{}).Where("id = ? AND id IN ("+categorySubtreeSQL+")", *req.ParentID, id).Count(&descendant).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+			if descendant > 0 {
+				tx.Rollback()
+				return entities.ErrInvalidCategoryParent
+			}
+			updates["parent_id"] = *req.ParentID
+		}
+	}
 
-	return r.db.WithContext(ctx).Model(&models.Category{}).Where("id = ?", id).Updates(updates).Error
+	if len(updates) > 0 {
+		if err := tx.Model(&models.Category{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
 }
 
-func (r *categoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&models.Category{}, "id = ?", id).Error
+// Delete ลบหมวดหมู่ หมวดหมู่ย่อยย้ายขึ้นไปอยู่ใต้หมวดหมู่แม่ของหมวดหมู่ที่ลบ
+// สินค้าที่อยู่ในหมวดหมู่นี้โดยตรงจะย้ายไปที่ reassignTo ถ้าไม่ระบุและยังมีสินค้าอยู่จะคืน ErrCategoryHasProducts
+// เพื่อไม่ให้สินค้าอ้างถึงหมวดหมู่ที่ถูกลบ
+func (r *categoryRepository) Delete(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	if err := lockCategoryTree(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var category models.Category
+	if err := findCategory(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id, &category); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if reassignTo != nil {
+		if *reassignTo == id {
+			tx.Rollback()
+			return entities.ErrInvalidCategoryReassign
+		}
+		if err := findCategory(tx, *reassignTo, &models.Category{}); err != nil {
+			tx.Rollback()
+			if errors.Is(err, entities.ErrCategoryNotFound) {
+				return fmt.Errorf("%w: %s", entities.ErrInvalidCategoryReassign, *reassignTo)
+			}
+			return err
+		}
+		if err := tx.Model(&models.Product{}).Where("category_id = ?", id).Update("category_id", *reassignTo).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	} else {
+		var products int64
+		if err := tx.Model(&models.Product{}).Where("category_id = ?", id).Count(&products).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		if products > 0 {
+			tx.Rollback()
+			return fmt.Errorf("%w: %d products", entities.ErrCategoryHasProducts, products)
+		}
+	}
+
+	if err := tx.Model(&models.Category{}).Where("parent_id = ?", id).Update("parent_id", category.ParentID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Delete(&category).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// withBreadcrumbs แปลงเป็น entity พร้อมเส้นทางจากหมวดหมู่บนสุดและจำนวนสินค้า
+func (r *categoryRepository) withBreadcrumbs(ctx context.Context, categoryModel *models.Category) (*entities.Category, error) {
+	category := r.modelToEntity(categoryModel)
+	if err := r.db.WithContext(ctx).Raw(categoryAncestorsSQL, categoryModel.ID).Scan(&category.Breadcrumbs).Error; err != nil {
+		return nil, err
+	}
+	if err := r.countProducts(ctx, []*entities.Category{category}); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// countProducts ใส่จำนวนสินค้า (รวมหมวดหมู่ย่อย) ให้หมวดหมู่ทั้งหมดด้วย query เดียว
+func (r *categoryRepository) countProducts(ctx context.Context, categories []*entities.Category) error {
+	if len(categories) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(categories))
+	for i, category := range categories {
+		ids[i] = category.ID
+	}
+
+	var rows []struct {
+		CategoryID uuid.UUID
+		Count      int
+	}
+	if err := r.db.WithContext(ctx).Raw(categoryProductCountsSQL, ids).Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Count
+	}
+	for _, category := range categories {
+		count := counts[category.ID]
+		category.ProductCount = &count
+	}
+	return nil
 }
 
 func (r *categoryRepository) modelToEntity(categoryModel *models.Category) *entities.Category {
 	return &entities.Category{
-		ID:          categoryModel.ID,
-		Name:        categoryModel.Name,
-		Description: categoryModel.Description,
-		Image:       categoryModel.Image,
-		CreatedAt:   categoryModel.CreatedAt,
-		UpdatedAt:   categoryModel.UpdatedAt,
+		ID:               categoryModel.ID,
+		ParentID:         categoryModel.ParentID,
+		Name:             categoryModel.Name,
+		Slug:             categoryModel.Slug,
+		Description:      categoryModel.Description,
+		Image:            categoryModel.Image,
+		SortOrder:        categoryModel.SortOrder,
+		ReorderThreshold: categoryModel.ReorderThreshold,
+		TaxRuleID:        categoryModel.TaxRuleID,
+		CreatedAt:        categoryModel.CreatedAt,
+		UpdatedAt:        categoryModel.UpdatedAt,
+	}
+}
+
+// findCategory โหลดหมวดหมู่ที่ยังไม่ถูกลบ คืน ErrCategoryNotFound ถ้าไม่พบ
+func findCategory(db *gorm.DB, id uuid.UUID, category *models.Category) error {
+	if err := db.First(category, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.ErrCategoryNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// findParentCategory ตรวจว่าหมวดหมู่แม่มีอยู่จริง คืน ErrParentCategoryNotFound ถ้าไม่พบ
+func findParentCategory(tx *gorm.DB, parentID uuid.UUID) error {
+	if err := findCategory(tx, parentID, &models.Category{}); err != nil {
+		if errors.Is(err, entities.ErrCategoryNotFound) {
+			return fmt.Errorf("%w: %s", entities.ErrParentCategoryNotFound, parentID)
+		}
+		return err
+	}
+	return nil
+}
+
+// lockCategoryTree ถือ advisory lock ของต้นไม้หมวดหมู่จนจบ transaction
+func lockCategoryTree(tx *gorm.DB) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", categoryTreeLockKey).Error
+}
+
+// categorySlug คืน slug ที่จะใช้ slug ที่ผู้ใช้ระบุต้องไม่ซ้ำกับหมวดหมู่อื่น
+// ถ้าไม่ได้ระบุจะสร้างจากชื่อ และเติม -2, -3, ... เมื่อซ้ำ exceptID คือหมวดหมู่ที่กำลังแก้ไข
+func categorySlug(tx *gorm.DB, requested, name string, exceptID uuid.UUID) (string, error) {
+	taken := func(slug string) (bool, error) {
+		var count int64
+		err := tx.Model(&models.Category{}).Where("slug = ? AND id <> ?", slug, exceptID).Count(&count).Error
+		return count > 0, err
+	}
+
+	if requested != "" {
+		slug := entities.Slugify(requested)
+		if slug == "" {
+			return "", entities.ErrInvalidCategorySlug
+		}
+		exists, err := taken(slug)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return "", fmt.Errorf("%w: %s", entities.ErrCategorySlugExists, slug)
+		}
+		return slug, nil
+	}
+
+	base := entities.Slugify(name)
+	if base == "" {
+		base = "category"
+	}
+
+	var existing []string
+	if err := tx.Model(&models.Category{}).Where("(slug = ? OR slug LIKE ?) AND id <> ?", base, escapeLike(base)+"-%", exceptID).Pluck("slug", &existing).Error; err != nil {
+		return "", err
+	}
+	used := make(map[string]bool, len(existing))
+	for _, slug := range existing {
+		used[slug] = true
 	}
+	return entities.AvailableSlug(base, used), nil
 }
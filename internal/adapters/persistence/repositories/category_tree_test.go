@@ -0,0 +1,137 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+func TestCategoryTree_ListsDescendantsAndKeepsProductsAttached(t *testing.T) {
+	d := newTestData(t)
+	db := d.db
+	ctx := context.Background()
+
+	categoryRepo := repositories.NewCategoryRepository(db)
+	productRepo := repositories.NewProductRepository(db)
+
+	prefix := "test-" + uuid.NewString()
+	create := func(name string, parentID *uuid.UUID) *entities.Category {
+		t.Helper()
+		category, err := categoryRepo.Create(ctx, &entities.CreateCategoryRequest{Name: prefix + " " + name, ParentID: parentID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.trackCategory(category.ID)
+		return category
+	}
+
+	clothing := create("Clothing", nil)
+	men := create("Men", &clothing.ID)
+	shirts := create("Shirts", &men.ID)
+
+	product := d.product(models.Product{Name: "Oxford shirt", Price: 89000, Currency: "THB", CategoryID: shirts.ID})
+
+	// สินค้าในหมวดหมู่ย่อยต้องแสดงในหมวดหมู่บนสุด
+	result, total, err := productRepo.GetByCategory(ctx, clothing.ID, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || result[0].ID != product.ID {
+		t.Fatalf("expected the shirt under clothing, got total=%d", total)
+	}
+
+	loaded, err := categoryRepo.GetBySlug(ctx, shirts.Slug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Breadcrumbs) != 3 || loaded.Breadcrumbs[0].ID != clothing.ID || loaded.Breadcrumbs[2].ID != shirts.ID {
+		t.Errorf("unexpected breadcrumbs: %+v", loaded.Breadcrumbs)
+	}
+
+	// ย้ายหมวดหมู่ไปอยู่ใต้หมวดหมู่ย่อยของตัวเองไม่ได้
+	if err := categoryRepo.Update(ctx, clothing.ID, &entities.UpdateCategoryRequest{ParentID: &shirts.ID}); !errors.Is(err, entities.ErrInvalidCategoryParent) {
+		t.Fatalf("expected ErrInvalidCategoryParent, got %v", err)
+	}
+
+	// ลบหมวดหมู่ที่ยังมีสินค้าไม่ได้
+	if err := categoryRepo.Delete(ctx, shirts.ID, nil); !errors.Is(err, entities.ErrCategoryHasProducts) {
+		t.Fatalf("expected ErrCategoryHasProducts, got %v", err)
+	}
+
+	// ลบหมวดหมู่กลาง หมวดหมู่ย่อยย้ายขึ้นไปอยู่ใต้หมวดหมู่แม่ และสินค้ายังอยู่ในต้นไม้เดิม
+	if err := categoryRepo.Delete(ctx, men.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	moved, err := categoryRepo.GetByID(ctx, shirts.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.ParentID == nil || *moved.ParentID != clothing.ID {
+		t.Errorf("expected shirts to move under clothing, got parent %v", moved.ParentID)
+	}
+	if _, total, err := productRepo.GetByCategory(ctx, clothing.ID, 1, 10); err != nil || total != 1 {
+		t.Errorf("expected the shirt to stay under clothing, got total=%d err=%v", total, err)
+	}
+
+	// slug ที่สร้างจากชื่อซ้ำกันต้องได้เลขต่อท้าย
+	duplicate := create("Shirts", nil)
+	if duplicate.Slug != shirts.Slug+"-2" {
+		t.Errorf("expected slug %q, got %q", shirts.Slug+"-2", duplicate.Slug)
+	}
+}
+
+func TestDeleteCategory_ReassignsProductsAndCountsSubtree(t *testing.T) {
+	d := newTestData(t)
+	db := d.db
+	ctx := context.Background()
+
+	categoryRepo := repositories.NewCategoryRepository(db)
+
+	prefix := "test-" + uuid.NewString()
+	create := func(name string, parentID *uuid.UUID) *entities.Category {
+		t.Helper()
+		category, err := categoryRepo.Create(ctx, &entities.CreateCategoryRequest{Name: prefix + " " + name, ParentID: parentID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.trackCategory(category.ID)
+		return category
+	}
+
+	kitchen := create("Kitchen", nil)
+	cookware := create("Cookware", &kitchen.ID)
+	clearance := create("Clearance", nil)
+
+	for _, categoryID := range []uuid.UUID{kitchen.ID, cookware.ID, cookware.ID} {
+		d.product(models.Product{Name: "Pan", Price: 59000, Currency: "THB", CategoryID: categoryID})
+	}
+
+	// จำนวนสินค้าของหมวดหมู่แม่ต้องรวมหมวดหมู่ย่อย
+	loaded, err := categoryRepo.GetByID(ctx, kitchen.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.ProductCount == nil || *loaded.ProductCount != 3 {
+		t.Fatalf("expected 3 products under kitchen, got %v", loaded.ProductCount)
+	}
+
+	if err := categoryRepo.Delete(ctx, cookware.ID, &cookware.ID); !errors.Is(err, entities.ErrInvalidCategoryReassign) {
+		t.Fatalf("expected ErrInvalidCategoryReassign, got %v", err)
+	}
+	if err := categoryRepo.Delete(ctx, cookware.ID, &clearance.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	var moved int64
+	if err := db.Model(&models.Product{}).Where("category_id = ?", clearance.ID).Count(&moved).Error; err != nil {
+		t.Fatal(err)
+	}
+	if moved != 2 {
+		t.Errorf("expected 2 products moved to clearance, got %d", moved)
+	}
+}
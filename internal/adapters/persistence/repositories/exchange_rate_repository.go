@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) repositories.ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+func (r *exchangeRateRepository) List(ctx context.Context) ([]*entities.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	if err := r.db.WithContext(ctx).Order("currency").Find(&rates).Error; err != nil {
+		return nil, err
+	}
+
+	var result []*entities.ExchangeRate
+	for _, rate := range rates {
+		result = append(result, r.modelToEntity(&rate))
+	}
+
+	return result, nil
+}
+
+// Upsert สร้างหรือแก้ไขอัตราแลกเปลี่ยนของสกุลเงิน
+func (r *exchangeRateRepository) Upsert(ctx context.Context, rate *entities.ExchangeRate) (*entities.ExchangeRate, error) {
+	model := &models.ExchangeRate{
+		Currency: rate.Currency,
+		Rate:     rate.Rate,
+	}
+
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(model).Error; err != nil {
+		return nil, err
+	}
+
+	return r.modelToEntity(model), nil
+}
+
+func (r *exchangeRateRepository) Delete(ctx context.Context, currency string) error {
+	result := r.db.WithContext(ctx).Delete(&models.ExchangeRate{}, "currency = ?", currency)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %s", entities.ErrUnsupportedCurrency, currency)
+	}
+
+	return nil
+}
+
+func (r *exchangeRateRepository) modelToEntity(rate *models.ExchangeRate) *entities.ExchangeRate {
+	return &entities.ExchangeRate{
+		Currency:  rate.Currency,
+		Rate:      rate.Rate,
+		UpdatedAt: rate.UpdatedAt,
+	}
+}
+
+// loadExchangeRateTable อ่านอัตราแลกเปลี่ยนทั้งหมดภายใน transaction ที่ส่งมา
+// ใช้ตอน checkout เพื่อให้อัตราที่ใช้คำนวณกับอัตราที่บันทึกลงคำสั่งซื้อเป็นชุดเดียวกัน
+func loadExchangeRateTable(tx *gorm.DB) (entities.ExchangeRateTable, error) {
+	var rates []models.ExchangeRate
+	if err := tx.Find(&rates).Error; err != nil {
+		return nil, err
+	}
+
+	table := entities.NewExchangeRateTable(nil)
+	for _, rate := range rates {
+		table[rate.Currency] = rate.Rate
+	}
+
+	return table, nil
+}
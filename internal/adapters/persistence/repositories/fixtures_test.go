@@ -0,0 +1,178 @@
+package repositories_test
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/config"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	migrateOnce sync.Once
+	migrateErr  error
+)
+
+// openTestDB เชื่อมต่อฐานข้อมูลสำหรับทดสอบจาก TEST_DATABASE_DSN
+// ถ้าไม่ได้ตั้งค่าไว้จะข้ามการทดสอบ เพราะต้องใช้ PostgreSQL จริงเพื่อทดสอบการล็อค
+// schema ถูก migrate ด้วย config.Migrate ชุดเดียวกับแอป เพียงครั้งเดียวต่อการรันทดสอบ
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+
+	migrateOnce.Do(func() {
+		migrateErr = config.Migrate(db)
+	})
+	if migrateErr != nil {
+		t.Fatalf("failed to migrate: %v", migrateErr)
+	}
+
+	return db
+}
+
+// testData สร้างข้อมูลตั้งต้นของการทดสอบ และจำไว้ว่าสร้างอะไรไปบ้างเพื่อลบทิ้งทั้งหมดเมื่อการทดสอบจบ
+// คำสั่งซื้อและข้อมูลที่ผูกกับคำสั่งซื้อถูกลบตามผู้ใช้ที่สร้างผ่าน user
+type testData struct {
+	t  *testing.T
+	db *gorm.DB
+
+	role            *models.Role
+	users           []uuid.UUID
+	categories      []uuid.UUID
+	products        []uuid.UUID
+	shippingMethods []uuid.UUID
+}
+
+func newTestData(t *testing.T) *testData {
+	t.Helper()
+
+	d := &testData{t: t, db: openTestDB(t)}
+	t.Cleanup(d.cleanup)
+	return d
+}
+
+// create บันทึก value ลงฐานข้อมูล ถ้าไม่สำเร็จจะหยุดการทดสอบ
+func (d *testData) create(value interface{}) {
+	d.t.Helper()
+	if err := d.db.Create(value).Error; err != nil {
+		d.t.Fatal(err)
+	}
+}
+
+// user สร้างผู้ใช้ใหม่ ผู้ใช้ทุกคนของการทดสอบเดียวกันใช้ role เดียวกัน
+func (d *testData) user() models.User {
+	d.t.Helper()
+
+	if d.role == nil {
+		d.role = &models.Role{Name: "test-" + uuid.NewString()}
+		d.create(d.role)
+	}
+
+	user := models.User{Email: fmt.Sprintf("%s@example.com", uuid.NewString()), RoleID: d.role.ID}
+	d.create(&user)
+	d.users = append(d.users, user.ID)
+	return user
+}
+
+// category สร้างหมวดหมู่ที่ชื่อและ slug ไม่ซ้ำกับข้อมูลอื่น
+func (d *testData) category() models.Category {
+	d.t.Helper()
+
+	name := "test-" + uuid.NewString()
+	category := models.Category{Name: name, Slug: name}
+	d.create(&category)
+	d.trackCategory(category.ID)
+	return category
+}
+
+// trackCategory ให้ลบหมวดหมู่ที่สร้างผ่าน repository เมื่อการทดสอบจบ
+func (d *testData) trackCategory(id uuid.UUID) {
+	d.categories = append(d.categories, id)
+}
+
+// product สร้างสินค้า ถ้าไม่ได้ระบุหมวดหมู่จะสร้างหมวดหมู่ใหม่ให้
+func (d *testData) product(product models.Product) models.Product {
+	d.t.Helper()
+
+	if product.CategoryID == uuid.Nil {
+		product.CategoryID = d.category().ID
+	}
+	d.create(&product)
+	d.products = append(d.products, product.ID)
+	return product
+}
+
+// shippingMethod สร้างวิธีจัดส่งแบบค่าส่งคงที่ที่เปิดใช้งานอยู่
+func (d *testData) shippingMethod() models.ShippingMethod {
+	d.t.Helper()
+
+	method := models.ShippingMethod{Code: "test-" + uuid.NewString(), Name: "Test shipping", RateType: string(entities.ShippingRateFlat), Active: true}
+	d.create(&method)
+	d.shippingMethods = append(d.shippingMethods, method.ID)
+	return method
+}
+
+// testOrdersSQL คำสั่งซื้อทั้งหมดของผู้ใช้ที่การทดสอบสร้างไว้
+const testOrdersSQL = "SELECT id FROM orders WHERE user_id IN ?"
+
+// cleanup ลบข้อมูลที่การทดสอบสร้างไว้ โดยลบแถวที่อ้างถึงแถวอื่นก่อนเสมอ
+func (d *testData) cleanup() {
+	byOrder := []string{
+		"DELETE FROM return_events WHERE return_id IN (SELECT id FROM returns WHERE order_id IN (" + testOrdersSQL + "))",
+		"DELETE FROM return_items WHERE return_id IN (SELECT id FROM returns WHERE order_id IN (" + testOrdersSQL + "))",
+		"DELETE FROM returns WHERE order_id IN (" + testOrdersSQL + ")",
+		"DELETE FROM refund_items WHERE refund_id IN (SELECT id FROM refunds WHERE order_id IN (" + testOrdersSQL + "))",
+		"DELETE FROM refunds WHERE order_id IN (" + testOrdersSQL + ")",
+		"DELETE FROM payment_events WHERE transaction_id IN (SELECT id FROM transactions WHERE order_id IN (" + testOrdersSQL + "))",
+		"DELETE FROM shipment_checkpoints WHERE shipment_id IN (SELECT id FROM shipments WHERE order_id IN (" + testOrdersSQL + "))",
+		"DELETE FROM shipment_items WHERE shipment_id IN (SELECT id FROM shipments WHERE order_id IN (" + testOrdersSQL + "))",
+		"DELETE FROM shipments WHERE order_id IN (" + testOrdersSQL + ")",
+		"DELETE FROM order_discounts WHERE order_id IN (" + testOrdersSQL + ")",
+		"DELETE FROM promotion_redemptions WHERE order_id IN (" + testOrdersSQL + ")",
+		"DELETE FROM order_status_events WHERE order_id IN (" + testOrdersSQL + ")",
+		"DELETE FROM transactions WHERE order_id IN (" + testOrdersSQL + ")",
+		"DELETE FROM order_items WHERE order_id IN (" + testOrdersSQL + ")",
+		"DELETE FROM orders WHERE user_id IN ?",
+		"DELETE FROM cart_items WHERE cart_id IN (SELECT id FROM carts WHERE user_id IN ?)",
+		"DELETE FROM carts WHERE user_id IN ?",
+	}
+	for _, statement := range byOrder {
+		d.db.Exec(statement, d.users)
+	}
+
+	byProduct := []string{
+		"DELETE FROM inventory_movements WHERE product_id IN ?",
+		"DELETE FROM product_variant_option_values WHERE product_variant_id IN (SELECT id FROM product_variants WHERE product_id IN ?)",
+		"DELETE FROM product_variants WHERE product_id IN ?",
+		"DELETE FROM product_option_values WHERE option_id IN (SELECT id FROM product_options WHERE product_id IN ?)",
+		"DELETE FROM product_options WHERE product_id IN ?",
+		"DELETE FROM products WHERE id IN ?",
+	}
+	for _, statement := range byProduct {
+		d.db.Exec(statement, d.products)
+	}
+
+	d.db.Exec("DELETE FROM users WHERE id IN ?", d.users)
+	if d.role != nil {
+		d.db.Exec("DELETE FROM roles WHERE id = ?", d.role.ID)
+	}
+	d.db.Exec("DELETE FROM shipping_methods WHERE id IN ?", d.shippingMethods)
+	d.db.Exec("UPDATE categories SET parent_id = NULL WHERE id IN ?", d.categories)
+	d.db.Exec("DELETE FROM categories WHERE id IN ?", d.categories)
+}
@@ -0,0 +1,268 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type inventoryRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) repositories.InventoryRepository {
+	return &inventoryRepository{db: db}
+}
+
+func (r *inventoryRepository) Adjust(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, delta int, reason entities.InventoryReason, referenceID *uuid.UUID, actorID uuid.UUID, note string) (*entities.InventoryMovement, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	var product models.Product
+	if err := tx.First(&product, "id = ?", productID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	var variant *models.ProductVariant
+	if variantID != nil {
+		variant = &models.ProductVariant{}
+		if err := tx.First(variant, "id = ? AND product_id = ?", *variantID, productID).Error; err != nil {
+			tx.Rollback()
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, entities.ErrVariantNotFound
+			}
+			return nil, err
+		}
+	}
+
+	if err := requireVariant(tx, productID, variantID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	movement, err := moveStock(tx, productID, variantID, delta, reason, referenceID, actorID, note)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if movement == nil {
+		tx.Rollback()
+		if variant != nil {
+			return nil, variantOutOfStockError(&product, variant, -delta)
+		}
+		return nil, outOfStockError(&product, -delta)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return r.modelToEntity(movement), nil
+}
+
+func (r *inventoryRepository) GetByProductID(ctx context.Context, productID uuid.UUID, page, limit int) ([]*entities.InventoryMovement, int, error) {
+	var movements []models.InventoryMovement
+	var total int64
+
+	offset := (page - 1) * limit
+
+	if err := r.db.WithContext(ctx).Model(&models.InventoryMovement{}).Where("product_id = ?", productID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.db.WithContext(ctx).Preload("Actor").Where("product_id = ?", productID).Order("created_at DESC").Offset(offset).Limit(limit).Find(&movements).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var result []*entities.InventoryMovement
+	for _, movement := range movements {
+		result = append(result, r.modelToEntity(&movement))
+	}
+
+	return result, int(total), nil
+}
+
+func (r *inventoryRepository) RebuildStock(ctx context.Context, productID uuid.UUID) (*entities.StockRebuildResult, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	// ล็อคแถวสินค้าไว้ระหว่างคำนวณ ไม่ให้มีการตัดสต็อกแทรกเข้ามา
+	var product models.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, "id = ?", productID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	var summary struct {
+		Stock     int
+		Movements int
+	}
+	if err := tx.Model(&models.InventoryMovement{}).
+		Select("COALESCE(SUM(delta), 0) AS stock, COUNT(*) AS movements").
+		Where("product_id = ?", productID).
+		Scan(&summary).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Model(&models.Product{}).Where("id = ?", productID).Update("stock", summary.Stock).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// สต็อกของแต่ละ variant คำนวณจาก movement ที่อ้างถึง variant นั้น
+	if err := tx.Exec(`
+		UPDATE product_variants SET stock = COALESCE((
+			SELECT SUM(delta) FROM inventory_movements
+			WHERE inventory_movements.variant_id = product_variants.id AND inventory_movements.deleted_at IS NULL
+		), 0)
+		WHERE product_id = ? AND deleted_at IS NULL
+	`, productID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &entities.StockRebuildResult{
+		ProductID:     productID,
+		PreviousStock: product.Stock,
+		Stock:         summary.Stock,
+		Movements:     summary.Movements,
+	}, nil
+}
+
+// moveStock เปลี่ยนสต็อกสินค้าตาม delta และบันทึกลง inventory ledger ใน transaction เดียวกัน
+// การเปลี่ยนสต็อกทุกครั้งต้องผ่านฟังก์ชันนี้ เพื่อให้ ledger ตรงกับสต็อกจริงเสมอ
+// ถ้าสต็อกไม่พอ (ผลลัพธ์จะติดลบ) จะไม่เปลี่ยนอะไรและคืนค่า nil movement
+// ถ้าสินค้าหรือ variant ถูกลบไปแล้วจะไม่เปลี่ยนอะไรและคืน ErrProductNotFound หรือ ErrVariantNotFound
+//
+// ถ้าระบุ variantID สต็อกของ variant และของสินค้าหลักจะเปลี่ยนพร้อมกัน สต็อกของสินค้าหลักจึงเท่ากับผลรวมของ variant
+// และ query รายการสินค้าไม่ต้อง join variant การขายหรือปรับสต็อกใหม่ต้องตรวจด้วย requireVariant ก่อน
+// ส่วนการคืนสต็อกของรายการในคำสั่งซื้อเดิมที่ซื้อก่อนสินค้าจะมี variant จะคืนเข้าสต็อกของสินค้าหลักอย่างเดียว
+func moveStock(tx *gorm.DB, productID uuid.UUID, variantID *uuid.UUID, delta int, reason entities.InventoryReason, referenceID *uuid.UUID, actorID uuid.UUID, note string) (*models.InventoryMovement, error) {
+	// ล็อคและตรวจทุกแถวก่อนเปลี่ยน จะได้ไม่มีกรณีที่สต็อกของ variant เปลี่ยนแล้วแต่ของสินค้าหลักไม่เปลี่ยน
+	// ล็อคสินค้าหลักก่อน variant ตามลำดับเดียวกับที่อื่นในระบบ
+	var product models.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "stock").First(&product, "id = ?", productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", entities.ErrProductNotFound, productID)
+		}
+		return nil, err
+	}
+	if product.Stock+delta < 0 {
+		return nil, nil
+	}
+
+	if variantID != nil {
+		var variant models.ProductVariant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "stock").First(&variant, "id = ? AND product_id = ?", *variantID, productID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: %s", entities.ErrVariantNotFound, *variantID)
+			}
+			return nil, err
+		}
+		if variant.Stock+delta < 0 {
+			return nil, nil
+		}
+
+		if err := tx.Model(&models.ProductVariant{}).Where("id = ?", *variantID).Update("stock", gorm.Expr("stock + ?", delta)).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Model(&models.Product{}).Where("id = ?", productID).Update("stock", gorm.Expr("stock + ?", delta)).Error; err != nil {
+		return nil, err
+	}
+
+	movement := &models.InventoryMovement{
+		ProductID:   productID,
+		VariantID:   variantID,
+		Delta:       delta,
+		Reason:      string(reason),
+		ReferenceID: referenceID,
+		Note:        note,
+	}
+	if actorID != uuid.Nil {
+		movement.ActorID = &actorID
+	}
+
+	if err := tx.Create(movement).Error; err != nil {
+		return nil, err
+	}
+
+	return movement, nil
+}
+
+// restock คืนสต็อกของรายการในคำสั่งซื้อเดิมพร้อมบันทึกลง ledger ใช้ตอนยกเลิกคำสั่งซื้อ คืนเงิน และรับสินค้าคืน
+// สินค้าหรือ variant ที่ถูกลบไปแล้วไม่มีสต็อกให้คืน จึงข้ามไปโดยไม่ถือเป็นข้อผิดพลาด
+func restock(tx *gorm.DB, productID uuid.UUID, variantID *uuid.UUID, quantity int, reason entities.InventoryReason, referenceID *uuid.UUID, actorID uuid.UUID, note string) error {
+	movement, err := moveStock(tx, productID, variantID, quantity, reason, referenceID, actorID, note)
+	if errors.Is(err, entities.ErrProductNotFound) || errors.Is(err, entities.ErrVariantNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if movement == nil {
+		return fmt.Errorf("restock product %s: stock was not moved", productID)
+	}
+	return nil
+}
+
+// requireVariant คืน ErrVariantRequired ถ้าไม่ได้ระบุ variant ของสินค้าที่มี variant
+// ใช้กับการขาย การหยิบใส่ตะกร้า และการปรับสต็อกใหม่ ไม่ใช้กับการคืนสต็อกของรายการในคำสั่งซื้อเดิม
+func requireVariant(db *gorm.DB, productID uuid.UUID, variantID *uuid.UUID) error {
+	if variantID != nil {
+		return nil
+	}
+
+	var variants int64
+	if err := db.Model(&models.ProductVariant{}).Where("product_id = ?", productID).Count(&variants).Error; err != nil {
+		return err
+	}
+	if variants > 0 {
+		return entities.ErrVariantRequired
+	}
+	return nil
+}
+
+func (r *inventoryRepository) modelToEntity(movement *models.InventoryMovement) *entities.InventoryMovement {
+	entity := &entities.InventoryMovement{
+		ID:          movement.ID,
+		ProductID:   movement.ProductID,
+		VariantID:   movement.VariantID,
+		Delta:       movement.Delta,
+		Reason:      entities.InventoryReason(movement.Reason),
+		ReferenceID: movement.ReferenceID,
+		ActorID:     movement.ActorID,
+		Note:        movement.Note,
+		CreatedAt:   movement.CreatedAt,
+	}
+
+	if movement.Actor != nil && movement.Actor.ID != uuid.Nil {
+		entity.Actor = &entities.User{
+			ID:        movement.Actor.ID,
+			Email:     movement.Actor.Email,
+			FirstName: movement.Actor.FirstName,
+			LastName:  movement.Actor.LastName,
+			RoleID:    movement.Actor.RoleID,
+		}
+	}
+
+	return entity
+}
@@ -3,12 +3,17 @@ package repositories
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type orderRepository struct {
@@ -22,35 +27,170 @@ func NewOrderRepository(db *gorm.DB) repositories.OrderRepository {
 func (r *orderRepository) Create(ctx context.Context, userID uuid.UUID, req *entities.CreateOrderRequest) (*entities.Order, error) {
 	tx := r.db.WithContext(ctx).Begin()
 
-	// หาตะกร้าของผู้ใช้
+	// หาตะกร้าของผู้ใช้และล็อคไว้ กันการ checkout ตะกร้าเดียวกันซ้อนกัน
 	var cart models.Cart
-	if err := tx.Preload("CartItems.Product").Where("user_id = ?", userID).First(&cart).Error; err != nil {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&cart).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Preload("Product.Category").Preload("Variant.OptionValues.Option").Where("cart_id = ?", cart.ID).Find(&cart.CartItems).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
 	if len(cart.CartItems) == 0 {
 		tx.Rollback()
-		return nil, errors.New("ตะกร้าสินค้าว่าง")
+		return nil, entities.ErrCartEmpty
+	}
+
+	// ราคาในตะกร้าเป็นสกุลเงินของสินค้า (base currency) ซึ่งต้องเป็นสกุลเดียวกันทุกรายการ
+	baseCurrency := cart.CartItems[0].Currency
+	currency := strings.ToUpper(req.Currency)
+	if currency == "" {
+		currency = baseCurrency
+	}
+
+	rates, err := loadExchangeRateTable(tx)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	rate, err := rates.Rate(baseCurrency, currency)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	taxRules, err := loadTaxRules(tx)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
 	}
 
-	// คำนวณราคารวม
-	var totalPrice float64
-	for _, item := range cart.CartItems {
-		totalPrice += item.Price * float64(item.Quantity)
+	// คัดลอกที่อยู่จากสมุดที่อยู่ไว้กับคำสั่งซื้อ การแก้ไขสมุดที่อยู่ภายหลังจึงไม่กระทบคำสั่งซื้อนี้
+	shippingAddress := req.ShippingAddress
+	shippingProvince := req.ShippingProvince
+	var addressDetail models.PostalAddress
+	if req.AddressID != nil {
+		address, err := findAddress(tx, userID, *req.AddressID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		addressDetail = address.PostalAddress
+		shippingAddress = postalAddressEntity(addressDetail).String()
+		shippingProvince = addressDetail.Province
 	}
 
-	// สร้างคำสั่งซื้อ
+	promotionLines := make([]entities.PromotionLine, len(cart.CartItems))
+	shippingInput := entities.ShippingQuoteInput{
+		Subtotal: entities.NewMoney(0, baseCurrency),
+		Province: shippingProvince,
+	}
+	for i, item := range cart.CartItems {
+		if item.Currency != baseCurrency {
+			tx.Rollback()
+			return nil, fmt.Errorf("%w: %s กับ %s", entities.ErrCurrencyMismatch, baseCurrency, item.Currency)
+		}
+
+		promotionLines[i] = entities.PromotionLine{
+			ProductID:  item.ProductID,
+			CategoryID: item.Product.CategoryID,
+			Amount:     entities.NewMoney(item.Price, item.Currency).Mul(item.Quantity),
+		}
+		shippingInput.Subtotal.Amount += promotionLines[i].Amount.Amount
+		shippingInput.Weight += item.Product.Weight * item.Quantity
+	}
+
+	// ตรวจคูปองอีกครั้งตอน checkout เพราะอาจหมดอายุหรือถูกใช้ครบไปแล้วหลังจากใส่ไว้ในตะกร้า
+	couponCode := strings.ToUpper(strings.TrimSpace(req.CouponCode))
+	if couponCode == "" {
+		couponCode = cart.CouponCode
+	}
+	var promotion *entities.Promotion
+	var discount *entities.PromotionResult
+	if couponCode != "" {
+		promotion, discount, err = applyPromotion(tx, couponCode, userID, promotionLines)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		shippingInput.Subtotal.Amount -= discount.Discount.Amount
+	}
+
+	// ค่าจัดส่งคิดในสกุลเงินของสินค้าจากยอดหลังหักส่วนลด แล้วจึงแปลงเป็นสกุลเงินที่ชำระ
+	shippingMethod, err := loadShippingMethod(tx, strings.ToLower(strings.TrimSpace(req.ShippingMethod)))
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	baseShippingFee, err := shippingMethod.Quote(shippingInput)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	shippingFee := baseShippingFee.Convert(currency, rate)
+
+	// แปลงราคาต่อชิ้นเป็นสกุลเงินที่ชำระก่อนแล้วจึงคูณจำนวน หักส่วนลดแล้วคิดภาษีต่อรายการ
+	// ยอดของคำสั่งซื้อจึงเท่ากับผลรวมของรายการบวกค่าจัดส่งเสมอ
+	lines := make([]orderLine, len(cart.CartItems))
+	subtotal := entities.NewMoney(0, currency)
+	taxTotal := entities.NewMoney(0, currency)
+	discountTotal := entities.NewMoney(0, currency)
+	totalPrice := entities.NewMoney(0, currency)
+	for i, item := range cart.CartItems {
+		price := entities.NewMoney(item.Price, item.Currency).Convert(currency, rate)
+		lineAmount := price.Mul(item.Quantity)
+
+		lineDiscount := entities.NewMoney(0, currency)
+		if discount != nil {
+			lineDiscount = discount.Allocations[i].Convert(currency, rate)
+			lineDiscount.Amount = min(lineDiscount.Amount, lineAmount.Amount)
+		}
+		lineAmount.Amount -= lineDiscount.Amount
+
+		taxRule := effectiveTaxRule(&item.Product, taxRules)
+		lines[i] = orderLine{price: price, discount: lineDiscount, taxRule: taxRule, amounts: taxRule.Calculate(lineAmount)}
+
+		subtotal.Amount += lines[i].amounts.Subtotal.Amount
+		taxTotal.Amount += lines[i].amounts.Tax.Amount
+		discountTotal.Amount += lineDiscount.Amount
+		totalPrice.Amount += lines[i].amounts.Total.Amount
+	}
+
+	// คูปองส่งฟรียกเว้นค่าจัดส่งทั้งหมด และนับเป็นส่วนลดของคำสั่งซื้อ
+	if discount != nil && discount.FreeShipping {
+		discountTotal.Amount += shippingFee.Amount
+		shippingFee.Amount = 0
+	}
+
+	// ค่าจัดส่งเป็นค่าบริการที่ต้องเสีย VAT จึงคิดภาษีด้วยกฎเริ่มต้นแบบราคารวมภาษี
+	shipping := entities.DefaultTaxRule.Calculate(shippingFee)
+	subtotal.Amount += shipping.Subtotal.Amount
+	taxTotal.Amount += shipping.Tax.Amount
+	totalPrice.Amount += shipping.Total.Amount
+
+	// สร้างคำสั่งซื้อ พร้อมเก็บอัตราแลกเปลี่ยนที่ใช้ไว้ การแก้อัตราภายหลังจึงไม่กระทบคำสั่งซื้อนี้
 	order := &models.Order{
-		UserID:          userID,
-		TotalPrice:      totalPrice,
-		Status:          "pending",
-		PaymentMethod:   req.PaymentMethod,
-		PaymentStatus:   "pending",
-		ShippingMethod:  req.ShippingMethod,
-		ShippingStatus:  "pending",
-		ShippingAddress: req.ShippingAddress,
-		Notes:           req.Notes,
+		UserID:                userID,
+		Subtotal:              subtotal.Amount,
+		TaxTotal:              taxTotal.Amount,
+		DiscountTotal:         discountTotal.Amount,
+		ShippingFee:           shipping.Total.Amount,
+		TotalPrice:            totalPrice.Amount,
+		Currency:              totalPrice.Currency,
+		BaseCurrency:          baseCurrency,
+		ExchangeRate:          rate,
+		Status:                string(entities.OrderStatusPending),
+		PaymentMethod:         req.PaymentMethod,
+		PaymentStatus:         string(entities.PaymentStatusPending),
+		ShippingMethod:        shippingMethod.Code,
+		ShippingMethodID:      &shippingMethod.ID,
+		ShippingStatus:        string(entities.ShippingStatusPending),
+		ShippingAddress:       shippingAddress,
+		ShippingAddressDetail: addressDetail,
+		Notes:                 req.Notes,
 	}
 
 	if err := tx.Create(order).Error; err != nil {
@@ -58,32 +198,66 @@ func (r *orderRepository) Create(ctx context.Context, userID uuid.UUID, req *ent
 		return nil, err
 	}
 
+	// บันทึกจุดเริ่มต้นของ timeline
+	if err := recordOrderStatusEvent(tx, order.ID, entities.StatusAxisOrder, "", order.Status, userID, ""); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// จองสต็อก ถ้ามีสินค้าไหนไม่พอจะยกเลิกทั้งคำสั่งซื้อ
+	if err := reserveStock(tx, cart.CartItems, order.ID, userID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	// สร้างรายการสินค้าในคำสั่งซื้อ
-	for _, cartItem := range cart.CartItems {
+	for i, cartItem := range cart.CartItems {
+		line := lines[i]
 		orderItem := &models.OrderItem{
-			OrderID:   order.ID,
-			ProductID: cartItem.ProductID,
-			Quantity:  cartItem.Quantity,
-			Price:     cartItem.Price,
+			OrderID:      order.ID,
+			ProductID:    cartItem.ProductID,
+			VariantID:    cartItem.VariantID,
+			Quantity:     cartItem.Quantity,
+			Price:        line.price.Amount,
+			Discount:     line.discount.Amount,
+			Subtotal:     line.amounts.Subtotal.Amount,
+			Tax:          line.amounts.Tax.Amount,
+			Total:        line.amounts.Total.Amount,
+			TaxRate:      line.taxRule.Rate,
+			TaxInclusive: line.taxRule.Inclusive,
+			TaxExempt:    line.taxRule.Exempt,
+		}
+
+		// เก็บ SKU และชื่อ variant ไว้ การแก้ไขหรือลบ variant ภายหลังจึงไม่กระทบคำสั่งซื้อนี้
+		if cartItem.Variant != nil {
+			variant := variantModelToEntity(cartItem.Variant, &cartItem.Product)
+			orderItem.SKU = variant.SKU
+			orderItem.VariantName = variant.Name
 		}
 
 		if err := tx.Create(orderItem).Error; err != nil {
 			tx.Rollback()
 			return nil, err
 		}
+	}
 
-		// อัพเดทสต็อกสินค้า
-		if err := tx.Model(&models.Product{}).Where("id = ?", cartItem.ProductID).Update("stock", gorm.Expr("stock - ?", cartItem.Quantity)).Error; err != nil {
+	// บันทึกการใช้คูปองและส่วนลดของคำสั่งซื้อ
+	if promotion != nil {
+		if err := redeemPromotion(tx, promotion, order.ID, userID, discountTotal); err != nil {
 			tx.Rollback()
 			return nil, err
 		}
 	}
 
-	// ล้างตะกร้าสินค้า
+	// ล้างตะกร้าสินค้าพร้อมคูปองที่ใช้ไปแล้ว
 	if err := tx.Where("cart_id = ?", cart.ID).Delete(&models.CartItem{}).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
+	if err := tx.Model(&cart).Update("coupon_code", "").Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
@@ -92,9 +266,149 @@ func (r *orderRepository) Create(ctx context.Context, userID uuid.UUID, req *ent
 	return r.GetByID(ctx, order.ID)
 }
 
+// orderLine ราคาต่อชิ้นในสกุลเงินที่ชำระ ส่วนลด กฎภาษีที่ใช้ และยอดภาษีของสินค้าหนึ่งรายการตอน checkout
+type orderLine struct {
+	price    entities.Money
+	discount entities.Money
+	taxRule  entities.TaxRule
+	amounts  entities.TaxBreakdown
+}
+
+// applyPromotion ล็อคโปรโมชันตามรหัสคูปองแล้วคำนวณส่วนลดของ lines (สกุลเงินของสินค้า)
+// การล็อคทำให้ checkout ที่เกิดพร้อมกันนับจำนวนครั้งที่ใช้ไปแล้วได้ถูกต้อง และใช้คูปองเกินจำนวนไม่ได้
+func applyPromotion(tx *gorm.DB, code string, userID uuid.UUID, lines []entities.PromotionLine) (*entities.Promotion, *entities.PromotionResult, error) {
+	var promotionModel models.Promotion
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", code).First(&promotionModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, entities.ErrPromotionNotFound
+		}
+		return nil, nil, err
+	}
+	if err := tx.Model(&promotionModel).Association("Products").Find(&promotionModel.Products); err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Model(&promotionModel).Association("Categories").Find(&promotionModel.Categories); err != nil {
+		return nil, nil, err
+	}
+	promotion := promotionModelToEntity(&promotionModel)
+
+	totalUses, userUses, err := countRedemptions(tx, promotion.ID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := promotion.CheckAvailability(time.Now(), totalUses, userUses); err != nil {
+		return nil, nil, err
+	}
+
+	result, err := promotion.Apply(lines)
+	if err != nil {
+		return nil, nil, err
+	}
+	return promotion, result, nil
+}
+
+// redeemPromotion บันทึกการใช้คูปองหนึ่งครั้ง และคัดลอกข้อมูลส่วนลดไว้กับคำสั่งซื้อ
+func redeemPromotion(tx *gorm.DB, promotion *entities.Promotion, orderID, userID uuid.UUID, amount entities.Money) error {
+	if err := tx.Create(&models.PromotionRedemption{
+		PromotionID: promotion.ID,
+		UserID:      userID,
+		OrderID:     orderID,
+	}).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&models.OrderDiscount{
+		OrderID:     orderID,
+		PromotionID: &promotion.ID,
+		Code:        promotion.Code,
+		Type:        string(promotion.Type),
+		Description: promotion.Name,
+		Amount:      amount.Amount,
+	}).Error
+}
+
+// reserveStock ตัดสต็อกของสินค้าในตะกร้าแบบมีเงื่อนไข (stock >= จำนวนที่สั่ง) และบันทึกเป็นการขายใน ledger
+// ทำให้ checkout ที่เกิดพร้อมกันไม่สามารถตัดสต็อกจนติดลบได้
+// ตัดตามลำดับ product ID และ variant ID เพื่อไม่ให้ transaction ที่ล็อคสินค้าหลายตัวเกิด deadlock กัน
+func reserveStock(tx *gorm.DB, items []models.CartItem, orderID, actorID uuid.UUID) error {
+	sorted := make([]models.CartItem, len(items))
+	copy(sorted, items)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].ProductID != sorted[j].ProductID {
+			return sorted[i].ProductID.String() < sorted[j].ProductID.String()
+		}
+		return variantKey(sorted[i].VariantID) < variantKey(sorted[j].VariantID)
+	})
+
+	var outOfStock []entities.OutOfStockItem
+	for _, item := range sorted {
+		if err := requireVariant(tx, item.ProductID, item.VariantID); err != nil {
+			return err
+		}
+		movement, err := moveStock(tx, item.ProductID, item.VariantID, -item.Quantity, entities.InventoryReasonSale, &orderID, actorID, "")
+		if err != nil {
+			return err
+		}
+		if movement != nil {
+			continue
+		}
+
+		// สต็อกไม่พอ อ่านจำนวนคงเหลือเพื่อแจ้งผู้ใช้
+		var available int
+		stockQuery := tx.Model(&models.Product{}).Select("stock").Where("id = ?", item.ProductID)
+		name := item.Product.Name
+		if item.VariantID != nil {
+			stockQuery = tx.Model(&models.ProductVariant{}).Select("stock").Where("id = ?", *item.VariantID)
+			if item.Variant != nil {
+				name = fmt.Sprintf("%s (%s)", name, variantModelToEntity(item.Variant, &item.Product).Name)
+			}
+		}
+		if err := stockQuery.Scan(&available).Error; err != nil {
+			return err
+		}
+
+		outOfStock = append(outOfStock, entities.OutOfStockItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Name:      name,
+			Requested: item.Quantity,
+			Available: available,
+		})
+	}
+
+	if len(outOfStock) > 0 {
+		return &entities.OutOfStockError{Items: outOfStock}
+	}
+	return nil
+}
+
+// variantKey คีย์สำหรับเรียงลำดับ variant ที่อาจเป็น nil
+func variantKey(variantID *uuid.UUID) string {
+	if variantID == nil {
+		return ""
+	}
+	return variantID.String()
+}
+
 func (r *orderRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Order, error) {
 	var order models.Order
-	if err := r.db.WithContext(ctx).Preload("User").Preload("OrderItems.Product").Preload("Transactions").First(&order, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("OrderItems.Product").
+		Preload("Discounts").
+		Preload("Transactions").
+		Preload("StatusEvents", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("StatusEvents.Actor").
+		Preload("Shipments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("shipped_at ASC")
+		}).
+		Preload("Shipments.Items").
+		Preload("Shipments.Checkpoints", func(db *gorm.DB) *gorm.DB {
+			return db.Order("occurred_at ASC")
+		}).
+		First(&order, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 
@@ -123,17 +437,43 @@ func (r *orderRepository) GetByUserID(ctx context.Context, userID uuid.UUID, pag
 	return result, int(total), nil
 }
 
-func (r *orderRepository) GetAll(ctx context.Context, page, limit int) ([]*entities.Order, int, error) {
+func (r *orderRepository) GetAll(ctx context.Context, filter *entities.OrderFilter) ([]*entities.Order, int, error) {
 	var orders []models.Order
 	var total int64
 
-	offset := (page - 1) * limit
+	query := r.db.WithContext(ctx).Model(&models.Order{})
 
-	if err := r.db.WithContext(ctx).Model(&models.Order{}).Count(&total).Error; err != nil {
+	// กรองตามสถานะต่างๆ
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	if filter.PaymentStatus != "" {
+		query = query.Where("payment_status = ?", string(filter.PaymentStatus))
+	}
+	if filter.ShippingStatus != "" {
+		query = query.Where("shipping_status = ?", string(filter.ShippingStatus))
+	}
+
+	// กรองตามผู้ใช้
+	if filter.UserID != uuid.Nil {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+
+	// กรองตามช่วงวันที่สร้างคำสั่งซื้อ
+	if !filter.DateFrom.IsZero() {
+		query = query.Where("created_at >= ?", filter.DateFrom)
+	}
+	if !filter.DateTo.IsZero() {
+		query = query.Where("created_at < ?", filter.DateTo)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if err := r.db.WithContext(ctx).Preload("User").Preload("OrderItems.Product").Order("created_at DESC").Offset(offset).Limit(limit).Find(&orders).Error; err != nil {
+	offset := (filter.Page - 1) * filter.Limit
+
+	if err := query.Preload("User").Preload("OrderItems.Product").Order("created_at DESC").Offset(offset).Limit(filter.Limit).Find(&orders).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -145,51 +485,93 @@ func (r *orderRepository) GetAll(ctx context.Context, page, limit int) ([]*entit
 	return result, int(total), nil
 }
 
-func (r *orderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
-	return r.db.WithContext(ctx).Model(&models.Order{}).Where("id = ?", id).Update("status", status).Error
-}
+func (r *orderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status entities.OrderStatus, actorID uuid.UUID, note string) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	if err := changeOrderStatus(tx, id, entities.StatusAxisOrder, string(status), nil, actorID, note); err != nil {
+		tx.Rollback()
+		return err
+	}
 
-func (r *orderRepository) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, paymentStatus string) error {
-	return r.db.WithContext(ctx).Model(&models.Order{}).Where("id = ?", id).Update("payment_status", paymentStatus).Error
+	return tx.Commit().Error
 }
 
-func (r *orderRepository) UpdateShippingStatus(ctx context.Context, id uuid.UUID, shippingStatus, trackingNumber string) error {
-	updates := map[string]interface{}{
-		"shipping_status": shippingStatus,
+func (r *orderRepository) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, paymentStatus entities.PaymentStatus, actorID uuid.UUID, note string) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	if err := changeOrderStatus(tx, id, entities.StatusAxisPayment, string(paymentStatus), nil, actorID, note); err != nil {
+		tx.Rollback()
+		return err
 	}
+
+	return tx.Commit().Error
+}
+
+func (r *orderRepository) UpdateShippingStatus(ctx context.Context, id uuid.UUID, shippingStatus entities.ShippingStatus, trackingNumber string, actorID uuid.UUID, note string) error {
+	extra := map[string]interface{}{}
 	if trackingNumber != "" {
-		updates["tracking_number"] = trackingNumber
+		extra["tracking_number"] = trackingNumber
 	}
 
-	return r.db.WithContext(ctx).Model(&models.Order{}).Where("id = ?", id).Updates(updates).Error
+	tx := r.db.WithContext(ctx).Begin()
+
+	if err := changeOrderStatus(tx, id, entities.StatusAxisShipping, string(shippingStatus), extra, actorID, note); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
 }
 
-func (r *orderRepository) Cancel(ctx context.Context, id uuid.UUID) error {
+func (r *orderRepository) Cancel(ctx context.Context, id uuid.UUID, actorID uuid.UUID, note string) error {
 	tx := r.db.WithContext(ctx).Begin()
 
-	// ดึงข้อมูลคำสั่งซื้อ
+	// ดึงข้อมูลคำสั่งซื้อ (ล็อคแถวไว้กันการยกเลิกซ้ำพร้อมกัน)
 	var order models.Order
-	if err := tx.Preload("OrderItems").First(&order, "id = ?", id).Error; err != nil {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "id = ?", id).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
 
-	// ตรวจสอบสถานะ
-	if order.Status != "pending" {
+	// ตรวจการเปลี่ยนสถานะก่อนคืนสต็อก กันการคืนสต็อกซ้ำเมื่อยกเลิกพร้อมกัน
+	if err := checkOrderStatusChange(&order, entities.StatusAxisOrder, string(entities.OrderStatusCancelled)); err != nil {
 		tx.Rollback()
-		return errors.New("ไม่สามารถยกเลิกคำสั่งซื้อนี้ได้")
+		return err
 	}
 
-	// คืนสต็อกสินค้า
-	for _, item := range order.OrderItems {
-		if err := tx.Model(&models.Product{}).Where("id = ?", item.ProductID).Update("stock", gorm.Expr("stock + ?", item.Quantity)).Error; err != nil {
+	var items []models.OrderItem
+	if err := tx.Where("order_id = ?", id).Find(&items).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// คำสั่งซื้อที่ชำระแล้วยกเลิกได้หลังคืนเงินครบ สินค้าที่คืนสต็อกไปพร้อมการคืนเงินแล้วจึงไม่คืนซ้ำ
+	restocked, err := refundRestockedQuantities(tx, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// คืนสต็อกสินค้าพร้อมบันทึกลง ledger
+	for _, item := range items {
+		quantity := item.Quantity - restocked[item.ID]
+		if quantity <= 0 {
+			continue
+		}
+		if err := restock(tx, item.ProductID, item.VariantID, quantity, entities.InventoryReasonCancellation, &id, actorID, note); err != nil {
 			tx.Rollback()
 			return err
 		}
 	}
 
-	// อัพเดทสถานะ
-	if err := tx.Model(&order).Update("status", "cancelled").Error; err != nil {
+	// คืนสิทธิ์การใช้คูปอง ส่วน OrderDiscount ยังเก็บไว้เป็นประวัติของคำสั่งซื้อ
+	if err := tx.Unscoped().Where("order_id = ?", id).Delete(&models.PromotionRedemption{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// อัพเดทสถานะพร้อมบันทึกประวัติ
+	if err := changeOrderStatus(tx, id, entities.StatusAxisOrder, string(entities.OrderStatusCancelled), nil, actorID, note); err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -197,16 +579,128 @@ func (r *orderRepository) Cancel(ctx context.Context, id uuid.UUID) error {
 	return tx.Commit().Error
 }
 
+// refundRestockedQuantities คืนจำนวนสินค้าของแต่ละ OrderItem ที่คืนสต็อกไปแล้วพร้อมการคืนเงินที่สำเร็จ
+func refundRestockedQuantities(tx *gorm.DB, orderID uuid.UUID) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		OrderItemID uuid.UUID
+		Quantity    int
+	}
+	if err := tx.Model(&models.RefundItem{}).
+		Select("refund_items.order_item_id, COALESCE(SUM(refund_items.quantity), 0) AS quantity").
+		Joins("JOIN refunds ON refunds.id = refund_items.refund_id AND refunds.deleted_at IS NULL").
+		Where("refunds.order_id = ? AND refunds.restock AND refunds.status = ?", orderID, string(entities.RefundStatusCompleted)).
+		Group("refund_items.order_item_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	quantities := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		quantities[row.OrderItemID] = row.Quantity
+	}
+	return quantities, nil
+}
+
+// changeOrderStatus เปลี่ยนสถานะหนึ่งแกนของคำสั่งซื้อ และบันทึก OrderStatusEvent ใน transaction เดียวกัน
+// ชื่อแกน (axis) ตรงกับชื่อคอลัมน์ใน orders ส่วน extra คือคอลัมน์อื่นที่ต้องอัพเดทไปพร้อมกัน
+// การเปลี่ยนสถานะถูกตรวจกับแถวที่ล็อคไว้แล้ว ถ้าไม่อนุญาตจะคืน *entities.StatusTransitionError โดยยังไม่ได้เขียนอะไร
+// ผู้เรียกจึงเลือกได้ว่าจะยกเลิกทั้ง transaction หรือบันทึกส่วนอื่นต่อไปโดยไม่เปลี่ยนสถานะคำสั่งซื้อ
+// ต้องเรียกภายใน transaction เสมอ
+func changeOrderStatus(tx *gorm.DB, orderID uuid.UUID, axis entities.StatusAxis, to string, extra map[string]interface{}, actorID uuid.UUID, note string) error {
+	// ล็อคแถวของคำสั่งซื้อเพื่ออ่านค่าเดิมก่อนเปลี่ยน
+	var order models.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "id = ?", orderID).Error; err != nil {
+		return err
+	}
+
+	// สถานะเดิมซ้ำไม่ถือเป็นการเปลี่ยนสถานะ จึงอัพเดทได้เฉพาะ extra โดยไม่บันทึกประวัติ
+	from := orderAxisStatus(&order, axis)
+	if from != to {
+		if err := checkOrderStatusChange(&order, axis, to); err != nil {
+			return err
+		}
+	}
+
+	updates := map[string]interface{}{
+		string(axis): to,
+	}
+	for column, value := range extra {
+		updates[column] = value
+	}
+
+	if err := tx.Model(&models.Order{}).Where("id = ?", orderID).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	if from == to {
+		return nil
+	}
+
+	return recordOrderStatusEvent(tx, orderID, axis, from, to, actorID, note)
+}
+
+// checkOrderStatusChange ตรวจการเปลี่ยนสถานะแกน axis ของคำสั่งซื้อด้วยตารางการเปลี่ยนสถานะใน entities
+func checkOrderStatusChange(order *models.Order, axis entities.StatusAxis, to string) error {
+	current := &entities.Order{
+		Status:         entities.OrderStatus(order.Status),
+		PaymentStatus:  entities.PaymentStatus(order.PaymentStatus),
+		ShippingStatus: entities.ShippingStatus(order.ShippingStatus),
+	}
+
+	switch axis {
+	case entities.StatusAxisOrder:
+		return entities.CheckOrderStatusTransition(current, entities.OrderStatus(to))
+	case entities.StatusAxisPayment:
+		return entities.CheckPaymentStatusTransition(current, entities.PaymentStatus(to))
+	case entities.StatusAxisShipping:
+		return entities.CheckShippingStatusTransition(current, entities.ShippingStatus(to))
+	}
+	return fmt.Errorf("ไม่รู้จักสถานะแกน %q", axis)
+}
+
+// orderAxisStatus คืนสถานะปัจจุบันของแกน axis
+func orderAxisStatus(order *models.Order, axis entities.StatusAxis) string {
+	switch axis {
+	case entities.StatusAxisPayment:
+		return order.PaymentStatus
+	case entities.StatusAxisShipping:
+		return order.ShippingStatus
+	}
+	return order.Status
+}
+
+// recordOrderStatusEvent บันทึกประวัติการเปลี่ยนสถานะ actorID เป็น uuid.Nil ได้ถ้าระบบเป็นผู้เปลี่ยน
+func recordOrderStatusEvent(tx *gorm.DB, orderID uuid.UUID, axis entities.StatusAxis, from, to string, actorID uuid.UUID, note string) error {
+	event := &models.OrderStatusEvent{
+		OrderID:    orderID,
+		Axis:       string(axis),
+		FromStatus: from,
+		ToStatus:   to,
+		Note:       note,
+	}
+	if actorID != uuid.Nil {
+		event.ActorID = &actorID
+	}
+
+	return tx.Create(event).Error
+}
+
 func (r *orderRepository) modelToEntity(order *models.Order) *entities.Order {
 	orderEntity := &entities.Order{
 		ID:              order.ID,
 		UserID:          order.UserID,
-		TotalPrice:      order.TotalPrice,
-		Status:          order.Status,
+		Subtotal:        entities.NewMoney(order.Subtotal, order.Currency),
+		TaxTotal:        entities.NewMoney(order.TaxTotal, order.Currency),
+		DiscountTotal:   entities.NewMoney(order.DiscountTotal, order.Currency),
+		ShippingFee:     entities.NewMoney(order.ShippingFee, order.Currency),
+		TotalPrice:      entities.NewMoney(order.TotalPrice, order.Currency),
+		BaseCurrency:    order.BaseCurrency,
+		ExchangeRate:    order.ExchangeRate,
+		Status:          entities.OrderStatus(order.Status),
 		PaymentMethod:   order.PaymentMethod,
-		PaymentStatus:   order.PaymentStatus,
+		PaymentStatus:   entities.PaymentStatus(order.PaymentStatus),
 		ShippingMethod:  order.ShippingMethod,
-		ShippingStatus:  order.ShippingStatus,
+		ShippingStatus:  entities.ShippingStatus(order.ShippingStatus),
 		ShippingAddress: order.ShippingAddress,
 		TrackingNumber:  order.TrackingNumber,
 		Notes:           order.Notes,
@@ -231,13 +725,23 @@ func (r *orderRepository) modelToEntity(order *models.Order) *entities.Order {
 
 	for _, item := range order.OrderItems {
 		orderItem := entities.OrderItem{
-			ID:        item.ID,
-			OrderID:   item.OrderID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-			CreatedAt: item.CreatedAt,
-			UpdatedAt: item.UpdatedAt,
+			ID:           item.ID,
+			OrderID:      item.OrderID,
+			ProductID:    item.ProductID,
+			VariantID:    item.VariantID,
+			SKU:          item.SKU,
+			VariantName:  item.VariantName,
+			Quantity:     item.Quantity,
+			Price:        entities.NewMoney(item.Price, order.Currency),
+			Discount:     entities.NewMoney(item.Discount, order.Currency),
+			Subtotal:     entities.NewMoney(item.Subtotal, order.Currency),
+			Tax:          entities.NewMoney(item.Tax, order.Currency),
+			Total:        entities.NewMoney(item.Total, order.Currency),
+			TaxRate:      item.TaxRate,
+			TaxInclusive: item.TaxInclusive,
+			TaxExempt:    item.TaxExempt,
+			CreatedAt:    item.CreatedAt,
+			UpdatedAt:    item.UpdatedAt,
 		}
 
 		if item.Product.ID != uuid.Nil {
@@ -245,8 +749,9 @@ func (r *orderRepository) modelToEntity(order *models.Order) *entities.Order {
 				ID:          item.Product.ID,
 				Name:        item.Product.Name,
 				Description: item.Product.Description,
-				Price:       item.Product.Price,
+				Price:       entities.NewMoney(item.Product.Price, item.Product.Currency),
 				Stock:       item.Product.Stock,
+				Weight:      item.Product.Weight,
 				Image:       item.Product.Image,
 				CategoryID:  item.Product.CategoryID,
 				CreatedAt:   item.Product.CreatedAt,
@@ -257,20 +762,67 @@ func (r *orderRepository) modelToEntity(order *models.Order) *entities.Order {
 		orderEntity.OrderItems = append(orderEntity.OrderItems, orderItem)
 	}
 
+	if order.ShippingAddressDetail.Line1 != "" {
+		detail := postalAddressEntity(order.ShippingAddressDetail)
+		orderEntity.ShippingAddressDetail = &detail
+	}
+
+	for _, shipment := range order.Shipments {
+		orderEntity.Shipments = append(orderEntity.Shipments, *shipmentModelToEntity(&shipment))
+	}
+
+	for _, discount := range order.Discounts {
+		orderEntity.Discounts = append(orderEntity.Discounts, entities.OrderDiscount{
+			ID:          discount.ID,
+			PromotionID: discount.PromotionID,
+			Code:        discount.Code,
+			Type:        entities.PromotionType(discount.Type),
+			Description: discount.Description,
+			Amount:      entities.NewMoney(discount.Amount, order.Currency),
+		})
+	}
+
 	for _, transaction := range order.Transactions {
 		transactionEntity := entities.Transaction{
-			ID:            transaction.ID,
-			OrderID:       transaction.OrderID,
-			Amount:        transaction.Amount,
-			PaymentMethod: transaction.PaymentMethod,
-			Status:        transaction.Status,
-			TransactionID: transaction.TransactionID,
-			PaymentData:   transaction.PaymentData,
-			CreatedAt:     transaction.CreatedAt,
-			UpdatedAt:     transaction.UpdatedAt,
+			ID:             transaction.ID,
+			OrderID:        transaction.OrderID,
+			Amount:         entities.NewMoney(transaction.Amount, transaction.Currency),
+			PaymentMethod:  transaction.PaymentMethod,
+			Status:         entities.TransactionStatus(transaction.Status),
+			TransactionID:  transaction.TransactionID,
+			ProviderRef:    transaction.ProviderRef,
+			PaymentPayload: transaction.PaymentPayload,
+			PaymentData:    transaction.PaymentData,
+			CreatedAt:      transaction.CreatedAt,
+			UpdatedAt:      transaction.UpdatedAt,
 		}
 		orderEntity.Transactions = append(orderEntity.Transactions, transactionEntity)
 	}
 
+	for _, event := range order.StatusEvents {
+		eventEntity := entities.OrderStatusEvent{
+			ID:         event.ID,
+			OrderID:    event.OrderID,
+			Axis:       entities.StatusAxis(event.Axis),
+			FromStatus: event.FromStatus,
+			ToStatus:   event.ToStatus,
+			ActorID:    event.ActorID,
+			Note:       event.Note,
+			CreatedAt:  event.CreatedAt,
+		}
+
+		if event.Actor != nil && event.Actor.ID != uuid.Nil {
+			eventEntity.Actor = &entities.User{
+				ID:        event.Actor.ID,
+				Email:     event.Actor.Email,
+				FirstName: event.Actor.FirstName,
+				LastName:  event.Actor.LastName,
+				RoleID:    event.Actor.RoleID,
+			}
+		}
+
+		orderEntity.Timeline = append(orderEntity.Timeline, eventEntity)
+	}
+
 	return orderEntity
 }
@@ -0,0 +1,132 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/notifiers"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/config"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/services"
+	"github.com/google/uuid"
+)
+
+func TestCreateOrder_ConcurrentCheckoutDoesNotOversell(t *testing.T) {
+	d := newTestData(t)
+	db := d.db
+
+	const (
+		stock    = 3
+		shoppers = 10
+	)
+
+	product := d.product(models.Product{Name: "Low stock product", Price: 100, Stock: stock})
+	shippingMethod := d.shippingMethod()
+
+	// ผู้ใช้แต่ละคนมีตะกร้าที่ใส่สินค้าตัวเดียวกัน 1 ชิ้น
+	userIDs := make([]uuid.UUID, shoppers)
+	for i := range userIDs {
+		user := d.user()
+		cart := models.Cart{UserID: user.ID}
+		d.create(&cart)
+		d.create(&models.CartItem{CartID: cart.ID, ProductID: product.ID, Quantity: 1, Price: product.Price})
+		userIDs[i] = user.ID
+	}
+
+	// ไม่ได้ Start stock monitor เพราะการทดสอบนี้ไม่ได้ตรวจการแจ้งเตือน
+	stockMonitor := services.NewStockMonitor(repositories.NewProductRepository(db), notifiers.NewLogNotifier())
+	orderService := services.NewOrderService(repositories.NewOrderRepository(db), stockMonitor)
+
+	var (
+		wg         sync.WaitGroup
+		start      = make(chan struct{})
+		errs       = make([]error, shoppers)
+		ctx        = context.Background()
+		createReq  = &entities.CreateOrderRequest{PaymentMethod: "bank_transfer", ShippingMethod: shippingMethod.Code, ShippingAddress: "Bangkok"}
+		succeeded  int
+		outOfStock int
+	)
+	for i, userID := range userIDs {
+		wg.Add(1)
+		go func(i int, userID uuid.UUID) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = orderService.CreateOrder(ctx, userID, createReq)
+		}(i, userID)
+	}
+	close(start)
+	wg.Wait()
+
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, entities.ErrOutOfStock):
+			outOfStock++
+			var stockErr *entities.OutOfStockError
+			if !errors.As(err, &stockErr) || len(stockErr.Items) != 1 || stockErr.Items[0].ProductID != product.ID {
+				t.Errorf("expected out-of-stock error naming the product, got %v", err)
+			}
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	if succeeded != stock {
+		t.Errorf("expected %d successful orders, got %d", stock, succeeded)
+	}
+	if outOfStock != shoppers-stock {
+		t.Errorf("expected %d out-of-stock errors, got %d", shoppers-stock, outOfStock)
+	}
+
+	var remaining int
+	if err := db.Model(&models.Product{}).Select("stock").Where("id = ?", product.ID).Scan(&remaining).Error; err != nil {
+		t.Fatal(err)
+	}
+	if remaining != 0 {
+		t.Errorf("expected stock to be 0, got %d", remaining)
+	}
+
+	// การขายทุกครั้งต้องถูกบันทึกลง inventory ledger
+	var sold int64
+	if err := db.Model(&models.InventoryMovement{}).Where("product_id = ? AND reason = ?", product.ID, entities.InventoryReasonSale).Count(&sold).Error; err != nil {
+		t.Fatal(err)
+	}
+	if sold != stock {
+		t.Errorf("expected %d sale movements, got %d", stock, sold)
+	}
+}
+
+func TestMigrate_KeepsFullyDiscountedOrderLines(t *testing.T) {
+	d := newTestData(t)
+	product := d.product(models.Product{Name: "Free sample", Price: 5000, Stock: 5})
+
+	// คูปองส่วนลด 100% ทำให้รายการมียอดรวมเป็นศูนย์ได้จริง
+	order := models.Order{
+		UserID:        d.user().ID,
+		Subtotal:      0,
+		DiscountTotal: 5000,
+		TotalPrice:    0,
+		Currency:      entities.DefaultCurrency,
+		PaymentMethod: entities.PaymentMethodBankTransfer,
+		OrderItems:    []models.OrderItem{{ProductID: product.ID, Quantity: 1, Price: 5000, Discount: 5000}},
+	}
+	d.create(&order)
+
+	// เริ่มแอปใหม่ต้องไม่เติมส่วนลดกลับเข้าไปในคำสั่งซื้อเก่า
+	if err := config.Migrate(d.db); err != nil {
+		t.Fatal(err)
+	}
+
+	var item models.OrderItem
+	if err := d.db.First(&item, "order_id = ?", order.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if item.Subtotal != 0 || item.Total != 0 {
+		t.Errorf("expected the discounted line to keep a zero total, got subtotal %d total %d", item.Subtotal, item.Total)
+	}
+}
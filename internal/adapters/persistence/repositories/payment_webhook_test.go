@@ -0,0 +1,272 @@
+package repositories_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	coreServices "github.com/Sup-Film/fiber-ecommerce-api/internal/core/services"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const testWebhookSecret = "test-webhook-secret"
+
+// webhookFixture คำสั่งซื้อหนึ่งรายการที่มีธุรกรรมรอชำระเงิน สำหรับทดสอบ webhook
+type webhookFixture struct {
+	data        *testData
+	db          *gorm.DB
+	service     services.PaymentService
+	order       models.Order
+	transaction models.Transaction
+}
+
+func newWebhookFixture(t *testing.T) *webhookFixture {
+	t.Helper()
+	d := newTestData(t)
+	db := d.db
+
+	user := d.user()
+	order := models.Order{UserID: user.ID, TotalPrice: 25000, Currency: entities.DefaultCurrency, PaymentMethod: entities.PaymentMethodBankTransfer}
+	d.create(&order)
+
+	service := coreServices.NewPaymentService(repositories.NewTransactionRepository(db), nil, map[string]entities.PaymentWebhookProvider{
+		"fake": {Secret: testWebhookSecret, PaymentMethods: []string{entities.PaymentMethodBankTransfer}},
+	})
+	f := &webhookFixture{data: d, db: db, service: service, order: order}
+	f.transaction = f.newTransaction()
+	return f
+}
+
+// newTransaction สร้างธุรกรรมที่รอชำระเงินของคำสั่งซื้อใน fixture
+func (f *webhookFixture) newTransaction() models.Transaction {
+	transaction := models.Transaction{
+		OrderID:       f.order.ID,
+		Amount:        f.order.TotalPrice,
+		Currency:      f.order.Currency,
+		PaymentMethod: f.order.PaymentMethod,
+		Status:        string(entities.TransactionStatusPending),
+		TransactionID: "TXN_TEST_" + uuid.NewString()[:8],
+	}
+	f.data.create(&transaction)
+	return transaction
+}
+
+// payload สร้าง body ของ webhook event สำหรับธุรกรรมหลักของ fixture
+func (f *webhookFixture) payload(t *testing.T, eventID string, status entities.GatewayStatus) []byte {
+	t.Helper()
+	return transactionPayload(t, f.transaction, eventID, status)
+}
+
+// transactionPayload สร้าง body ของ webhook event สำหรับธุรกรรมที่ระบุ
+func transactionPayload(t *testing.T, transaction models.Transaction, eventID string, status entities.GatewayStatus) []byte {
+	t.Helper()
+
+	body, err := json.Marshal(entities.PaymentWebhookEvent{
+		EventID:    eventID,
+		Reference:  transaction.TransactionID,
+		Status:     status,
+		Amount:     entities.NewMoney(transaction.Amount, transaction.Currency),
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+// assertState ตรวจสถานะธุรกรรม สถานะการชำระเงินของคำสั่งซื้อ และจำนวน event ที่บันทึกไว้
+func (f *webhookFixture) assertState(t *testing.T, status entities.TransactionStatus, paymentStatus entities.PaymentStatus, events, applied int64) {
+	t.Helper()
+
+	var transaction models.Transaction
+	if err := f.db.First(&transaction, "id = ?", f.transaction.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if transaction.Status != string(status) {
+		t.Errorf("expected transaction status %q, got %q", status, transaction.Status)
+	}
+
+	var order models.Order
+	if err := f.db.First(&order, "id = ?", f.order.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if order.PaymentStatus != string(paymentStatus) {
+		t.Errorf("expected order payment status %q, got %q", paymentStatus, order.PaymentStatus)
+	}
+
+	var total, appliedTotal int64
+	f.db.Model(&models.PaymentEvent{}).Where("transaction_id = ?", f.transaction.ID).Count(&total)
+	f.db.Model(&models.PaymentEvent{}).Where("transaction_id = ? AND applied", f.transaction.ID).Count(&appliedTotal)
+	if total != events {
+		t.Errorf("expected %d stored events, got %d", events, total)
+	}
+	if appliedTotal != applied {
+		t.Errorf("expected %d applied events, got %d", applied, appliedTotal)
+	}
+}
+
+func sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestHandleWebhook_DuplicateEventIsNoop(t *testing.T) {
+	f := newWebhookFixture(t)
+	ctx := context.Background()
+
+	body := f.payload(t, "evt_"+uuid.NewString(), entities.GatewayStatusCaptured)
+	signature := sign(testWebhookSecret, body)
+
+	// gateway ส่ง event เดิมซ้ำหลายครั้งพร้อมกัน ต้องถูกประมวลผลเพียงครั้งเดียว
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = f.service.HandleWebhook(ctx, "fake", body, signature)
+		}(i)
+	}
+	wg.Wait()
+
+	// ส่งซ้ำอีกครั้งหลังจากประมวลผลเสร็จแล้ว
+	errs = append(errs, f.service.HandleWebhook(ctx, "fake", body, signature))
+
+	for _, err := range errs {
+		if err != nil {
+			t.Errorf("expected redelivered event to be acknowledged, got %v", err)
+		}
+	}
+
+	f.assertState(t, entities.TransactionStatusCompleted, entities.PaymentStatusPaid, 1, 1)
+
+	var timeline int64
+	f.db.Model(&models.OrderStatusEvent{}).Where("order_id = ? AND axis = ?", f.order.ID, entities.StatusAxisPayment).Count(&timeline)
+	if timeline != 1 {
+		t.Errorf("expected 1 payment status change on the order timeline, got %d", timeline)
+	}
+}
+
+func TestHandleWebhook_OutOfOrderEventsDoNotRegress(t *testing.T) {
+	f := newWebhookFixture(t)
+	ctx := context.Background()
+
+	// event ที่เกิดก่อน (pending) และ event ที่ขัดแย้ง (failed) มาถึงหลัง captured
+	events := []struct {
+		id     string
+		status entities.GatewayStatus
+	}{
+		{"evt_3_" + uuid.NewString(), entities.GatewayStatusCaptured},
+		{"evt_1_" + uuid.NewString(), entities.GatewayStatusPending},
+		{"evt_2_" + uuid.NewString(), entities.GatewayStatusFailed},
+	}
+	for _, event := range events {
+		body := f.payload(t, event.id, event.status)
+		if err := f.service.HandleWebhook(ctx, "fake", body, sign(testWebhookSecret, body)); err != nil {
+			t.Fatalf("event %s: expected to be acknowledged, got %v", event.status, err)
+		}
+	}
+
+	f.assertState(t, entities.TransactionStatusCompleted, entities.PaymentStatusPaid, 3, 1)
+}
+
+func TestHandleWebhook_BadSignatureIsRejected(t *testing.T) {
+	f := newWebhookFixture(t)
+	ctx := context.Background()
+
+	body := f.payload(t, "evt_"+uuid.NewString(), entities.GatewayStatusCaptured)
+	tampered := f.payload(t, "evt_"+uuid.NewString(), entities.GatewayStatusCaptured)
+
+	cases := map[string]struct {
+		body      []byte
+		signature string
+	}{
+		"wrong secret":   {body, sign("another-secret", body)},
+		"tampered body":  {tampered, sign(testWebhookSecret, body)},
+		"missing":        {body, ""},
+		"not hex":        {body, "sha256=not-a-signature"},
+		"truncated hmac": {body, sign(testWebhookSecret, body)[:20]},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := f.service.HandleWebhook(ctx, "fake", tc.body, tc.signature)
+			if !errors.Is(err, entities.ErrInvalidWebhookSignature) {
+				t.Fatalf("expected ErrInvalidWebhookSignature, got %v", err)
+			}
+		})
+	}
+
+	f.assertState(t, entities.TransactionStatusPending, entities.PaymentStatusPending, 0, 0)
+}
+
+func TestUpdateStatus_SecondTransactionDoesNotOverridePaidOrder(t *testing.T) {
+	f := newWebhookFixture(t)
+	ctx := context.Background()
+	transactionRepo := repositories.NewTransactionRepository(f.db)
+
+	// ลูกค้ากดชำระเงินสองครั้ง ธุรกรรมแรกชำระสำเร็จ ส่วนธุรกรรมที่สองถูกยกเลิกและล้มเหลวทีหลัง
+	second := f.newTransaction()
+	third := f.newTransaction()
+
+	body := f.payload(t, "evt_"+uuid.NewString(), entities.GatewayStatusCaptured)
+	if err := f.service.HandleWebhook(ctx, "fake", body, sign(testWebhookSecret, body)); err != nil {
+		t.Fatal(err)
+	}
+	if err := transactionRepo.Cancel(ctx, second.ID); err != nil {
+		t.Fatal(err)
+	}
+	body = transactionPayload(t, third, "evt_"+uuid.NewString(), entities.GatewayStatusFailed)
+	if err := f.service.HandleWebhook(ctx, "fake", body, sign(testWebhookSecret, body)); err != nil {
+		t.Fatal(err)
+	}
+
+	f.assertState(t, entities.TransactionStatusCompleted, entities.PaymentStatusPaid, 1, 1)
+
+	statuses := map[uuid.UUID]string{}
+	for _, transaction := range []models.Transaction{second, third} {
+		var stored models.Transaction
+		if err := f.db.First(&stored, "id = ?", transaction.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		statuses[transaction.ID] = stored.Status
+	}
+	if statuses[second.ID] != string(entities.TransactionStatusCancelled) || statuses[third.ID] != string(entities.TransactionStatusFailed) {
+		t.Errorf("expected the other transactions to be settled on their own, got %v", statuses)
+	}
+
+	var timeline int64
+	f.db.Model(&models.OrderStatusEvent{}).Where("order_id = ? AND axis = ?", f.order.ID, entities.StatusAxisPayment).Count(&timeline)
+	if timeline != 1 {
+		t.Errorf("expected only the capture on the order timeline, got %d payment changes", timeline)
+	}
+}
+
+func TestHandleWebhook_LateCaptureLeavesCancelledOrder(t *testing.T) {
+	f := newWebhookFixture(t)
+	ctx := context.Background()
+
+	orderRepo := repositories.NewOrderRepository(f.db)
+	if err := orderRepo.Cancel(ctx, f.order.ID, uuid.Nil, "customer changed their mind"); err != nil {
+		t.Fatal(err)
+	}
+
+	body := f.payload(t, "evt_"+uuid.NewString(), entities.GatewayStatusCaptured)
+	if err := f.service.HandleWebhook(ctx, "fake", body, sign(testWebhookSecret, body)); err != nil {
+		t.Fatalf("expected the late capture to be acknowledged, got %v", err)
+	}
+
+	// ธุรกรรมปิดตามที่ gateway แจ้ง แต่คำสั่งซื้อที่ยกเลิกแล้วต้องไม่กลายเป็นชำระแล้ว
+	f.assertState(t, entities.TransactionStatusCompleted, entities.PaymentStatusPending, 1, 1)
+}
@@ -3,12 +3,15 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"strings"
+	"unicode"
 
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type productRepository struct {
@@ -19,23 +22,39 @@ func NewProductRepository(db *gorm.DB) repositories.ProductRepository {
 	return &productRepository{db: db}
 }
 
-func (r *productRepository) Create(ctx context.Context, req *entities.CreateProductRequest) (*entities.Product, error) {
+func (r *productRepository) Create(ctx context.Context, req *entities.CreateProductRequest, actorID uuid.UUID) (*entities.Product, error) {
 	productModel := &models.Product{
-		Name:        req.Name,
-		Description: req.Description,
-		Price:       req.Price,
-		Stock:       req.Stock,
-		Image:       req.Image,
-		CategoryID:  req.CategoryID,
+		Name:             req.Name,
+		Description:      req.Description,
+		Price:            req.Price.Amount,
+		Currency:         entities.NewMoney(0, req.Price.Currency).Currency,
+		Weight:           req.Weight,
+		ReorderThreshold: req.ReorderThreshold,
+		TaxRuleID:        req.TaxRuleID,
+		Image:            req.Image,
+		CategoryID:       req.CategoryID,
 	}
 
 	tx := r.db.WithContext(ctx).Begin()
 
+	if err := findCategory(tx, req.CategoryID, &models.Category{}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	if err := tx.Create(productModel).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
+	// สต็อกเริ่มต้นต้องผ่าน ledger เหมือนการเปลี่ยนสต็อกอื่นๆ
+	if req.Stock > 0 {
+		if _, err := moveStock(tx, productModel.ID, nil, req.Stock, entities.InventoryReasonAdjustment, nil, actorID, "initial stock"); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
 	// เพิ่มรูปภาพเพิ่มเติม
 	for _, imageURL := range req.Images {
 		productImage := &models.ProductImage{
@@ -57,13 +76,14 @@ func (r *productRepository) Create(ctx context.Context, req *entities.CreateProd
 
 func (r *productRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Product, error) {
 	var productModel models.Product
-	if err := r.db.WithContext(ctx).Preload("Category").Preload("Images").First(&productModel, "id = ?", id).Error; err != nil {
+	if err := preloadVariants(r.db.WithContext(ctx)).Preload("Category").Preload("Images").First(&productModel, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 
 	return r.modelToEntity(&productModel), nil
 }
 
+// GetAll คืนสินค้าทั้งหมดเรียงจากใหม่ไปเก่า ใช้ id เป็นลำดับรองเพื่อให้แต่ละหน้าไม่ซ้ำและไม่ข้ามกัน
 func (r *productRepository) GetAll(ctx context.Context, page, limit int) ([]*entities.Product, int, error) {
 	var products []models.Product
 	var total int64
@@ -74,7 +94,7 @@ func (r *productRepository) GetAll(ctx context.Context, page, limit int) ([]*ent
 		return nil, 0, err
 	}
 
-	if err := r.db.WithContext(ctx).Preload("Category").Preload("Images").Offset(offset).Limit(limit).Find(&products).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Category").Preload("Images").Order("created_at DESC, id").Offset(offset).Limit(limit).Find(&products).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -86,17 +106,18 @@ func (r *productRepository) GetAll(ctx context.Context, page, limit int) ([]*ent
 	return result, int(total), nil
 }
 
+// GetByCategory คืนสินค้าในหมวดหมู่และหมวดหมู่ย่อยทุกระดับ
 func (r *productRepository) GetByCategory(ctx context.Context, categoryID uuid.UUID, page, limit int) ([]*entities.Product, int, error) {
 	var products []models.Product
 	var total int64
 
 	offset := (page - 1) * limit
 
-	if err := r.db.WithContext(ctx).Model(&models.Product{}).Where("category_id = ?", categoryID).Count(&total).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.Product{}).Where("category_id IN ("+categorySubtreeSQL+")", categoryID).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if err := r.db.WithContext(ctx).Preload("Category").Preload("Images").Where("category_id = ?", categoryID).Offset(offset).Limit(limit).Find(&products).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Category").Preload("Images").Where("category_id IN ("+categorySubtreeSQL+")", categoryID).Order("created_at DESC, id").Offset(offset).Limit(limit).Find(&products).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -108,32 +129,17 @@ func (r *productRepository) GetByCategory(ctx context.Context, categoryID uuid.U
 	return result, int(total), nil
 }
 
-func (r *productRepository) Search(ctx context.Context, req *entities.ProductSearchRequest) ([]*entities.Product, int, error) {
-	var products []models.Product
-	var total int64
-
-	query := r.db.WithContext(ctx).Model(&models.Product{})
-
-	// ค้นหาตามชื่อสินค้า
-	if req.Query != "" {
-		query = query.Where("name ILIKE ? OR description ILIKE ?", fmt.Sprintf("%%%s%%", req.Query), fmt.Sprintf("%%%s%%", req.Query))
-	}
-
-	// กรองตามหมวดหมู่
-	if req.CategoryID != uuid.Nil {
-		query = query.Where("category_id = ?", req.CategoryID)
-	}
+// Search ค้นหาสินค้าด้วย full-text search บนคอลัมน์ search_vector (ชื่อน้ำหนัก A คำอธิบายน้ำหนัก B)
+// คำค้นแต่ละคำจับคู่แบบ prefix ทำให้ค้นคำต้นของข้อความภาษาไทยที่ไม่มีการเว้นวรรคได้
+// และจับคู่ชื่อสินค้าด้วย ILIKE (ใช้ trigram index) สำหรับคำที่อยู่กลางข้อความ
+// ถ้า req.ProductIDs ไม่เป็น nil (ผลจาก search index) จะกรองด้วยรายการนั้นและเรียงความเกี่ยวข้องตามลำดับในรายการแทน
+// คืนผลลัพธ์พร้อม facet ของหมวดหมู่และช่วงราคาจากเงื่อนไขเดียวกัน
+func (r *productRepository) Search(ctx context.Context, req *entities.ProductSearchRequest) ([]*entities.Product, *entities.SearchFacets, int, error) {
+	search := newProductSearch(req)
 
-	// กรองตามราคา
-	if req.MinPrice > 0 {
-		query = query.Where("price >= ?", req.MinPrice)
-	}
-	if req.MaxPrice > 0 {
-		query = query.Where("price <= ?", req.MaxPrice)
-	}
-
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	var total int64
+	if err := search.filter(r.db.WithContext(ctx), true, true).Count(&total).Error; err != nil {
+		return nil, nil, 0, err
 	}
 
 	page := req.Page
@@ -147,8 +153,17 @@ func (r *productRepository) Search(ctx context.Context, req *entities.ProductSea
 
 	offset := (page - 1) * limit
 
-	if err := query.Preload("Category").Preload("Images").Offset(offset).Limit(limit).Find(&products).Error; err != nil {
-		return nil, 0, err
+	var products []models.Product
+	if err := search.order(search.filter(r.db.WithContext(ctx), true, true)).
+		Preload("Category").Preload("Images").
+		Offset(offset).Limit(limit).
+		Find(&products).Error; err != nil {
+		return nil, nil, 0, err
+	}
+
+	facets, err := r.searchFacets(ctx, search)
+	if err != nil {
+		return nil, nil, 0, err
 	}
 
 	var result []*entities.Product
@@ -156,10 +171,187 @@ func (r *productRepository) Search(ctx context.Context, req *entities.ProductSea
 		result = append(result, r.modelToEntity(&product))
 	}
 
-	return result, int(total), nil
+	return result, facets, int(total), nil
 }
 
-func (r *productRepository) Update(ctx context.Context, id uuid.UUID, req *entities.UpdateProductRequest) error {
+// searchFacets นับสินค้าแยกตามหมวดหมู่และช่วงราคา แต่ละกลุ่มไม่ใช้ตัวกรองของกลุ่มตัวเอง
+// ช่วงราคานับเฉพาะสินค้าที่ตั้งราคาเป็นสกุลเงินหลัก เพราะขอบของช่วงเป็นสกุลเงินหลัก
+func (r *productRepository) searchFacets(ctx context.Context, search *productSearch) (*entities.SearchFacets, error) {
+	facets := &entities.SearchFacets{Categories: []entities.CategoryFacet{}}
+
+	if err := search.filter(r.db.WithContext(ctx), false, true).
+		Select("products.category_id, categories.name, COUNT(*) AS count").
+		Joins("JOIN categories ON categories.id = products.category_id AND categories.deleted_at IS NULL").
+		Group("products.category_id, categories.name").
+		Order("count DESC, categories.name ASC").
+		Scan(&facets.Categories).Error; err != nil {
+		return nil, err
+	}
+
+	bucket, bounds := priceBucketSQL()
+	var rows []struct {
+		Bucket int
+		Count  int
+	}
+	if err := search.filter(r.db.WithContext(ctx), true, false).
+		Select(bucket+" AS bucket, COUNT(*) AS count", bounds...).
+		Where("products.currency = ?", entities.DefaultCurrency).
+		Group("bucket").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int]int, len(rows))
+	for _, row := range rows {
+		counts[row.Bucket] = row.Count
+	}
+	facets.PriceRanges = entities.NewPriceRangeFacets(counts)
+
+	return facets, nil
+}
+
+// productSearch เงื่อนไขการค้นหาที่แปลงแล้ว ใช้สร้าง query ของผลลัพธ์ จำนวน และ facet ให้ตรงกัน
+type productSearch struct {
+	req     *entities.ProductSearchRequest
+	tsquery string
+	pattern string
+}
+
+func newProductSearch(req *entities.ProductSearchRequest) *productSearch {
+	search := &productSearch{req: req}
+	if req.ProductIDs != nil {
+		return search
+	}
+	if query := strings.TrimSpace(req.Query); query != "" {
+		search.tsquery = prefixTSQuery(query)
+		search.pattern = "%" + escapeLike(query) + "%"
+	}
+	return search
+}
+
+// filter ใส่เงื่อนไขการค้นหา withCategory และ withPrice ใช้ปิดตัวกรองของ facet กลุ่มนั้น
+func (s *productSearch) filter(db *gorm.DB, withCategory, withPrice bool) *gorm.DB {
+	query := db.Model(&models.Product{})
+
+	if s.req.ProductIDs != nil {
+		query = query.Where("products.id IN ?", s.req.ProductIDs)
+	}
+	if s.pattern != "" {
+		if s.tsquery != "" {
+			query = query.Where("(products.search_vector @@ to_tsquery('simple', ?) OR products.name ILIKE ?)", s.tsquery, s.pattern)
+		} else {
+			query = query.Where("products.name ILIKE ?", s.pattern)
+		}
+	}
+
+	// กรองตามหมวดหมู่ รวมหมวดหมู่ย่อยทุกระดับ
+	if withCategory && s.req.CategoryID != uuid.Nil {
+		query = query.Where("products.category_id IN ("+categorySubtreeSQL+")", s.req.CategoryID)
+	}
+
+	// กรองตามราคา (เทียบเฉพาะสินค้าที่ตั้งราคาเป็นสกุลเงินเดียวกัน)
+	if withPrice && s.req.MinPrice != nil {
+		query = query.Where("products.currency = ? AND products.price >= ?", s.req.MinPrice.Currency, s.req.MinPrice.Amount)
+	}
+	if withPrice && s.req.MaxPrice != nil {
+		query = query.Where("products.currency = ? AND products.price <= ?", s.req.MaxPrice.Currency, s.req.MaxPrice.Amount)
+	}
+
+	if s.req.InStock {
+		query = query.Where("products.stock > 0")
+	}
+
+	return query
+}
+
+// order เรียงผลลัพธ์ตาม Sort ความเกี่ยวข้องคือคะแนน ts_rank บวกคะแนนพิเศษเมื่อชื่อสินค้ามีคำค้นทั้งข้อความ
+func (s *productSearch) order(query *gorm.DB) *gorm.DB {
+	sort := s.req.Sort
+	if sort == "" {
+		sort = entities.ProductSortRelevance
+	}
+	if sort == entities.ProductSortRelevance && s.pattern == "" && s.req.ProductIDs == nil {
+		sort = entities.ProductSortNewest
+	}
+
+	switch sort {
+	case entities.ProductSortPriceAsc:
+		return query.Order("products.price ASC, products.name ASC")
+	case entities.ProductSortPriceDesc:
+		return query.Order("products.price DESC, products.name ASC")
+	case entities.ProductSortNewest:
+		return query.Order("products.created_at DESC")
+	}
+
+	if s.req.ProductIDs != nil {
+		return query.Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "array_position(?::uuid[], products.id)",
+			Vars:               []interface{}{uuidArray(s.req.ProductIDs)},
+			WithoutParentheses: true,
+		}})
+	}
+
+	rank := clause.Expr{SQL: "CASE WHEN products.name ILIKE ? THEN 1 ELSE 0 END", Vars: []interface{}{s.pattern}}
+	if s.tsquery != "" {
+		rank = clause.Expr{
+			SQL:  "ts_rank(products.search_vector, to_tsquery('simple', ?)) + CASE WHEN products.name ILIKE ? THEN 1 ELSE 0 END",
+			Vars: []interface{}{s.tsquery, s.pattern},
+		}
+	}
+
+	return query.Clauses(clause.OrderBy{Expression: clause.Expr{
+		SQL:                "(?) DESC, products.created_at DESC",
+		Vars:               []interface{}{rank},
+		WithoutParentheses: true,
+	}})
+}
+
+// prefixTSQuery แปลงคำค้นเป็น tsquery แบบทุกคำต้องตรง (AND) และจับคู่แบบ prefix
+// ตัดอักขระที่มีความหมายพิเศษใน tsquery ออก คืนค่าว่างถ้าไม่เหลือคำให้ค้น
+func prefixTSQuery(query string) string {
+	var terms []string
+	for _, word := range strings.Fields(query) {
+		word = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`&|!():*'\<>`, r) {
+				return -1
+			}
+			return unicode.ToLower(r)
+		}, word)
+		if word != "" {
+			terms = append(terms, word+":*")
+		}
+	}
+	return strings.Join(terms, " & ")
+}
+
+// uuidArray แปลงรายการ UUID เป็น array literal ของ Postgres
+func uuidArray(ids []uuid.UUID) string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+	return "{" + strings.Join(values, ",") + "}"
+}
+
+// escapeLike escape อักขระพิเศษของ LIKE เพื่อให้คำค้นถูกจับคู่ตามตัวอักษร
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// priceBucketSQL นิพจน์ที่คืนลำดับของช่วงราคาใน SearchPriceBuckets ที่ราคาสินค้าอยู่
+func priceBucketSQL() (string, []interface{}) {
+	var sql strings.Builder
+	var bounds []interface{}
+	sql.WriteString("CASE")
+	for i := len(entities.SearchPriceBuckets) - 1; i > 0; i-- {
+		fmt.Fprintf(&sql, " WHEN products.price >= ? THEN %d", i)
+		bounds = append(bounds, entities.SearchPriceBuckets[i])
+	}
+	sql.WriteString(" ELSE 0 END")
+	return sql.String(), bounds
+}
+
+func (r *productRepository) Update(ctx context.Context, id uuid.UUID, req *entities.UpdateProductRequest, actorID uuid.UUID) error {
 	updates := map[string]interface{}{}
 
 	if req.Name != "" {
@@ -168,11 +360,23 @@ func (r *productRepository) Update(ctx context.Context, id uuid.UUID, req *entit
 	if req.Description != "" {
 		updates["description"] = req.Description
 	}
-	if req.Price > 0 {
-		updates["price"] = req.Price
+	if req.Price != nil {
+		updates["price"] = req.Price.Amount
+		updates["currency"] = entities.NewMoney(0, req.Price.Currency).Currency
+	}
+	if req.Weight != nil {
+		updates["weight"] = *req.Weight
+	}
+	if req.ReorderThreshold != nil {
+		updates["reorder_threshold"] = *req.ReorderThreshold
 	}
-	if req.Stock >= 0 {
-		updates["stock"] = req.Stock
+	// ส่ง uuid ว่างเพื่อยกเลิกกฎภาษีที่กำหนดไว้ แล้วกลับไปใช้กฎของหมวดหมู่
+	if req.TaxRuleID != nil {
+		if *req.TaxRuleID == uuid.Nil {
+			updates["tax_rule_id"] = nil
+		} else {
+			updates["tax_rule_id"] = *req.TaxRuleID
+		}
 	}
 	if req.Image != "" {
 		updates["image"] = req.Image
@@ -183,9 +387,38 @@ func (r *productRepository) Update(ctx context.Context, id uuid.UUID, req *entit
 
 	tx := r.db.WithContext(ctx).Begin()
 
-	if err := tx.Model(&models.Product{}).Where("id = ?", id).Updates(updates).Error; err != nil {
-		tx.Rollback()
-		return err
+	if req.CategoryID != uuid.Nil {
+		if err := findCategory(tx, req.CategoryID, &models.Category{}); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if len(updates) > 0 {
+		if err := tx.Model(&models.Product{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// การตั้งค่าสต็อกใหม่จะถูกบันทึกเป็น adjustment ตามส่วนต่างจากสต็อกปัจจุบัน
+	if req.Stock != nil {
+		var product models.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, "id = ?", id).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if delta := *req.Stock - product.Stock; delta != 0 {
+			if err := requireVariant(tx, id, nil); err != nil {
+				tx.Rollback()
+				return err
+			}
+			if _, err := moveStock(tx, id, nil, delta, entities.InventoryReasonAdjustment, nil, actorID, "stock set via product update"); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
 	}
 
 	// อัพเดทรูปภาพเพิ่มเติม (ลบรูปเก่าและเพิ่มรูปใหม่)
@@ -216,46 +449,71 @@ func (r *productRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&models.Product{}, "id = ?", id).Error
 }
 
-func (r *productRepository) UpdateStock(ctx context.Context, id uuid.UUID, stock int) error {
-	return r.db.WithContext(ctx).Model(&models.Product{}).Where("id = ?", id).Update("stock", stock).Error
-}
+// effectiveReorderThresholdSQL จุดสั่งซื้อเพิ่มที่ใช้จริงของสินค้า (สินค้า → หมวดหมู่ → ค่า default)
+// ต้องใช้คู่กับ lowStockJoinSQL
+const (
+	effectiveReorderThresholdSQL = "COALESCE(products.reorder_threshold, categories.reorder_threshold, ?)"
+	lowStockJoinSQL              = "LEFT JOIN categories ON categories.id = products.category_id AND categories.deleted_at IS NULL"
+)
 
-func (r *productRepository) GetLowStockProducts(ctx context.Context, threshold int) ([]*entities.Product, error) {
-	var products []models.Product
+func (r *productRepository) GetLowStockProducts(ctx context.Context, filter *entities.LowStockFilter) ([]*entities.LowStockProduct, int, error) {
+	query := r.db.WithContext(ctx).Model(&models.Product{}).
+		Joins(lowStockJoinSQL).
+		Where("products.stock <= "+effectiveReorderThresholdSQL, entities.DefaultReorderThreshold)
 
-	if err := r.db.WithContext(ctx).Preload("Category").Where("stock <= ?", threshold).Find(&products).Error; err != nil {
-		return nil, err
+	if len(filter.ProductIDs) > 0 {
+		query = query.Where("products.id IN ?", filter.ProductIDs)
+	}
+	if filter.CategoryID != uuid.Nil {
+		query = query.Where("products.category_id = ?", filter.CategoryID)
 	}
 
-	var result []*entities.Product
-	for _, product := range products {
-		result = append(result, r.modelToEntity(&product))
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.
+		Select("products.id AS product_id, products.name, products.category_id, products.stock, "+effectiveReorderThresholdSQL+" AS threshold", entities.DefaultReorderThreshold).
+		Order("products.stock ASC, products.name ASC")
+	if filter.Limit > 0 {
+		query = query.Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit)
+	}
+
+	var result []*entities.LowStockProduct
+	if err := query.Scan(&result).Error; err != nil {
+		return nil, 0, err
 	}
 
-	return result, nil
+	return result, int(total), nil
 }
 
 func (r *productRepository) modelToEntity(productModel *models.Product) *entities.Product {
 	product := &entities.Product{
-		ID:          productModel.ID,
-		Name:        productModel.Name,
-		Description: productModel.Description,
-		Price:       productModel.Price,
-		Stock:       productModel.Stock,
-		Image:       productModel.Image,
-		CategoryID:  productModel.CategoryID,
-		CreatedAt:   productModel.CreatedAt,
-		UpdatedAt:   productModel.UpdatedAt,
+		ID:               productModel.ID,
+		Name:             productModel.Name,
+		Description:      productModel.Description,
+		Price:            entities.NewMoney(productModel.Price, productModel.Currency),
+		Stock:            productModel.Stock,
+		Weight:           productModel.Weight,
+		ReorderThreshold: productModel.ReorderThreshold,
+		TaxRuleID:        productModel.TaxRuleID,
+		Image:            productModel.Image,
+		CategoryID:       productModel.CategoryID,
+		CreatedAt:        productModel.CreatedAt,
+		UpdatedAt:        productModel.UpdatedAt,
 	}
 
 	if productModel.Category.ID != uuid.Nil {
 		product.Category = &entities.Category{
-			ID:          productModel.Category.ID,
-			Name:        productModel.Category.Name,
-			Description: productModel.Category.Description,
-			Image:       productModel.Category.Image,
-			CreatedAt:   productModel.Category.CreatedAt,
-			UpdatedAt:   productModel.Category.UpdatedAt,
+			ID:               productModel.Category.ID,
+			Name:             productModel.Category.Name,
+			Description:      productModel.Category.Description,
+			Image:            productModel.Category.Image,
+			ReorderThreshold: productModel.Category.ReorderThreshold,
+			TaxRuleID:        productModel.Category.TaxRuleID,
+			CreatedAt:        productModel.Category.CreatedAt,
+			UpdatedAt:        productModel.Category.UpdatedAt,
 		}
 	}
 
@@ -269,5 +527,12 @@ func (r *productRepository) modelToEntity(productModel *models.Product) *entitie
 		})
 	}
 
+	for _, option := range productModel.Options {
+		product.Options = append(product.Options, optionModelToEntity(&option))
+	}
+	for _, variant := range productModel.Variants {
+		product.Variants = append(product.Variants, variantModelToEntity(&variant, productModel))
+	}
+
 	return product
 }
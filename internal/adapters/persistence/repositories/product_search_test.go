@@ -0,0 +1,114 @@
+package repositories_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+func TestSearch_RanksNameMatchesAndCountsFacets(t *testing.T) {
+	d := newTestData(t)
+
+	// คำที่ไม่ซ้ำกับข้อมูลอื่นในฐานข้อมูลทดสอบ
+	marker := "zq" + strings.ReplaceAll(uuid.NewString(), "-", "")[:8]
+
+	clothing := d.category()
+	home := d.category()
+
+	products := []models.Product{
+		d.product(models.Product{Name: marker + " cotton shirt", Price: 30000, Currency: "THB", Stock: 5, CategoryID: clothing.ID}),
+		d.product(models.Product{Name: marker + " shirt hanger", Price: 9000, Currency: "THB", Stock: 0, CategoryID: home.ID}),
+		d.product(models.Product{Name: marker + " bath towel", Description: "soft as your favourite shirt", Price: 120000, Currency: "THB", Stock: 2, CategoryID: home.ID}),
+		d.product(models.Product{Name: marker + " coffee mug", Price: 15000, Currency: "THB", Stock: 9, CategoryID: home.ID}),
+	}
+
+	repo := repositories.NewProductRepository(d.db)
+	ctx := context.Background()
+
+	result, facets, total, err := repo.Search(ctx, &entities.ProductSearchRequest{Query: marker + " shirt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 || len(result) != 3 {
+		t.Fatalf("expected 3 matches, got total=%d len=%d", total, len(result))
+	}
+	// สินค้าที่ชื่อมีคำค้นต้องมาก่อนสินค้าที่ตรงเฉพาะคำอธิบาย
+	if result[2].ID != products[2].ID {
+		t.Errorf("expected the description-only match last, got %s", result[2].Name)
+	}
+
+	categoryCounts := map[uuid.UUID]int{}
+	for _, facet := range facets.Categories {
+		categoryCounts[facet.CategoryID] = facet.Count
+	}
+	if categoryCounts[clothing.ID] != 1 || categoryCounts[home.ID] != 2 {
+		t.Errorf("unexpected category facets: %+v", facets.Categories)
+	}
+	if len(facets.PriceRanges) != len(entities.SearchPriceBuckets) {
+		t.Fatalf("expected %d price ranges, got %d", len(entities.SearchPriceBuckets), len(facets.PriceRanges))
+	}
+	if facets.PriceRanges[0].Count != 2 || facets.PriceRanges[2].Count != 1 {
+		t.Errorf("unexpected price facets: %+v", facets.PriceRanges)
+	}
+
+	// กรองหมวดหมู่แล้วผลลัพธ์ต้องลดลง แต่ facet ของหมวดหมู่ยังนับทุกหมวด
+	result, facets, total, err = repo.Search(ctx, &entities.ProductSearchRequest{
+		Query:      marker + " shirt",
+		CategoryID: home.ID,
+		InStock:    true,
+		Sort:       entities.ProductSortPriceAsc,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || result[0].ID != products[2].ID {
+		t.Errorf("expected only the in-stock towel, got total=%d", total)
+	}
+	if len(facets.Categories) != 2 {
+		t.Errorf("expected category facets to ignore the category filter, got %+v", facets.Categories)
+	}
+}
+
+func TestGetAll_PagesDoNotOverlapForProductsCreatedTogether(t *testing.T) {
+	d := newTestData(t)
+	ctx := context.Background()
+
+	// สินค้าที่นำเข้าพร้อมกันมีเวลาสร้างเท่ากัน การแบ่งหน้าต้องยังได้ครบทุกตัวและไม่ซ้ำ
+	createdAt := time.Now().Add(-time.Hour)
+	ours := map[uuid.UUID]bool{}
+	category := d.category()
+	for i := 0; i < 7; i++ {
+		product := d.product(models.Product{Name: "Imported " + uuid.NewString()[:8], Price: 1000, CategoryID: category.ID})
+		if err := d.db.Model(&models.Product{}).Where("id = ?", product.ID).Update("created_at", createdAt).Error; err != nil {
+			t.Fatal(err)
+		}
+		ours[product.ID] = true
+	}
+
+	productRepo := repositories.NewProductRepository(d.db)
+	seen := map[uuid.UUID]int{}
+	for page := 1; ; page++ {
+		products, total, err := productRepo.GetAll(ctx, page, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, product := range products {
+			seen[product.ID]++
+		}
+		if page*3 >= total {
+			break
+		}
+	}
+
+	for id := range ours {
+		if seen[id] != 1 {
+			t.Errorf("expected product %s on exactly one page, got %d", id, seen[id])
+		}
+	}
+}
@@ -0,0 +1,501 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type productVariantRepository struct {
+	db *gorm.DB
+}
+
+func NewProductVariantRepository(db *gorm.DB) repositories.ProductVariantRepository {
+	return &productVariantRepository{db: db}
+}
+
+// CreateOption เพิ่มประเภทตัวเลือกพร้อมค่าต่างๆ ต่อท้ายตัวเลือกที่มีอยู่
+// เพิ่มได้เฉพาะสินค้าที่ยังไม่มี variant เพราะ variant เดิมจะไม่มีค่าของตัวเลือกใหม่
+func (r *productVariantRepository) CreateOption(ctx context.Context, productID uuid.UUID, req *entities.CreateProductOptionRequest) (*entities.ProductOption, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	if _, err := lockProduct(tx, productID); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var variants int64
+	if err := tx.Model(&models.ProductVariant{}).Where("product_id = ?", productID).Count(&variants).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if variants > 0 {
+		tx.Rollback()
+		return nil, entities.ErrProductHasVariants
+	}
+
+	var position int64
+	if err := tx.Model(&models.ProductOption{}).Where("product_id = ?", productID).Count(&position).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	option := &models.ProductOption{
+		ProductID: productID,
+		Name:      req.Name,
+		Position:  int(position),
+	}
+	for i, value := range req.Values {
+		option.Values = append(option.Values, models.ProductOptionValue{Value: value, Position: i})
+	}
+
+	if err := tx.Create(option).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	entity := optionModelToEntity(option)
+	return &entity, nil
+}
+
+// DeleteOption ลบประเภทตัวเลือกพร้อมค่าทั้งหมด ถ้ามี variant ใช้ค่าของตัวเลือกนี้อยู่จะลบไม่ได้
+func (r *productVariantRepository) DeleteOption(ctx context.Context, productID, optionID uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	var option models.ProductOption
+	if err := tx.First(&option, "id = ? AND product_id = ?", optionID, productID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.ErrProductOptionNotFound
+		}
+		return err
+	}
+
+	var used int64
+	if err := tx.Table("product_variant_option_values").
+		Joins("JOIN product_option_values ON product_option_values.id = product_variant_option_values.product_option_value_id").
+		Joins("JOIN product_variants ON product_variants.id = product_variant_option_values.product_variant_id AND product_variants.deleted_at IS NULL").
+		Where("product_option_values.option_id = ?", optionID).
+		Count(&used).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if used > 0 {
+		tx.Rollback()
+		return entities.ErrProductOptionInUse
+	}
+
+	if err := tx.Where("option_id = ?", optionID).Delete(&models.ProductOptionValue{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(&option).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (r *productVariantRepository) GetByProductID(ctx context.Context, productID uuid.UUID) ([]*entities.ProductVariant, error) {
+	var product models.Product
+	if err := preloadVariants(r.db.WithContext(ctx)).First(&product, "id = ?", productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	var result []*entities.ProductVariant
+	for _, variant := range product.Variants {
+		entity := variantModelToEntity(&variant, &product)
+		result = append(result, &entity)
+	}
+
+	return result, nil
+}
+
+func (r *productVariantRepository) GetByID(ctx context.Context, productID, variantID uuid.UUID) (*entities.ProductVariant, error) {
+	var variant models.ProductVariant
+	if err := r.db.WithContext(ctx).
+		Preload("Product").
+		Preload("OptionValues.Option").
+		First(&variant, "id = ? AND product_id = ?", variantID, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrVariantNotFound
+		}
+		return nil, err
+	}
+
+	entity := variantModelToEntity(&variant, &variant.Product)
+	return &entity, nil
+}
+
+// Create เพิ่ม variant โดยต้องเลือกค่าครบทุกตัวเลือกของสินค้า ตัวเลือกละหนึ่งค่า และชุดค่าต้องไม่ซ้ำกับ variant อื่น
+// สต็อกเริ่มต้นบันทึกผ่าน ledger เหมือนสินค้าทั่วไป
+//
+// เมื่อเพิ่ม variant แรก สต็อกของสินค้าหลักต้องเป็น 0 เพื่อให้สต็อกของสินค้าหลักเท่ากับผลรวมของ variant
+// และรายการในตะกร้าที่ไม่ได้ระบุ variant จะถูกนำออก เพราะจะ checkout ไม่ได้อีกแล้ว
+func (r *productVariantRepository) Create(ctx context.Context, productID, actorID uuid.UUID, req *entities.CreateVariantRequest) (*entities.ProductVariant, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	product, err := lockProduct(tx, productID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	price, err := variantPriceOverride(product, req.Price)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	optionValues, err := findVariantOptionValues(tx, productID, req.OptionValueIDs)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var existing []models.ProductVariant
+	if err := tx.Preload("OptionValues").Where("product_id = ?", productID).Find(&existing).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	for _, variant := range existing {
+		if sameOptionValues(variant.OptionValues, optionValues) {
+			tx.Rollback()
+			return nil, fmt.Errorf("%w: มี variant %s ที่ใช้ชุดค่าตัวเลือกนี้แล้ว", entities.ErrInvalidVariantOptions, variant.SKU)
+		}
+	}
+
+	if len(existing) == 0 {
+		if product.Stock != 0 {
+			tx.Rollback()
+			return nil, entities.ErrVariantStockConflict
+		}
+		if err := tx.Where("product_id = ? AND variant_id IS NULL", productID).Delete(&models.CartItem{}).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := checkSKUAvailable(tx, req.SKU, uuid.Nil); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	variant := &models.ProductVariant{
+		ProductID:    productID,
+		SKU:          req.SKU,
+		Price:        price,
+		Image:        req.Image,
+		OptionValues: optionValues,
+	}
+	if err := tx.Omit("OptionValues.*").Create(variant).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if req.Stock > 0 {
+		if _, err := moveStock(tx, productID, &variant.ID, req.Stock, entities.InventoryReasonAdjustment, nil, actorID, "initial stock"); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, productID, variant.ID)
+}
+
+func (r *productVariantRepository) Update(ctx context.Context, productID, variantID uuid.UUID, req *entities.UpdateVariantRequest) (*entities.ProductVariant, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	product, err := lockProduct(tx, productID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var variant models.ProductVariant
+	if err := tx.First(&variant, "id = ? AND product_id = ?", variantID, productID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrVariantNotFound
+		}
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.SKU != nil && *req.SKU != variant.SKU {
+		if err := checkSKUAvailable(tx, *req.SKU, variantID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		updates["sku"] = *req.SKU
+	}
+	if req.ClearPrice {
+		updates["price"] = nil
+	} else if req.Price != nil {
+		price, err := variantPriceOverride(product, req.Price)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		updates["price"] = *price
+	}
+	if req.Image != nil {
+		updates["image"] = *req.Image
+	}
+
+	if len(updates) > 0 {
+		if err := tx.Model(&variant).Updates(updates).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, productID, variantID)
+}
+
+// Delete ลบ variant สต็อกที่เหลือจะถูกตัดออกผ่าน ledger และรายการในตะกร้าที่อ้างถึง variant นี้จะถูกนำออก
+// รายการในคำสั่งซื้อเดิมยังเก็บ SKU และชื่อ variant ไว้ตามตอนสั่งซื้อ
+func (r *productVariantRepository) Delete(ctx context.Context, productID, variantID, actorID uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	if _, err := lockProduct(tx, productID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var variant models.ProductVariant
+	if err := tx.First(&variant, "id = ? AND product_id = ?", variantID, productID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.ErrVariantNotFound
+		}
+		return err
+	}
+
+	if variant.Stock != 0 {
+		if _, err := moveStock(tx, productID, &variantID, -variant.Stock, entities.InventoryReasonAdjustment, nil, actorID, "variant deleted"); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Where("variant_id = ?", variantID).Delete(&models.CartItem{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Model(&variant).Association("OptionValues").Clear(); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(&variant).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// lockProduct ล็อคแถวสินค้าไว้ตลอด transaction กันการแก้ตัวเลือกและ variant ของสินค้าเดียวกันซ้อนกัน
+func lockProduct(tx *gorm.DB, productID uuid.UUID) (*models.Product, error) {
+	var product models.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, "id = ?", productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrProductNotFound
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
+// variantPriceOverride แปลงราคาของ variant เป็นจำนวนเงินในสกุลเงินของสินค้า nil คือใช้ราคาของสินค้าหลัก
+func variantPriceOverride(product *models.Product, price *entities.Money) (*int64, error) {
+	if price == nil {
+		return nil, nil
+	}
+	if price.Currency != "" && price.Currency != product.Currency {
+		return nil, fmt.Errorf("%w: ราคาของ variant ต้องเป็นสกุลเงิน %s", entities.ErrCurrencyMismatch, product.Currency)
+	}
+	if price.Amount <= 0 {
+		return nil, errors.New("ราคาของ variant ต้องมากกว่า 0")
+	}
+	amount := price.Amount
+	return &amount, nil
+}
+
+// findVariantOptionValues ตรวจว่าค่าตัวเลือกที่เลือกเป็นของสินค้านี้ และครบทุกตัวเลือก ตัวเลือกละหนึ่งค่า
+func findVariantOptionValues(tx *gorm.DB, productID uuid.UUID, valueIDs []uuid.UUID) ([]models.ProductOptionValue, error) {
+	var options int64
+	if err := tx.Model(&models.ProductOption{}).Where("product_id = ?", productID).Count(&options).Error; err != nil {
+		return nil, err
+	}
+
+	var values []models.ProductOptionValue
+	if err := tx.Joins("JOIN product_options ON product_options.id = product_option_values.option_id AND product_options.deleted_at IS NULL").
+		Where("product_options.product_id = ? AND product_option_values.id IN ?", productID, valueIDs).
+		Find(&values).Error; err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool, len(values))
+	for _, value := range values {
+		if seen[value.OptionID] {
+			return nil, fmt.Errorf("%w: เลือกได้ตัวเลือกละหนึ่งค่า", entities.ErrInvalidVariantOptions)
+		}
+		seen[value.OptionID] = true
+	}
+	if len(values) != len(valueIDs) || int64(len(values)) != options {
+		return nil, fmt.Errorf("%w: ต้องเลือกค่าครบทุกตัวเลือกของสินค้า", entities.ErrInvalidVariantOptions)
+	}
+
+	return values, nil
+}
+
+func sameOptionValues(a, b []models.ProductOptionValue) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	ids := make(map[uuid.UUID]bool, len(a))
+	for _, value := range a {
+		ids[value.ID] = true
+	}
+	for _, value := range b {
+		if !ids[value.ID] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkSKUAvailable ตรวจว่า SKU ยังไม่ถูกใช้โดย variant อื่น (ยกเว้น variant ที่กำลังแก้ไข)
+func checkSKUAvailable(tx *gorm.DB, sku string, exceptID uuid.UUID) error {
+	var count int64
+	if err := tx.Model(&models.ProductVariant{}).Where("sku = ? AND id <> ?", sku, exceptID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %s", entities.ErrDuplicateSKU, sku)
+	}
+	return nil
+}
+
+// preloadVariants โหลดตัวเลือกและ variant ของสินค้าตามลำดับการแสดงผล
+// ใช้กับการดูสินค้าทีละตัวเท่านั้น query รายการสินค้าใช้สต็อกรวมที่เก็บไว้ในสินค้าหลักแทน
+func preloadVariants(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("Options.Values", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Variants.OptionValues.Option")
+}
+
+// variantOutOfStockError สร้าง OutOfStockError สำหรับ variant ตัวเดียว
+func variantOutOfStockError(product *models.Product, variant *models.ProductVariant, requested int) error {
+	entity := variantModelToEntity(variant, product)
+	return &entities.OutOfStockError{Items: []entities.OutOfStockItem{{
+		ProductID: product.ID,
+		VariantID: &variant.ID,
+		Name:      fmt.Sprintf("%s (%s)", product.Name, entity.Name),
+		Requested: requested,
+		Available: variant.Stock,
+	}}}
+}
+
+func optionModelToEntity(option *models.ProductOption) entities.ProductOption {
+	entity := entities.ProductOption{
+		ID:        option.ID,
+		ProductID: option.ProductID,
+		Name:      option.Name,
+		Position:  option.Position,
+		Values:    []entities.ProductOptionValue{},
+	}
+
+	for _, value := range option.Values {
+		entity.Values = append(entity.Values, entities.ProductOptionValue{
+			ID:       value.ID,
+			OptionID: value.OptionID,
+			Value:    value.Value,
+			Position: value.Position,
+		})
+	}
+
+	return entity
+}
+
+// variantModelToEntity แปลง variant โดยใช้ราคาของสินค้าหลักเมื่อ variant ไม่ได้กำหนดราคาเอง
+// ค่าตัวเลือกเรียงตามลำดับของตัวเลือก ถ้าไม่ได้ preload ตัวเลือกไว้จะไม่มีชื่อตัวเลือก
+func variantModelToEntity(variant *models.ProductVariant, product *models.Product) entities.ProductVariant {
+	entity := entities.ProductVariant{
+		ID:        variant.ID,
+		ProductID: variant.ProductID,
+		SKU:       variant.SKU,
+		Price:     entities.NewMoney(product.Price, product.Currency),
+		Stock:     variant.Stock,
+		Image:     variant.Image,
+		Options:   []entities.VariantOption{},
+		CreatedAt: variant.CreatedAt,
+		UpdatedAt: variant.UpdatedAt,
+	}
+
+	if variant.Price != nil {
+		entity.Price = entities.NewMoney(*variant.Price, product.Currency)
+		override := entity.Price
+		entity.PriceOverride = &override
+	}
+
+	values := make([]models.ProductOptionValue, len(variant.OptionValues))
+	copy(values, variant.OptionValues)
+	sort.Slice(values, func(i, j int) bool {
+		return optionPosition(values[i]) < optionPosition(values[j])
+	})
+	for _, value := range values {
+		option := entities.VariantOption{
+			OptionID: value.OptionID,
+			ValueID:  value.ID,
+			Value:    value.Value,
+		}
+		if value.Option != nil {
+			option.Name = value.Option.Name
+		}
+		entity.Options = append(entity.Options, option)
+	}
+	entity.Name = entities.VariantName(entity.Options)
+
+	return entity
+}
+
+func optionPosition(value models.ProductOptionValue) int {
+	if value.Option == nil {
+		return 0
+	}
+	return value.Option.Position
+}
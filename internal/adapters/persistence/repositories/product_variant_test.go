@@ -0,0 +1,232 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+func TestCreateOrder_DecrementsVariantStock(t *testing.T) {
+	d := newTestData(t)
+	db := d.db
+	ctx := context.Background()
+
+	user := d.user()
+	product := d.product(models.Product{Name: "T-shirt", Price: 29900, Currency: "THB"})
+	shippingMethod := d.shippingMethod()
+
+	variantRepo := repositories.NewProductVariantRepository(db)
+	cartRepo := repositories.NewCartRepository(db)
+	orderRepo := repositories.NewOrderRepository(db)
+
+	option, err := variantRepo.CreateOption(ctx, product.ID, &entities.CreateProductOptionRequest{Name: "Size", Values: []string{"S", "M"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	small, err := variantRepo.Create(ctx, product.ID, user.ID, &entities.CreateVariantRequest{
+		SKU:            "TS-S-" + uuid.NewString(),
+		Stock:          2,
+		OptionValueIDs: []uuid.UUID{option.Values[0].ID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	price := entities.NewMoney(34900, "THB")
+	medium, err := variantRepo.Create(ctx, product.ID, user.ID, &entities.CreateVariantRequest{
+		SKU:            "TS-M-" + uuid.NewString(),
+		Price:          &price,
+		Stock:          1,
+		OptionValueIDs: []uuid.UUID{option.Values[1].ID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ชุดค่าตัวเลือกซ้ำกับ variant ที่มีอยู่ต้องถูกปฏิเสธ
+	if _, err := variantRepo.Create(ctx, product.ID, user.ID, &entities.CreateVariantRequest{
+		SKU:            "TS-S2-" + uuid.NewString(),
+		OptionValueIDs: []uuid.UUID{option.Values[0].ID},
+	}); !errors.Is(err, entities.ErrInvalidVariantOptions) {
+		t.Fatalf("expected ErrInvalidVariantOptions, got %v", err)
+	}
+
+	if err := cartRepo.AddItem(ctx, user.ID, &entities.AddToCartRequest{ProductID: product.ID, Quantity: 1}); !errors.Is(err, entities.ErrVariantRequired) {
+		t.Fatalf("expected ErrVariantRequired, got %v", err)
+	}
+	if err := cartRepo.AddItem(ctx, user.ID, &entities.AddToCartRequest{ProductID: product.ID, VariantID: &medium.ID, Quantity: 2}); !errors.Is(err, entities.ErrOutOfStock) {
+		t.Fatalf("expected ErrOutOfStock for medium, got %v", err)
+	}
+	if err := cartRepo.AddItem(ctx, user.ID, &entities.AddToCartRequest{ProductID: product.ID, VariantID: &small.ID, Quantity: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cartRepo.AddItem(ctx, user.ID, &entities.AddToCartRequest{ProductID: product.ID, VariantID: &medium.ID, Quantity: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := orderRepo.Create(ctx, user.ID, &entities.CreateOrderRequest{PaymentMethod: "bank_transfer", ShippingMethod: shippingMethod.Code, ShippingAddress: "Bangkok"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	skus := map[string]int64{}
+	for _, item := range order.OrderItems {
+		skus[item.SKU] = item.Price.Amount
+	}
+	if skus[small.SKU] != 29900 || skus[medium.SKU] != 34900 {
+		t.Errorf("expected variant SKUs with base and override prices, got %v", skus)
+	}
+
+	var variantStock []int
+	if err := db.Model(&models.ProductVariant{}).Where("product_id = ?", product.ID).Order("sku ASC").Pluck("stock", &variantStock).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(variantStock) != 2 || variantStock[0] != 0 || variantStock[1] != 0 {
+		t.Errorf("expected both variants to be sold out, got %v", variantStock)
+	}
+
+	// สต็อกของสินค้าหลักต้องเท่ากับผลรวมของ variant
+	var productStock int
+	if err := db.Model(&models.Product{}).Select("stock").Where("id = ?", product.ID).Scan(&productStock).Error; err != nil {
+		t.Fatal(err)
+	}
+	if productStock != 0 {
+		t.Errorf("expected product stock to be 0, got %d", productStock)
+	}
+
+	// คืนสต็อกเมื่อยกเลิกต้องกลับไปที่ variant เดิม
+	if err := orderRepo.Cancel(ctx, order.ID, user.ID, "test"); err != nil {
+		t.Fatal(err)
+	}
+	restocked, err := variantRepo.GetByID(ctx, product.ID, small.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restocked.Stock != 2 {
+		t.Errorf("expected small variant stock to be restored to 2, got %d", restocked.Stock)
+	}
+}
+
+func TestCancelOrder_RestocksItemSoldBeforeVariantsExisted(t *testing.T) {
+	d := newTestData(t)
+	db := d.db
+	ctx := context.Background()
+
+	user := d.user()
+	product := d.product(models.Product{Name: "Mug", Price: 15000, Stock: 5, Currency: "THB"})
+	shippingMethod := d.shippingMethod()
+
+	variantRepo := repositories.NewProductVariantRepository(db)
+	cartRepo := repositories.NewCartRepository(db)
+	orderRepo := repositories.NewOrderRepository(db)
+
+	// สั่งซื้อตอนที่สินค้ายังไม่มี variant
+	if err := cartRepo.AddItem(ctx, user.ID, &entities.AddToCartRequest{ProductID: product.ID, Quantity: 2}); err != nil {
+		t.Fatal(err)
+	}
+	order, err := orderRepo.Create(ctx, user.ID, &entities.CreateOrderRequest{PaymentMethod: "bank_transfer", ShippingMethod: shippingMethod.Code, ShippingAddress: "Bangkok"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ร้านเพิ่ม variant ทีหลัง
+	option, err := variantRepo.CreateOption(ctx, product.ID, &entities.CreateProductOptionRequest{Name: "Color", Values: []string{"White"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := variantRepo.Create(ctx, product.ID, user.ID, &entities.CreateVariantRequest{
+		SKU:            "MUG-W-" + uuid.NewString(),
+		Stock:          4,
+		OptionValueIDs: []uuid.UUID{option.Values[0].ID},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var before int
+	if err := db.Model(&models.Product{}).Select("stock").Where("id = ?", product.ID).Scan(&before).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// การยกเลิกคำสั่งซื้อเดิมต้องคืนสต็อกเข้าสินค้าหลักได้ แม้รายการจะไม่มี variant
+	if err := orderRepo.Cancel(ctx, order.ID, user.ID, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	var after int
+	if err := db.Model(&models.Product{}).Select("stock").Where("id = ?", product.ID).Scan(&after).Error; err != nil {
+		t.Fatal(err)
+	}
+	if after != before+2 {
+		t.Errorf("expected product stock %d after cancellation, got %d", before+2, after)
+	}
+
+	// แต่การขายใหม่ยังต้องระบุ variant
+	if err := cartRepo.AddItem(ctx, user.ID, &entities.AddToCartRequest{ProductID: product.ID, Quantity: 1}); !errors.Is(err, entities.ErrVariantRequired) {
+		t.Fatalf("expected ErrVariantRequired, got %v", err)
+	}
+}
+
+func TestCancelOrder_SkipsRestockOfDeletedProduct(t *testing.T) {
+	d := newTestData(t)
+	db := d.db
+	ctx := context.Background()
+
+	user := d.user()
+	product := d.product(models.Product{Name: "Cap", Price: 25000, Currency: "THB"})
+	shippingMethod := d.shippingMethod()
+
+	variantRepo := repositories.NewProductVariantRepository(db)
+	cartRepo := repositories.NewCartRepository(db)
+	orderRepo := repositories.NewOrderRepository(db)
+
+	option, err := variantRepo.CreateOption(ctx, product.ID, &entities.CreateProductOptionRequest{Name: "Size", Values: []string{"L"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	variant, err := variantRepo.Create(ctx, product.ID, user.ID, &entities.CreateVariantRequest{
+		SKU:            "CAP-L-" + uuid.NewString(),
+		Stock:          4,
+		OptionValueIDs: []uuid.UUID{option.Values[0].ID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cartRepo.AddItem(ctx, user.ID, &entities.AddToCartRequest{ProductID: product.ID, VariantID: &variant.ID, Quantity: 1}); err != nil {
+		t.Fatal(err)
+	}
+	order, err := orderRepo.Create(ctx, user.ID, &entities.CreateOrderRequest{PaymentMethod: "bank_transfer", ShippingMethod: shippingMethod.Code, ShippingAddress: "Bangkok"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ร้านลบสินค้าหลังมีคำสั่งซื้อ แต่ variant ยังอยู่
+	if err := db.Delete(&models.Product{}, "id = ?", product.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// การยกเลิกต้องสำเร็จ โดยไม่เพิ่มสต็อกของ variant ฝั่งเดียวและไม่บันทึก ledger ที่ไม่มีการเปลี่ยนสต็อกจริง
+	if err := orderRepo.Cancel(ctx, order.ID, user.ID, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	var variantStock int
+	if err := db.Model(&models.ProductVariant{}).Select("stock").Where("id = ?", variant.ID).Scan(&variantStock).Error; err != nil {
+		t.Fatal(err)
+	}
+	if variantStock != 3 {
+		t.Errorf("expected variant stock to stay 3, got %d", variantStock)
+	}
+
+	var movements int64
+	if err := db.Model(&models.InventoryMovement{}).Where("reference_id = ? AND reason = ?", order.ID, string(entities.InventoryReasonCancellation)).Count(&movements).Error; err != nil {
+		t.Fatal(err)
+	}
+	if movements != 0 {
+		t.Errorf("expected no cancellation movements, got %d", movements)
+	}
+}
@@ -0,0 +1,279 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) repositories.PromotionRepository {
+	return &promotionRepository{db: db}
+}
+
+func (r *promotionRepository) Create(ctx context.Context, req *entities.CreatePromotionRequest) (*entities.Promotion, error) {
+	// นับรวมโปรโมชันที่ถูกลบไปแล้ว เพราะ unique index ของ code ไม่สนใจ soft delete
+	var count int64
+	if err := r.db.WithContext(ctx).Unscoped().Model(&models.Promotion{}).Where("code = ?", req.Code).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, entities.ErrPromotionCodeExists
+	}
+
+	currency := req.Amount.Currency
+	if currency == "" {
+		currency = req.MinSpend.Currency
+	}
+
+	promotion := &models.Promotion{
+		Code:         req.Code,
+		Name:         req.Name,
+		Type:         string(req.Type),
+		Rate:         req.Rate,
+		Amount:       req.Amount.Amount,
+		MinSpend:     req.MinSpend.Amount,
+		Currency:     entities.NewMoney(0, currency).Currency,
+		UsageLimit:   req.UsageLimit,
+		PerUserLimit: req.PerUserLimit,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		Active:       req.Active == nil || *req.Active,
+		Products:     productRefs(req.ProductIDs),
+		Categories:   categoryRefs(req.CategoryIDs),
+	}
+
+	// Omit("*.*") สร้างเฉพาะแถวในตารางเชื่อม ไม่ไปสร้างหรือแก้ไขสินค้าและหมวดหมู่
+	if err := r.db.WithContext(ctx).Omit("Products.*", "Categories.*").Create(promotion).Error; err != nil {
+		return nil, err
+	}
+
+	return promotionModelToEntity(promotion), nil
+}
+
+func (r *promotionRepository) GetAll(ctx context.Context, page, limit int) ([]*entities.Promotion, int, error) {
+	var promotions []models.Promotion
+	var total int64
+
+	offset := (page - 1) * limit
+
+	if err := r.db.WithContext(ctx).Model(&models.Promotion{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.db.WithContext(ctx).Preload("Products").Preload("Categories").
+		Order("created_at DESC").Offset(offset).Limit(limit).Find(&promotions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// จำนวนครั้งที่ใช้ไปแล้วของทุกโปรโมชันในหน้านี้
+	ids := make([]uuid.UUID, 0, len(promotions))
+	for _, promotion := range promotions {
+		ids = append(ids, promotion.ID)
+	}
+	var usage []struct {
+		PromotionID uuid.UUID
+		Count       int
+	}
+	if err := r.db.WithContext(ctx).Model(&models.PromotionRedemption{}).
+		Select("promotion_id, COUNT(*) AS count").
+		Where("promotion_id IN ?", ids).
+		Group("promotion_id").
+		Scan(&usage).Error; err != nil {
+		return nil, 0, err
+	}
+	usedCount := make(map[uuid.UUID]int, len(usage))
+	for _, row := range usage {
+		usedCount[row.PromotionID] = row.Count
+	}
+
+	var result []*entities.Promotion
+	for _, promotion := range promotions {
+		entity := promotionModelToEntity(&promotion)
+		entity.UsedCount = usedCount[promotion.ID]
+		result = append(result, entity)
+	}
+
+	return result, int(total), nil
+}
+
+func (r *promotionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Promotion, error) {
+	return r.getBy(ctx, "id = ?", id)
+}
+
+func (r *promotionRepository) GetByCode(ctx context.Context, code string) (*entities.Promotion, error) {
+	return r.getBy(ctx, "code = ?", code)
+}
+
+func (r *promotionRepository) getBy(ctx context.Context, query string, value interface{}) (*entities.Promotion, error) {
+	var promotion models.Promotion
+	if err := r.db.WithContext(ctx).Preload("Products").Preload("Categories").Where(query, value).First(&promotion).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrPromotionNotFound
+		}
+		return nil, err
+	}
+
+	var used int64
+	if err := r.db.WithContext(ctx).Model(&models.PromotionRedemption{}).Where("promotion_id = ?", promotion.ID).Count(&used).Error; err != nil {
+		return nil, err
+	}
+
+	entity := promotionModelToEntity(&promotion)
+	entity.UsedCount = int(used)
+	return entity, nil
+}
+
+func (r *promotionRepository) Update(ctx context.Context, id uuid.UUID, req *entities.UpdatePromotionRequest) error {
+	updates := map[string]interface{}{}
+
+	if req.Name != "" {
+		updates["name"] = req.Name
+	}
+	if req.Rate != nil {
+		updates["rate"] = *req.Rate
+	}
+	if req.Amount != nil {
+		updates["amount"] = req.Amount.Amount
+	}
+	if req.MinSpend != nil {
+		updates["min_spend"] = req.MinSpend.Amount
+	}
+	if req.UsageLimit != nil {
+		updates["usage_limit"] = *req.UsageLimit
+	}
+	if req.PerUserLimit != nil {
+		updates["per_user_limit"] = *req.PerUserLimit
+	}
+	if req.StartsAt != nil {
+		updates["starts_at"] = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		updates["ends_at"] = *req.EndsAt
+	}
+	if req.Active != nil {
+		updates["active"] = *req.Active
+	}
+
+	tx := r.db.WithContext(ctx).Begin()
+
+	var promotion models.Promotion
+	if err := tx.First(&promotion, "id = ?", id).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.ErrPromotionNotFound
+		}
+		return err
+	}
+
+	if len(updates) > 0 {
+		if err := tx.Model(&promotion).Updates(updates).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// แทนที่ขอบเขตสินค้าและหมวดหมู่ทั้งหมดถ้าส่งมา
+	if req.ProductIDs != nil {
+		if err := tx.Model(&promotion).Omit("Products.*").Association("Products").Replace(productRefs(*req.ProductIDs)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if req.CategoryIDs != nil {
+		if err := tx.Model(&promotion).Omit("Categories.*").Association("Categories").Replace(categoryRefs(*req.CategoryIDs)); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (r *promotionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&models.Promotion{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrPromotionNotFound
+	}
+
+	return nil
+}
+
+func (r *promotionRepository) CountRedemptions(ctx context.Context, promotionID, userID uuid.UUID) (int, int, error) {
+	return countRedemptions(r.db.WithContext(ctx), promotionID, userID)
+}
+
+// countRedemptions นับจำนวนครั้งที่ใช้คูปองไปแล้วทั้งหมด และของผู้ใช้คนนี้
+func countRedemptions(db *gorm.DB, promotionID, userID uuid.UUID) (int, int, error) {
+	var usage struct {
+		Total  int
+		ByUser int
+	}
+	if err := db.Model(&models.PromotionRedemption{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE user_id = ?) AS by_user", userID).
+		Where("promotion_id = ?", promotionID).
+		Scan(&usage).Error; err != nil {
+		return 0, 0, err
+	}
+
+	return usage.Total, usage.ByUser, nil
+}
+
+// productRefs สร้าง model ที่มีแค่ ID สำหรับใช้กับตารางเชื่อม many2many
+func productRefs(ids []uuid.UUID) []models.Product {
+	products := make([]models.Product, 0, len(ids))
+	for _, id := range ids {
+		products = append(products, models.Product{BaseModel: models.BaseModel{ID: id}})
+	}
+	return products
+}
+
+// categoryRefs สร้าง model ที่มีแค่ ID สำหรับใช้กับตารางเชื่อม many2many
+func categoryRefs(ids []uuid.UUID) []models.Category {
+	categories := make([]models.Category, 0, len(ids))
+	for _, id := range ids {
+		categories = append(categories, models.Category{BaseModel: models.BaseModel{ID: id}})
+	}
+	return categories
+}
+
+// promotionModelToEntity แปลง model เป็น entity ใช้ร่วมกับ orderRepository ตอน checkout
+// ต้อง preload Products และ Categories มาด้วยเพื่อให้ได้ขอบเขตของโปรโมชัน
+func promotionModelToEntity(promotion *models.Promotion) *entities.Promotion {
+	entity := &entities.Promotion{
+		ID:           promotion.ID,
+		Code:         promotion.Code,
+		Name:         promotion.Name,
+		Type:         entities.PromotionType(promotion.Type),
+		Rate:         promotion.Rate,
+		Amount:       entities.NewMoney(promotion.Amount, promotion.Currency),
+		MinSpend:     entities.NewMoney(promotion.MinSpend, promotion.Currency),
+		UsageLimit:   promotion.UsageLimit,
+		PerUserLimit: promotion.PerUserLimit,
+		StartsAt:     promotion.StartsAt,
+		EndsAt:       promotion.EndsAt,
+		Active:       promotion.Active,
+		CreatedAt:    promotion.CreatedAt,
+		UpdatedAt:    promotion.UpdatedAt,
+	}
+
+	for _, product := range promotion.Products {
+		entity.ProductIDs = append(entity.ProductIDs, product.ID)
+	}
+	for _, category := range promotion.Categories {
+		entity.CategoryIDs = append(entity.CategoryIDs, category.ID)
+	}
+
+	return entity
+}
@@ -0,0 +1,359 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// สถานะการคืนเงินที่นับรวมเป็นยอดที่คืนไปแล้ว (pending คือกำลังคืนเงินอยู่ที่ gateway)
+var activeRefundStatuses = []string{string(entities.RefundStatusPending), string(entities.RefundStatusCompleted)}
+
+type refundRepository struct {
+	db *gorm.DB
+}
+
+func NewRefundRepository(db *gorm.DB) repositories.RefundRepository {
+	return &refundRepository{db: db}
+}
+
+func (r *refundRepository) Create(ctx context.Context, transactionID uuid.UUID, req *entities.CreateRefundRequest, actorID uuid.UUID) (*entities.Refund, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	// ล็อคแถวธุรกรรมไว้ การคืนเงินของธุรกรรมเดียวกันจะทำทีละรายการ เพื่อไม่ให้ยอดรวมเกินยอดที่ชำระ
+	var transaction models.Transaction
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&transaction, "id = ?", transactionID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrPaymentNotFound
+		}
+		return nil, err
+	}
+
+	if transaction.Status != string(entities.TransactionStatusCompleted) {
+		tx.Rollback()
+		return nil, entities.ErrRefundNotAllowed
+	}
+
+	// คำสั่งซื้อที่ยกเลิกแล้วคืนสต็อกทุกรายการไปตอนยกเลิก การคืนสต็อกอีกครั้งจะทำให้สต็อกเกินจริง
+	if req.Restock {
+		var orderStatus string
+		if err := tx.Model(&models.Order{}).Select("status").Where("id = ?", transaction.OrderID).Scan(&orderStatus).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if orderStatus == string(entities.OrderStatusCancelled) {
+			tx.Rollback()
+			return nil, entities.ErrRestockCancelledOrder
+		}
+	}
+
+	// ยอดคืนเงินเป็นสกุลเงินเดียวกับธุรกรรมเสมอ
+	if req.Amount != nil && req.Amount.Currency != transaction.Currency {
+		tx.Rollback()
+		return nil, fmt.Errorf("%w: ธุรกรรมนี้ชำระเป็น %s", entities.ErrCurrencyMismatch, transaction.Currency)
+	}
+
+	var refunded int64
+	if err := tx.Model(&models.Refund{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("transaction_id = ? AND status IN ?", transactionID, activeRefundStatuses).
+		Scan(&refunded).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	remaining := transaction.Amount - refunded
+
+	items, err := r.refundItems(tx, transaction.OrderID, req, remaining)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// ยอดคืนเงิน: ตามที่ระบุ หรือยอดรวมของสินค้าที่คืน หรือยอดที่เหลือทั้งหมด
+	amount := remaining
+	switch {
+	case req.Amount != nil:
+		amount = req.Amount.Amount
+	case len(req.Items) > 0:
+		amount = 0
+		for _, item := range items {
+			amount += item.Amount
+		}
+	}
+
+	if amount <= 0 || amount > remaining {
+		tx.Rollback()
+		return nil, fmt.Errorf("%w: ขอคืน %s คืนได้อีก %s", entities.ErrRefundExceedsCaptured,
+			entities.NewMoney(amount, transaction.Currency), entities.NewMoney(remaining, transaction.Currency))
+	}
+
+	refund := &models.Refund{
+		TransactionID: transactionID,
+		OrderID:       transaction.OrderID,
+		Amount:        amount,
+		Reason:        req.Reason,
+		Restock:       req.Restock,
+		Status:        string(entities.RefundStatusPending),
+		Items:         items,
+	}
+	if actorID != uuid.Nil {
+		refund.ActorID = &actorID
+	}
+
+	if err := tx.Create(refund).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return r.modelToEntity(refund, transaction.Currency), nil
+}
+
+// refundItems ตรวจสอบรายการสินค้าที่ขอคืนกับ OrderItem ของคำสั่งซื้อ และจำนวนที่เคยคืนไปแล้ว
+// ถ้าต้องคืนสต็อกแต่ไม่ได้ระบุสินค้า จะคืนสินค้าทุกรายการที่ยังไม่ได้คืน ซึ่งทำได้เฉพาะการคืนเงินเต็มจำนวนที่เหลือ
+func (r *refundRepository) refundItems(tx *gorm.DB, orderID uuid.UUID, req *entities.CreateRefundRequest, remaining int64) ([]models.RefundItem, error) {
+	if len(req.Items) == 0 && !req.Restock {
+		return nil, nil
+	}
+
+	var orderItems []models.OrderItem
+	if err := tx.Where("order_id = ?", orderID).Find(&orderItems).Error; err != nil {
+		return nil, err
+	}
+
+	// จำนวนที่เคยคืนไปแล้วของแต่ละ OrderItem
+	var refundedRows []struct {
+		OrderItemID uuid.UUID
+		Quantity    int
+	}
+	if err := tx.Model(&models.RefundItem{}).
+		Select("refund_items.order_item_id, COALESCE(SUM(refund_items.quantity), 0) AS quantity").
+		Joins("JOIN refunds ON refunds.id = refund_items.refund_id AND refunds.deleted_at IS NULL").
+		Where("refunds.status IN ? AND refund_items.order_item_id IN (?)", activeRefundStatuses,
+			tx.Model(&models.OrderItem{}).Select("id").Where("order_id = ?", orderID)).
+		Group("refund_items.order_item_id").
+		Scan(&refundedRows).Error; err != nil {
+		return nil, err
+	}
+	refundedQuantity := make(map[uuid.UUID]int, len(refundedRows))
+	for _, row := range refundedRows {
+		refundedQuantity[row.OrderItemID] = row.Quantity
+	}
+
+	var items []models.RefundItem
+
+	if len(req.Items) == 0 {
+		if req.Amount != nil && req.Amount.Amount != remaining {
+			return nil, fmt.Errorf("%w: ต้องระบุสินค้าที่จะคืนสต็อกเมื่อคืนเงินบางส่วน", entities.ErrInvalidRefundItem)
+		}
+		for _, orderItem := range orderItems {
+			if quantity := orderItem.Quantity - refundedQuantity[orderItem.ID]; quantity > 0 {
+				items = append(items, newRefundItem(&orderItem, quantity))
+			}
+		}
+		return items, nil
+	}
+
+	orderItemsByID := make(map[uuid.UUID]*models.OrderItem, len(orderItems))
+	for i := range orderItems {
+		orderItemsByID[orderItems[i].ID] = &orderItems[i]
+	}
+
+	seen := make(map[uuid.UUID]bool, len(req.Items))
+	for _, requested := range req.Items {
+		orderItem, ok := orderItemsByID[requested.OrderItemID]
+		if !ok {
+			return nil, fmt.Errorf("%w: ไม่พบสินค้า %s ในคำสั่งซื้อนี้", entities.ErrInvalidRefundItem, requested.OrderItemID)
+		}
+		if seen[requested.OrderItemID] {
+			return nil, fmt.Errorf("%w: สินค้า %s ถูกระบุซ้ำ", entities.ErrInvalidRefundItem, requested.OrderItemID)
+		}
+		seen[requested.OrderItemID] = true
+
+		available := orderItem.Quantity - refundedQuantity[orderItem.ID]
+		if requested.Quantity > available {
+			return nil, fmt.Errorf("%w: สินค้า %s ขอคืน %d ชิ้น คืนได้อีก %d ชิ้น", entities.ErrInvalidRefundItem, requested.OrderItemID, requested.Quantity, available)
+		}
+
+		items = append(items, newRefundItem(orderItem, requested.Quantity))
+	}
+
+	return items, nil
+}
+
+// newRefundItem ยอดคืนเงินของสินค้าคิดตามสัดส่วนของยอดรวมภาษีที่ลูกค้าจ่ายจริงในรายการนั้น
+func newRefundItem(orderItem *models.OrderItem, quantity int) models.RefundItem {
+	return models.RefundItem{
+		OrderItemID: orderItem.ID,
+		ProductID:   orderItem.ProductID,
+		VariantID:   orderItem.VariantID,
+		Quantity:    quantity,
+		Amount:      orderItem.Total * int64(quantity) / int64(orderItem.Quantity),
+	}
+}
+
+// Complete บันทึกว่าคืนเงินสำเร็จ คืนสต็อกถ้าต้องการ และเปลี่ยนสถานะการชำระเงินของคำสั่งซื้อ
+// เป็น refunded เมื่อคืนครบยอดที่ชำระสำเร็จทุกธุรกรรมของคำสั่งซื้อ หรือ partially_refunded ถ้ายังคืนไม่ครบ
+// เงินถูกคืนที่ gateway ไปแล้วตอนเรียกฟังก์ชันนี้ ถ้าสถานะของคำสั่งซื้อเปลี่ยนตามไม่ได้
+// (เช่น ยกเลิกไปก่อนที่ธุรกรรมจะชำระสำเร็จ) จะปิดการคืนเงินนี้โดยไม่เปลี่ยนสถานะคำสั่งซื้อ
+func (r *refundRepository) Complete(ctx context.Context, id uuid.UUID, providerRef string, actorID uuid.UUID) (*entities.Refund, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	var refund models.Refund
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&refund, "id = ?", id).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if refund.Status != string(entities.RefundStatusPending) {
+		tx.Rollback()
+		return nil, fmt.Errorf("การคืนเงินนี้อยู่ในสถานะ %s แล้ว", refund.Status)
+	}
+
+	if err := tx.Where("refund_id = ?", id).Find(&refund.Items).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	refund.Status = string(entities.RefundStatusCompleted)
+	refund.ProviderRef = providerRef
+	if err := tx.Model(&refund).Updates(map[string]interface{}{
+		"status":       refund.Status,
+		"provider_ref": refund.ProviderRef,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var transaction models.Transaction
+	if err := tx.First(&transaction, "id = ?", refund.TransactionID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	note := fmt.Sprintf("refund %s: %s", entities.NewMoney(refund.Amount, transaction.Currency), refund.Reason)
+
+	// ล็อคคำสั่งซื้อก่อนคืนสต็อก เพื่อให้ทำทีละรายการกับการยกเลิกคำสั่งซื้อที่คืนสต็อกเหมือนกัน
+	// ถ้าคำสั่งซื้อถูกยกเลิกหลังจากสร้างการคืนเงินนี้ สต็อกถูกคืนไปแล้วตอนยกเลิก จึงคืนเฉพาะเงิน
+	var order models.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status").First(&order, "id = ?", refund.OrderID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// คืนสต็อกพร้อมบันทึกลง ledger
+	if refund.Restock && order.Status != string(entities.OrderStatusCancelled) {
+		for _, item := range refund.Items {
+			if err := restock(tx, item.ProductID, item.VariantID, item.Quantity, entities.InventoryReasonReturn, &refund.ID, actorID, note); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+	}
+
+	// คำสั่งซื้อหนึ่งรายการมีธุรกรรมที่ชำระสำเร็จได้หลายรายการ จึงเทียบยอดคืนเงินกับยอดที่ชำระทั้งหมดของคำสั่งซื้อ
+	var captured, refunded int64
+	if err := tx.Model(&models.Transaction{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("order_id = ? AND status = ?", refund.OrderID, string(entities.TransactionStatusCompleted)).
+		Scan(&captured).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Model(&models.Refund{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("order_id = ? AND status = ?", refund.OrderID, string(entities.RefundStatusCompleted)).
+		Scan(&refunded).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	paymentStatus := entities.PaymentStatusPartiallyRefunded
+	if refunded >= captured {
+		paymentStatus = entities.PaymentStatusRefunded
+	}
+
+	var transitionErr *entities.StatusTransitionError
+	if err := changeOrderStatus(tx, refund.OrderID, entities.StatusAxisPayment, string(paymentStatus), nil, actorID, note); err != nil && !errors.As(err, &transitionErr) {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return r.modelToEntity(&refund, transaction.Currency), nil
+}
+
+// Fail บันทึกว่าคืนเงินไม่สำเร็จ ยอดของการคืนเงินนี้จะไม่ถูกนับรวมอีก
+func (r *refundRepository) Fail(ctx context.Context, id uuid.UUID, reason string) error {
+	return r.db.WithContext(ctx).Model(&models.Refund{}).
+		Where("id = ? AND status = ?", id, string(entities.RefundStatusPending)).
+		Updates(map[string]interface{}{
+			"status":         string(entities.RefundStatusFailed),
+			"failure_reason": reason,
+		}).Error
+}
+
+func (r *refundRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*entities.Refund, error) {
+	var transaction models.Transaction
+	if err := r.db.WithContext(ctx).Select("id", "currency").First(&transaction, "id = ?", transactionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrPaymentNotFound
+		}
+		return nil, err
+	}
+
+	var refunds []models.Refund
+	if err := r.db.WithContext(ctx).Preload("Items").Where("transaction_id = ?", transactionID).Order("created_at DESC").Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+
+	var result []*entities.Refund
+	for _, refund := range refunds {
+		result = append(result, r.modelToEntity(&refund, transaction.Currency))
+	}
+
+	return result, nil
+}
+
+// modelToEntity แปลง model เป็น entity โดยจำนวนเงินใช้สกุลเงินของธุรกรรมที่คืนเงิน
+func (r *refundRepository) modelToEntity(refund *models.Refund, currency string) *entities.Refund {
+	entity := &entities.Refund{
+		ID:            refund.ID,
+		TransactionID: refund.TransactionID,
+		OrderID:       refund.OrderID,
+		Amount:        entities.NewMoney(refund.Amount, currency),
+		Reason:        refund.Reason,
+		Restock:       refund.Restock,
+		Status:        entities.RefundStatus(refund.Status),
+		ProviderRef:   refund.ProviderRef,
+		FailureReason: refund.FailureReason,
+		ActorID:       refund.ActorID,
+		CreatedAt:     refund.CreatedAt,
+		UpdatedAt:     refund.UpdatedAt,
+	}
+
+	for _, item := range refund.Items {
+		entity.Items = append(entity.Items, entities.RefundItem{
+			ID:          item.ID,
+			OrderItemID: item.OrderItemID,
+			ProductID:   item.ProductID,
+			Quantity:    item.Quantity,
+			Amount:      entities.NewMoney(item.Amount, currency),
+		})
+	}
+
+	return entity
+}
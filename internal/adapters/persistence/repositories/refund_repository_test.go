@@ -0,0 +1,166 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+func TestRefund_DoesNotRestockCancelledOrderTwice(t *testing.T) {
+	d := newTestData(t)
+	ctx := context.Background()
+
+	admin := d.user()
+	product := d.product(models.Product{Name: "Cancelled mug", Price: 5000, Stock: 8})
+
+	// คำสั่งซื้อที่ชำระเงินแล้วแต่ยังไม่จัดส่ง สต็อกถูกตัดไปแล้ว 2 ชิ้น
+	order := models.Order{
+		UserID:        d.user().ID,
+		Subtotal:      10000,
+		TotalPrice:    10000,
+		Currency:      entities.DefaultCurrency,
+		PaymentMethod: entities.PaymentMethodBankTransfer,
+		PaymentStatus: string(entities.PaymentStatusPaid),
+		OrderItems:    []models.OrderItem{{ProductID: product.ID, Quantity: 2, Price: 5000, Subtotal: 10000, Total: 10000}},
+	}
+	d.create(&order)
+	transaction := models.Transaction{
+		OrderID:       order.ID,
+		Amount:        order.TotalPrice,
+		Currency:      order.Currency,
+		PaymentMethod: order.PaymentMethod,
+		Status:        string(entities.TransactionStatusCompleted),
+		TransactionID: "TXN_TEST_" + uuid.NewString()[:8],
+	}
+	d.create(&transaction)
+
+	refundRepo := repositories.NewRefundRepository(d.db)
+	orderRepo := repositories.NewOrderRepository(d.db)
+
+	// คำสั่งซื้อที่ชำระแล้วยกเลิกไม่ได้จนกว่าจะคืนเงินครบ
+	var transitionErr *entities.StatusTransitionError
+	if err := orderRepo.Cancel(ctx, order.ID, admin.ID, "cancelled after payment"); !errors.As(err, &transitionErr) {
+		t.Fatalf("expected StatusTransitionError when cancelling a paid order, got %v", err)
+	}
+
+	// คืนเงินเต็มจำนวนพร้อมคืนสต็อก แล้วจึงยกเลิก การยกเลิกต้องไม่คืนสต็อกซ้ำ
+	refund, err := refundRepo.Create(ctx, transaction.ID, &entities.CreateRefundRequest{Reason: "customer cancelled", Restock: true}, admin.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := refundRepo.Complete(ctx, refund.ID, "refund_ref", admin.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := orderRepo.Cancel(ctx, order.ID, admin.ID, "cancelled after refund"); err != nil {
+		t.Fatal(err)
+	}
+
+	var stock int
+	if err := d.db.Model(&models.Product{}).Select("stock").Where("id = ?", product.ID).Scan(&stock).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stock != 10 {
+		t.Errorf("expected the refund and cancellation to restock once to 10, got %d", stock)
+	}
+
+	// ขอคืนสต็อกของคำสั่งซื้อที่ยกเลิกแล้วต้องถูกปฏิเสธตั้งแต่ตอนสร้าง
+	if _, err := refundRepo.Create(ctx, transaction.ID, &entities.CreateRefundRequest{Reason: "again", Restock: true}, admin.ID); !errors.Is(err, entities.ErrRestockCancelledOrder) {
+		t.Fatalf("expected ErrRestockCancelledOrder, got %v", err)
+	}
+}
+
+// completedTransaction สร้างธุรกรรมที่ชำระเงินสำเร็จแล้วของคำสั่งซื้อ
+func completedTransaction(d *testData, order models.Order, amount int64) models.Transaction {
+	transaction := models.Transaction{
+		OrderID:       order.ID,
+		Amount:        amount,
+		Currency:      order.Currency,
+		PaymentMethod: order.PaymentMethod,
+		Status:        string(entities.TransactionStatusCompleted),
+		TransactionID: "TXN_TEST_" + uuid.NewString()[:8],
+	}
+	d.create(&transaction)
+	return transaction
+}
+
+// refundOrderPaymentStatus คืนเงินเต็มจำนวนของธุรกรรม แล้วคืนสถานะการชำระเงินของคำสั่งซื้อ
+func refundOrderPaymentStatus(t *testing.T, d *testData, transaction models.Transaction, actorID uuid.UUID) string {
+	t.Helper()
+	ctx := context.Background()
+	refundRepo := repositories.NewRefundRepository(d.db)
+
+	refund, err := refundRepo.Create(ctx, transaction.ID, &entities.CreateRefundRequest{Reason: "duplicate charge"}, actorID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	completed, err := refundRepo.Complete(ctx, refund.ID, "refund_ref", actorID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if completed.Status != entities.RefundStatusCompleted {
+		t.Fatalf("expected refund to be completed, got %s", completed.Status)
+	}
+
+	var status string
+	if err := d.db.Model(&models.Order{}).Select("payment_status").Where("id = ?", transaction.OrderID).Scan(&status).Error; err != nil {
+		t.Fatal(err)
+	}
+	return status
+}
+
+func TestRefund_ComparesAgainstEveryCapturedTransaction(t *testing.T) {
+	d := newTestData(t)
+	admin := d.user()
+
+	// ลูกค้าถูกตัดเงินสองครั้งสำหรับคำสั่งซื้อเดียวกัน
+	order := models.Order{
+		UserID:        d.user().ID,
+		TotalPrice:    10000,
+		Currency:      entities.DefaultCurrency,
+		PaymentMethod: entities.PaymentMethodBankTransfer,
+		PaymentStatus: string(entities.PaymentStatusPaid),
+	}
+	d.create(&order)
+	first := completedTransaction(d, order, 10000)
+	second := completedTransaction(d, order, 10000)
+
+	if status := refundOrderPaymentStatus(t, d, second, admin.ID); status != string(entities.PaymentStatusPartiallyRefunded) {
+		t.Errorf("expected partially_refunded after refunding one of two captures, got %s", status)
+	}
+	if status := refundOrderPaymentStatus(t, d, first, admin.ID); status != string(entities.PaymentStatusRefunded) {
+		t.Errorf("expected refunded after refunding both captures, got %s", status)
+	}
+}
+
+func TestRefund_SettlesWhenOrderPaymentStatusCannotChange(t *testing.T) {
+	d := newTestData(t)
+	admin := d.user()
+
+	// ธุรกรรมชำระสำเร็จหลังจากคำสั่งซื้อถูกยกเลิกไปแล้ว คำสั่งซื้อจึงยังไม่ได้ชำระเงิน
+	order := models.Order{
+		UserID:        d.user().ID,
+		TotalPrice:    10000,
+		Currency:      entities.DefaultCurrency,
+		PaymentMethod: entities.PaymentMethodBankTransfer,
+		Status:        string(entities.OrderStatusCancelled),
+		PaymentStatus: string(entities.PaymentStatusPending),
+	}
+	d.create(&order)
+	transaction := completedTransaction(d, order, 10000)
+
+	if status := refundOrderPaymentStatus(t, d, transaction, admin.ID); status != string(entities.PaymentStatusPending) {
+		t.Errorf("expected the cancelled order payment status to stay pending, got %s", status)
+	}
+
+	// ยอดที่คืนแล้วต้องไม่ค้างเป็น pending จนคืนเงินซ้ำได้
+	var pending int64
+	d.db.Model(&models.Refund{}).Where("order_id = ? AND status = ?", order.ID, string(entities.RefundStatusPending)).Count(&pending)
+	if pending != 0 {
+		t.Errorf("expected no pending refunds, got %d", pending)
+	}
+}
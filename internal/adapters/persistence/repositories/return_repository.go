@@ -0,0 +1,459 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// สถานะของคำขอคืนสินค้าที่ยังจองจำนวนสินค้าไว้
+var openReturnStatuses = []string{
+	string(entities.ReturnStatusRequested),
+	string(entities.ReturnStatusApproved),
+	string(entities.ReturnStatusReceived),
+	string(entities.ReturnStatusInspected),
+}
+
+type returnRepository struct {
+	db *gorm.DB
+}
+
+func NewReturnRepository(db *gorm.DB) repositories.ReturnRepository {
+	return &returnRepository{db: db}
+}
+
+func (r *returnRepository) Create(ctx context.Context, userID uuid.UUID, req *entities.CreateReturnRequest) (*entities.Return, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	// ล็อคแถวคำสั่งซื้อไว้ คำขอคืนสินค้าของคำสั่งซื้อเดียวกันจะสร้างทีละรายการ เพื่อไม่ให้ขอคืนเกินจำนวนที่ซื้อ
+	var order models.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "id = ? AND user_id = ?", req.OrderID, userID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	var orderItems []models.OrderItem
+	if err := tx.Where("order_id = ?", req.OrderID).Find(&orderItems).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	unavailable, err := returnedQuantities(tx, req.OrderID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	items, err := returnItems(orderItems, unavailable, req.Items)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	ret := &models.Return{
+		OrderID: req.OrderID,
+		UserID:  userID,
+		Status:  string(entities.ReturnStatusRequested),
+		Reason:  req.Reason,
+		Items:   items,
+		Events: []models.ReturnEvent{{
+			ToStatus: string(entities.ReturnStatusRequested),
+			ActorID:  &userID,
+			Note:     req.Reason,
+		}},
+	}
+
+	if err := tx.Create(ret).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return returnModelToEntity(ret), nil
+}
+
+// returnedQuantities จำนวนที่ขอคืนไม่ได้แล้วของแต่ละ OrderItem
+// คือจำนวนในคำขอคืนสินค้าที่ยังไม่จบ รวมกับจำนวนที่คืนเงินไปแล้ว
+// คำขอที่ผูกการคืนเงินแล้วนับผ่าน RefundItem อย่างเดียว เพื่อไม่ให้นับจำนวนเดียวกันซ้ำ
+func returnedQuantities(tx *gorm.DB, orderID uuid.UUID) (map[uuid.UUID]int, error) {
+	var returnedRows []struct {
+		OrderItemID uuid.UUID
+		Quantity    int
+	}
+	if err := tx.Model(&models.ReturnItem{}).
+		Select("return_items.order_item_id, COALESCE(SUM(return_items.quantity), 0) AS quantity").
+		Joins("JOIN returns ON returns.id = return_items.return_id AND returns.deleted_at IS NULL").
+		Where("returns.order_id = ? AND returns.status IN ? AND returns.refund_id IS NULL", orderID, openReturnStatuses).
+		Group("return_items.order_item_id").
+		Scan(&returnedRows).Error; err != nil {
+		return nil, err
+	}
+
+	var refundedRows []struct {
+		OrderItemID uuid.UUID
+		Quantity    int
+	}
+	if err := tx.Model(&models.RefundItem{}).
+		Select("refund_items.order_item_id, COALESCE(SUM(refund_items.quantity), 0) AS quantity").
+		Joins("JOIN refunds ON refunds.id = refund_items.refund_id AND refunds.deleted_at IS NULL").
+		Where("refunds.order_id = ? AND refunds.status IN ?", orderID, activeRefundStatuses).
+		Group("refund_items.order_item_id").
+		Scan(&refundedRows).Error; err != nil {
+		return nil, err
+	}
+
+	unavailable := make(map[uuid.UUID]int, len(returnedRows)+len(refundedRows))
+	for _, row := range returnedRows {
+		unavailable[row.OrderItemID] += row.Quantity
+	}
+	for _, row := range refundedRows {
+		unavailable[row.OrderItemID] += row.Quantity
+	}
+	return unavailable, nil
+}
+
+// returnItems ตรวจสอบรายการสินค้าที่ขอคืนกับ OrderItem ของคำสั่งซื้อ และจำนวนที่ขอคืนไม่ได้แล้ว
+func returnItems(orderItems []models.OrderItem, unavailable map[uuid.UUID]int, requested []entities.ReturnItemRequest) ([]models.ReturnItem, error) {
+	orderItemsByID := make(map[uuid.UUID]*models.OrderItem, len(orderItems))
+	for i := range orderItems {
+		orderItemsByID[orderItems[i].ID] = &orderItems[i]
+	}
+
+	items := make([]models.ReturnItem, 0, len(requested))
+	seen := make(map[uuid.UUID]bool, len(requested))
+	for _, request := range requested {
+		orderItem, ok := orderItemsByID[request.OrderItemID]
+		if !ok {
+			return nil, fmt.Errorf("%w: ไม่พบสินค้า %s ในคำสั่งซื้อนี้", entities.ErrInvalidReturnItem, request.OrderItemID)
+		}
+		if seen[request.OrderItemID] {
+			return nil, fmt.Errorf("%w: สินค้า %s ถูกระบุซ้ำ", entities.ErrInvalidReturnItem, request.OrderItemID)
+		}
+		seen[request.OrderItemID] = true
+
+		available := orderItem.Quantity - unavailable[orderItem.ID]
+		if request.Quantity > available {
+			return nil, fmt.Errorf("%w: สินค้า %s ขอคืน %d ชิ้น คืนได้อีก %d ชิ้น", entities.ErrInvalidReturnItem, request.OrderItemID, request.Quantity, available)
+		}
+
+		items = append(items, models.ReturnItem{
+			OrderItemID: orderItem.ID,
+			ProductID:   orderItem.ProductID,
+			VariantID:   orderItem.VariantID,
+			Quantity:    request.Quantity,
+			Reason:      request.Reason,
+		})
+	}
+
+	return items, nil
+}
+
+func (r *returnRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Return, error) {
+	var ret models.Return
+	if err := r.db.WithContext(ctx).
+		Preload("Items").
+		Preload("Events", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		First(&ret, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrReturnNotFound
+		}
+		return nil, err
+	}
+
+	return returnModelToEntity(&ret), nil
+}
+
+func (r *returnRepository) GetByUserID(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entities.Return, int, error) {
+	return r.list(r.db.WithContext(ctx).Where("user_id = ?", userID), page, limit)
+}
+
+func (r *returnRepository) GetAll(ctx context.Context, status entities.ReturnStatus, page, limit int) ([]*entities.Return, int, error) {
+	query := r.db.WithContext(ctx)
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+	return r.list(query, page, limit)
+}
+
+// list ดึงคำขอคืนสินค้าแบบแบ่งหน้า ใหม่สุดก่อน
+func (r *returnRepository) list(query *gorm.DB, page, limit int) ([]*entities.Return, int, error) {
+	var total int64
+	if err := query.Model(&models.Return{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var returns []models.Return
+	offset := (page - 1) * limit
+	if err := query.Preload("Items").Order("created_at DESC").Offset(offset).Limit(limit).Find(&returns).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var result []*entities.Return
+	for _, ret := range returns {
+		result = append(result, returnModelToEntity(&ret))
+	}
+
+	return result, int(total), nil
+}
+
+func (r *returnRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to entities.ReturnStatus, actorID uuid.UUID, note string) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	ret, err := lockReturn(tx, id, from)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := changeReturnStatus(tx, ret, to, actorID, note); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// Inspect บันทึกผลการตรวจสินค้าที่ได้รับคืน แล้วเปลี่ยนสถานะจาก received เป็น inspected
+func (r *returnRepository) Inspect(ctx context.Context, id uuid.UUID, req *entities.InspectReturnRequest, actorID uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	ret, err := lockReturn(tx, id, entities.ReturnStatusReceived)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var items []models.ReturnItem
+	if err := tx.Where("return_id = ?", id).Find(&items).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	inspections := make(map[uuid.UUID]entities.ReturnInspectionItem, len(req.Items))
+	for _, inspection := range req.Items {
+		if _, ok := inspections[inspection.ReturnItemID]; ok {
+			tx.Rollback()
+			return fmt.Errorf("%w: สินค้า %s ถูกระบุซ้ำ", entities.ErrInvalidReturnItem, inspection.ReturnItemID)
+		}
+		inspections[inspection.ReturnItemID] = inspection
+	}
+
+	for _, item := range items {
+		inspection, ok := inspections[item.ID]
+		delete(inspections, item.ID)
+		if inspection.AcceptedQuantity > item.Quantity {
+			tx.Rollback()
+			return fmt.Errorf("%w: สินค้า %s รับคืน %d ชิ้น แต่ขอคืนไว้ %d ชิ้น", entities.ErrInvalidReturnItem, item.ID, inspection.AcceptedQuantity, item.Quantity)
+		}
+
+		// สินค้าที่ไม่ได้ระบุในผลการตรวจถือว่าไม่ผ่าน
+		updates := map[string]interface{}{
+			"accepted_quantity": 0,
+			"restock":           false,
+		}
+		if ok {
+			updates["accepted_quantity"] = inspection.AcceptedQuantity
+			updates["restock"] = inspection.Restock && inspection.AcceptedQuantity > 0
+		}
+		if err := tx.Model(&item).Updates(updates).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	for returnItemID := range inspections {
+		tx.Rollback()
+		return fmt.Errorf("%w: ไม่พบสินค้า %s ในคำขอคืนสินค้านี้", entities.ErrInvalidReturnItem, returnItemID)
+	}
+
+	if err := changeReturnStatus(tx, ret, entities.ReturnStatusInspected, actorID, req.Note); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// ClaimRefund ล็อคคำขอคืนสินค้าที่ตรวจแล้วและจองการคืนเงินไว้ก่อนเรียก payment gateway
+// ถ้าคำขอผูกการคืนเงินไว้แล้วจะคืน false ส่วนคำขอที่มีการคืนเงินอื่นกำลังทำอยู่จะคืน ErrReturnStatusConflict
+// การจองไม่มีวันหมดอายุ ถ้าคืนเงินค้างกลางทางต้องตรวจกับ gateway ก่อนปล่อย เพื่อไม่ให้คืนเงินซ้ำ
+func (r *returnRepository) ClaimRefund(ctx context.Context, id uuid.UUID) (bool, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	ret, err := lockReturn(tx, id, entities.ReturnStatusInspected)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if ret.RefundID != nil {
+		tx.Rollback()
+		return false, nil
+	}
+	if ret.RefundClaimedAt != nil {
+		tx.Rollback()
+		return false, fmt.Errorf("%w: กำลังคืนเงินของคำขอนี้อยู่", entities.ErrReturnStatusConflict)
+	}
+
+	if err := tx.Model(ret).Update("refund_claimed_at", time.Now()).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	return true, tx.Commit().Error
+}
+
+// ReleaseRefund ปล่อยการจองคืนเงินที่ไม่สำเร็จ เพื่อให้ลองคืนเงินใหม่ได้
+func (r *returnRepository) ReleaseRefund(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&models.Return{}).
+		Where("id = ? AND refund_id IS NULL", id).
+		Update("refund_claimed_at", nil).Error
+}
+
+// AttachRefund ผูกการคืนเงินกับคำขอคืนสินค้าและปล่อยการจอง ทำได้ครั้งเดียวเพื่อไม่ให้คืนเงินซ้ำเมื่อลองปิดคำขออีกครั้ง
+func (r *returnRepository) AttachRefund(ctx context.Context, id, refundID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&models.Return{}).
+		Where("id = ? AND status = ? AND refund_id IS NULL", id, string(entities.ReturnStatusInspected)).
+		Updates(map[string]interface{}{
+			"refund_id":         refundID,
+			"refund_claimed_at": nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: คำขอนี้คืนเงินไปแล้วหรือไม่ได้อยู่ในสถานะ inspected", entities.ErrReturnStatusConflict)
+	}
+
+	return nil
+}
+
+// Complete คืนสต็อกของสินค้าที่รับคืนและนำกลับเข้าสต็อกได้ พร้อมบันทึกลง ledger แล้วปิดคำขอคืนสินค้า
+func (r *returnRepository) Complete(ctx context.Context, id, actorID uuid.UUID, note string) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	ret, err := lockReturn(tx, id, entities.ReturnStatusInspected)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var items []models.ReturnItem
+	if err := tx.Where("return_id = ? AND restock = ? AND accepted_quantity > 0", id, true).Find(&items).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, item := range items {
+		if err := restock(tx, item.ProductID, item.VariantID, item.AcceptedQuantity, entities.InventoryReasonReturn, &ret.ID, actorID, note); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := changeReturnStatus(tx, ret, entities.ReturnStatusCompleted, actorID, note); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// lockReturn ล็อคแถวคำขอคืนสินค้าและตรวจว่ายังอยู่ในสถานะ from ต้องเรียกภายใน transaction เสมอ
+func lockReturn(tx *gorm.DB, id uuid.UUID, from entities.ReturnStatus) (*models.Return, error) {
+	var ret models.Return
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&ret, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrReturnNotFound
+		}
+		return nil, err
+	}
+
+	if ret.Status != string(from) {
+		return nil, fmt.Errorf("%w: คำขอนี้อยู่ในสถานะ %s แล้ว", entities.ErrReturnStatusConflict, ret.Status)
+	}
+
+	return &ret, nil
+}
+
+// changeReturnStatus เปลี่ยนสถานะคำขอคืนสินค้าและบันทึก ReturnEvent ใน transaction เดียวกัน
+// note ที่ไม่ว่างจะถูกเก็บเป็นหมายเหตุล่าสุดของคำขอด้วย
+func changeReturnStatus(tx *gorm.DB, ret *models.Return, to entities.ReturnStatus, actorID uuid.UUID, note string) error {
+	updates := map[string]interface{}{
+		"status": string(to),
+	}
+	if note != "" {
+		updates["note"] = note
+	}
+
+	if err := tx.Model(ret).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	event := &models.ReturnEvent{
+		ReturnID:   ret.ID,
+		FromStatus: ret.Status,
+		ToStatus:   string(to),
+		Note:       note,
+	}
+	if actorID != uuid.Nil {
+		event.ActorID = &actorID
+	}
+
+	return tx.Create(event).Error
+}
+
+func returnModelToEntity(ret *models.Return) *entities.Return {
+	entity := &entities.Return{
+		ID:        ret.ID,
+		OrderID:   ret.OrderID,
+		UserID:    ret.UserID,
+		Status:    entities.ReturnStatus(ret.Status),
+		Reason:    ret.Reason,
+		Note:      ret.Note,
+		RefundID:  ret.RefundID,
+		Items:     []entities.ReturnItem{},
+		CreatedAt: ret.CreatedAt,
+		UpdatedAt: ret.UpdatedAt,
+	}
+
+	for _, item := range ret.Items {
+		entity.Items = append(entity.Items, entities.ReturnItem{
+			ID:               item.ID,
+			OrderItemID:      item.OrderItemID,
+			ProductID:        item.ProductID,
+			Quantity:         item.Quantity,
+			Reason:           item.Reason,
+			AcceptedQuantity: item.AcceptedQuantity,
+			Restock:          item.Restock,
+		})
+	}
+
+	for _, event := range ret.Events {
+		entity.Timeline = append(entity.Timeline, entities.ReturnEvent{
+			ID:         event.ID,
+			FromStatus: entities.ReturnStatus(event.FromStatus),
+			ToStatus:   entities.ReturnStatus(event.ToStatus),
+			ActorID:    event.ActorID,
+			Note:       event.Note,
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+
+	return entity
+}
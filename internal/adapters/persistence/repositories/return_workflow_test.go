@@ -0,0 +1,340 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	coreServices "github.com/Sup-Film/fiber-ecommerce-api/internal/core/services"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// refundGateway payment gateway สำหรับทดสอบที่สั่งให้การคืนเงินล้มได้ และนับจำนวนครั้งที่คืนเงินสำเร็จ
+type refundGateway struct {
+	gateways.PaymentGateway
+	fail    bool
+	refunds []entities.Money
+}
+
+func (g *refundGateway) Refund(ctx context.Context, providerRef string, amount entities.Money) (*entities.PaymentResult, error) {
+	if g.fail {
+		return nil, errors.New("gateway unavailable")
+	}
+	g.refunds = append(g.refunds, amount)
+	return &entities.PaymentResult{ProviderRef: providerRef, Status: entities.GatewayStatusRefunded, Amount: amount}, nil
+}
+
+type noopStockMonitor struct{}
+
+func (noopStockMonitor) Start(ctx context.Context)            {}
+func (noopStockMonitor) StockChanged(productIDs ...uuid.UUID) {}
+
+// returnFixture คำสั่งซื้อที่ชำระเงินและจัดส่งสำเร็จแล้ว มีสินค้าสองรายการ
+// items[0] ซื้อ 2 ชิ้น ยอด 200.00 บาท items[1] ซื้อ 1 ชิ้น ยอด 50.00 บาท
+type returnFixture struct {
+	db       *gorm.DB
+	service  services.ReturnService
+	gateway  *refundGateway
+	customer models.User
+	admin    models.User
+	order    models.Order
+	products []models.Product
+	items    []models.OrderItem
+}
+
+func newReturnFixture(t *testing.T) *returnFixture {
+	t.Helper()
+	d := newTestData(t)
+	db := d.db
+
+	customer := d.user()
+	admin := d.user()
+	category := d.category()
+	products := []models.Product{
+		d.product(models.Product{Name: "Returnable shirt", Price: 10000, Stock: 10, CategoryID: category.ID}),
+		d.product(models.Product{Name: "Returnable mug", Price: 5000, Stock: 10, CategoryID: category.ID}),
+	}
+
+	order := models.Order{
+		UserID:         customer.ID,
+		Subtotal:       25000,
+		TotalPrice:     25000,
+		Currency:       entities.DefaultCurrency,
+		Status:         string(entities.OrderStatusProcessing),
+		PaymentMethod:  "fake",
+		PaymentStatus:  string(entities.PaymentStatusPaid),
+		ShippingStatus: string(entities.ShippingStatusDelivered),
+		OrderItems: []models.OrderItem{
+			{ProductID: products[0].ID, Quantity: 2, Price: 10000, Subtotal: 20000, Total: 20000},
+			{ProductID: products[1].ID, Quantity: 1, Price: 5000, Subtotal: 5000, Total: 5000},
+		},
+	}
+	d.create(&order)
+
+	transaction := models.Transaction{
+		OrderID:       order.ID,
+		Amount:        order.TotalPrice,
+		Currency:      order.Currency,
+		PaymentMethod: order.PaymentMethod,
+		Status:        string(entities.TransactionStatusCompleted),
+		TransactionID: "TXN_TEST_" + uuid.NewString()[:8],
+		ProviderRef:   "fake_" + uuid.NewString(),
+	}
+	d.create(&transaction)
+
+	gateway := &refundGateway{}
+	transactionRepo := repositories.NewTransactionRepository(db)
+	refundService := coreServices.NewRefundService(repositories.NewRefundRepository(db), transactionRepo,
+		map[string]gateways.PaymentGateway{"fake": gateway}, noopStockMonitor{})
+	service := coreServices.NewReturnService(repositories.NewReturnRepository(db), repositories.NewOrderRepository(db),
+		transactionRepo, refundService, noopStockMonitor{})
+
+	return &returnFixture{
+		db:       db,
+		service:  service,
+		gateway:  gateway,
+		customer: customer,
+		admin:    admin,
+		order:    order,
+		products: products,
+		items:    order.OrderItems,
+	}
+}
+
+// receivedReturn สร้างคำขอคืนสินค้าทุกชิ้นของคำสั่งซื้อ แล้วอนุมัติและรับสินค้าคืน
+func (f *returnFixture) receivedReturn(t *testing.T) *entities.Return {
+	t.Helper()
+	ctx := context.Background()
+
+	ret, err := f.service.CreateReturn(ctx, f.customer.ID, &entities.CreateReturnRequest{
+		OrderID: f.order.ID,
+		Reason:  "wrong size",
+		Items: []entities.ReturnItemRequest{
+			{OrderItemID: f.items[0].ID, Quantity: 2},
+			{OrderItemID: f.items[1].ID, Quantity: 1, Reason: "cracked"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.service.ApproveReturn(ctx, ret.ID, f.admin.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	ret, err = f.service.ReceiveReturn(ctx, ret.ID, f.admin.ID, "parcel arrived")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ret
+}
+
+// inspection ผลการตรวจ: รับคืนเสื้อ 1 ชิ้นและนำกลับเข้าสต็อก ส่วนแก้วรับคืนแต่แตกจึงไม่นำกลับเข้าสต็อก
+func (f *returnFixture) inspection(ret *entities.Return) *entities.InspectReturnRequest {
+	req := &entities.InspectReturnRequest{Note: "checked"}
+	for _, item := range ret.Items {
+		switch item.OrderItemID {
+		case f.items[0].ID:
+			req.Items = append(req.Items, entities.ReturnInspectionItem{ReturnItemID: item.ID, AcceptedQuantity: 1, Restock: true})
+		case f.items[1].ID:
+			req.Items = append(req.Items, entities.ReturnInspectionItem{ReturnItemID: item.ID, AcceptedQuantity: 1})
+		}
+	}
+	return req
+}
+
+func (f *returnFixture) stock(t *testing.T, product models.Product) int {
+	t.Helper()
+
+	var stock int
+	if err := f.db.Model(&models.Product{}).Select("stock").Where("id = ?", product.ID).Scan(&stock).Error; err != nil {
+		t.Fatal(err)
+	}
+	return stock
+}
+
+func TestReturnWorkflow_RefundsAcceptedAndRestocksFlaggedItems(t *testing.T) {
+	f := newReturnFixture(t)
+	ctx := context.Background()
+
+	ret := f.receivedReturn(t)
+	ret, err := f.service.InspectReturn(ctx, ret.ID, f.admin.ID, f.inspection(ret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ret.Status != entities.ReturnStatusCompleted {
+		t.Fatalf("expected return to be completed, got %s", ret.Status)
+	}
+	if ret.RefundID == nil {
+		t.Fatal("expected refund to be attached")
+	}
+
+	// เสื้อ 1 ใน 2 ชิ้น (100.00) และแก้ว 1 ชิ้น (50.00)
+	if len(f.gateway.refunds) != 1 || f.gateway.refunds[0].Amount != 15000 {
+		t.Fatalf("expected one refund of 15000, got %v", f.gateway.refunds)
+	}
+
+	var order models.Order
+	if err := f.db.First(&order, "id = ?", f.order.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if order.PaymentStatus != string(entities.PaymentStatusPartiallyRefunded) {
+		t.Errorf("expected order payment status partially_refunded, got %s", order.PaymentStatus)
+	}
+
+	if stock := f.stock(t, f.products[0]); stock != 11 {
+		t.Errorf("expected restocked product stock 11, got %d", stock)
+	}
+	if stock := f.stock(t, f.products[1]); stock != 10 {
+		t.Errorf("expected damaged product stock to stay 10, got %d", stock)
+	}
+
+	var movements []models.InventoryMovement
+	if err := f.db.Where("reference_id = ?", ret.ID).Find(&movements).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(movements) != 1 || movements[0].ProductID != f.products[0].ID || movements[0].Delta != 1 ||
+		movements[0].Reason != string(entities.InventoryReasonReturn) {
+		t.Errorf("expected one return movement of +1 for the restocked product, got %+v", movements)
+	}
+
+	var statuses []entities.ReturnStatus
+	for _, event := range ret.Timeline {
+		statuses = append(statuses, event.ToStatus)
+	}
+	want := []entities.ReturnStatus{
+		entities.ReturnStatusRequested,
+		entities.ReturnStatusApproved,
+		entities.ReturnStatusReceived,
+		entities.ReturnStatusInspected,
+		entities.ReturnStatusCompleted,
+	}
+	if fmt.Sprint(statuses) != fmt.Sprint(want) {
+		t.Errorf("expected timeline %v, got %v", want, statuses)
+	}
+}
+
+func TestReturnWorkflow_RefundFailureCanBeRetriedOnce(t *testing.T) {
+	f := newReturnFixture(t)
+	ctx := context.Background()
+
+	ret := f.receivedReturn(t)
+
+	f.gateway.fail = true
+	if _, err := f.service.InspectReturn(ctx, ret.ID, f.admin.ID, f.inspection(ret)); !errors.Is(err, entities.ErrRefundFailed) {
+		t.Fatalf("expected ErrRefundFailed, got %v", err)
+	}
+
+	// ผลการตรวจถูกบันทึกแล้ว แต่ยังไม่คืนเงินและยังไม่คืนสต็อก
+	ret, err := f.service.GetReturn(ctx, ret.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Status != entities.ReturnStatusInspected || ret.RefundID != nil {
+		t.Fatalf("expected inspected return without refund, got %s refund=%v", ret.Status, ret.RefundID)
+	}
+	if stock := f.stock(t, f.products[0]); stock != 10 {
+		t.Errorf("expected stock to stay 10 until refund succeeds, got %d", stock)
+	}
+
+	f.gateway.fail = false
+	ret, err = f.service.RefundReturn(ctx, ret.ID, f.admin.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Status != entities.ReturnStatusCompleted {
+		t.Fatalf("expected return to be completed, got %s", ret.Status)
+	}
+	if stock := f.stock(t, f.products[0]); stock != 11 {
+		t.Errorf("expected restocked product stock 11, got %d", stock)
+	}
+
+	if _, err := f.service.RefundReturn(ctx, ret.ID, f.admin.ID); !errors.Is(err, entities.ErrReturnStatusConflict) {
+		t.Fatalf("expected ErrReturnStatusConflict on second refund, got %v", err)
+	}
+	if len(f.gateway.refunds) != 1 {
+		t.Errorf("expected exactly one successful refund, got %d", len(f.gateway.refunds))
+	}
+}
+
+func TestCreateReturn_RejectsQuantityAboveReturnable(t *testing.T) {
+	f := newReturnFixture(t)
+	ctx := context.Background()
+
+	request := func(quantity int) *entities.CreateReturnRequest {
+		return &entities.CreateReturnRequest{
+			OrderID: f.order.ID,
+			Reason:  "changed my mind",
+			Items:   []entities.ReturnItemRequest{{OrderItemID: f.items[0].ID, Quantity: quantity}},
+		}
+	}
+
+	if _, err := f.service.CreateReturn(ctx, f.customer.ID, request(3)); !errors.Is(err, entities.ErrInvalidReturnItem) {
+		t.Fatalf("expected ErrInvalidReturnItem above purchased quantity, got %v", err)
+	}
+
+	first, err := f.service.CreateReturn(ctx, f.customer.ID, request(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// จำนวนในคำขอที่ยังไม่จบขอคืนซ้ำไม่ได้
+	if _, err := f.service.CreateReturn(ctx, f.customer.ID, request(1)); !errors.Is(err, entities.ErrInvalidReturnItem) {
+		t.Fatalf("expected ErrInvalidReturnItem while another return is open, got %v", err)
+	}
+
+	if _, err := f.service.CancelReturn(ctx, f.customer.ID, first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.service.CreateReturn(ctx, f.customer.ID, request(2)); err != nil {
+		t.Fatalf("expected cancelled return to release its quantity, got %v", err)
+	}
+}
+
+func TestReturnWorkflow_RejectedAndCancelledReturnsAreFinal(t *testing.T) {
+	f := newReturnFixture(t)
+	ctx := context.Background()
+
+	create := func() *entities.Return {
+		ret, err := f.service.CreateReturn(ctx, f.customer.ID, &entities.CreateReturnRequest{
+			OrderID: f.order.ID,
+			Reason:  "not as described",
+			Items:   []entities.ReturnItemRequest{{OrderItemID: f.items[1].ID, Quantity: 1}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ret
+	}
+
+	rejected, err := f.service.RejectReturn(ctx, create().ID, f.admin.ID, "outside return window")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rejected.Status != entities.ReturnStatusRejected || rejected.Note != "outside return window" {
+		t.Fatalf("expected rejected return with note, got %s %q", rejected.Status, rejected.Note)
+	}
+	if _, err := f.service.ApproveReturn(ctx, rejected.ID, f.admin.ID, ""); !errors.Is(err, entities.ErrReturnStatusConflict) {
+		t.Fatalf("expected ErrReturnStatusConflict approving a rejected return, got %v", err)
+	}
+
+	cancelled := create()
+	if _, err := f.service.ApproveReturn(ctx, cancelled.ID, f.admin.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.service.CancelReturn(ctx, uuid.New(), cancelled.ID); !errors.Is(err, entities.ErrReturnNotFound) {
+		t.Fatalf("expected ErrReturnNotFound cancelling another user's return, got %v", err)
+	}
+	if _, err := f.service.CancelReturn(ctx, f.customer.ID, cancelled.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.service.ReceiveReturn(ctx, cancelled.ID, f.admin.ID, ""); !errors.Is(err, entities.ErrReturnStatusConflict) {
+		t.Fatalf("expected ErrReturnStatusConflict receiving a cancelled return, got %v", err)
+	}
+}
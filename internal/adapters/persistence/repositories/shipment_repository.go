@@ -0,0 +1,248 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// สถานะของพัสดุที่ยังต้องติดตามกับผู้ให้บริการขนส่ง
+var activeShipmentStatuses = []string{string(entities.ShippingStatusShipped), string(entities.ShippingStatusInTransit)}
+
+type shipmentRepository struct {
+	db *gorm.DB
+}
+
+func NewShipmentRepository(db *gorm.DB) repositories.ShipmentRepository {
+	return &shipmentRepository{db: db}
+}
+
+// Create บันทึกพัสดุพร้อมสินค้าและจุดติดตามแรก
+// ตรวจจำนวนที่ส่งได้ซ้ำอีกครั้งโดยล็อคแถวคำสั่งซื้อไว้ เพื่อไม่ให้สร้างพัสดุพร้อมกันแล้วส่งเกินจำนวนที่สั่ง
+func (r *shipmentRepository) Create(ctx context.Context, shipment *entities.Shipment) (*entities.Shipment, error) {
+	tx := r.db.WithContext(ctx).Begin()
+
+	var order models.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "id = ?", shipment.OrderID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	var orderItems []models.OrderItem
+	if err := tx.Where("order_id = ?", shipment.OrderID).Find(&orderItems).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	shipped, err := shippedQuantities(tx, shipment.OrderID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	requested := make([]entities.ShipmentItemRequest, 0, len(shipment.Items))
+	for _, item := range shipment.Items {
+		requested = append(requested, entities.ShipmentItemRequest{OrderItemID: item.OrderItemID, Quantity: item.Quantity})
+	}
+	items := make([]entities.OrderItem, 0, len(orderItems))
+	for _, orderItem := range orderItems {
+		items = append(items, entities.OrderItem{ID: orderItem.ID, ProductID: orderItem.ProductID, Quantity: orderItem.Quantity})
+	}
+	if _, err := entities.ResolveShipmentItems(items, shipped, requested); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	model := &models.Shipment{
+		OrderID:        shipment.OrderID,
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		Status:         string(shipment.Status),
+		ShippedAt:      shipment.ShippedAt,
+	}
+	for _, item := range shipment.Items {
+		model.Items = append(model.Items, models.ShipmentItem{
+			OrderItemID: item.OrderItemID,
+			ProductID:   item.ProductID,
+			Quantity:    item.Quantity,
+		})
+	}
+	model.Checkpoints = checkpointModels(shipment.Checkpoints)
+
+	if err := tx.Create(model).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return shipmentModelToEntity(model), nil
+}
+
+func (r *shipmentRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Shipment, error) {
+	var shipment models.Shipment
+	if err := preloadShipment(r.db.WithContext(ctx)).First(&shipment, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrShipmentNotFound
+		}
+		return nil, err
+	}
+
+	return shipmentModelToEntity(&shipment), nil
+}
+
+func (r *shipmentRepository) GetActive(ctx context.Context) ([]*entities.Shipment, error) {
+	var shipments []models.Shipment
+	if err := preloadShipment(r.db.WithContext(ctx)).
+		Where("status IN ?", activeShipmentStatuses).
+		Order("shipped_at").
+		Find(&shipments).Error; err != nil {
+		return nil, err
+	}
+
+	var result []*entities.Shipment
+	for _, shipment := range shipments {
+		result = append(result, shipmentModelToEntity(&shipment))
+	}
+
+	return result, nil
+}
+
+func (r *shipmentRepository) AddCheckpoints(ctx context.Context, id uuid.UUID, status entities.ShippingStatus, checkpoints []entities.TrackingCheckpoint) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	var shipment models.Shipment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&shipment, "id = ?", id).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.ErrShipmentNotFound
+		}
+		return err
+	}
+
+	if len(checkpoints) > 0 {
+		rows := checkpointModels(checkpoints)
+		for i := range rows {
+			rows[i].ShipmentID = id
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	updates := map[string]interface{}{
+		"status": string(status),
+	}
+	// เวลาที่ถึงปลายทางใช้เวลาของจุดติดตามที่เป็น delivered
+	if status == entities.ShippingStatusDelivered && shipment.DeliveredAt == nil {
+		for _, checkpoint := range checkpoints {
+			if checkpoint.Status == entities.ShippingStatusDelivered {
+				deliveredAt := checkpoint.OccurredAt
+				updates["delivered_at"] = &deliveredAt
+				break
+			}
+		}
+	}
+
+	if err := tx.Model(&shipment).Updates(updates).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// preloadShipment โหลดสินค้าและจุดติดตามของพัสดุ โดยเรียงจุดติดตามตามเวลาที่เกิดขึ้น
+func preloadShipment(db *gorm.DB) *gorm.DB {
+	return db.Preload("Items").Preload("Checkpoints", func(db *gorm.DB) *gorm.DB {
+		return db.Order("occurred_at ASC")
+	})
+}
+
+// shippedQuantities จำนวนที่ส่งไปแล้วของแต่ละ OrderItem ของคำสั่งซื้อ
+func shippedQuantities(tx *gorm.DB, orderID uuid.UUID) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		OrderItemID uuid.UUID
+		Quantity    int
+	}
+	if err := tx.Model(&models.ShipmentItem{}).
+		Select("shipment_items.order_item_id, COALESCE(SUM(shipment_items.quantity), 0) AS quantity").
+		Joins("JOIN shipments ON shipments.id = shipment_items.shipment_id AND shipments.deleted_at IS NULL").
+		Where("shipments.order_id = ?", orderID).
+		Group("shipment_items.order_item_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	shipped := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		shipped[row.OrderItemID] = row.Quantity
+	}
+	return shipped, nil
+}
+
+// checkpointModels แปลงจุดติดตามจาก entity เป็น model
+func checkpointModels(checkpoints []entities.TrackingCheckpoint) []models.ShipmentCheckpoint {
+	result := make([]models.ShipmentCheckpoint, 0, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		result = append(result, models.ShipmentCheckpoint{
+			EventID:     checkpoint.EventID,
+			Status:      string(checkpoint.Status),
+			Location:    checkpoint.Location,
+			Description: checkpoint.Description,
+			OccurredAt:  checkpoint.OccurredAt,
+		})
+	}
+	return result
+}
+
+// shipmentModelToEntity แปลง model เป็น entity ใช้ร่วมกับ orderRepository ตอนแสดงรายละเอียดคำสั่งซื้อ
+func shipmentModelToEntity(shipment *models.Shipment) *entities.Shipment {
+	entity := &entities.Shipment{
+		ID:             shipment.ID,
+		OrderID:        shipment.OrderID,
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		Status:         entities.ShippingStatus(shipment.Status),
+		Items:          []entities.ShipmentItem{},
+		Checkpoints:    []entities.TrackingCheckpoint{},
+		ShippedAt:      shipment.ShippedAt,
+		DeliveredAt:    shipment.DeliveredAt,
+		CreatedAt:      shipment.CreatedAt,
+		UpdatedAt:      shipment.UpdatedAt,
+	}
+
+	for _, item := range shipment.Items {
+		entity.Items = append(entity.Items, entities.ShipmentItem{
+			ID:          item.ID,
+			OrderItemID: item.OrderItemID,
+			ProductID:   item.ProductID,
+			Quantity:    item.Quantity,
+		})
+	}
+
+	for _, checkpoint := range shipment.Checkpoints {
+		entity.Checkpoints = append(entity.Checkpoints, entities.TrackingCheckpoint{
+			ID:          checkpoint.ID,
+			EventID:     checkpoint.EventID,
+			Status:      entities.ShippingStatus(checkpoint.Status),
+			Location:    checkpoint.Location,
+			Description: checkpoint.Description,
+			OccurredAt:  checkpoint.OccurredAt,
+		})
+	}
+
+	return entity
+}
@@ -0,0 +1,219 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type shippingMethodRepository struct {
+	db *gorm.DB
+}
+
+func NewShippingMethodRepository(db *gorm.DB) repositories.ShippingMethodRepository {
+	return &shippingMethodRepository{db: db}
+}
+
+func (r *shippingMethodRepository) Create(ctx context.Context, req *entities.CreateShippingMethodRequest) (*entities.ShippingMethod, error) {
+	// นับรวมวิธีจัดส่งที่ถูกลบไปแล้ว เพราะ unique index ของ code ไม่สนใจ soft delete
+	var count int64
+	if err := r.db.WithContext(ctx).Unscoped().Model(&models.ShippingMethod{}).Where("code = ?", req.Code).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, entities.ErrShippingCodeExists
+	}
+
+	method := &models.ShippingMethod{
+		Code:          req.Code,
+		Name:          req.Name,
+		Description:   req.Description,
+		RateType:      string(req.RateType),
+		Fee:           req.Fee.Amount,
+		FeePerKg:      req.FeePerKg.Amount,
+		FreeOver:      req.FreeOver.Amount,
+		Currency:      entities.NewMoney(0, req.Fee.Currency).Currency,
+		RegionsOnly:   req.RegionsOnly,
+		EstimatedDays: req.EstimatedDays,
+		Active:        req.Active == nil || *req.Active,
+		Regions:       regionRateModels(req.Regions),
+	}
+
+	if err := r.db.WithContext(ctx).Create(method).Error; err != nil {
+		return nil, err
+	}
+
+	return shippingMethodModelToEntity(method), nil
+}
+
+func (r *shippingMethodRepository) GetAll(ctx context.Context, activeOnly bool) ([]*entities.ShippingMethod, error) {
+	query := r.db.WithContext(ctx).Preload("Regions")
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+
+	var methods []models.ShippingMethod
+	if err := query.Order("fee, name").Find(&methods).Error; err != nil {
+		return nil, err
+	}
+
+	var result []*entities.ShippingMethod
+	for _, method := range methods {
+		result = append(result, shippingMethodModelToEntity(&method))
+	}
+
+	return result, nil
+}
+
+func (r *shippingMethodRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.ShippingMethod, error) {
+	var method models.ShippingMethod
+	if err := r.db.WithContext(ctx).Preload("Regions").First(&method, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrShippingMethodNotFound
+		}
+		return nil, err
+	}
+
+	return shippingMethodModelToEntity(&method), nil
+}
+
+func (r *shippingMethodRepository) Update(ctx context.Context, id uuid.UUID, req *entities.UpdateShippingMethodRequest) error {
+	updates := map[string]interface{}{}
+
+	if req.Name != "" {
+		updates["name"] = req.Name
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.RateType != nil {
+		updates["rate_type"] = string(*req.RateType)
+	}
+	if req.Fee != nil {
+		updates["fee"] = req.Fee.Amount
+		updates["currency"] = entities.NewMoney(0, req.Fee.Currency).Currency
+	}
+	if req.FeePerKg != nil {
+		updates["fee_per_kg"] = req.FeePerKg.Amount
+	}
+	if req.FreeOver != nil {
+		updates["free_over"] = req.FreeOver.Amount
+	}
+	if req.RegionsOnly != nil {
+		updates["regions_only"] = *req.RegionsOnly
+	}
+	if req.EstimatedDays != nil {
+		updates["estimated_days"] = *req.EstimatedDays
+	}
+	if req.Active != nil {
+		updates["active"] = *req.Active
+	}
+
+	tx := r.db.WithContext(ctx).Begin()
+
+	var method models.ShippingMethod
+	if err := tx.First(&method, "id = ?", id).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.ErrShippingMethodNotFound
+		}
+		return err
+	}
+
+	if len(updates) > 0 {
+		if err := tx.Model(&method).Updates(updates).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// แทนที่ค่าจัดส่งรายจังหวัดทั้งหมดถ้าส่งมา
+	if req.Regions != nil {
+		if err := tx.Unscoped().Where("shipping_method_id = ?", id).Delete(&models.ShippingRegionRate{}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		regions := regionRateModels(*req.Regions)
+		for i := range regions {
+			regions[i].ShippingMethodID = id
+		}
+		if len(regions) > 0 {
+			if err := tx.Create(&regions).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (r *shippingMethodRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&models.ShippingMethod{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrShippingMethodNotFound
+	}
+
+	return nil
+}
+
+// loadShippingMethod ดึงวิธีจัดส่งที่เปิดใช้งานตามรหัส ใช้ภายใน transaction ของการสร้างคำสั่งซื้อ
+func loadShippingMethod(tx *gorm.DB, code string) (*entities.ShippingMethod, error) {
+	var method models.ShippingMethod
+	if err := tx.Preload("Regions").Where("code = ? AND active = ?", code, true).First(&method).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrShippingMethodNotFound
+		}
+		return nil, err
+	}
+
+	return shippingMethodModelToEntity(&method), nil
+}
+
+// regionRateModels แปลงค่าจัดส่งรายจังหวัดจาก request เป็น model
+func regionRateModels(regions []entities.ShippingRegionRate) []models.ShippingRegionRate {
+	result := make([]models.ShippingRegionRate, 0, len(regions))
+	for _, region := range regions {
+		result = append(result, models.ShippingRegionRate{
+			Province: region.Province,
+			Fee:      region.Fee.Amount,
+		})
+	}
+	return result
+}
+
+// shippingMethodModelToEntity แปลง model เป็น entity ใช้ร่วมกับ orderRepository ตอน checkout
+func shippingMethodModelToEntity(method *models.ShippingMethod) *entities.ShippingMethod {
+	entity := &entities.ShippingMethod{
+		ID:            method.ID,
+		Code:          method.Code,
+		Name:          method.Name,
+		Description:   method.Description,
+		RateType:      entities.ShippingRateType(method.RateType),
+		Fee:           entities.NewMoney(method.Fee, method.Currency),
+		FeePerKg:      entities.NewMoney(method.FeePerKg, method.Currency),
+		FreeOver:      entities.NewMoney(method.FreeOver, method.Currency),
+		RegionsOnly:   method.RegionsOnly,
+		EstimatedDays: method.EstimatedDays,
+		Active:        method.Active,
+		CreatedAt:     method.CreatedAt,
+		UpdatedAt:     method.UpdatedAt,
+	}
+
+	for _, region := range method.Regions {
+		entity.Regions = append(entity.Regions, entities.ShippingRegionRate{
+			Province: region.Province,
+			Fee:      entities.NewMoney(region.Fee, method.Currency),
+		})
+	}
+
+	return entity
+}
@@ -27,74 +27,51 @@ func (r *statsRepository) GetSalesStats(ctx context.Context) (*entities.SalesSta
 	stats := &entities.SalesStats{}
 
 	// Total sales และ orders (ทั้งหมด)
-	var totalSales float64
-	var totalOrders int64
-	if err := r.db.WithContext(ctx).Model(&models.Order{}).
-		Where("status != ?", "cancelled").
-		Select("COALESCE(SUM(total_price), 0)").
-		Scan(&totalSales).Error; err != nil {
-		return nil, err
-	}
-	if err := r.db.WithContext(ctx).Model(&models.Order{}).
-		Where("status != ?", "cancelled").
-		Count(&totalOrders).Error; err != nil {
+	var err error
+	if stats.TotalSales, stats.TotalNetSales, stats.TotalOrders, err = r.salesSince(ctx, time.Time{}); err != nil {
 		return nil, err
 	}
-	stats.TotalSales = totalSales
-	stats.TotalOrders = int(totalOrders)
 
 	// Today's sales และ orders
-	var todaySales float64
-	var todayOrders int64
-	if err := r.db.WithContext(ctx).Model(&models.Order{}).
-		Where("status != ? AND created_at >= ?", "cancelled", today).
-		Select("COALESCE(SUM(total_price), 0)").
-		Scan(&todaySales).Error; err != nil {
+	if stats.TodaySales, stats.TodayNetSales, stats.TodayOrders, err = r.salesSince(ctx, today); err != nil {
 		return nil, err
 	}
-	if err := r.db.WithContext(ctx).Model(&models.Order{}).
-		Where("status != ? AND created_at >= ?", "cancelled", today).
-		Count(&todayOrders).Error; err != nil {
-		return nil, err
-	}
-	stats.TodaySales = todaySales
-	stats.TodayOrders = int(todayOrders)
 
 	// Monthly sales และ orders
-	var monthlySales float64
-	var monthlyOrders int64
-	if err := r.db.WithContext(ctx).Model(&models.Order{}).
-		Where("status != ? AND created_at >= ?", "cancelled", thisMonth).
-		Select("COALESCE(SUM(total_price), 0)").
-		Scan(&monthlySales).Error; err != nil {
+	if stats.MonthlySales, stats.MonthlyNetSales, stats.MonthlyOrders, err = r.salesSince(ctx, thisMonth); err != nil {
 		return nil, err
 	}
-	if err := r.db.WithContext(ctx).Model(&models.Order{}).
-		Where("status != ? AND created_at >= ?", "cancelled", thisMonth).
-		Count(&monthlyOrders).Error; err != nil {
+
+	// Yearly sales และ orders
+	if stats.YearlySales, stats.YearlyNetSales, stats.YearlyOrders, err = r.salesSince(ctx, thisYear); err != nil {
 		return nil, err
 	}
-	stats.MonthlySales = monthlySales
-	stats.MonthlyOrders = int(monthlyOrders)
 
-	// Yearly sales และ orders
-	var yearlySales float64
-	var yearlyOrders int64
+	return stats, nil
+}
+
+// salesSince ยอดขายรวมภาษี (gross) ยอดก่อนภาษี (net) และจำนวนคำสั่งซื้อที่ไม่ถูกยกเลิกตั้งแต่ since
+// ยอดขายรวมเฉพาะคำสั่งซื้อสกุลเงินหลักของร้าน ส่วนจำนวนคำสั่งซื้อนับทุกสกุลเงิน
+func (r *statsRepository) salesSince(ctx context.Context, since time.Time) (entities.Money, entities.Money, int, error) {
+	var sales struct {
+		Gross int64
+		Net   int64
+	}
 	if err := r.db.WithContext(ctx).Model(&models.Order{}).
-		Where("status != ? AND created_at >= ?", "cancelled", thisYear).
-		Select("COALESCE(SUM(total_price), 0)").
-		Scan(&yearlySales).Error; err != nil {
-		return nil, err
+		Where("status != ? AND currency = ? AND created_at >= ?", "cancelled", entities.DefaultCurrency, since).
+		Select("COALESCE(SUM(total_price), 0) AS gross, COALESCE(SUM(subtotal), 0) AS net").
+		Scan(&sales).Error; err != nil {
+		return entities.Money{}, entities.Money{}, 0, err
 	}
+
+	var orders int64
 	if err := r.db.WithContext(ctx).Model(&models.Order{}).
-		Where("status != ? AND created_at >= ?", "cancelled", thisYear).
-		Count(&yearlyOrders).Error; err != nil {
-		return nil, err
+		Where("status != ? AND created_at >= ?", "cancelled", since).
+		Count(&orders).Error; err != nil {
+		return entities.Money{}, entities.Money{}, 0, err
 	}
-	stats.YearlySales = yearlySales
-	stats.YearlyOrders = int(yearlyOrders)
 
-	return stats, nil
+	return entities.NewMoney(sales.Gross, entities.DefaultCurrency), entities.NewMoney(sales.Net, entities.DefaultCurrency), int(orders), nil
 }
 
 func (r *statsRepository) GetProductStats(ctx context.Context) (*entities.ProductStats, error) {
@@ -107,10 +84,11 @@ func (r *statsRepository) GetProductStats(ctx context.Context) (*entities.Produc
 	}
 	stats.TotalProducts = int(totalProducts)
 
-	// Low stock products (สต็อกต่ำกว่า 10)
+	// Low stock products (สต็อกไม่เกินจุดสั่งซื้อเพิ่มของแต่ละสินค้า)
 	var lowStockProducts int64
 	if err := r.db.WithContext(ctx).Model(&models.Product{}).
-		Where("stock > 0 AND stock <= 10").
+		Joins(lowStockJoinSQL).
+		Where("products.stock > 0 AND products.stock <= "+effectiveReorderThresholdSQL, entities.DefaultReorderThreshold).
 		Count(&lowStockProducts).Error; err != nil {
 		return nil, err
 	}
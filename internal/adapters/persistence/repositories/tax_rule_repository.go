@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type taxRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxRuleRepository(db *gorm.DB) repositories.TaxRuleRepository {
+	return &taxRuleRepository{db: db}
+}
+
+func (r *taxRuleRepository) Create(ctx context.Context, req *entities.CreateTaxRuleRequest) (*entities.TaxRule, error) {
+	taxRule := &models.TaxRule{
+		Name:      req.Name,
+		Rate:      req.Rate,
+		Inclusive: req.Inclusive,
+		Exempt:    req.Exempt,
+	}
+
+	if err := r.db.WithContext(ctx).Create(taxRule).Error; err != nil {
+		return nil, err
+	}
+
+	return r.modelToEntity(taxRule), nil
+}
+
+func (r *taxRuleRepository) GetAll(ctx context.Context) ([]*entities.TaxRule, error) {
+	var taxRules []models.TaxRule
+	if err := r.db.WithContext(ctx).Order("name").Find(&taxRules).Error; err != nil {
+		return nil, err
+	}
+
+	var result []*entities.TaxRule
+	for _, taxRule := range taxRules {
+		result = append(result, r.modelToEntity(&taxRule))
+	}
+
+	return result, nil
+}
+
+func (r *taxRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.TaxRule, error) {
+	var taxRule models.TaxRule
+	if err := r.db.WithContext(ctx).First(&taxRule, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrTaxRuleNotFound
+		}
+		return nil, err
+	}
+
+	return r.modelToEntity(&taxRule), nil
+}
+
+func (r *taxRuleRepository) Update(ctx context.Context, id uuid.UUID, req *entities.UpdateTaxRuleRequest) error {
+	updates := map[string]interface{}{}
+
+	if req.Name != "" {
+		updates["name"] = req.Name
+	}
+	if req.Rate != nil {
+		updates["rate"] = *req.Rate
+	}
+	if req.Inclusive != nil {
+		updates["inclusive"] = *req.Inclusive
+	}
+	if req.Exempt != nil {
+		updates["exempt"] = *req.Exempt
+	}
+
+	result := r.db.WithContext(ctx).Model(&models.TaxRule{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if len(updates) > 0 && result.RowsAffected == 0 {
+		return entities.ErrTaxRuleNotFound
+	}
+
+	return nil
+}
+
+// Delete ลบกฎภาษีที่ไม่มีสินค้าหรือหมวดหมู่ใช้อยู่แล้ว
+// คำสั่งซื้อเก่าเก็บอัตราภาษีไว้เองจึงไม่ได้รับผลกระทบ
+func (r *taxRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	var products, categories int64
+	if err := tx.Model(&models.Product{}).Where("tax_rule_id = ?", id).Count(&products).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Model(&models.Category{}).Where("tax_rule_id = ?", id).Count(&categories).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if products > 0 || categories > 0 {
+		tx.Rollback()
+		return entities.ErrTaxRuleInUse
+	}
+
+	result := tx.Delete(&models.TaxRule{}, "id = ?", id)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return entities.ErrTaxRuleNotFound
+	}
+
+	return tx.Commit().Error
+}
+
+func (r *taxRuleRepository) modelToEntity(taxRule *models.TaxRule) *entities.TaxRule {
+	return &entities.TaxRule{
+		ID:        taxRule.ID,
+		Name:      taxRule.Name,
+		Rate:      taxRule.Rate,
+		Inclusive: taxRule.Inclusive,
+		Exempt:    taxRule.Exempt,
+		CreatedAt: taxRule.CreatedAt,
+		UpdatedAt: taxRule.UpdatedAt,
+	}
+}
+
+// loadTaxRules อ่านกฎภาษีทั้งหมดภายใน transaction ที่ส่งมา ใช้ตอน checkout
+func loadTaxRules(tx *gorm.DB) (map[uuid.UUID]entities.TaxRule, error) {
+	var taxRules []models.TaxRule
+	if err := tx.Find(&taxRules).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[uuid.UUID]entities.TaxRule, len(taxRules))
+	for _, taxRule := range taxRules {
+		result[taxRule.ID] = entities.TaxRule{
+			ID:        taxRule.ID,
+			Name:      taxRule.Name,
+			Rate:      taxRule.Rate,
+			Inclusive: taxRule.Inclusive,
+			Exempt:    taxRule.Exempt,
+		}
+	}
+
+	return result, nil
+}
+
+// effectiveTaxRule กฎภาษีที่ใช้จริงของสินค้า (สินค้า → หมวดหมู่ → DefaultTaxRule)
+// product ต้อง preload Category มาด้วย
+func effectiveTaxRule(product *models.Product, taxRules map[uuid.UUID]entities.TaxRule) entities.TaxRule {
+	for _, id := range []*uuid.UUID{product.TaxRuleID, product.Category.TaxRuleID} {
+		if id == nil {
+			continue
+		}
+		if taxRule, ok := taxRules[*id]; ok {
+			return taxRule
+		}
+	}
+	return entities.DefaultTaxRule
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type transactionRepository struct {
@@ -33,8 +35,9 @@ func (r *transactionRepository) Create(ctx context.Context, req *entities.Create
 	transaction := &models.Transaction{
 		OrderID:       req.OrderID,
 		Amount:        order.TotalPrice,
+		Currency:      order.Currency,
 		PaymentMethod: req.PaymentMethod,
-		Status:        "pending",
+		Status:        string(entities.TransactionStatusPending),
 		TransactionID: transactionID,
 		PaymentData:   req.PaymentData,
 	}
@@ -78,36 +81,90 @@ func (r *transactionRepository) GetByTransactionID(ctx context.Context, transact
 	return r.modelToEntity(&transaction), nil
 }
 
-func (r *transactionRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
+// UpdateStatus เปลี่ยนสถานะธุรกรรมพร้อมสถานะการชำระเงินของคำสั่งซื้อใน transaction เดียวกัน
+// ธุรกรรมเปลี่ยนสถานะได้เฉพาะตอนที่ยังเป็น pending ถ้าจบไปแล้วจะคืน ErrTransactionFinalized
+// ถ้าส่ง event มาด้วยจะบันทึก event ไว้ใน transaction เดียวกัน event ที่เคยบันทึกแล้วจะคืน ErrDuplicatePaymentEvent
+// ส่วน event ที่มาช้ากว่าสถานะปัจจุบันจะยังถูกบันทึกไว้ (applied = false) เพื่อไม่ให้ประมวลผลซ้ำ
+// สถานะการชำระเงินของคำสั่งซื้อเปลี่ยนตามเฉพาะเมื่อตารางการเปลี่ยนสถานะอนุญาต
+func (r *transactionRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status entities.TransactionStatus, event *entities.PaymentEvent) error {
 	tx := r.db.WithContext(ctx).Begin()
 
-	// อัพเดทสถานะ transaction
-	if err := tx.Model(&models.Transaction{}).Where("id = ?", id).Update("status", status).Error; err != nil {
-		tx.Rollback()
-		return err
+	var eventModel *models.PaymentEvent
+	if event != nil {
+		eventModel = &models.PaymentEvent{
+			Provider:      event.Provider,
+			EventID:       event.EventID,
+			TransactionID: id,
+			Status:        string(event.Status),
+			Payload:       event.Payload,
+		}
+
+		// ON CONFLICT DO NOTHING ทำให้ event ที่ถูกส่งซ้ำพร้อมกันผ่านได้เพียงครั้งเดียว
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(eventModel)
+		if result.Error != nil {
+			tx.Rollback()
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			tx.Rollback()
+			return entities.ErrDuplicatePaymentEvent
+		}
 	}
 
 	// หา transaction เพื่อดึง order ID
 	var transaction models.Transaction
 	if err := tx.First(&transaction, "id = ?", id).Error; err != nil {
 		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.ErrPaymentNotFound
+		}
 		return err
 	}
 
+	// สถานะ pending ไม่ต้องเปลี่ยนอะไร แค่บันทึก event ไว้
+	if status == entities.TransactionStatusPending {
+		return tx.Commit().Error
+	}
+
+	// อัพเดทสถานะ transaction เฉพาะตอนที่ยังเป็น pending เพื่อกันการเปลี่ยนทับสถานะที่จบไปแล้ว
+	result := tx.Model(&models.Transaction{}).
+		Where("id = ? AND status = ?", id, string(entities.TransactionStatusPending)).
+		Update("status", string(status))
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		finalizedErr := fmt.Errorf("%w: %s", entities.ErrTransactionFinalized, transaction.Status)
+		if eventModel == nil {
+			tx.Rollback()
+			return finalizedErr
+		}
+		if err := tx.Commit().Error; err != nil {
+			return err
+		}
+		return finalizedErr
+	}
+
+	if eventModel != nil {
+		if err := tx.Model(eventModel).Update("applied", true).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
 	// อัพเดทสถานะการชำระเงินของคำสั่งซื้อ
-	var paymentStatus string
-	switch status {
-	case "completed":
-		paymentStatus = "paid"
-	case "failed":
-		paymentStatus = "failed"
-	case "cancelled":
-		paymentStatus = "cancelled"
-	default:
-		paymentStatus = "pending"
-	}
-
-	if err := tx.Model(&models.Order{}).Where("id = ?", transaction.OrderID).Update("payment_status", paymentStatus).Error; err != nil {
+	paymentStatus := string(status.PaymentStatus())
+
+	// บันทึกลง timeline ของคำสั่งซื้อด้วย โดยไม่มีผู้ดำเนินการเพราะมาจากระบบชำระเงิน
+	note := fmt.Sprintf("transaction %s: %s", transaction.TransactionID, status)
+	if event != nil {
+		note = fmt.Sprintf("%s (%s event %s)", note, event.Provider, event.EventID)
+	}
+	// คำสั่งซื้อหนึ่งรายการมีได้หลายธุรกรรม ถ้าสถานะของธุรกรรมนี้ขัดกับสถานะของคำสั่งซื้อ
+	// (เช่น ชำระด้วยธุรกรรมอื่นไปแล้ว หรือคำสั่งซื้อถูกยกเลิกไปแล้ว) จะปิดเฉพาะธุรกรรมนี้ ไม่เปลี่ยนคำสั่งซื้อ
+	var transitionErr *entities.StatusTransitionError
+	if err := changeOrderStatus(tx, transaction.OrderID, entities.StatusAxisPayment, paymentStatus, nil, uuid.Nil, note); err != nil && !errors.As(err, &transitionErr) {
 		tx.Rollback()
 		return err
 	}
@@ -115,20 +172,30 @@ func (r *transactionRepository) UpdateStatus(ctx context.Context, id uuid.UUID,
 	return tx.Commit().Error
 }
 
+// AttachIntent บันทึกเลขอ้างอิงและข้อมูลสำหรับชำระเงินที่ได้จาก payment gateway
+func (r *transactionRepository) AttachIntent(ctx context.Context, id uuid.UUID, providerRef, payload string) error {
+	return r.db.WithContext(ctx).Model(&models.Transaction{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"provider_ref":    providerRef,
+		"payment_payload": payload,
+	}).Error
+}
+
 func (r *transactionRepository) Cancel(ctx context.Context, id uuid.UUID) error {
-	return r.UpdateStatus(ctx, id, "cancelled")
+	return r.UpdateStatus(ctx, id, entities.TransactionStatusCancelled, nil)
 }
 
 func (r *transactionRepository) modelToEntity(transaction *models.Transaction) *entities.Transaction {
 	return &entities.Transaction{
-		ID:            transaction.ID,
-		OrderID:       transaction.OrderID,
-		Amount:        transaction.Amount,
-		PaymentMethod: transaction.PaymentMethod,
-		Status:        transaction.Status,
-		TransactionID: transaction.TransactionID,
-		PaymentData:   transaction.PaymentData,
-		CreatedAt:     transaction.CreatedAt,
-		UpdatedAt:     transaction.UpdatedAt,
+		ID:             transaction.ID,
+		OrderID:        transaction.OrderID,
+		Amount:         entities.NewMoney(transaction.Amount, transaction.Currency),
+		PaymentMethod:  transaction.PaymentMethod,
+		Status:         entities.TransactionStatus(transaction.Status),
+		TransactionID:  transaction.TransactionID,
+		ProviderRef:    transaction.ProviderRef,
+		PaymentPayload: transaction.PaymentPayload,
+		PaymentData:    transaction.PaymentData,
+		CreatedAt:      transaction.CreatedAt,
+		UpdatedAt:      transaction.UpdatedAt,
 	}
 }
@@ -0,0 +1,241 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type wishlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWishlistRepository(db *gorm.DB) repositories.WishlistRepository {
+	return &wishlistRepository{db: db}
+}
+
+// Add เพิ่มสินค้าลงรายการที่อยากได้ ถ้ามีอยู่แล้วจะอัพเดทเฉพาะการแจ้งเตือนที่เลือกรับ
+// ราคาและสถานะสต็อกตอนเพิ่มเป็นจุดเริ่มต้นของการตรวจลดราคาและการกลับมามีสต็อก
+func (r *wishlistRepository) Add(ctx context.Context, userID uuid.UUID, req *entities.AddToWishlistRequest) (*entities.WishlistItem, error) {
+	var product models.Product
+	if err := r.db.WithContext(ctx).First(&product, "id = ?", req.ProductID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	item := &models.UserWishlist{
+		UserID:            userID,
+		ProductID:         product.ID,
+		NotifyBackInStock: req.NotifyBackInStock,
+		NotifyPriceDrop:   req.NotifyPriceDrop,
+		AwaitingStock:     product.Stock <= 0,
+		LastPrice:         product.Price,
+	}
+
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"notify_back_in_stock", "notify_price_drop"}),
+	}).Create(item).Error; err != nil {
+		return nil, err
+	}
+
+	return r.Get(ctx, userID, product.ID)
+}
+
+func (r *wishlistRepository) Get(ctx context.Context, userID, productID uuid.UUID) (*entities.WishlistItem, error) {
+	var item models.UserWishlist
+	if err := r.activeProducts(r.db.WithContext(ctx)).
+		Preload("Product").
+		First(&item, "user_wishlist.user_id = ? AND user_wishlist.product_id = ?", userID, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrWishlistItemNotFound
+		}
+		return nil, err
+	}
+
+	return r.modelToEntity(&item), nil
+}
+
+// GetByUserID ดึงรายการที่อยากได้ของผู้ใช้แบบแบ่งหน้า สินค้าที่เพิ่มล่าสุดก่อน
+func (r *wishlistRepository) GetByUserID(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entities.WishlistItem, int, error) {
+	query := r.activeProducts(r.db.WithContext(ctx).Model(&models.UserWishlist{})).
+		Where("user_wishlist.user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []models.UserWishlist
+	offset := (page - 1) * limit
+	if err := query.Preload("Product").
+		Order("user_wishlist.created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var result []*entities.WishlistItem
+	for _, item := range items {
+		result = append(result, r.modelToEntity(&item))
+	}
+
+	return result, int(total), nil
+}
+
+func (r *wishlistRepository) Remove(ctx context.Context, userID, productID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&models.UserWishlist{}, "user_id = ? AND product_id = ?", userID, productID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrWishlistItemNotFound
+	}
+
+	return nil
+}
+
+// PendingNotifications ปรับสถานะที่ใช้ตรวจของสินค้าที่ระบุ แล้วคืนการแจ้งเตือนที่ยังไม่ได้ส่ง
+//   - back_in_stock: สินค้าที่เคยหมดสต็อก (AwaitingStock) และตอนนี้มีสต็อกแล้ว
+//   - price_drop: ราคาปัจจุบันต่ำกว่า LastPrice ราคาที่ขึ้นจะกลายเป็น LastPrice ใหม่ทันที
+//
+// รายการที่ไม่ได้เลือกรับการแจ้งเตือนจะถูกอัพเดทตามสินค้าเสมอ เพื่อไม่ให้แจ้งเหตุการณ์เก่าเมื่อเปิดรับภายหลัง
+func (r *wishlistRepository) PendingNotifications(ctx context.Context, productIDs []uuid.UUID) ([]*entities.WishlistNotification, error) {
+	db := r.db.WithContext(ctx)
+
+	if err := db.Exec(`
+		UPDATE user_wishlist SET awaiting_stock = (products.stock <= 0)
+		FROM products
+		WHERE products.id = user_wishlist.product_id AND products.id IN ?
+		AND (products.stock <= 0 OR NOT user_wishlist.notify_back_in_stock)
+	`, productIDs).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Exec(`
+		UPDATE user_wishlist SET last_price = products.price
+		FROM products
+		WHERE products.id = user_wishlist.product_id AND products.id IN ?
+		AND (products.price > user_wishlist.last_price OR NOT user_wishlist.notify_price_drop)
+	`, productIDs).Error; err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		UserID            uuid.UUID
+		ProductID         uuid.UUID
+		NotifyBackInStock bool
+		NotifyPriceDrop   bool
+		AwaitingStock     bool
+		LastPrice         int64
+		Email             string
+		FirstName         string
+		Name              string
+		Stock             int
+		Price             int64
+		Currency          string
+	}
+	if err := db.Model(&models.UserWishlist{}).
+		Select(`user_wishlist.user_id, user_wishlist.product_id, user_wishlist.notify_back_in_stock,
+			user_wishlist.notify_price_drop, user_wishlist.awaiting_stock, user_wishlist.last_price,
+			users.email, users.first_name, products.name, products.stock, products.price, products.currency`).
+		Joins("JOIN users ON users.id = user_wishlist.user_id AND users.deleted_at IS NULL AND users.active").
+		Joins("JOIN products ON products.id = user_wishlist.product_id AND products.deleted_at IS NULL").
+		Where("user_wishlist.product_id IN ?", productIDs).
+		Where(`(user_wishlist.notify_back_in_stock AND user_wishlist.awaiting_stock AND products.stock > 0)
+			OR (user_wishlist.notify_price_drop AND products.price < user_wishlist.last_price)`).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var notifications []*entities.WishlistNotification
+	for _, row := range rows {
+		notification := entities.WishlistNotification{
+			UserID:      row.UserID,
+			Email:       row.Email,
+			FirstName:   row.FirstName,
+			ProductID:   row.ProductID,
+			ProductName: row.Name,
+			Stock:       row.Stock,
+			Price:       entities.NewMoney(row.Price, row.Currency),
+		}
+
+		if row.NotifyBackInStock && row.AwaitingStock && row.Stock > 0 {
+			backInStock := notification
+			backInStock.Kind = entities.WishlistNotificationBackInStock
+			notifications = append(notifications, &backInStock)
+		}
+		if row.NotifyPriceDrop && row.Price < row.LastPrice {
+			previous := entities.NewMoney(row.LastPrice, row.Currency)
+			priceDrop := notification
+			priceDrop.Kind = entities.WishlistNotificationPriceDrop
+			priceDrop.PreviousPrice = &previous
+			notifications = append(notifications, &priceDrop)
+		}
+	}
+
+	return notifications, nil
+}
+
+// MarkNotified บันทึกว่าส่งการแจ้งเตือนแล้ว การลดราคาครั้งถัดไปต้องต่ำกว่าราคาที่แจ้งไปแล้ว
+func (r *wishlistRepository) MarkNotified(ctx context.Context, notifications []*entities.WishlistNotification) error {
+	tx := r.db.WithContext(ctx).Begin()
+
+	for _, notification := range notifications {
+		query := tx.Model(&models.UserWishlist{}).
+			Where("user_id = ? AND product_id = ?", notification.UserID, notification.ProductID)
+
+		var err error
+		switch notification.Kind {
+		case entities.WishlistNotificationBackInStock:
+			err = query.Update("awaiting_stock", false).Error
+		case entities.WishlistNotificationPriceDrop:
+			err = query.Update("last_price", notification.Price.Amount).Error
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// activeProducts กรองรายการที่สินค้าถูกลบไปแล้วออก
+func (r *wishlistRepository) activeProducts(query *gorm.DB) *gorm.DB {
+	return query.Joins("JOIN products ON products.id = user_wishlist.product_id AND products.deleted_at IS NULL")
+}
+
+func (r *wishlistRepository) modelToEntity(item *models.UserWishlist) *entities.WishlistItem {
+	entity := &entities.WishlistItem{
+		ProductID:         item.ProductID,
+		NotifyBackInStock: item.NotifyBackInStock,
+		NotifyPriceDrop:   item.NotifyPriceDrop,
+		AddedAt:           item.CreatedAt,
+	}
+
+	if item.Product.ID != uuid.Nil {
+		entity.Product = &entities.Product{
+			ID:          item.Product.ID,
+			Name:        item.Product.Name,
+			Description: item.Product.Description,
+			Price:       entities.NewMoney(item.Product.Price, item.Product.Currency),
+			Stock:       item.Product.Stock,
+			Weight:      item.Product.Weight,
+			Image:       item.Product.Image,
+			CategoryID:  item.Product.CategoryID,
+			CreatedAt:   item.Product.CreatedAt,
+			UpdatedAt:   item.Product.UpdatedAt,
+		}
+	}
+
+	return entity
+}
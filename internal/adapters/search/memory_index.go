@@ -0,0 +1,447 @@
+// package search รวม adapter ของ search engine ที่ใช้จับคู่คำค้นกับสินค้า
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/search"
+	"github.com/google/uuid"
+)
+
+// ฟิลด์ของสินค้าที่พบคำ ใช้เป็น bitmask ใน posting
+const (
+	fieldName uint8 = 1 << iota
+	fieldCategory
+	fieldDescription
+)
+
+// indexedProduct สิ่งที่เก็บไว้ต่อสินค้า เพื่อลบ posting และคำแนะนำออกได้เมื่อสินค้าเปลี่ยน
+type indexedProduct struct {
+	name  string
+	terms map[string]uint8
+	words []string
+}
+
+// vocabularyWord คำจากชื่อสินค้าและหมวดหมู่ที่ใช้เติมคำค้นให้สมบูรณ์
+// display คือคำตามที่พบ (ยังมีวรรณยุกต์) ส่วน count คือจำนวนสินค้าที่มีคำนี้
+type vocabularyWord struct {
+	display string
+	count   int
+}
+
+// memoryIndex inverted index ในหน่วยความจำ เหมาะกับการทดสอบและร้านค้าขนาดเล็ก
+// คำภาษาอังกฤษเก็บเป็นทั้งคำ ส่วนข้อความภาษาไทยที่ไม่เว้นวรรคเก็บเป็น bigram ของตัวอักษร
+// ทำให้ค้นคำที่อยู่กลางข้อความได้โดยไม่ต้องใช้พจนานุกรมตัดคำ
+type memoryIndex struct {
+	mu         sync.RWMutex
+	products   map[uuid.UUID]*indexedProduct
+	postings   map[string]map[uuid.UUID]uint8
+	vocabulary map[string]*vocabularyWord
+}
+
+// NewMemoryIndex สร้าง search index ในหน่วยความจำ ข้อมูลหายเมื่อรีสตาร์ต จึงต้องสร้างใหม่จากฐานข้อมูลตอนเริ่มระบบ
+func NewMemoryIndex() search.ProductSearchIndex {
+	return &memoryIndex{
+		products:   make(map[uuid.UUID]*indexedProduct),
+		postings:   make(map[string]map[uuid.UUID]uint8),
+		vocabulary: make(map[string]*vocabularyWord),
+	}
+}
+
+// Index เพิ่มหรือแทนที่สินค้าใน index จากชื่อ ชื่อหมวดหมู่ และคำอธิบาย
+func (idx *memoryIndex) Index(ctx context.Context, product *entities.Product) error {
+	entry := &indexedProduct{
+		name:  strings.ToLower(product.Name),
+		terms: make(map[string]uint8),
+	}
+	words := make(map[string]string)
+
+	add := func(text string, field uint8, suggest bool) {
+		for _, word := range splitWords(text) {
+			key := normalizeWord(word)
+			for _, term := range wordTerms(key) {
+				entry.terms[term] |= field
+			}
+			if _, ok := words[key]; suggest && !ok {
+				words[key] = word
+			}
+		}
+	}
+	add(product.Name, fieldName, true)
+	if product.Category != nil {
+		add(product.Category.Name, fieldCategory, true)
+	}
+	add(product.Description, fieldDescription, false)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(product.ID)
+
+	for term, fields := range entry.terms {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[uuid.UUID]uint8)
+		}
+		idx.postings[term][product.ID] = fields
+	}
+	for key, display := range words {
+		word := idx.vocabulary[key]
+		if word == nil {
+			word = &vocabularyWord{display: display}
+			idx.vocabulary[key] = word
+		}
+		word.count++
+		entry.words = append(entry.words, key)
+	}
+	idx.products[product.ID] = entry
+
+	return nil
+}
+
+// Delete ลบสินค้าออกจาก index ถ้าไม่มีสินค้านี้อยู่จะไม่ทำอะไร
+func (idx *memoryIndex) Delete(ctx context.Context, productID uuid.UUID) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(productID)
+	return nil
+}
+
+// remove ลบ posting และคำแนะนำของสินค้า ต้องถือ lock สำหรับเขียนอยู่แล้ว
+func (idx *memoryIndex) remove(productID uuid.UUID) {
+	entry, ok := idx.products[productID]
+	if !ok {
+		return
+	}
+
+	for term := range entry.terms {
+		delete(idx.postings[term], productID)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	for _, key := range entry.words {
+		if word := idx.vocabulary[key]; word != nil {
+			word.count--
+			if word.count == 0 {
+				delete(idx.vocabulary, key)
+			}
+		}
+	}
+	delete(idx.products, productID)
+}
+
+// Query หาสินค้าที่ตรงกับทุกคำในคำค้น (AND) เรียงตามคะแนนจากมากไปน้อย
+// คำสุดท้ายจับคู่แบบ prefix เพื่อรองรับการค้นขณะพิมพ์ และคำที่ยาวพอยอมให้สะกดผิดได้
+func (idx *memoryIndex) Query(ctx context.Context, query *entities.ProductSearchQuery) (*entities.ProductSearchResult, error) {
+	result := &entities.ProductSearchResult{ProductIDs: []uuid.UUID{}}
+
+	words := splitWords(query.Text)
+	if len(words) == 0 {
+		return result, nil
+	}
+	keys := make([]string, len(words))
+	for i, word := range words {
+		keys[i] = normalizeWord(word)
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var scores map[uuid.UUID]float64
+	for i, key := range keys {
+		matches := idx.matchWord(key, i == len(keys)-1)
+		if scores == nil {
+			scores = matches
+			continue
+		}
+		for id, score := range scores {
+			if match, ok := matches[id]; ok {
+				scores[id] = score + match
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	for id := range scores {
+		result.ProductIDs = append(result.ProductIDs, id)
+	}
+	sort.Slice(result.ProductIDs, func(i, j int) bool {
+		a, b := result.ProductIDs[i], result.ProductIDs[j]
+		if scores[a] != scores[b] {
+			return scores[a] > scores[b]
+		}
+		if idx.products[a].name != idx.products[b].name {
+			return idx.products[a].name < idx.products[b].name
+		}
+		return a.String() < b.String()
+	})
+	if query.Limit > 0 && len(result.ProductIDs) > query.Limit {
+		result.ProductIDs = result.ProductIDs[:query.Limit]
+	}
+
+	if query.SuggestionLimit > 0 {
+		result.Suggestions = idx.suggest(words, keys, query.SuggestionLimit)
+	}
+
+	return result, nil
+}
+
+// matchWord คะแนนของสินค้าที่ตรงกับคำค้นหนึ่งคำ คะแนนคือคุณภาพการจับคู่คูณน้ำหนักของฟิลด์ที่พบ
+func (idx *memoryIndex) matchWord(key string, last bool) map[uuid.UUID]float64 {
+	matches := make(map[uuid.UUID]float64)
+
+	if isThaiWord(key) {
+		// ข้อความภาษาไทยตรงเมื่อพบ bigram อย่างน้อยสองในสาม จึงทนต่อการพิมพ์ผิดหนึ่งตัวอักษรในคำที่ยาว
+		grams := wordTerms(key)
+		matched := make(map[uuid.UUID]int)
+		fields := make(map[uuid.UUID]uint8)
+		for _, gram := range grams {
+			for id, field := range idx.postings[gram] {
+				matched[id]++
+				fields[id] |= field
+			}
+		}
+		required := len(grams)
+		if required > 2 {
+			required = int(math.Ceil(float64(required) * 2 / 3))
+		}
+		for id, count := range matched {
+			if count >= required {
+				matches[id] = fieldWeight(fields[id]) * float64(count) / float64(len(grams))
+			}
+		}
+		return matches
+	}
+
+	for term, postings := range idx.postings {
+		quality := latinMatch(key, term, last)
+		if quality == 0 {
+			continue
+		}
+		for id, fields := range postings {
+			if score := quality * fieldWeight(fields); score > matches[id] {
+				matches[id] = score
+			}
+		}
+	}
+	return matches
+}
+
+// suggest เติมคำสุดท้ายของคำค้นจากชื่อสินค้าและหมวดหมู่ที่ขึ้นต้นด้วยคำนั้น เรียงตามจำนวนสินค้าที่มีคำ
+// ถ้าไม่มีคำให้เติม จะเสนอคำค้นที่แก้คำสะกดผิดแทน
+func (idx *memoryIndex) suggest(words, keys []string, limit int) []string {
+	prefix := strings.Join(words[:len(words)-1], " ")
+	last := keys[len(keys)-1]
+
+	var completions []*vocabularyWord
+	for key, word := range idx.vocabulary {
+		if key != last && strings.HasPrefix(key, last) {
+			completions = append(completions, word)
+		}
+	}
+	sort.Slice(completions, func(i, j int) bool {
+		if completions[i].count != completions[j].count {
+			return completions[i].count > completions[j].count
+		}
+		return completions[i].display < completions[j].display
+	})
+
+	var suggestions []string
+	for _, word := range completions[:min(limit, len(completions))] {
+		suggestions = append(suggestions, strings.TrimSpace(prefix+" "+word.display))
+	}
+	if len(suggestions) == 0 {
+		if corrected, ok := idx.correct(words, keys); ok {
+			suggestions = append(suggestions, corrected)
+		}
+	}
+	return suggestions
+}
+
+// correct แทนคำภาษาอังกฤษที่ไม่มีใน index ด้วยคำที่สะกดใกล้ที่สุด (ถ้าเท่ากันเลือกคำที่มีสินค้ามากกว่า)
+func (idx *memoryIndex) correct(words, keys []string) (string, bool) {
+	corrected := make([]string, len(words))
+	changed := false
+
+	for i, key := range keys {
+		corrected[i] = words[i]
+		if isThaiWord(key) || idx.postings[key] != nil {
+			continue
+		}
+
+		maxDistance := maxEdits(key)
+		best, bestDistance, bestCount := "", maxDistance+1, 0
+		for term, postings := range idx.postings {
+			if isThaiWord(term) {
+				continue
+			}
+			distance := editDistance(key, term, maxDistance)
+			if distance < bestDistance || (distance == bestDistance && len(postings) > bestCount) {
+				best, bestDistance, bestCount = term, distance, len(postings)
+			}
+		}
+		if best != "" && bestDistance <= maxDistance {
+			corrected[i] = best
+			changed = true
+		}
+	}
+
+	return strings.Join(corrected, " "), changed
+}
+
+// fieldWeight น้ำหนักของคำที่พบในแต่ละฟิลด์ ชื่อสินค้าสำคัญที่สุด
+func fieldWeight(fields uint8) float64 {
+	switch {
+	case fields&fieldName != 0:
+		return 3
+	case fields&fieldCategory != 0:
+		return 2
+	}
+	return 1
+}
+
+// latinMatch คุณภาพการจับคู่คำค้นกับคำใน index: ตรงทั้งคำ > prefix > สะกดผิด และ 0 ถ้าไม่ตรง
+func latinMatch(key, term string, prefix bool) float64 {
+	if isThaiWord(term) {
+		return 0
+	}
+	if term == key {
+		return 1
+	}
+	if prefix && strings.HasPrefix(term, key) {
+		return 0.8
+	}
+
+	edits := maxEdits(key)
+	if edits == 0 {
+		return 0
+	}
+	if distance := editDistance(key, term, edits); distance <= edits {
+		return 1 - 0.3*float64(distance)
+	}
+	// คำที่กำลังพิมพ์และสะกดผิด เทียบกับต้นคำที่ยาวเท่ากัน
+	if runes := []rune(term); prefix && len(runes) > len([]rune(key)) {
+		if editDistance(key, string(runes[:len([]rune(key))]), edits) <= edits {
+			return 0.5
+		}
+	}
+	return 0
+}
+
+// maxEdits จำนวนตัวอักษรที่ยอมให้สะกดผิด คำสั้นต้องตรงเท่านั้นเพื่อไม่ให้ได้ผลลัพธ์ที่ไม่เกี่ยวข้อง
+func maxEdits(word string) int {
+	switch length := len([]rune(word)); {
+	case length < 4:
+		return 0
+	case length < 8:
+		return 1
+	}
+	return 2
+}
+
+// editDistance ระยะ Damerau-Levenshtein (สลับตัวอักษรติดกันนับเป็นหนึ่งครั้ง)
+// คืน max+1 ทันทีเมื่อความยาวต่างกันเกิน max
+func editDistance(a, b string, max int) int {
+	s, t := []rune(a), []rune(b)
+	if diff := len(s) - len(t); diff > max || -diff > max {
+		return max + 1
+	}
+
+	rows := make([][]int, len(s)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(t)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(s)][len(t)]
+}
+
+// splitWords แยกข้อความเป็นคำตัวพิมพ์เล็ก ตัดที่อักขระที่ไม่ใช่ตัวอักษรหรือตัวเลข
+// และตัดเมื่อเปลี่ยนระหว่างอักษรไทยกับอักษรอื่น เช่น "เคสiphone" เป็น "เคส" และ "iphone"
+func splitWords(text string) []string {
+	var words []string
+	var current []rune
+	thai := false
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = current[:0]
+		}
+	}
+
+	for _, r := range strings.ToLower(text) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r) {
+			flush()
+			continue
+		}
+		if isThai := unicode.Is(unicode.Thai, r); isThai != thai {
+			flush()
+			thai = isThai
+		}
+		current = append(current, r)
+	}
+	flush()
+
+	return words
+}
+
+// normalizeWord ตัดวรรณยุกต์ไทยออก เพื่อให้ค้นเจอแม้ใส่วรรณยุกต์ผิดหรือไม่ใส่
+func normalizeWord(word string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '\u0e48' && r <= '\u0e4b' {
+			return -1
+		}
+		return r
+	}, word)
+}
+
+// wordTerms term ที่ใช้เก็บคำใน index คำภาษาไทยแตกเป็น bigram ที่ไม่ซ้ำกัน ส่วนคำอื่นใช้ทั้งคำ
+func wordTerms(key string) []string {
+	runes := []rune(key)
+	if !isThaiWord(key) || len(runes) <= 2 {
+		return []string{key}
+	}
+
+	seen := make(map[string]bool)
+	var grams []string
+	for i := 0; i+2 <= len(runes); i++ {
+		gram := string(runes[i : i+2])
+		if !seen[gram] {
+			seen[gram] = true
+			grams = append(grams, gram)
+		}
+	}
+	return grams
+}
+
+// isThaiWord คำที่ได้จาก splitWords เป็นอักษรชุดเดียวกันทั้งคำ จึงดูจากตัวแรกพอ
+func isThaiWord(word string) bool {
+	for _, r := range word {
+		return unicode.Is(unicode.Thai, r)
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+func newTestIndex(t *testing.T, products ...*entities.Product) *memoryIndex {
+	t.Helper()
+	idx := NewMemoryIndex().(*memoryIndex)
+	for _, product := range products {
+		if err := idx.Index(context.Background(), product); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return idx
+}
+
+func query(t *testing.T, idx *memoryIndex, text string) *entities.ProductSearchResult {
+	t.Helper()
+	result, err := idx.Query(context.Background(), &entities.ProductSearchQuery{Text: text, SuggestionLimit: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestQuery_RanksNameMatchesAndToleratesTypos(t *testing.T) {
+	shirt := &entities.Product{ID: uuid.New(), Name: "Cotton Shirt", Category: &entities.Category{Name: "Clothing"}}
+	towel := &entities.Product{ID: uuid.New(), Name: "Bath Towel", Description: "as soft as a cotton shirt"}
+	mug := &entities.Product{ID: uuid.New(), Name: "Coffee Mug"}
+	idx := newTestIndex(t, shirt, towel, mug)
+
+	result := query(t, idx, "shirt")
+	if len(result.ProductIDs) != 2 || result.ProductIDs[0] != shirt.ID {
+		t.Fatalf("expected the name match first, got %v", result.ProductIDs)
+	}
+
+	// สะกดผิดหนึ่งตัวอักษรและสลับตัวอักษรติดกัน
+	for _, text := range []string{"cottn shirt", "cofefe"} {
+		if result := query(t, idx, text); len(result.ProductIDs) == 0 {
+			t.Errorf("expected %q to match despite the typo", text)
+		}
+	}
+
+	// คำสั้นต้องตรงทั้งคำหรือเป็น prefix ของคำสุดท้าย
+	if result := query(t, idx, "mog"); len(result.ProductIDs) != 0 {
+		t.Errorf("expected no typo tolerance for short words, got %v", result.ProductIDs)
+	}
+	if result := query(t, idx, "cloth"); len(result.ProductIDs) != 1 || result.ProductIDs[0] != shirt.ID {
+		t.Errorf("expected the category prefix to match the shirt, got %v", result.ProductIDs)
+	}
+}
+
+func TestQuery_MatchesThaiWithoutWordBreaks(t *testing.T) {
+	tshirt := &entities.Product{ID: uuid.New(), Name: "เสื้อยืดคอกลมสีขาว"}
+	phoneCase := &entities.Product{ID: uuid.New(), Name: "เคสiphone 15 กันกระแทก"}
+	idx := newTestIndex(t, tshirt, phoneCase)
+
+	// คำที่อยู่กลางข้อความ
+	if result := query(t, idx, "คอกลม"); len(result.ProductIDs) != 1 || result.ProductIDs[0] != tshirt.ID {
+		t.Errorf("expected a match inside the Thai run, got %v", result.ProductIDs)
+	}
+	// ใส่วรรณยุกต์ผิด
+	if result := query(t, idx, "เสือยืด"); len(result.ProductIDs) != 1 {
+		t.Errorf("expected a match without the tone mark, got %v", result.ProductIDs)
+	}
+	// อักษรไทยติดกับอักษรอังกฤษ
+	if result := query(t, idx, "iphone เคส"); len(result.ProductIDs) != 1 || result.ProductIDs[0] != phoneCase.ID {
+		t.Errorf("expected the mixed-script name to match, got %v", result.ProductIDs)
+	}
+}
+
+func TestQuery_SuggestsCompletionsAndCorrections(t *testing.T) {
+	idx := newTestIndex(t,
+		&entities.Product{ID: uuid.New(), Name: "Running Shoes"},
+		&entities.Product{ID: uuid.New(), Name: "Running Shorts"},
+		&entities.Product{ID: uuid.New(), Name: "Rugby Ball"},
+	)
+
+	result := query(t, idx, "running sho")
+	if len(result.Suggestions) != 2 || result.Suggestions[0] != "running shoes" || result.Suggestions[1] != "running shorts" {
+		t.Errorf("unexpected completions: %v", result.Suggestions)
+	}
+
+	result = query(t, idx, "runnign")
+	if len(result.Suggestions) != 1 || result.Suggestions[0] != "running" {
+		t.Errorf("expected a spelling correction, got %v", result.Suggestions)
+	}
+}
+
+func TestIndex_ReplacesAndDeletesProducts(t *testing.T) {
+	product := &entities.Product{ID: uuid.New(), Name: "Desk Lamp"}
+	idx := newTestIndex(t, product)
+
+	product.Name = "Floor Lamp"
+	if err := idx.Index(context.Background(), product); err != nil {
+		t.Fatal(err)
+	}
+	if result := query(t, idx, "desk"); len(result.ProductIDs) != 0 || len(result.Suggestions) != 0 {
+		t.Errorf("expected the old name to be removed, got %+v", result)
+	}
+	if result := query(t, idx, "floor"); len(result.ProductIDs) != 1 {
+		t.Errorf("expected the new name to match, got %v", result.ProductIDs)
+	}
+
+	if err := idx.Delete(context.Background(), product.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(idx.postings) != 0 || len(idx.vocabulary) != 0 {
+		t.Errorf("expected an empty index after delete, got %d postings and %d words", len(idx.postings), len(idx.vocabulary))
+	}
+}
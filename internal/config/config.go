@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -26,6 +27,28 @@ type Config struct {
 	AdminPassword  string
 	AdminFirstName string
 	AdminLastName  string
+
+	// การแจ้งเตือนสินค้าสต็อกต่ำ: log, webhook หรือ email
+	LowStockNotifier   string
+	LowStockWebhookURL string
+	LowStockEmailTo    string
+
+	// การแจ้งเตือนสินค้าในรายการที่อยากได้ถึงผู้ใช้: log, webhook หรือ email (ส่งถึงอีเมลของผู้ใช้แต่ละคน)
+	WishlistNotifier   string
+	WishlistWebhookURL string
+
+	// หมายเลขพร้อมเพย์ของร้าน (เบอร์โทรหรือเลขประจำตัวผู้เสียภาษี) ถ้าว่างจะไม่เปิดรับชำระผ่าน PromptPay
+	PromptPayID string
+
+	// secret สำหรับตรวจลายเซ็นของ payment webhook แต่ละผู้ให้บริการ ถ้าว่างจะไม่รับ webhook จากผู้ให้บริการนั้น
+	FakePaymentWebhookSecret string
+	PromptPayWebhookSecret   string
+
+	// ระยะเวลาระหว่างการดึงสถานะพัสดุจากผู้ให้บริการขนส่ง
+	ShipmentPollInterval time.Duration
+
+	// ตัวจับคู่คำค้นสินค้า: database (full-text search ของ Postgres) หรือ memory (inverted index ในหน่วยความจำ)
+	SearchIndex string
 }
 
 func LoadConfig() (*Config, error) {
@@ -47,6 +70,16 @@ func LoadConfig() (*Config, error) {
 		DBSSL:        getEnv("DB_SSL", "disable"),
 		JWTExpiresIn: getEnv("JWT_EXPIRES_IN", "24h"),
 
+		LowStockNotifier:   getEnv("LOW_STOCK_NOTIFIER", "log"),
+		LowStockWebhookURL: getEnv("LOW_STOCK_WEBHOOK_URL", ""),
+		LowStockEmailTo:    getEnv("LOW_STOCK_EMAIL_TO", ""),
+		WishlistNotifier:   getEnv("WISHLIST_NOTIFIER", "log"),
+		WishlistWebhookURL: getEnv("WISHLIST_WEBHOOK_URL", ""),
+		PromptPayID:        getEnv("PROMPTPAY_ID", ""),
+
+		ShipmentPollInterval: getEnvDuration("SHIPMENT_POLL_INTERVAL", 15*time.Minute),
+		SearchIndex:          getEnv("SEARCH_INDEX", "database"),
+
 		// ค่าที่ไม่ปลอดภัยสำหรับการตั่งค่า Default ต้องตั่งค่าในไฟล์ .env เท่านั้น
 		DBPass:         getEnv("DB_PASS", ""),
 		DBName:         getEnv("DB_NAME", ""),
@@ -55,6 +88,9 @@ func LoadConfig() (*Config, error) {
 		AdminPassword:  getEnv("ADMIN_PASSWORD", ""),
 		AdminFirstName: getEnv("ADMIN_FIRST_NAME", ""),
 		AdminLastName:  getEnv("ADMIN_LAST_NAME", ""),
+
+		FakePaymentWebhookSecret: getEnv("FAKE_PAYMENT_WEBHOOK_SECRET", ""),
+		PromptPayWebhookSecret:   getEnv("PROMPTPAY_WEBHOOK_SECRET", ""),
 	}
 
 	// ตรวจสอบค่าที่จำเป็น
@@ -99,6 +135,40 @@ func validateConfig(config *Config) error {
 		return errors.New("ADMIN_EMAIL is not a valid email address")
 	}
 
+	switch config.LowStockNotifier {
+	case "log":
+	case "webhook":
+		if config.LowStockWebhookURL == "" {
+			return errors.New("LOW_STOCK_WEBHOOK_URL must be set when LOW_STOCK_NOTIFIER is webhook")
+		}
+	case "email":
+		if !isValidEmail(config.LowStockEmailTo) {
+			return errors.New("LOW_STOCK_EMAIL_TO must be a valid email address when LOW_STOCK_NOTIFIER is email")
+		}
+	default:
+		return fmt.Errorf("LOW_STOCK_NOTIFIER must be one of log, webhook, email (got %q)", config.LowStockNotifier)
+	}
+
+	switch config.WishlistNotifier {
+	case "log", "email":
+	case "webhook":
+		if config.WishlistWebhookURL == "" {
+			return errors.New("WISHLIST_WEBHOOK_URL must be set when WISHLIST_NOTIFIER is webhook")
+		}
+	default:
+		return fmt.Errorf("WISHLIST_NOTIFIER must be one of log, webhook, email (got %q)", config.WishlistNotifier)
+	}
+
+	if config.ShipmentPollInterval <= 0 {
+		return errors.New("SHIPMENT_POLL_INTERVAL must be a positive duration such as 15m")
+	}
+
+	switch config.SearchIndex {
+	case "database", "memory":
+	default:
+		return fmt.Errorf("SEARCH_INDEX must be one of database, memory (got %q)", config.SearchIndex)
+	}
+
 	// ตรวจสอบค่าพื้นฐาน
 	if config.DBName == "" {
 		return fmt.Errorf("DB_NAME must be set")
@@ -113,6 +183,20 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvDuration อ่านค่า env เป็น time.Duration ถ้าอ่านไม่ได้จะคืน 0 ให้ validateConfig แจ้ง error
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0
+	}
+	return duration
+}
+
 func isValidEmail(email string) bool {
 	if email == "" {
 		return false
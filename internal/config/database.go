@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/adapters/persistence/models"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // SetupDatabase
@@ -72,12 +75,53 @@ func shouldRunMigration() bool {
 	return false
 }
 
+// migrationModels คืนรายการ model ทั้งหมดที่ต้อง migrate
+// เมื่อเพิ่ม model ใหม่ให้เพิ่มไว้ที่นี่ที่เดียว
+func migrationModels() []interface{} {
+	return []interface{}{
+		&models.Role{},
+		&models.Permission{},
+		&models.User{},
+		&models.TaxRule{},
+		&models.Category{},
+		&models.Product{},
+		&models.ProductImage{},
+		&models.ProductOption{},
+		&models.ProductOptionValue{},
+		&models.ProductVariant{},
+		&models.Cart{},
+		&models.CartItem{},
+		&models.Order{},
+		&models.OrderItem{},
+		&models.Transaction{},
+		&models.OrderStatusEvent{},
+		&models.InventoryMovement{},
+		&models.PaymentEvent{},
+		&models.Refund{},
+		&models.RefundItem{},
+		&models.ExchangeRate{},
+		&models.Promotion{},
+		&models.PromotionRedemption{},
+		&models.OrderDiscount{},
+		&models.ShippingMethod{},
+		&models.ShippingRegionRate{},
+		&models.Address{},
+		&models.Shipment{},
+		&models.ShipmentItem{},
+		&models.ShipmentCheckpoint{},
+		&models.Return{},
+		&models.ReturnItem{},
+		&models.ReturnEvent{},
+		&models.UserWishlist{},
+		&models.DataMigration{},
+	}
+}
+
 // ฟังก์ชันสำหรับ migrate
 func runMigration(db *gorm.DB) {
 	log.Println("Running database migration...")
 
-	err := db.AutoMigrate(&models.User{})
-	if err != nil {
+	if err := Migrate(db); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
@@ -89,9 +133,210 @@ func RunMigrationManual(config *Config) error {
 
 	log.Println("Running manual database migration...")
 
-	err := db.AutoMigrate(&models.User{})
-	if err != nil {
+	return Migrate(db)
+}
+
+// Migrate ปรับ schema ให้ตรงกับ model ทั้งหมด แล้วเติมข้อมูลที่ schema ใหม่ต้องใช้ ทำซ้ำได้
+// ชุดทดสอบที่ใช้ฐานข้อมูลจริงก็ migrate ผ่านฟังก์ชันนี้ เพื่อให้ schema ตรงกับที่แอปใช้
+func Migrate(db *gorm.DB) error {
+	if err := migrateMoneyColumns(db); err != nil {
+		return fmt.Errorf("failed to migrate money columns: %w", err)
+	}
+
+	if err := migrateCategorySlugs(db); err != nil {
+		return fmt.Errorf("failed to migrate category slugs: %w", err)
+	}
+
+	// ต้องดูก่อน AutoMigrate ว่าคำสั่งซื้อที่มีอยู่สร้างก่อนจะมีคอลัมน์ภาษีหรือไม่
+	taxBackfill := pendingTaxBackfill(db)
+
+	if err := db.AutoMigrate(migrationModels()...); err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
+
+	if err := migrateProductSearch(db); err != nil {
+		return fmt.Errorf("failed to migrate product search: %w", err)
+	}
+
+	if err := backfillInventoryLedger(db); err != nil {
+		return fmt.Errorf("failed to backfill inventory ledger: %w", err)
+	}
+
+	if err := runDataMigrationOnce(db, "order_tax_breakdown", taxBackfill.run); err != nil {
+		return fmt.Errorf("failed to backfill order tax breakdown: %w", err)
+	}
+	return nil
+}
+
+// migrateProductSearch สร้างคอลัมน์ search_vector ที่ Postgres คำนวณจากชื่อ (น้ำหนัก A) และคำอธิบาย (น้ำหนัก B)
+// พร้อม GIN index สำหรับ full-text search และ trigram index บนชื่อสินค้าสำหรับการค้นด้วย ILIKE
+// ใช้ text search config แบบ simple เพราะ Postgres ไม่มี config ภาษาไทย ทำซ้ำได้
+func migrateProductSearch(db *gorm.DB) error {
+	statements := []string{
+		`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+		`ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+			setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+		) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector)`,
+		`CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING GIN (name gin_trgm_ops)`,
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateCategorySlugs เพิ่มคอลัมน์ slug ให้หมวดหมู่ที่มีอยู่ก่อนจะมี slug และสร้างค่าที่ไม่ซ้ำจากชื่อ
+// ต้องทำก่อน AutoMigrate เพราะ unique index ของ slug สร้างไม่ได้ถ้ามีหลายแถวเป็นค่าว่าง ทำซ้ำได้
+func migrateCategorySlugs(db *gorm.DB) error {
+	if !db.Migrator().HasTable("categories") {
+		return nil
+	}
+
+	if err := db.Exec(`ALTER TABLE categories ADD COLUMN IF NOT EXISTS slug varchar(120) NOT NULL DEFAULT ''`).Error; err != nil {
+		return err
+	}
+
+	var categories []struct {
+		ID   string
+		Name string
+	}
+	if err := db.Table("categories").Select("id, name").Where("slug = ''").Order("created_at ASC").Scan(&categories).Error; err != nil {
+		return err
+	}
+
+	if len(categories) > 0 {
+		var existing []string
+		if err := db.Table("categories").Where("slug <> ''").Pluck("slug", &existing).Error; err != nil {
+			return err
+		}
+		used := make(map[string]bool, len(existing))
+		for _, slug := range existing {
+			used[slug] = true
+		}
+
+		log.Printf("Generating slugs for %d categories\n", len(categories))
+		for _, category := range categories {
+			base := entities.Slugify(category.Name)
+			if base == "" {
+				base = "category"
+			}
+			slug := entities.AvailableSlug(base, used)
+			used[slug] = true
+
+			if err := db.Exec("UPDATE categories SET slug = ? WHERE id = ?", slug, category.ID).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return db.Exec(`ALTER TABLE categories ALTER COLUMN slug DROP DEFAULT`).Error
+}
+
+// backfillInventoryLedger สร้างยอดยกมาใน inventory ledger ให้สินค้าที่มีอยู่ก่อนจะมี ledger
+// เพื่อให้ผลรวมของ ledger ตรงกับสต็อกปัจจุบัน ทำซ้ำได้โดยไม่สร้างรายการซ้ำ
+func backfillInventoryLedger(db *gorm.DB) error {
+	return db.Exec(`
+		INSERT INTO inventory_movements (product_id, delta, reason, note, created_at, updated_at)
+		SELECT p.id, p.stock, ?, 'opening balance', NOW(), NOW()
+		FROM products p
+		WHERE p.stock <> 0
+		AND NOT EXISTS (SELECT 1 FROM inventory_movements m WHERE m.product_id = p.id)
+	`, string(entities.InventoryReasonAdjustment)).Error
+}
+
+// runDataMigrationOnce รันการเติมข้อมูลที่ชื่อ name เพียงครั้งเดียว โดยบันทึกชื่อลง data_migrations
+// ใน transaction เดียวกับการเติมข้อมูล แอปที่เริ่มพร้อมกันหลายตัวจึงมีเพียงตัวเดียวที่ได้รัน
+func runDataMigrationOnce(db *gorm.DB, name string, migrate func(tx *gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.DataMigration{Name: name, AppliedAt: time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		log.Printf("Running data migration %s\n", name)
+		return migrate(tx)
+	})
+}
+
+// taxBackfill บอกว่าตารางใดมีข้อมูลที่สร้างก่อนจะมีคอลัมน์ยอดก่อนภาษีและยอดรวม
+type taxBackfill struct {
+	orderItems bool
+	orders     bool
+}
+
+// pendingTaxBackfill ต้องเรียกก่อน AutoMigrate ถ้าคอลัมน์มีอยู่แล้ว แถวที่มีอยู่ถูกสร้างพร้อมยอดภาษีแล้ว
+// ยอดรวมที่เป็นศูนย์ของแถวเหล่านั้นอาจมาจากคูปองส่วนลด จึงห้ามเติมทับ
+func pendingTaxBackfill(db *gorm.DB) taxBackfill {
+	migrator := db.Migrator()
+	return taxBackfill{
+		orderItems: migrator.HasTable("order_items") && !migrator.HasColumn("order_items", "total"),
+		orders:     migrator.HasTable("orders") && !migrator.HasColumn("orders", "subtotal"),
+	}
+}
+
+// run เติมยอดก่อนภาษีของคำสั่งซื้อที่สร้างก่อนจะมีการคำนวณภาษี
+// คำสั่งซื้อเหล่านั้นไม่ได้บันทึกภาษีไว้ จึงถือว่ายอดก่อนภาษีเท่ากับยอดรวมและภาษีเป็นศูนย์
+func (b taxBackfill) run(tx *gorm.DB) error {
+	if b.orderItems {
+		if err := tx.Exec(`UPDATE order_items SET subtotal = price * quantity, total = price * quantity`).Error; err != nil {
+			return err
+		}
+	}
+	if b.orders {
+		if err := tx.Exec(`UPDATE orders SET subtotal = total_price`).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moneyColumns คอลัมน์จำนวนเงินที่เคยเป็น decimal(10,2) หน่วยบาท
+var moneyColumns = []struct {
+	table  string
+	column string
+}{
+	{"products", "price"},
+	{"carts", "total_price"},
+	{"cart_items", "price"},
+	{"orders", "total_price"},
+	{"order_items", "price"},
+	{"transactions", "amount"},
+	{"refunds", "amount"},
+	{"refund_items", "amount"},
+}
+
+// migrateMoneyColumns แปลงคอลัมน์จำนวนเงินจาก decimal หน่วยบาทเป็น bigint หน่วยสตางค์
+// ต้องทำก่อน AutoMigrate เพราะ AutoMigrate จะเปลี่ยนชนิดคอลัมน์โดยไม่คูณ 100 ให้
+// คอลัมน์ที่แปลงไปแล้วจะถูกข้าม จึงรันซ้ำได้
+func migrateMoneyColumns(db *gorm.DB) error {
+	for _, money := range moneyColumns {
+		var dataType string
+		if err := db.Raw(`
+			SELECT data_type FROM information_schema.columns
+			WHERE table_schema = CURRENT_SCHEMA() AND table_name = ? AND column_name = ?
+		`, money.table, money.column).Scan(&dataType).Error; err != nil {
+			return err
+		}
+		if dataType != "numeric" {
+			continue
+		}
+
+		log.Printf("Converting %s.%s to minor units\n", money.table, money.column)
+		if err := db.Exec(fmt.Sprintf(
+			"ALTER TABLE %[1]s ALTER COLUMN %[2]s TYPE bigint USING ROUND(%[2]s * 100)::bigint",
+			money.table, money.column,
+		)).Error; err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -9,11 +9,34 @@ import (
 	"gorm.io/gorm"
 )
 
+// seedRole สร้าง Role ตามชื่อที่กำหนดถ้ายังไม่มีในฐานข้อมูล และคืนค่า Role นั้นกลับไป
+func seedRole(db *gorm.DB, name, description string) (*models.Role, error) {
+	role := &models.Role{}
+	err := db.Where(models.Role{Name: name}).
+		Attrs(models.Role{Description: description}).
+		FirstOrCreate(role).Error
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
 func SeedAdminUser(db *gorm.DB, config *Config) error {
 
+	// สร้าง Role พื้นฐาน (user ใช้ตอนสมัครสมาชิก, admin ใช้กับผู้ดูแลระบบ)
+	if _, err := seedRole(db, entities.RoleUser, "ผู้ใช้งานทั่วไป"); err != nil {
+		log.Printf("❌ Error seeding user role: %v", err)
+		return err
+	}
+	adminRole, err := seedRole(db, entities.RoleAdmin, "ผู้ดูแลระบบ")
+	if err != nil {
+		log.Printf("❌ Error seeding admin role: %v", err)
+		return err
+	}
+
 	// ตรวจสอบว่ามี admin user อยู่แล้วหรือไม่
 	var count int64
-	db.Model(&models.User{}).Where("role = ?", entities.RoleAdmin).Count(&count)
+	db.Model(&models.User{}).Where("role_id = ?", adminRole.ID).Count(&count)
 
 	if count > 0 {
 		log.Println("Admin user already exists, skipping seeding")
@@ -62,8 +85,8 @@ func SeedAdminUser(db *gorm.DB, config *Config) error {
 		Password:  hashedPassword,
 		FirstName: config.AdminFirstName,
 		LastName:  config.AdminLastName,
-		Role:      entities.RoleAdmin,
-		IsActive:  true,
+		RoleID:    adminRole.ID,
+		Active:    true,
 	}
 
 	if err := db.Create(adminUser).Error; err != nil {
@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrAddressNotFound = errors.New("ไม่พบที่อยู่")
+
+// PostalAddress ที่อยู่สำหรับจัดส่งแบบแยกช่องตามรูปแบบที่อยู่ของไทย
+type PostalAddress struct {
+	RecipientName string `json:"recipient_name" validate:"required,max=100"`
+	Phone         string `json:"phone" validate:"required,max=20"`
+	Line1         string `json:"line1" validate:"required,max=255"`
+	Line2         string `json:"line2" validate:"max=255"`
+	Subdistrict   string `json:"subdistrict" validate:"max=100"`
+	District      string `json:"district" validate:"max=100"`
+	Province      string `json:"province" validate:"required,max=100"`
+	PostalCode    string `json:"postal_code" validate:"required,max=10"`
+	Country       string `json:"country" validate:"omitempty,len=2"`
+}
+
+// String จัดรูปแบบที่อยู่เป็นข้อความบรรทัดเดียว ใช้เก็บใน Order.ShippingAddress
+func (a PostalAddress) String() string {
+	parts := []string{a.RecipientName, a.Phone, a.Line1, a.Line2, a.Subdistrict, a.District, a.Province, a.PostalCode, a.Country}
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, ", ")
+}
+
+// Address ที่อยู่หนึ่งรายการในสมุดที่อยู่ของผู้ใช้ ผู้ใช้แต่ละคนมีที่อยู่เริ่มต้นได้เพียงรายการเดียว
+type Address struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	Label  string    `json:"label"`
+	PostalAddress
+	IsDefault bool      `json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreateAddressRequest ที่อยู่แรกของผู้ใช้จะเป็นที่อยู่เริ่มต้นเสมอ
+type CreateAddressRequest struct {
+	Label string `json:"label" validate:"max=50"`
+	PostalAddress
+	IsDefault bool `json:"is_default"`
+}
+
+// UpdateAddressRequest แทนที่ข้อมูลที่อยู่ทั้งหมด ส่วน IsDefault เป็น nil ได้ถ้าไม่ต้องการเปลี่ยน
+type UpdateAddressRequest struct {
+	Label string `json:"label" validate:"max=50"`
+	PostalAddress
+	IsDefault *bool `json:"is_default"`
+}
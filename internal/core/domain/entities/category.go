@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCategoryNotFound        = errors.New("ไม่พบหมวดหมู่")
+	ErrCategorySlugExists      = errors.New("slug ของหมวดหมู่นี้ถูกใช้แล้ว")
+	ErrInvalidCategorySlug     = errors.New("slug ของหมวดหมู่ต้องมีตัวอักษรหรือตัวเลขอย่างน้อยหนึ่งตัว")
+	ErrInvalidCategoryParent   = errors.New("ไม่สามารถย้ายหมวดหมู่ไปอยู่ใต้ตัวเองหรือหมวดหมู่ย่อยของตัวเองได้")
+	ErrCategoryHasProducts     = errors.New("หมวดหมู่นี้ยังมีสินค้าอยู่")
+	ErrParentCategoryNotFound  = errors.New("ไม่พบหมวดหมู่แม่")
+	ErrInvalidCategoryReassign = errors.New("ต้องย้ายสินค้าไปยังหมวดหมู่อื่นที่มีอยู่และไม่ใช่หมวดหมู่ที่กำลังลบ")
+)
+
+// MaxCategorySlugLength ความยาวสูงสุดของ slug (นับเป็นตัวอักษร) เผื่อที่ให้เลขต่อท้ายเมื่อ slug ซ้ำ
+const MaxCategorySlugLength = 100
+
+// CategoryBreadcrumb หมวดหมู่หนึ่งระดับในเส้นทางจากหมวดหมู่บนสุดลงมา
+type CategoryBreadcrumb struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Slug string    `json:"slug"`
+}
+
+// Slugify แปลงข้อความเป็น slug สำหรับ URL: ตัวพิมพ์เล็ก คั่นคำด้วย "-"
+// เก็บตัวอักษรทุกภาษา (รวมสระและวรรณยุกต์ไทย) และตัวเลขไว้ คืนค่าว่างถ้าไม่เหลือตัวอักษรเลย
+func Slugify(text string) string {
+	var slug strings.Builder
+	length := 0
+	pendingDash := false
+
+	for _, r := range strings.ToLower(text) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r) {
+			pendingDash = length > 0
+			continue
+		}
+		if length >= MaxCategorySlugLength {
+			break
+		}
+		if pendingDash {
+			slug.WriteRune('-')
+			length++
+			pendingDash = false
+		}
+		slug.WriteRune(r)
+		length++
+	}
+
+	return strings.TrimSuffix(slug.String(), "-")
+}
+
+// AvailableSlug คืน base ถ้ายังไม่ถูกใช้ ไม่เช่นนั้นคืน base-2, base-3, ... ตัวแรกที่ยังว่าง
+func AvailableSlug(base string, used map[string]bool) string {
+	if !used[base] {
+		return base
+	}
+	for n := 2; ; n++ {
+		if slug := fmt.Sprintf("%s-%d", base, n); !used[slug] {
+			return slug
+		}
+	}
+}
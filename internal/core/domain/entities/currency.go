@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExchangeRate อัตราแลกเปลี่ยนจากสกุลเงินหลักของร้าน (DefaultCurrency) เป็นสกุลเงินอื่น
+// Rate คือจำนวนหน่วยหลักของ Currency ต่อ 1 หน่วยหลักของ DefaultCurrency เช่น USD 0.028
+type ExchangeRate struct {
+	Currency  string    `json:"currency"`
+	Rate      float64   `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SetExchangeRateRequest ข้อมูลสำหรับกำหนดอัตราแลกเปลี่ยนของสกุลเงิน
+type SetExchangeRateRequest struct {
+	Rate float64 `json:"rate" validate:"required,gt=0"`
+}
+
+// ExchangeRateTable อัตราแลกเปลี่ยนตามรหัสสกุลเงิน เทียบกับ DefaultCurrency
+// สกุลเงินหลักมีอัตรา 1 เสมอโดยไม่ต้องเก็บไว้ในตาราง
+type ExchangeRateTable map[string]float64
+
+// NewExchangeRateTable สร้างตารางอัตราแลกเปลี่ยนจากรายการอัตราที่เก็บไว้
+func NewExchangeRateTable(rates []*ExchangeRate) ExchangeRateTable {
+	table := ExchangeRateTable{DefaultCurrency: 1}
+	for _, rate := range rates {
+		table[rate.Currency] = rate.Rate
+	}
+	return table
+}
+
+// Rate คืนอัตราแลกเปลี่ยนจากสกุลเงิน from เป็น to โดยคิดผ่านสกุลเงินหลัก
+func (t ExchangeRateTable) Rate(from, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	fromRate, ok := t[from]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, from)
+	}
+	toRate, ok := t[to]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, to)
+	}
+
+	return toRate / fromRate, nil
+}
+
+// Convert แปลงเงินเป็นสกุลเงิน to ตามอัตราในตาราง
+func (t ExchangeRateTable) Convert(money Money, to string) (Money, error) {
+	rate, err := t.Rate(money.Currency, to)
+	if err != nil {
+		return Money{}, err
+	}
+	return money.Convert(to, rate), nil
+}
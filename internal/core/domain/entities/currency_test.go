@@ -0,0 +1,57 @@
+package entities_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+func TestExchangeRateTableConvert(t *testing.T) {
+	table := entities.NewExchangeRateTable([]*entities.ExchangeRate{
+		{Currency: "USD", Rate: 0.028},
+		{Currency: "JPY", Rate: 4},
+		{Currency: "EUR", Rate: 0.5},
+	})
+
+	tests := []struct {
+		name  string
+		money entities.Money
+		to    string
+		want  entities.Money
+	}{
+		{"same currency", entities.NewMoney(12345, "THB"), "THB", entities.NewMoney(12345, "THB")},
+		{"default to other", entities.NewMoney(10000, "THB"), "USD", entities.NewMoney(280, "USD")},
+		{"other to default", entities.NewMoney(280, "USD"), "THB", entities.NewMoney(10000, "THB")},
+		// JPY ไม่มีหน่วยย่อย 100.00 บาทจึงเป็น 400 เยน
+		{"to zero-decimal currency", entities.NewMoney(10000, "THB"), "JPY", entities.NewMoney(400, "JPY")},
+		{"between two non-default currencies", entities.NewMoney(1000, "JPY"), "USD", entities.NewMoney(700, "USD")},
+		{"rounds half up", entities.NewMoney(1, "THB"), "EUR", entities.NewMoney(1, "EUR")},
+		{"rounds negative half away from zero", entities.NewMoney(-1, "THB"), "EUR", entities.NewMoney(-1, "EUR")},
+		{"rounds down below half", entities.NewMoney(1, "THB"), "USD", entities.NewMoney(0, "USD")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := table.Convert(tt.money, tt.to)
+			if err != nil {
+				t.Fatalf("Convert: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Convert(%d %s, %s) = %d %s, want %d %s",
+					tt.money.Amount, tt.money.Currency, tt.to, got.Amount, got.Currency, tt.want.Amount, tt.want.Currency)
+			}
+		})
+	}
+}
+
+func TestExchangeRateTableConvert_UnsupportedCurrency(t *testing.T) {
+	table := entities.NewExchangeRateTable([]*entities.ExchangeRate{{Currency: "USD", Rate: 0.028}})
+
+	if got, err := table.Convert(entities.NewMoney(100, "THB"), "GBP"); !errors.Is(err, entities.ErrUnsupportedCurrency) {
+		t.Errorf("Convert to GBP = %v, %v, want ErrUnsupportedCurrency", got, err)
+	}
+	if got, err := table.Convert(entities.NewMoney(100, "GBP"), "THB"); !errors.Is(err, entities.ErrUnsupportedCurrency) {
+		t.Errorf("Convert from GBP = %v, %v, want ErrUnsupportedCurrency", got, err)
+	}
+}
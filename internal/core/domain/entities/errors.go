@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Domain errors ที่ handler ใช้แยกแยะเพื่อตอบ HTTP status ให้ถูกต้อง
+var (
+	ErrCartItemNotFound = errors.New("ไม่พบสินค้าในตะกร้า")
+	ErrCartEmpty        = errors.New("ตะกร้าสินค้าว่าง")
+	ErrOrderNotFound    = errors.New("ไม่พบคำสั่งซื้อ")
+	ErrProductNotFound  = errors.New("ไม่พบสินค้า")
+	ErrOutOfStock       = errors.New("สินค้าในสต็อกไม่พอ")
+)
+
+// OutOfStockItem สินค้าหนึ่งรายการที่มีสต็อกไม่พอสำหรับจำนวนที่ต้องการ
+type OutOfStockItem struct {
+	ProductID uuid.UUID  `json:"product_id"`
+	VariantID *uuid.UUID `json:"variant_id,omitempty"`
+	Name      string     `json:"name"`
+	Requested int        `json:"requested"`
+	Available int        `json:"available"`
+}
+
+// OutOfStockError คือ error ที่เกิดเมื่อสต็อกไม่พอ โดยระบุสินค้าทุกรายการที่มีปัญหา
+// ใช้ errors.Is(err, ErrOutOfStock) เพื่อตรวจสอบได้
+type OutOfStockError struct {
+	Items []OutOfStockItem
+}
+
+func (e *OutOfStockError) Error() string {
+	parts := make([]string, 0, len(e.Items))
+	for _, item := range e.Items {
+		parts = append(parts, fmt.Sprintf("%s (ต้องการ %d เหลือ %d)", item.Name, item.Requested, item.Available))
+	}
+	return fmt.Sprintf("%s: %s", ErrOutOfStock, strings.Join(parts, ", "))
+}
+
+func (e *OutOfStockError) Unwrap() error {
+	return ErrOutOfStock
+}
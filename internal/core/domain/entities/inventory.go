@@ -0,0 +1,82 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InventoryReason เหตุผลของการเปลี่ยนแปลงสต็อกสินค้า
+type InventoryReason string
+
+const (
+	InventoryReasonSale         InventoryReason = "sale"
+	InventoryReasonCancellation InventoryReason = "cancellation"
+	InventoryReasonAdjustment   InventoryReason = "adjustment"
+	InventoryReasonReturn       InventoryReason = "return"
+)
+
+// IsValid ตรวจสอบว่าเป็นเหตุผลที่ระบบรู้จัก
+func (r InventoryReason) IsValid() bool {
+	switch r {
+	case InventoryReasonSale, InventoryReasonCancellation, InventoryReasonAdjustment, InventoryReasonReturn:
+		return true
+	}
+	return false
+}
+
+// InventoryMovement บันทึกการเปลี่ยนแปลงสต็อกหนึ่งครั้ง (ledger)
+// ผลรวมของ Delta ทั้งหมดของสินค้าหนึ่งตัวต้องเท่ากับสต็อกปัจจุบันเสมอ
+type InventoryMovement struct {
+	ID          uuid.UUID       `json:"id"`
+	ProductID   uuid.UUID       `json:"product_id"`
+	VariantID   *uuid.UUID      `json:"variant_id,omitempty"`
+	Delta       int             `json:"delta"`
+	Reason      InventoryReason `json:"reason"`
+	ReferenceID *uuid.UUID      `json:"reference_id,omitempty"`
+	ActorID     *uuid.UUID      `json:"actor_id,omitempty"`
+	Actor       *User           `json:"actor,omitempty"`
+	Note        string          `json:"note"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// AdjustStockRequest คำขอปรับสต็อกโดยผู้ดูแลระบบ
+// Reason ใช้ได้เฉพาะ adjustment และ return เพราะ sale/cancellation เกิดจากคำสั่งซื้อเท่านั้น
+// สินค้าที่มี variant ต้องระบุ VariantID เพราะสต็อกของสินค้าหลักคือผลรวมของ variant
+type AdjustStockRequest struct {
+	VariantID   *uuid.UUID      `json:"variant_id"`
+	Delta       int             `json:"delta" validate:"required"`
+	Reason      InventoryReason `json:"reason"`
+	ReferenceID *uuid.UUID      `json:"reference_id"`
+	Note        string          `json:"note"`
+}
+
+// StockRebuildResult ผลลัพธ์จากการคำนวณสต็อกใหม่จาก ledger
+type StockRebuildResult struct {
+	ProductID     uuid.UUID `json:"product_id"`
+	PreviousStock int       `json:"previous_stock"`
+	Stock         int       `json:"stock"`
+	Movements     int       `json:"movements"`
+}
+
+// DefaultReorderThreshold จุดสั่งซื้อเพิ่มที่ใช้เมื่อทั้งสินค้าและหมวดหมู่ไม่ได้กำหนดไว้
+// ลำดับการเลือกใช้คือ Product.ReorderThreshold → Category.ReorderThreshold → ค่านี้
+const DefaultReorderThreshold = 10
+
+// LowStockProduct สินค้าที่สต็อกเหลือไม่เกินจุดสั่งซื้อเพิ่ม
+type LowStockProduct struct {
+	ProductID  uuid.UUID `json:"product_id"`
+	Name       string    `json:"name"`
+	CategoryID uuid.UUID `json:"category_id"`
+	Stock      int       `json:"stock"`
+	Threshold  int       `json:"threshold"`
+}
+
+// LowStockFilter เงื่อนไขการค้นหาสินค้าที่สต็อกต่ำ
+// ถ้า Limit เป็น 0 จะคืนผลลัพธ์ทั้งหมดโดยไม่แบ่งหน้า
+type LowStockFilter struct {
+	ProductIDs []uuid.UUID
+	CategoryID uuid.UUID
+	Page       int
+	Limit      int
+}
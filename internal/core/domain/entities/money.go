@@ -0,0 +1,145 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency สกุลเงินหลักของร้าน ใช้เมื่อข้อมูลไม่ได้ระบุสกุลเงิน
+const DefaultCurrency = "THB"
+
+var (
+	// ErrCurrencyMismatch เกิดเมื่อนำเงินต่างสกุลมาคำนวณด้วยกันโดยไม่ได้แปลงสกุลเงินก่อน
+	ErrCurrencyMismatch = errors.New("สกุลเงินไม่ตรงกัน")
+	// ErrUnsupportedCurrency เกิดเมื่อสกุลเงินที่ขอไม่มีอัตราแลกเปลี่ยนในระบบ
+	ErrUnsupportedCurrency = errors.New("ไม่รองรับสกุลเงินนี้")
+)
+
+// Money จำนวนเงินในหน่วยย่อยที่สุดของสกุลเงิน (เช่น สตางค์ หรือเซนต์) พร้อมรหัสสกุลเงิน ISO 4217
+// ใช้จำนวนเต็มเพื่อให้การรวมยอดไม่มี error สะสมแบบ float64
+// ตัวอย่าง: 125.50 บาท คือ Money{Amount: 12550, Currency: "THB"}
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// NewMoney สร้าง Money จากจำนวนเงินในหน่วยย่อย ถ้าไม่ระบุสกุลเงินจะใช้ DefaultCurrency
+func NewMoney(amount int64, currency string) Money {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return Money{Amount: amount, Currency: currency}
+}
+
+// ParseMoney แปลงจำนวนเงินแบบทศนิยม เช่น "125.50" เป็น Money โดยไม่ผ่าน float
+// ทศนิยมต้องไม่เกินจำนวนหลักของหน่วยย่อยของสกุลเงินนั้น
+func ParseMoney(value, currency string) (Money, error) {
+	money := NewMoney(0, currency)
+	exponent := CurrencyExponent(money.Currency)
+
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" || len(fraction) > exponent || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, fmt.Errorf("จำนวนเงิน %q ไม่ถูกต้อง", value)
+	}
+	fraction += strings.Repeat("0", exponent-len(fraction))
+
+	amount, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("จำนวนเงิน %q ไม่ถูกต้อง", value)
+	}
+	if negative {
+		amount = -amount
+	}
+
+	money.Amount = amount
+	return money, nil
+}
+
+// isDigits ตรวจว่าข้อความมีแต่ตัวเลข 0-9 เพื่อไม่ให้เครื่องหมายหรืออักขระอื่นหลุดไปถึง strconv
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// CurrencyExponent คืนจำนวนหลักทศนิยมของหน่วยย่อยของสกุลเงิน
+func CurrencyExponent(currency string) int {
+	switch currency {
+	case "JPY", "KRW", "VND":
+		return 0
+	}
+	return 2
+}
+
+// Add รวมเงินสองจำนวนที่เป็นสกุลเดียวกัน Money ที่เป็นศูนย์และไม่มีสกุลเงินใช้เป็นค่าเริ่มต้นของการรวมยอดได้
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency == "" {
+		m.Currency = other.Currency
+	}
+	if other.Currency != "" && other.Currency != m.Currency {
+		return Money{}, fmt.Errorf("%w: %s กับ %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	m.Amount += other.Amount
+	return m, nil
+}
+
+// Sub ลบเงินสกุลเดียวกัน
+func (m Money) Sub(other Money) (Money, error) {
+	other.Amount = -other.Amount
+	return m.Add(other)
+}
+
+// Mul คูณจำนวนเงินด้วยจำนวนชิ้น
+func (m Money) Mul(quantity int) Money {
+	m.Amount *= int64(quantity)
+	return m
+}
+
+// Convert แปลงเป็นสกุลเงินอื่นด้วยอัตรา rate (จำนวนหน่วยหลักของสกุลปลายทางต่อ 1 หน่วยหลักของสกุลต้นทาง)
+// ปัดเศษหน่วยย่อยแบบครึ่งหนึ่งปัดขึ้น
+func (m Money) Convert(currency string, rate float64) Money {
+	shift := math.Pow10(CurrencyExponent(currency) - CurrencyExponent(m.Currency))
+	return NewMoney(int64(math.Round(float64(m.Amount)*rate*shift)), currency)
+}
+
+// IsZero ตรวจสอบว่าจำนวนเงินเป็นศูนย์
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsPositive ตรวจสอบว่าจำนวนเงินมากกว่าศูนย์
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// Decimal คืนจำนวนเงินเป็นทศนิยมตามจำนวนหลักของสกุลเงิน เช่น "125.50"
+func (m Money) Decimal() string {
+	exponent := CurrencyExponent(m.Currency)
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	if exponent == 0 {
+		return sign + strconv.FormatInt(amount, 10)
+	}
+
+	digits := fmt.Sprintf("%0*d", exponent+1, amount)
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+// String คืนจำนวนเงินพร้อมสกุลเงิน เช่น "125.50 THB"
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
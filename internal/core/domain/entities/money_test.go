@@ -0,0 +1,36 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+func TestParseMoney(t *testing.T) {
+	valid := map[string]int64{
+		"125.50": 12550,
+		"125.5":  12550,
+		"125":    12500,
+		" 0.01 ": 1,
+		"-5":     -500,
+		"-5.25":  -525,
+	}
+	for value, want := range valid {
+		money, err := entities.ParseMoney(value, "THB")
+		if err != nil {
+			t.Errorf("ParseMoney(%q): unexpected error %v", value, err)
+			continue
+		}
+		if money.Amount != want || money.Currency != "THB" {
+			t.Errorf("ParseMoney(%q) = %+v, want %d THB", value, money, want)
+		}
+	}
+
+	// เครื่องหมายซ้ำหรืออักขระที่ไม่ใช่ตัวเลขต้องถูกปฏิเสธ ไม่ใช่ถูกตีความเป็นจำนวนอื่น
+	invalid := []string{"--5", "-+5", "+5", "5.-1", "5.+1", "1e3", ".5", "", "-", "1,000", "12.345"}
+	for _, value := range invalid {
+		if money, err := entities.ParseMoney(value, "THB"); err == nil {
+			t.Errorf("ParseMoney(%q) = %+v, want error", value, money)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package entities
+
+// ตารางการเปลี่ยนสถานะที่อนุญาตของแต่ละแกน
+// key คือสถานะปัจจุบัน value คือสถานะถัดไปที่เปลี่ยนไปได้
+// สถานะที่ไม่มีปลายทาง (เช่น cancelled) ถือเป็นสถานะสุดท้าย
+
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:    {OrderStatusConfirmed, OrderStatusCancelled},
+	OrderStatusConfirmed:  {OrderStatusProcessing, OrderStatusCancelled},
+	OrderStatusProcessing: {OrderStatusCompleted, OrderStatusCancelled},
+	OrderStatusCompleted:  {},
+	OrderStatusCancelled:  {},
+}
+
+var paymentStatusTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentStatusPending:           {PaymentStatusPaid, PaymentStatusFailed, PaymentStatusCancelled},
+	PaymentStatusFailed:            {PaymentStatusPending, PaymentStatusPaid, PaymentStatusCancelled},
+	PaymentStatusPaid:              {PaymentStatusRefunded, PaymentStatusPartiallyRefunded},
+	PaymentStatusPartiallyRefunded: {PaymentStatusRefunded},
+	PaymentStatusCancelled:         {},
+	PaymentStatusRefunded:          {},
+}
+
+var shippingStatusTransitions = map[ShippingStatus][]ShippingStatus{
+	ShippingStatusPending:   {ShippingStatusShipped},
+	ShippingStatusShipped:   {ShippingStatusInTransit, ShippingStatusDelivered, ShippingStatusReturned},
+	ShippingStatusInTransit: {ShippingStatusDelivered, ShippingStatusReturned},
+	ShippingStatusDelivered: {ShippingStatusReturned},
+	ShippingStatusReturned:  {},
+}
+
+// CanTransition ตรวจสอบว่า to อยู่ในรายการปลายทางของ from หรือไม่
+func CanTransition[T comparable](table map[T][]T, from, to T) bool {
+	for _, next := range table[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionTo ตรวจสอบเฉพาะตารางการเปลี่ยนสถานะการจัดส่ง ไม่ตรวจสถานะแกนอื่นของคำสั่งซื้อ
+// ใช้กับสถานะของพัสดุแต่ละกล่อง
+func (s ShippingStatus) CanTransitionTo(to ShippingStatus) bool {
+	return CanTransition(shippingStatusTransitions, s, to)
+}
+
+// CheckOrderStatusTransition ตรวจสอบการเปลี่ยนสถานะหลักของคำสั่งซื้อ
+// รวมถึงความสอดคล้องกับสถานะการชำระเงินและการจัดส่ง
+func CheckOrderStatusTransition(order *Order, to OrderStatus) error {
+	fail := func(reason string) error {
+		return &StatusTransitionError{
+			Axis:   StatusAxisOrder,
+			From:   string(order.Status),
+			To:     string(to),
+			Reason: reason,
+		}
+	}
+
+	if !to.IsValid() {
+		return fail("ไม่รู้จักสถานะนี้")
+	}
+	if !CanTransition(orderStatusTransitions, order.Status, to) {
+		return fail("ไม่อนุญาตให้เปลี่ยนสถานะนี้")
+	}
+
+	switch to {
+	case OrderStatusProcessing:
+		if order.PaymentStatus != PaymentStatusPaid {
+			return fail("ต้องชำระเงินก่อนจึงจะดำเนินการคำสั่งซื้อได้")
+		}
+	case OrderStatusCompleted:
+		if order.ShippingStatus != ShippingStatusDelivered {
+			return fail("ต้องจัดส่งสำเร็จก่อนจึงจะปิดคำสั่งซื้อได้")
+		}
+	case OrderStatusCancelled:
+		if order.ShippingStatus != ShippingStatusPending {
+			return fail("ไม่สามารถยกเลิกคำสั่งซื้อที่จัดส่งแล้วได้")
+		}
+		// เงินที่ชำระแล้วต้องคืนผ่านการคืนเงินให้ครบก่อน การยกเลิกไม่ได้คืนเงินให้ลูกค้า
+		if order.PaymentStatus == PaymentStatusPaid || order.PaymentStatus == PaymentStatusPartiallyRefunded {
+			return fail("ต้องคืนเงินเต็มจำนวนก่อนจึงจะยกเลิกคำสั่งซื้อที่ชำระเงินแล้วได้")
+		}
+	}
+
+	return nil
+}
+
+// CheckPaymentStatusTransition ตรวจสอบการเปลี่ยนสถานะการชำระเงิน
+func CheckPaymentStatusTransition(order *Order, to PaymentStatus) error {
+	fail := func(reason string) error {
+		return &StatusTransitionError{
+			Axis:   StatusAxisPayment,
+			From:   string(order.PaymentStatus),
+			To:     string(to),
+			Reason: reason,
+		}
+	}
+
+	if !to.IsValid() {
+		return fail("ไม่รู้จักสถานะนี้")
+	}
+	if !CanTransition(paymentStatusTransitions, order.PaymentStatus, to) {
+		return fail("ไม่อนุญาตให้เปลี่ยนสถานะนี้")
+	}
+
+	switch to {
+	case PaymentStatusPaid, PaymentStatusPending:
+		if order.Status == OrderStatusCancelled {
+			return fail("คำสั่งซื้อถูกยกเลิกแล้ว")
+		}
+	}
+
+	return nil
+}
+
+// CheckManualPaymentStatusTransition ตรวจสอบการเปลี่ยนสถานะการชำระเงินที่ผู้ดูแลระบบสั่งเปลี่ยนเอง
+// สถานะ refunded และ partially_refunded ต้องมาจากการคืนเงินที่มีบันทึกการคืนเงินเท่านั้น
+func CheckManualPaymentStatusTransition(order *Order, to PaymentStatus) error {
+	if to.IsRefund() {
+		return &StatusTransitionError{
+			Axis:   StatusAxisPayment,
+			From:   string(order.PaymentStatus),
+			To:     string(to),
+			Reason: "ต้องคืนเงินผ่านการคืนเงินของธุรกรรม",
+		}
+	}
+	return CheckPaymentStatusTransition(order, to)
+}
+
+// CheckShippingStatusTransition ตรวจสอบการเปลี่ยนสถานะการจัดส่ง
+// การจัดส่งจะเริ่มได้ก็ต่อเมื่อชำระเงินแล้วและคำสั่งซื้อได้รับการยืนยันแล้วเท่านั้น
+func CheckShippingStatusTransition(order *Order, to ShippingStatus) error {
+	fail := func(reason string) error {
+		return &StatusTransitionError{
+			Axis:   StatusAxisShipping,
+			From:   string(order.ShippingStatus),
+			To:     string(to),
+			Reason: reason,
+		}
+	}
+
+	if !to.IsValid() {
+		return fail("ไม่รู้จักสถานะนี้")
+	}
+	if !CanTransition(shippingStatusTransitions, order.ShippingStatus, to) {
+		return fail("ไม่อนุญาตให้เปลี่ยนสถานะนี้")
+	}
+
+	if to == ShippingStatusShipped {
+		if order.PaymentStatus != PaymentStatusPaid {
+			return fail("ต้องชำระเงินก่อนจึงจะจัดส่งได้")
+		}
+		if order.Status != OrderStatusConfirmed && order.Status != OrderStatusProcessing {
+			return fail("คำสั่งซื้อต้องได้รับการยืนยันก่อนจึงจะจัดส่งได้")
+		}
+	}
+
+	return nil
+}
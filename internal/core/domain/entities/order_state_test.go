@@ -0,0 +1,336 @@
+package entities_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+var (
+	allOrderStatuses = []entities.OrderStatus{
+		entities.OrderStatusPending,
+		entities.OrderStatusConfirmed,
+		entities.OrderStatusProcessing,
+		entities.OrderStatusCompleted,
+		entities.OrderStatusCancelled,
+	}
+	allPaymentStatuses = []entities.PaymentStatus{
+		entities.PaymentStatusPending,
+		entities.PaymentStatusPaid,
+		entities.PaymentStatusFailed,
+		entities.PaymentStatusCancelled,
+		entities.PaymentStatusRefunded,
+		entities.PaymentStatusPartiallyRefunded,
+	}
+	allShippingStatuses = []entities.ShippingStatus{
+		entities.ShippingStatusPending,
+		entities.ShippingStatusShipped,
+		entities.ShippingStatusInTransit,
+		entities.ShippingStatusDelivered,
+		entities.ShippingStatusReturned,
+	}
+)
+
+// assertTransitionError ตรวจสอบว่าได้ StatusTransitionError ของแกนที่ถูกต้องเมื่อคาดว่าจะไม่ผ่าน
+func assertTransitionError(t *testing.T, err error, wantErr bool, axis entities.StatusAxis) {
+	t.Helper()
+
+	if !wantErr {
+		if err != nil {
+			t.Fatalf("expected transition to be allowed, got %v", err)
+		}
+		return
+	}
+
+	var transitionErr *entities.StatusTransitionError
+	if !errors.As(err, &transitionErr) {
+		t.Fatalf("expected *entities.StatusTransitionError, got %v", err)
+	}
+	if transitionErr.Axis != axis {
+		t.Fatalf("expected axis %q, got %q", axis, transitionErr.Axis)
+	}
+}
+
+func TestCheckOrderStatusTransition_Table(t *testing.T) {
+	allowed := map[[2]entities.OrderStatus]bool{
+		{entities.OrderStatusPending, entities.OrderStatusConfirmed}:    true,
+		{entities.OrderStatusPending, entities.OrderStatusCancelled}:    true,
+		{entities.OrderStatusConfirmed, entities.OrderStatusProcessing}: true,
+		{entities.OrderStatusConfirmed, entities.OrderStatusCancelled}:  true,
+		{entities.OrderStatusProcessing, entities.OrderStatusCompleted}: true,
+		{entities.OrderStatusProcessing, entities.OrderStatusCancelled}: true,
+	}
+
+	for _, from := range allOrderStatuses {
+		for _, to := range allOrderStatuses {
+			t.Run(string(from)+"->"+string(to), func(t *testing.T) {
+				// ตั้งค่าแกนอื่นให้ผ่านเงื่อนไขข้ามแกน เพื่อทดสอบเฉพาะตารางการเปลี่ยนสถานะ
+				order := &entities.Order{
+					Status:         from,
+					PaymentStatus:  entities.PaymentStatusPaid,
+					ShippingStatus: entities.ShippingStatusPending,
+				}
+				if to == entities.OrderStatusCompleted {
+					order.ShippingStatus = entities.ShippingStatusDelivered
+				}
+				if to == entities.OrderStatusCancelled {
+					order.PaymentStatus = entities.PaymentStatusRefunded
+				}
+
+				err := entities.CheckOrderStatusTransition(order, to)
+				assertTransitionError(t, err, !allowed[[2]entities.OrderStatus{from, to}], entities.StatusAxisOrder)
+			})
+		}
+	}
+}
+
+func TestCheckPaymentStatusTransition_Table(t *testing.T) {
+	allowed := map[[2]entities.PaymentStatus]bool{
+		{entities.PaymentStatusPending, entities.PaymentStatusPaid}:               true,
+		{entities.PaymentStatusPending, entities.PaymentStatusFailed}:             true,
+		{entities.PaymentStatusPending, entities.PaymentStatusCancelled}:          true,
+		{entities.PaymentStatusFailed, entities.PaymentStatusPending}:             true,
+		{entities.PaymentStatusFailed, entities.PaymentStatusPaid}:                true,
+		{entities.PaymentStatusFailed, entities.PaymentStatusCancelled}:           true,
+		{entities.PaymentStatusPaid, entities.PaymentStatusRefunded}:              true,
+		{entities.PaymentStatusPaid, entities.PaymentStatusPartiallyRefunded}:     true,
+		{entities.PaymentStatusPartiallyRefunded, entities.PaymentStatusRefunded}: true,
+	}
+
+	for _, from := range allPaymentStatuses {
+		for _, to := range allPaymentStatuses {
+			t.Run(string(from)+"->"+string(to), func(t *testing.T) {
+				order := &entities.Order{
+					Status:         entities.OrderStatusPending,
+					PaymentStatus:  from,
+					ShippingStatus: entities.ShippingStatusPending,
+				}
+
+				err := entities.CheckPaymentStatusTransition(order, to)
+				assertTransitionError(t, err, !allowed[[2]entities.PaymentStatus{from, to}], entities.StatusAxisPayment)
+			})
+		}
+	}
+}
+
+func TestCheckShippingStatusTransition_Table(t *testing.T) {
+	allowed := map[[2]entities.ShippingStatus]bool{
+		{entities.ShippingStatusPending, entities.ShippingStatusShipped}:     true,
+		{entities.ShippingStatusShipped, entities.ShippingStatusInTransit}:   true,
+		{entities.ShippingStatusShipped, entities.ShippingStatusDelivered}:   true,
+		{entities.ShippingStatusShipped, entities.ShippingStatusReturned}:    true,
+		{entities.ShippingStatusInTransit, entities.ShippingStatusDelivered}: true,
+		{entities.ShippingStatusInTransit, entities.ShippingStatusReturned}:  true,
+		{entities.ShippingStatusDelivered, entities.ShippingStatusReturned}:  true,
+	}
+
+	for _, from := range allShippingStatuses {
+		for _, to := range allShippingStatuses {
+			t.Run(string(from)+"->"+string(to), func(t *testing.T) {
+				order := &entities.Order{
+					Status:         entities.OrderStatusProcessing,
+					PaymentStatus:  entities.PaymentStatusPaid,
+					ShippingStatus: from,
+				}
+
+				err := entities.CheckShippingStatusTransition(order, to)
+				assertTransitionError(t, err, !allowed[[2]entities.ShippingStatus{from, to}], entities.StatusAxisShipping)
+			})
+		}
+	}
+}
+
+func TestCheckOrderStatusTransition_CrossAxis(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   entities.Order
+		to      entities.OrderStatus
+		wantErr bool
+	}{
+		{
+			name:    "processing requires paid",
+			order:   entities.Order{Status: entities.OrderStatusConfirmed, PaymentStatus: entities.PaymentStatusPending, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.OrderStatusProcessing,
+			wantErr: true,
+		},
+		{
+			name:    "processing after paid",
+			order:   entities.Order{Status: entities.OrderStatusConfirmed, PaymentStatus: entities.PaymentStatusPaid, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.OrderStatusProcessing,
+			wantErr: false,
+		},
+		{
+			name:    "completed requires delivered",
+			order:   entities.Order{Status: entities.OrderStatusProcessing, PaymentStatus: entities.PaymentStatusPaid, ShippingStatus: entities.ShippingStatusInTransit},
+			to:      entities.OrderStatusCompleted,
+			wantErr: true,
+		},
+		{
+			name:    "cannot cancel after shipping started",
+			order:   entities.Order{Status: entities.OrderStatusProcessing, PaymentStatus: entities.PaymentStatusPaid, ShippingStatus: entities.ShippingStatusShipped},
+			to:      entities.OrderStatusCancelled,
+			wantErr: true,
+		},
+		{
+			name:    "cannot cancel a paid order",
+			order:   entities.Order{Status: entities.OrderStatusPending, PaymentStatus: entities.PaymentStatusPaid, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.OrderStatusCancelled,
+			wantErr: true,
+		},
+		{
+			name:    "cannot cancel a partially refunded order",
+			order:   entities.Order{Status: entities.OrderStatusProcessing, PaymentStatus: entities.PaymentStatusPartiallyRefunded, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.OrderStatusCancelled,
+			wantErr: true,
+		},
+		{
+			name:    "cancel after a full refund",
+			order:   entities.Order{Status: entities.OrderStatusConfirmed, PaymentStatus: entities.PaymentStatusRefunded, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.OrderStatusCancelled,
+			wantErr: false,
+		},
+		{
+			name:    "cancel an unpaid order",
+			order:   entities.Order{Status: entities.OrderStatusConfirmed, PaymentStatus: entities.PaymentStatusFailed, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.OrderStatusCancelled,
+			wantErr: false,
+		},
+		{
+			name:    "confirm does not require payment",
+			order:   entities.Order{Status: entities.OrderStatusPending, PaymentStatus: entities.PaymentStatusPending, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.OrderStatusConfirmed,
+			wantErr: false,
+		},
+		{
+			name:    "unknown status",
+			order:   entities.Order{Status: entities.OrderStatusPending, PaymentStatus: entities.PaymentStatusPending, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.OrderStatus("delivered"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := entities.CheckOrderStatusTransition(&tt.order, tt.to)
+			assertTransitionError(t, err, tt.wantErr, entities.StatusAxisOrder)
+		})
+	}
+}
+
+func TestCheckPaymentStatusTransition_CrossAxis(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   entities.Order
+		to      entities.PaymentStatus
+		wantErr bool
+	}{
+		{
+			name:    "cannot pay a cancelled order",
+			order:   entities.Order{Status: entities.OrderStatusCancelled, PaymentStatus: entities.PaymentStatusPending, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.PaymentStatusPaid,
+			wantErr: true,
+		},
+		{
+			name:    "cannot retry payment on a cancelled order",
+			order:   entities.Order{Status: entities.OrderStatusCancelled, PaymentStatus: entities.PaymentStatusFailed, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.PaymentStatusPending,
+			wantErr: true,
+		},
+		{
+			name:    "refund a cancelled order",
+			order:   entities.Order{Status: entities.OrderStatusCancelled, PaymentStatus: entities.PaymentStatusPaid, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.PaymentStatusRefunded,
+			wantErr: false,
+		},
+		{
+			name:    "cancel payment of a cancelled order",
+			order:   entities.Order{Status: entities.OrderStatusCancelled, PaymentStatus: entities.PaymentStatusPending, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.PaymentStatusCancelled,
+			wantErr: false,
+		},
+		{
+			name:    "unknown status",
+			order:   entities.Order{Status: entities.OrderStatusPending, PaymentStatus: entities.PaymentStatusPending, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.PaymentStatus("completed"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := entities.CheckPaymentStatusTransition(&tt.order, tt.to)
+			assertTransitionError(t, err, tt.wantErr, entities.StatusAxisPayment)
+		})
+	}
+}
+
+func TestCheckManualPaymentStatusTransition_RefundsOnlyThroughRefundFlow(t *testing.T) {
+	paid := entities.Order{Status: entities.OrderStatusConfirmed, PaymentStatus: entities.PaymentStatusPaid, ShippingStatus: entities.ShippingStatusPending}
+
+	for _, to := range []entities.PaymentStatus{entities.PaymentStatusRefunded, entities.PaymentStatusPartiallyRefunded} {
+		t.Run(string(to), func(t *testing.T) {
+			// ตารางการเปลี่ยนสถานะอนุญาต แต่ผู้ดูแลระบบเปลี่ยนเองโดยไม่มีบันทึกการคืนเงินไม่ได้
+			if err := entities.CheckPaymentStatusTransition(&paid, to); err != nil {
+				t.Fatalf("expected the refund flow to be allowed, got %v", err)
+			}
+			assertTransitionError(t, entities.CheckManualPaymentStatusTransition(&paid, to), true, entities.StatusAxisPayment)
+		})
+	}
+
+	pending := entities.Order{Status: entities.OrderStatusPending, PaymentStatus: entities.PaymentStatusPending, ShippingStatus: entities.ShippingStatusPending}
+	assertTransitionError(t, entities.CheckManualPaymentStatusTransition(&pending, entities.PaymentStatusPaid), false, entities.StatusAxisPayment)
+}
+
+func TestCheckShippingStatusTransition_CrossAxis(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   entities.Order
+		to      entities.ShippingStatus
+		wantErr bool
+	}{
+		{
+			name:    "cannot ship before payment",
+			order:   entities.Order{Status: entities.OrderStatusConfirmed, PaymentStatus: entities.PaymentStatusPending, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.ShippingStatusShipped,
+			wantErr: true,
+		},
+		{
+			name:    "cannot ship after failed payment",
+			order:   entities.Order{Status: entities.OrderStatusConfirmed, PaymentStatus: entities.PaymentStatusFailed, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.ShippingStatusShipped,
+			wantErr: true,
+		},
+		{
+			name:    "cannot ship an unconfirmed order",
+			order:   entities.Order{Status: entities.OrderStatusPending, PaymentStatus: entities.PaymentStatusPaid, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.ShippingStatusShipped,
+			wantErr: true,
+		},
+		{
+			name:    "cannot ship a cancelled order",
+			order:   entities.Order{Status: entities.OrderStatusCancelled, PaymentStatus: entities.PaymentStatusPaid, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.ShippingStatusShipped,
+			wantErr: true,
+		},
+		{
+			name:    "ship a confirmed and paid order",
+			order:   entities.Order{Status: entities.OrderStatusConfirmed, PaymentStatus: entities.PaymentStatusPaid, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.ShippingStatusShipped,
+			wantErr: false,
+		},
+		{
+			name:    "unknown status",
+			order:   entities.Order{Status: entities.OrderStatusProcessing, PaymentStatus: entities.PaymentStatusPaid, ShippingStatus: entities.ShippingStatusPending},
+			to:      entities.ShippingStatus("lost"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := entities.CheckShippingStatusTransition(&tt.order, tt.to)
+			assertTransitionError(t, err, tt.wantErr, entities.StatusAxisShipping)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package entities
+
+import "fmt"
+
+// OrderStatus สถานะหลักของคำสั่งซื้อ
+type OrderStatus string
+
+const (
+	OrderStatusPending    OrderStatus = "pending"
+	OrderStatusConfirmed  OrderStatus = "confirmed"
+	OrderStatusProcessing OrderStatus = "processing"
+	OrderStatusCompleted  OrderStatus = "completed"
+	OrderStatusCancelled  OrderStatus = "cancelled"
+)
+
+// IsValid ตรวจสอบว่าเป็นสถานะที่ระบบรู้จัก
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderStatusPending, OrderStatusConfirmed, OrderStatusProcessing, OrderStatusCompleted, OrderStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// PaymentStatus สถานะการชำระเงินของคำสั่งซื้อ
+type PaymentStatus string
+
+const (
+	PaymentStatusPending   PaymentStatus = "pending"
+	PaymentStatusPaid      PaymentStatus = "paid"
+	PaymentStatusFailed    PaymentStatus = "failed"
+	PaymentStatusCancelled PaymentStatus = "cancelled"
+	PaymentStatusRefunded  PaymentStatus = "refunded"
+
+	PaymentStatusPartiallyRefunded PaymentStatus = "partially_refunded"
+)
+
+// IsValid ตรวจสอบว่าเป็นสถานะที่ระบบรู้จัก
+func (s PaymentStatus) IsValid() bool {
+	switch s {
+	case PaymentStatusPending, PaymentStatusPaid, PaymentStatusFailed, PaymentStatusCancelled, PaymentStatusRefunded, PaymentStatusPartiallyRefunded:
+		return true
+	}
+	return false
+}
+
+// IsRefund ตรวจสอบว่าเป็นสถานะที่เกิดจากการคืนเงิน
+func (s PaymentStatus) IsRefund() bool {
+	return s == PaymentStatusRefunded || s == PaymentStatusPartiallyRefunded
+}
+
+// ShippingStatus สถานะการจัดส่งของคำสั่งซื้อ
+type ShippingStatus string
+
+const (
+	ShippingStatusPending   ShippingStatus = "pending"
+	ShippingStatusShipped   ShippingStatus = "shipped"
+	ShippingStatusInTransit ShippingStatus = "in_transit"
+	ShippingStatusDelivered ShippingStatus = "delivered"
+	ShippingStatusReturned  ShippingStatus = "returned"
+)
+
+// IsValid ตรวจสอบว่าเป็นสถานะที่ระบบรู้จัก
+func (s ShippingStatus) IsValid() bool {
+	switch s {
+	case ShippingStatusPending, ShippingStatusShipped, ShippingStatusInTransit, ShippingStatusDelivered, ShippingStatusReturned:
+		return true
+	}
+	return false
+}
+
+// StatusAxis บอกว่าการเปลี่ยนสถานะเกิดขึ้นกับสถานะแกนไหนของคำสั่งซื้อ
+type StatusAxis string
+
+const (
+	StatusAxisOrder    StatusAxis = "status"
+	StatusAxisPayment  StatusAxis = "payment_status"
+	StatusAxisShipping StatusAxis = "shipping_status"
+)
+
+// StatusTransitionError คือ error ที่เกิดเมื่อพยายามเปลี่ยนสถานะที่ไม่อนุญาต
+type StatusTransitionError struct {
+	Axis   StatusAxis
+	From   string
+	To     string
+	Reason string
+}
+
+func (e *StatusTransitionError) Error() string {
+	return fmt.Sprintf("ไม่สามารถเปลี่ยน %s จาก %q เป็น %q ได้: %s", e.Axis, e.From, e.To, e.Reason)
+}
@@ -0,0 +1,125 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payment methods ที่ระบบรองรับ ใช้เลือก PaymentGateway ของแต่ละธุรกรรม
+const (
+	PaymentMethodCreditCard   = "credit_card"
+	PaymentMethodBankTransfer = "bank_transfer"
+	PaymentMethodPromptPay    = "promptpay"
+)
+
+// TransactionStatus สถานะของธุรกรรมการชำระเงิน
+type TransactionStatus string
+
+const (
+	TransactionStatusPending   TransactionStatus = "pending"
+	TransactionStatusCompleted TransactionStatus = "completed"
+	TransactionStatusFailed    TransactionStatus = "failed"
+	TransactionStatusCancelled TransactionStatus = "cancelled"
+)
+
+// PaymentStatus คืนสถานะการชำระเงินของคำสั่งซื้อที่สอดคล้องกับสถานะธุรกรรม
+func (s TransactionStatus) PaymentStatus() PaymentStatus {
+	switch s {
+	case TransactionStatusCompleted:
+		return PaymentStatusPaid
+	case TransactionStatusFailed:
+		return PaymentStatusFailed
+	case TransactionStatusCancelled:
+		return PaymentStatusCancelled
+	}
+	return PaymentStatusPending
+}
+
+// GatewayStatus สถานะของการชำระเงินฝั่ง payment gateway
+type GatewayStatus string
+
+const (
+	GatewayStatusPending    GatewayStatus = "pending"
+	GatewayStatusAuthorized GatewayStatus = "authorized"
+	GatewayStatusCaptured   GatewayStatus = "captured"
+	GatewayStatusFailed     GatewayStatus = "failed"
+	GatewayStatusCancelled  GatewayStatus = "cancelled"
+	GatewayStatusRefunded   GatewayStatus = "refunded"
+)
+
+// PaymentIntentRequest ข้อมูลที่ส่งให้ gateway เพื่อเริ่มการชำระเงิน
+// Reference คือ TransactionID ของระบบเรา
+type PaymentIntentRequest struct {
+	Reference   string
+	Amount      Money
+	Description string
+}
+
+// PaymentIntent ผลลัพธ์จากการเริ่มการชำระเงินที่ gateway
+// Payload คือข้อมูลที่ผู้ซื้อใช้ชำระเงิน เช่น PromptPay QR payload
+type PaymentIntent struct {
+	ProviderRef string
+	Status      GatewayStatus
+	Payload     string
+	ExpiresAt   *time.Time
+}
+
+// PaymentResult สถานะล่าสุดของการชำระเงินที่ gateway
+type PaymentResult struct {
+	ProviderRef string
+	Status      GatewayStatus
+	Amount      Money
+}
+
+// PaymentWebhookEvent เนื้อหาของ webhook ที่ payment gateway ส่งมาแจ้งผลการชำระเงิน
+// Reference คือ TransactionID ของระบบเราที่ส่งให้ gateway ตอนสร้าง intent
+type PaymentWebhookEvent struct {
+	EventID    string        `json:"event_id" validate:"required"`
+	Reference  string        `json:"reference" validate:"required"`
+	Status     GatewayStatus `json:"status" validate:"required"`
+	Amount     Money         `json:"amount"`
+	OccurredAt time.Time     `json:"occurred_at"`
+}
+
+// PaymentWebhookProvider ผู้ให้บริการที่ส่ง webhook มาที่ /api/webhooks/payments/{provider}
+// Secret ใช้ตรวจลายเซ็น ส่วน PaymentMethods คือช่องทางการชำระเงินที่ผู้ให้บริการนี้แจ้งผลได้
+type PaymentWebhookProvider struct {
+	Secret         string
+	PaymentMethods []string
+}
+
+// Handles ตรวจสอบว่าผู้ให้บริการนี้เป็นผู้ดูแลช่องทางการชำระเงิน paymentMethod หรือไม่
+func (p PaymentWebhookProvider) Handles(paymentMethod string) bool {
+	for _, method := range p.PaymentMethods {
+		if method == paymentMethod {
+			return true
+		}
+	}
+	return false
+}
+
+// PaymentEvent webhook event ที่บันทึกไว้คู่กับการเปลี่ยนสถานะธุรกรรม
+// provider กับ EventID คู่เดียวกันจะถูกประมวลผลได้เพียงครั้งเดียว
+type PaymentEvent struct {
+	Provider      string
+	EventID       string
+	TransactionID uuid.UUID
+	Status        GatewayStatus
+	Payload       string
+}
+
+// Payment errors
+var (
+	ErrUnsupportedPaymentMethod = errors.New("ไม่รองรับช่องทางการชำระเงินนี้")
+	ErrPaymentPending           = errors.New("การชำระเงินยังไม่เสร็จสมบูรณ์")
+	ErrPaymentNotFound          = errors.New("ไม่พบรายการชำระเงิน")
+	ErrTransactionFinalized     = errors.New("ธุรกรรมนี้ไม่ได้อยู่ในสถานะรอชำระเงินแล้ว")
+
+	ErrUnknownWebhookProvider  = errors.New("ไม่รู้จักผู้ให้บริการชำระเงินนี้")
+	ErrInvalidWebhookSignature = errors.New("ลายเซ็นของ webhook ไม่ถูกต้อง")
+	ErrWebhookProviderMismatch = errors.New("ผู้ให้บริการนี้ไม่ได้ดูแลช่องทางการชำระเงินของธุรกรรมนี้")
+	ErrInvalidWebhookPayload   = errors.New("ข้อมูลของ webhook ไม่ถูกต้อง")
+	ErrDuplicatePaymentEvent   = errors.New("เคยประมวลผล webhook event นี้แล้ว")
+)
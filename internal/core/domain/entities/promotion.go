@@ -0,0 +1,210 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPromotionNotFound = errors.New("ไม่พบคูปองส่วนลด")
+	// ErrPromotionNotApplicable เกิดเมื่อคูปองมีอยู่จริงแต่ใช้กับตะกร้านี้ไม่ได้ สาเหตุจะถูกต่อท้ายไว้ใน error
+	ErrPromotionNotApplicable = errors.New("ไม่สามารถใช้คูปองนี้ได้")
+	ErrInvalidPromotion       = errors.New("ข้อมูลโปรโมชันไม่ถูกต้อง")
+	ErrPromotionCodeExists    = errors.New("รหัสคูปองนี้ถูกใช้แล้ว")
+)
+
+// PromotionType ประเภทของส่วนลด
+type PromotionType string
+
+const (
+	PromotionTypePercentage   PromotionType = "percentage"
+	PromotionTypeFixed        PromotionType = "fixed"
+	PromotionTypeFreeShipping PromotionType = "free_shipping"
+)
+
+// IsValid ตรวจสอบว่าเป็นประเภทส่วนลดที่รองรับ
+func (t PromotionType) IsValid() bool {
+	switch t {
+	case PromotionTypePercentage, PromotionTypeFixed, PromotionTypeFreeShipping:
+		return true
+	}
+	return false
+}
+
+// Promotion โปรโมชันที่ใช้ผ่านรหัสคูปอง
+// Rate ใช้กับส่วนลดแบบ percentage เป็น basis point (1000 คือ 10%) ส่วน Amount ใช้กับส่วนลดแบบ fixed
+// ถ้ากำหนด ProductIDs หรือ CategoryIDs ส่วนลดจะคิดเฉพาะสินค้าที่ตรงกับรายการเหล่านั้น
+type Promotion struct {
+	ID           uuid.UUID     `json:"id"`
+	Code         string        `json:"code"`
+	Name         string        `json:"name"`
+	Type         PromotionType `json:"type"`
+	Rate         int           `json:"rate"`
+	Amount       Money         `json:"amount"`
+	MinSpend     Money         `json:"min_spend"`
+	UsageLimit   *int          `json:"usage_limit,omitempty"`
+	PerUserLimit *int          `json:"per_user_limit,omitempty"`
+	StartsAt     *time.Time    `json:"starts_at,omitempty"`
+	EndsAt       *time.Time    `json:"ends_at,omitempty"`
+	Active       bool          `json:"active"`
+	ProductIDs   []uuid.UUID   `json:"product_ids,omitempty"`
+	CategoryIDs  []uuid.UUID   `json:"category_ids,omitempty"`
+	UsedCount    int           `json:"used_count"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+type CreatePromotionRequest struct {
+	Code         string        `json:"code" validate:"required,max=50"`
+	Name         string        `json:"name" validate:"required"`
+	Type         PromotionType `json:"type" validate:"required"`
+	Rate         int           `json:"rate" validate:"min=0,max=10000"`
+	Amount       Money         `json:"amount" validate:"min=0"`
+	MinSpend     Money         `json:"min_spend" validate:"min=0"`
+	UsageLimit   *int          `json:"usage_limit" validate:"omitempty,min=1"`
+	PerUserLimit *int          `json:"per_user_limit" validate:"omitempty,min=1"`
+	StartsAt     *time.Time    `json:"starts_at"`
+	EndsAt       *time.Time    `json:"ends_at"`
+	Active       *bool         `json:"active"`
+	ProductIDs   []uuid.UUID   `json:"product_ids"`
+	CategoryIDs  []uuid.UUID   `json:"category_ids"`
+}
+
+// UpdatePromotionRequest ฟิลด์ที่เป็น nil จะไม่ถูกแก้ไข ส่วน ProductIDs และ CategoryIDs ถ้าส่งมาจะแทนที่รายการเดิมทั้งหมด
+type UpdatePromotionRequest struct {
+	Name         string       `json:"name"`
+	Rate         *int         `json:"rate" validate:"omitempty,min=0,max=10000"`
+	Amount       *Money       `json:"amount" validate:"omitempty,min=0"`
+	MinSpend     *Money       `json:"min_spend" validate:"omitempty,min=0"`
+	UsageLimit   *int         `json:"usage_limit" validate:"omitempty,min=1"`
+	PerUserLimit *int         `json:"per_user_limit" validate:"omitempty,min=1"`
+	StartsAt     *time.Time   `json:"starts_at"`
+	EndsAt       *time.Time   `json:"ends_at"`
+	Active       *bool        `json:"active"`
+	ProductIDs   *[]uuid.UUID `json:"product_ids"`
+	CategoryIDs  *[]uuid.UUID `json:"category_ids"`
+}
+
+// ApplyCouponRequest ข้อมูลสำหรับใช้คูปองกับตะกร้า
+type ApplyCouponRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// PromotionLine สินค้าหนึ่งรายการที่ใช้คำนวณส่วนลด Amount คือราคาต่อชิ้น × จำนวน
+type PromotionLine struct {
+	ProductID  uuid.UUID
+	CategoryID uuid.UUID
+	Amount     Money
+}
+
+// PromotionResult ผลการคำนวณส่วนลด Allocations คือส่วนลดของแต่ละรายการตามลำดับของ lines ที่ส่งเข้าไป
+type PromotionResult struct {
+	Discount     Money
+	Allocations  []Money
+	FreeShipping bool
+}
+
+// OrderDiscount ส่วนลดหนึ่งบรรทัดที่บันทึกไว้กับคำสั่งซื้อ
+type OrderDiscount struct {
+	ID          uuid.UUID     `json:"id"`
+	PromotionID *uuid.UUID    `json:"promotion_id,omitempty"`
+	Code        string        `json:"code"`
+	Type        PromotionType `json:"type"`
+	Description string        `json:"description"`
+	Amount      Money         `json:"amount"`
+}
+
+// CheckAvailability ตรวจสอบสถานะ ช่วงเวลา และจำนวนครั้งที่ใช้ได้ของโปรโมชัน
+// totalUses คือจำนวนครั้งที่ถูกใช้ไปแล้วทั้งหมด userUses คือจำนวนครั้งที่ผู้ใช้คนนี้ใช้ไปแล้ว
+func (p *Promotion) CheckAvailability(now time.Time, totalUses, userUses int) error {
+	switch {
+	case !p.Active:
+		return fmt.Errorf("%w: คูปองถูกปิดใช้งาน", ErrPromotionNotApplicable)
+	case p.StartsAt != nil && now.Before(*p.StartsAt):
+		return fmt.Errorf("%w: คูปองยังไม่เริ่มใช้งาน", ErrPromotionNotApplicable)
+	case p.EndsAt != nil && !now.Before(*p.EndsAt):
+		return fmt.Errorf("%w: คูปองหมดอายุแล้ว", ErrPromotionNotApplicable)
+	case p.UsageLimit != nil && totalUses >= *p.UsageLimit:
+		return fmt.Errorf("%w: คูปองถูกใช้ครบจำนวนแล้ว", ErrPromotionNotApplicable)
+	case p.PerUserLimit != nil && userUses >= *p.PerUserLimit:
+		return fmt.Errorf("%w: คุณใช้คูปองนี้ครบจำนวนแล้ว", ErrPromotionNotApplicable)
+	}
+	return nil
+}
+
+// Apply คำนวณส่วนลดของรายการสินค้า ยอดขั้นต่ำคิดจากสินค้าที่อยู่ในขอบเขตของโปรโมชันเท่านั้น
+// ส่วนลดถูกกระจายไปยังแต่ละรายการตามสัดส่วนราคา เพื่อให้คิดภาษีจากราคาหลังหักส่วนลดได้
+func (p *Promotion) Apply(lines []PromotionLine) (*PromotionResult, error) {
+	eligible := NewMoney(0, p.MinSpend.Currency)
+	eligibleLines := make([]bool, len(lines))
+	for i, line := range lines {
+		if !p.covers(line) {
+			continue
+		}
+
+		sum, err := eligible.Add(line.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("%w: คูปองนี้ใช้ได้กับสกุลเงิน %s เท่านั้น", ErrPromotionNotApplicable, eligible.Currency)
+		}
+		eligible = sum
+		eligibleLines[i] = true
+	}
+
+	if !eligible.IsPositive() {
+		return nil, fmt.Errorf("%w: ไม่มีสินค้าที่ร่วมรายการ", ErrPromotionNotApplicable)
+	}
+	if eligible.Amount < p.MinSpend.Amount {
+		return nil, fmt.Errorf("%w: ยอดซื้อขั้นต่ำ %s", ErrPromotionNotApplicable, p.MinSpend)
+	}
+
+	result := &PromotionResult{
+		Discount:    NewMoney(0, eligible.Currency),
+		Allocations: make([]Money, len(lines)),
+	}
+	switch p.Type {
+	case PromotionTypePercentage:
+		result.Discount.Amount = divRound(eligible.Amount*int64(p.Rate), 10000)
+	case PromotionTypeFixed:
+		result.Discount.Amount = min(p.Amount.Amount, eligible.Amount)
+	case PromotionTypeFreeShipping:
+		result.FreeShipping = true
+	}
+
+	// กระจายส่วนลดตามสัดส่วน เศษที่เหลือจากการปัดลงจะไปอยู่ที่รายการสุดท้ายที่ร่วมรายการ
+	remaining := result.Discount.Amount
+	last := -1
+	for i, line := range lines {
+		result.Allocations[i] = NewMoney(0, line.Amount.Currency)
+		if !eligibleLines[i] {
+			continue
+		}
+		share := result.Discount.Amount * line.Amount.Amount / eligible.Amount
+		result.Allocations[i].Amount = share
+		remaining -= share
+		last = i
+	}
+	result.Allocations[last].Amount += remaining
+
+	return result, nil
+}
+
+// covers ตรวจสอบว่าสินค้าอยู่ในขอบเขตของโปรโมชัน ถ้าไม่กำหนดขอบเขตจะใช้ได้กับสินค้าทุกรายการ
+func (p *Promotion) covers(line PromotionLine) bool {
+	if len(p.ProductIDs) == 0 && len(p.CategoryIDs) == 0 {
+		return true
+	}
+	for _, id := range p.ProductIDs {
+		if id == line.ProductID {
+			return true
+		}
+	}
+	for _, id := range p.CategoryIDs {
+		if id == line.CategoryID {
+			return true
+		}
+	}
+	return false
+}
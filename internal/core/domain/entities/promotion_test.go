@@ -0,0 +1,115 @@
+package entities_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+func thb(amount int64) entities.Money {
+	return entities.NewMoney(amount, "THB")
+}
+
+func TestPromotionApply(t *testing.T) {
+	shirt, mug, book := uuid.New(), uuid.New(), uuid.New()
+	lines := func(amounts ...int64) []entities.PromotionLine {
+		ids := []uuid.UUID{shirt, mug, book}
+		result := make([]entities.PromotionLine, len(amounts))
+		for i, amount := range amounts {
+			result[i] = entities.PromotionLine{ProductID: ids[i], CategoryID: uuid.New(), Amount: thb(amount)}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name        string
+		promotion   entities.Promotion
+		lines       []entities.PromotionLine
+		discount    int64
+		allocations []int64
+	}{
+		{
+			name:        "percentage is allocated in proportion to line amounts",
+			promotion:   entities.Promotion{Type: entities.PromotionTypePercentage, Rate: 1000, MinSpend: thb(0)},
+			lines:       lines(10000, 20000),
+			discount:    3000,
+			allocations: []int64{1000, 2000},
+		},
+		{
+			// 101 แบ่งตามสัดส่วนได้ 50 กับ 50 เศษ 1 ไปอยู่ที่ mug ซึ่งเป็นรายการสุดท้ายที่ร่วมรายการ ไม่ใช่ book
+			name:        "remainder goes to the last eligible line",
+			promotion:   entities.Promotion{Type: entities.PromotionTypeFixed, Amount: thb(101), MinSpend: thb(0), ProductIDs: []uuid.UUID{shirt, mug}},
+			lines:       lines(10000, 10000, 10000),
+			discount:    101,
+			allocations: []int64{50, 51, 0},
+		},
+		{
+			name:        "100% coupon discounts every line in full",
+			promotion:   entities.Promotion{Type: entities.PromotionTypePercentage, Rate: 10000, MinSpend: thb(0)},
+			lines:       lines(3333, 6667),
+			discount:    10000,
+			allocations: []int64{3333, 6667},
+		},
+		{
+			name:        "fixed amount is capped at the eligible subtotal",
+			promotion:   entities.Promotion{Type: entities.PromotionTypeFixed, Amount: thb(50000), MinSpend: thb(0), ProductIDs: []uuid.UUID{mug}},
+			lines:       lines(10000, 20000),
+			discount:    20000,
+			allocations: []int64{0, 20000},
+		},
+		{
+			name:        "min spend counts eligible lines only",
+			promotion:   entities.Promotion{Type: entities.PromotionTypeFixed, Amount: thb(1000), MinSpend: thb(20000), ProductIDs: []uuid.UUID{mug}},
+			lines:       lines(10000, 20000),
+			discount:    1000,
+			allocations: []int64{0, 1000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.promotion.Apply(tt.lines)
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+
+			if result.Discount.Amount != tt.discount {
+				t.Errorf("discount = %d, want %d", result.Discount.Amount, tt.discount)
+			}
+			if len(result.Allocations) != len(tt.allocations) {
+				t.Fatalf("got %d allocations, want %d", len(result.Allocations), len(tt.allocations))
+			}
+			var sum int64
+			for i, allocation := range result.Allocations {
+				if allocation.Amount != tt.allocations[i] {
+					t.Errorf("allocation %d = %d, want %d", i, allocation.Amount, tt.allocations[i])
+				}
+				sum += allocation.Amount
+			}
+			if sum != result.Discount.Amount {
+				t.Errorf("allocations add up to %d, want the discount %d", sum, result.Discount.Amount)
+			}
+		})
+	}
+}
+
+func TestPromotionApply_RejectsCartsBelowMinSpend(t *testing.T) {
+	mug := uuid.New()
+	promotion := entities.Promotion{Type: entities.PromotionTypeFixed, Amount: thb(1000), MinSpend: thb(20000), ProductIDs: []uuid.UUID{mug}}
+
+	// ยอดรวมทั้งตะกร้าเกินยอดขั้นต่ำ แต่สินค้าที่ร่วมรายการยังไม่ถึง
+	lines := []entities.PromotionLine{
+		{ProductID: uuid.New(), Amount: thb(50000)},
+		{ProductID: mug, Amount: thb(19999)},
+	}
+	if result, err := promotion.Apply(lines); !errors.Is(err, entities.ErrPromotionNotApplicable) {
+		t.Fatalf("Apply = %+v, %v, want ErrPromotionNotApplicable", result, err)
+	}
+
+	// ไม่มีสินค้าที่ร่วมรายการเลย
+	if result, err := promotion.Apply(lines[:1]); !errors.Is(err, entities.ErrPromotionNotApplicable) {
+		t.Fatalf("Apply = %+v, %v, want ErrPromotionNotApplicable", result, err)
+	}
+}
@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefundStatus สถานะของการคืนเงิน
+// การคืนเงินที่เป็น pending จะถูกนับรวมในยอดคืนเงินแล้ว เพื่อกันการคืนเงินเกินยอดเมื่อทำพร้อมกัน
+type RefundStatus string
+
+const (
+	RefundStatusPending   RefundStatus = "pending"
+	RefundStatusCompleted RefundStatus = "completed"
+	RefundStatusFailed    RefundStatus = "failed"
+)
+
+// Refund การคืนเงินหนึ่งครั้งของธุรกรรมที่ชำระเงินสำเร็จแล้ว
+// คืนได้ทั้งเต็มจำนวนหรือบางส่วน โดยยอดรวมของทุกครั้งต้องไม่เกินยอดที่ชำระไว้
+type Refund struct {
+	ID            uuid.UUID    `json:"id"`
+	TransactionID uuid.UUID    `json:"transaction_id"`
+	OrderID       uuid.UUID    `json:"order_id"`
+	Amount        Money        `json:"amount"`
+	Reason        string       `json:"reason"`
+	Restock       bool         `json:"restock"`
+	Status        RefundStatus `json:"status"`
+	ProviderRef   string       `json:"provider_ref"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+	ActorID       *uuid.UUID   `json:"actor_id,omitempty"`
+	Items         []RefundItem `json:"items,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// RefundItem จำนวนสินค้าของ OrderItem หนึ่งรายการที่คืนเงินในการคืนเงินครั้งนั้น
+type RefundItem struct {
+	ID          uuid.UUID `json:"id"`
+	OrderItemID uuid.UUID `json:"order_item_id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	Quantity    int       `json:"quantity"`
+	Amount      Money     `json:"amount"`
+}
+
+// CreateRefundRequest คำขอคืนเงินโดยผู้ดูแลระบบ
+// ถ้าไม่ระบุ Amount จะใช้ยอดรวมของ Items หรือยอดที่ยังคืนได้ทั้งหมดถ้าไม่ระบุ Items ด้วย
+// Restock จะคืนสต็อกตาม Items ถ้าไม่ระบุ Items ต้องเป็นการคืนเงินเต็มจำนวนที่เหลือ
+// คำสั่งซื้อที่ยกเลิกแล้วคืนสต็อกไปตอนยกเลิก จึงขอ Restock ไม่ได้
+type CreateRefundRequest struct {
+	Amount  *Money              `json:"amount" validate:"omitempty,gt=0"`
+	Reason  string              `json:"reason" validate:"required"`
+	Restock bool                `json:"restock"`
+	Items   []RefundItemRequest `json:"items" validate:"omitempty,dive"`
+}
+
+// RefundItemRequest สินค้าที่ต้องการคืนเงินในคำขอคืนเงิน
+type RefundItemRequest struct {
+	OrderItemID uuid.UUID `json:"order_item_id" validate:"required"`
+	Quantity    int       `json:"quantity" validate:"required,min=1"`
+}
+
+// Refund errors
+var (
+	ErrRefundNotAllowed      = errors.New("คืนเงินได้เฉพาะธุรกรรมที่ชำระเงินสำเร็จแล้ว")
+	ErrRefundExceedsCaptured = errors.New("ยอดคืนเงินรวมเกินยอดที่ชำระไว้")
+	ErrInvalidRefundItem     = errors.New("รายการสินค้าที่ขอคืนเงินไม่ถูกต้อง")
+	ErrRestockCancelledOrder = errors.New("คำสั่งซื้อที่ยกเลิกแล้วคืนสินค้าเข้าสต็อกไปแล้ว จึงคืนสต็อกซ้ำไม่ได้")
+	ErrRefundFailed          = errors.New("payment gateway คืนเงินไม่สำเร็จ")
+)
@@ -0,0 +1,120 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReturnStatus สถานะของคำขอคืนสินค้า (RMA)
+// requested → approved → received → inspected → completed โดยผู้ดูแลระบบปฏิเสธได้ตอน requested
+// และลูกค้ายกเลิกได้ก่อนร้านได้รับสินค้าคืน
+type ReturnStatus string
+
+const (
+	ReturnStatusRequested ReturnStatus = "requested"
+	ReturnStatusApproved  ReturnStatus = "approved"
+	ReturnStatusRejected  ReturnStatus = "rejected"
+	ReturnStatusReceived  ReturnStatus = "received"
+	ReturnStatusInspected ReturnStatus = "inspected"
+	ReturnStatusCompleted ReturnStatus = "completed"
+	ReturnStatusCancelled ReturnStatus = "cancelled"
+)
+
+// IsValid ตรวจสอบว่าเป็นสถานะที่ระบบรู้จัก
+func (s ReturnStatus) IsValid() bool {
+	switch s {
+	case ReturnStatusRequested, ReturnStatusApproved, ReturnStatusRejected, ReturnStatusReceived,
+		ReturnStatusInspected, ReturnStatusCompleted, ReturnStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// IsOpen คำขอคืนสินค้าที่ยังไม่จบ จำนวนสินค้าในคำขอเหล่านี้จะขอคืนซ้ำไม่ได้
+func (s ReturnStatus) IsOpen() bool {
+	switch s {
+	case ReturnStatusRequested, ReturnStatusApproved, ReturnStatusReceived, ReturnStatusInspected:
+		return true
+	}
+	return false
+}
+
+// Return คำขอคืนสินค้าของคำสั่งซื้อที่จัดส่งสำเร็จแล้ว
+// RefundID คือการคืนเงินที่สร้างหลังตรวจสินค้า เป็น nil ถ้ายังไม่ได้คืนเงินหรือไม่มีสินค้าที่รับคืน
+type Return struct {
+	ID        uuid.UUID     `json:"id"`
+	OrderID   uuid.UUID     `json:"order_id"`
+	UserID    uuid.UUID     `json:"user_id"`
+	Status    ReturnStatus  `json:"status"`
+	Reason    string        `json:"reason"`
+	Note      string        `json:"note"`
+	RefundID  *uuid.UUID    `json:"refund_id,omitempty"`
+	Items     []ReturnItem  `json:"items"`
+	Timeline  []ReturnEvent `json:"timeline,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// ReturnItem จำนวนสินค้าของ OrderItem หนึ่งรายการที่ขอคืน
+// AcceptedQuantity คือจำนวนที่ผ่านการตรวจและจะได้เงินคืน Restock บอกว่าจำนวนนั้นนำกลับเข้าสต็อกได้
+type ReturnItem struct {
+	ID               uuid.UUID `json:"id"`
+	OrderItemID      uuid.UUID `json:"order_item_id"`
+	ProductID        uuid.UUID `json:"product_id"`
+	Quantity         int       `json:"quantity"`
+	Reason           string    `json:"reason"`
+	AcceptedQuantity int       `json:"accepted_quantity"`
+	Restock          bool      `json:"restock"`
+}
+
+// ReturnEvent บันทึกการเปลี่ยนสถานะหนึ่งครั้งของคำขอคืนสินค้า
+type ReturnEvent struct {
+	ID         uuid.UUID    `json:"id"`
+	FromStatus ReturnStatus `json:"from_status"`
+	ToStatus   ReturnStatus `json:"to_status"`
+	ActorID    *uuid.UUID   `json:"actor_id,omitempty"`
+	Note       string       `json:"note"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// CreateReturnRequest คำขอคืนสินค้าของลูกค้า
+type CreateReturnRequest struct {
+	OrderID uuid.UUID           `json:"order_id" validate:"required"`
+	Reason  string              `json:"reason" validate:"required,max=500"`
+	Items   []ReturnItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// ReturnItemRequest สินค้าที่ต้องการคืนในคำขอคืนสินค้า
+type ReturnItemRequest struct {
+	OrderItemID uuid.UUID `json:"order_item_id" validate:"required"`
+	Quantity    int       `json:"quantity" validate:"required,min=1"`
+	Reason      string    `json:"reason" validate:"max=500"`
+}
+
+// ReturnActionRequest หมายเหตุของผู้ดูแลระบบตอนอนุมัติ ปฏิเสธ หรือรับสินค้าคืน
+type ReturnActionRequest struct {
+	Note string `json:"note"`
+}
+
+// InspectReturnRequest ผลการตรวจสินค้าที่ได้รับคืน สินค้าที่ไม่ได้ระบุถือว่าไม่ผ่านการตรวจ
+type InspectReturnRequest struct {
+	Items []ReturnInspectionItem `json:"items" validate:"required,min=1,dive"`
+	Note  string                 `json:"note"`
+}
+
+// ReturnInspectionItem ผลการตรวจสินค้าหนึ่งรายการของคำขอคืนสินค้า
+type ReturnInspectionItem struct {
+	ReturnItemID     uuid.UUID `json:"return_item_id" validate:"required"`
+	AcceptedQuantity int       `json:"accepted_quantity" validate:"min=0"`
+	Restock          bool      `json:"restock"`
+}
+
+// Return errors
+var (
+	ErrReturnNotFound       = errors.New("ไม่พบคำขอคืนสินค้า")
+	ErrReturnNotAllowed     = errors.New("คำสั่งซื้อนี้ขอคืนสินค้าไม่ได้")
+	ErrInvalidReturnItem    = errors.New("รายการสินค้าที่ขอคืนไม่ถูกต้อง")
+	ErrReturnStatusConflict = errors.New("ไม่สามารถเปลี่ยนสถานะคำขอคืนสินค้าได้")
+)
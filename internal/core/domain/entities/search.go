@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+)
+
+// ProductSort ลำดับผลการค้นหาสินค้า
+// relevance เรียงตามความเกี่ยวข้องกับคำค้น ถ้าไม่ได้ระบุคำค้นจะเรียงแบบ newest แทน
+type ProductSort string
+
+const (
+	ProductSortRelevance ProductSort = "relevance"
+	ProductSortNewest    ProductSort = "newest"
+	ProductSortPriceAsc  ProductSort = "price_asc"
+	ProductSortPriceDesc ProductSort = "price_desc"
+)
+
+// IsValid ตรวจสอบว่าเป็นลำดับที่ระบบรู้จัก
+func (s ProductSort) IsValid() bool {
+	switch s {
+	case ProductSortRelevance, ProductSortNewest, ProductSortPriceAsc, ProductSortPriceDesc:
+		return true
+	}
+	return false
+}
+
+// SearchPriceBuckets ขอบล่างของช่วงราคาที่ใช้นับ facet ในหน่วยย่อยของ DefaultCurrency
+// ช่วงสุดท้ายไม่มีขอบบน: 0–500, 500–1,000, 1,000–2,500, 2,500–5,000 และ 5,000 บาทขึ้นไป
+var SearchPriceBuckets = []int64{0, 50000, 100000, 250000, 500000}
+
+// SearchFacets จำนวนสินค้าที่ตรงกับการค้นหาแยกตามหมวดหมู่และช่วงราคา
+// จำนวนของแต่ละกลุ่มไม่นับตัวกรองของกลุ่มนั้นเอง เพื่อให้เห็นว่าเปลี่ยนตัวเลือกแล้วจะได้สินค้ากี่รายการ
+type SearchFacets struct {
+	Categories  []CategoryFacet   `json:"categories"`
+	PriceRanges []PriceRangeFacet `json:"price_ranges"`
+}
+
+// CategoryFacet จำนวนสินค้าที่ตรงกับการค้นหาในหมวดหมู่หนึ่ง
+type CategoryFacet struct {
+	CategoryID uuid.UUID `json:"category_id"`
+	Name       string    `json:"name"`
+	Count      int       `json:"count"`
+}
+
+// PriceRangeFacet จำนวนสินค้าที่ตรงกับการค้นหาในช่วงราคา [Min, Max) ของ DefaultCurrency
+// Max เป็น nil สำหรับช่วงสุดท้ายที่ไม่มีขอบบน
+type PriceRangeFacet struct {
+	Min   Money  `json:"min"`
+	Max   *Money `json:"max,omitempty"`
+	Count int    `json:"count"`
+}
+
+// NewPriceRangeFacets สร้าง facet ของทุกช่วงราคาใน SearchPriceBuckets จากจำนวนสินค้าของแต่ละช่วง
+// counts ใช้ลำดับของช่วงเป็น key ช่วงที่ไม่มีสินค้าจะได้จำนวนเป็น 0
+func NewPriceRangeFacets(counts map[int]int) []PriceRangeFacet {
+	facets := make([]PriceRangeFacet, len(SearchPriceBuckets))
+	for i, min := range SearchPriceBuckets {
+		facets[i] = PriceRangeFacet{
+			Min:   NewMoney(min, DefaultCurrency),
+			Count: counts[i],
+		}
+		if i+1 < len(SearchPriceBuckets) {
+			max := NewMoney(SearchPriceBuckets[i+1], DefaultCurrency)
+			facets[i].Max = &max
+		}
+	}
+	return facets
+}
+
+// ProductSearchQuery คำค้นที่ส่งให้ search index
+// Limit คือจำนวนสินค้าสูงสุดที่ต้องการ (0 คือไม่จำกัด) และ SuggestionLimit คือจำนวนคำแนะนำสูงสุด (0 คือไม่ต้องการ)
+type ProductSearchQuery struct {
+	Text            string
+	Limit           int
+	SuggestionLimit int
+}
+
+// ProductSearchResult ผลจาก search index
+// ProductIDs เรียงตามความเกี่ยวข้องจากมากไปน้อย Suggestions คือคำค้นที่เติมให้สมบูรณ์หรือสะกดใหม่
+type ProductSearchResult struct {
+	ProductIDs  []uuid.UUID
+	Suggestions []string
+}
@@ -0,0 +1,191 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Carriers ที่ระบบมี CarrierClient ให้ใช้ ผู้ให้บริการอื่นยังบันทึกพัสดุได้ถ้าระบุเลขพัสดุเอง แต่จะไม่ถูกติดตามสถานะอัตโนมัติ
+const (
+	CarrierFake = "fake"
+)
+
+// Shipment พัสดุหนึ่งกล่องที่ส่งออกไปของคำสั่งซื้อ คำสั่งซื้อหนึ่งอาจแยกส่งหลายพัสดุ
+// Status ใช้สถานะเดียวกับ ShippingStatus ของคำสั่งซื้อ โดยเริ่มที่ shipped เสมอ
+type Shipment struct {
+	ID             uuid.UUID            `json:"id"`
+	OrderID        uuid.UUID            `json:"order_id"`
+	Carrier        string               `json:"carrier"`
+	TrackingNumber string               `json:"tracking_number"`
+	Status         ShippingStatus       `json:"status"`
+	Items          []ShipmentItem       `json:"items"`
+	Checkpoints    []TrackingCheckpoint `json:"checkpoints"`
+	ShippedAt      time.Time            `json:"shipped_at"`
+	DeliveredAt    *time.Time           `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
+
+// ShipmentItem จำนวนสินค้าของ OrderItem หนึ่งรายการที่อยู่ในพัสดุ
+type ShipmentItem struct {
+	ID          uuid.UUID `json:"id"`
+	OrderItemID uuid.UUID `json:"order_item_id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	Quantity    int       `json:"quantity"`
+}
+
+// TrackingCheckpoint จุดติดตามพัสดุหนึ่งจุดที่แสดงให้ลูกค้าเห็น
+type TrackingCheckpoint struct {
+	ID          uuid.UUID      `json:"id"`
+	EventID     string         `json:"event_id"`
+	Status      ShippingStatus `json:"status"`
+	Location    string         `json:"location"`
+	Description string         `json:"description"`
+	OccurredAt  time.Time      `json:"occurred_at"`
+}
+
+// CreateShipmentRequest คำขอสร้างพัสดุของคำสั่งซื้อโดยผู้ดูแลระบบ
+// ถ้าไม่ระบุ TrackingNumber จะขอเลขพัสดุจาก CarrierClient ของ Carrier นั้น
+// ถ้าไม่ระบุ Items จะส่งสินค้าทุกรายการที่ยังไม่ได้ส่ง
+type CreateShipmentRequest struct {
+	Carrier        string                `json:"carrier" validate:"required,max=50"`
+	TrackingNumber string                `json:"tracking_number" validate:"max=100"`
+	Items          []ShipmentItemRequest `json:"items" validate:"omitempty,dive"`
+	Note           string                `json:"note"`
+}
+
+// ShipmentItemRequest สินค้าที่ต้องการใส่ในพัสดุ
+type ShipmentItemRequest struct {
+	OrderItemID uuid.UUID `json:"order_item_id" validate:"required"`
+	Quantity    int       `json:"quantity" validate:"required,min=1"`
+}
+
+// CarrierShipmentRequest ข้อมูลที่ส่งให้ผู้ให้บริการขนส่งเพื่อสร้างพัสดุ
+// Reference คือ ID ของคำสั่งซื้อ Weight เป็นกรัม
+type CarrierShipmentRequest struct {
+	Reference string
+	Address   string
+	Weight    int
+}
+
+// CarrierShipment ผลลัพธ์จากการสร้างพัสดุที่ผู้ให้บริการขนส่ง
+type CarrierShipment struct {
+	TrackingNumber string
+}
+
+// CarrierEvent เหตุการณ์ของพัสดุจากผู้ให้บริการขนส่ง EventID ต้องไม่ซ้ำกันภายในพัสดุเดียวกัน
+type CarrierEvent struct {
+	EventID     string
+	Status      ShippingStatus
+	Location    string
+	Description string
+	OccurredAt  time.Time
+}
+
+// Shipment errors
+var (
+	ErrShipmentNotFound    = errors.New("ไม่พบพัสดุ")
+	ErrUnknownCarrier      = errors.New("ไม่รองรับผู้ให้บริการขนส่งนี้")
+	ErrInvalidShipmentItem = errors.New("รายการสินค้าในพัสดุไม่ถูกต้อง")
+	ErrCarrierFailed       = errors.New("ผู้ให้บริการขนส่งทำรายการไม่สำเร็จ")
+)
+
+// ShippedQuantities จำนวนที่ส่งไปแล้วของแต่ละ OrderItem จากทุกพัสดุของคำสั่งซื้อ
+func (o *Order) ShippedQuantities() map[uuid.UUID]int {
+	shipped := make(map[uuid.UUID]int, len(o.OrderItems))
+	for _, shipment := range o.Shipments {
+		for _, item := range shipment.Items {
+			shipped[item.OrderItemID] += item.Quantity
+		}
+	}
+	return shipped
+}
+
+// FullyShipped ตรวจสอบว่าสินค้าทุกรายการของคำสั่งซื้อถูกส่งครบแล้ว
+func (o *Order) FullyShipped() bool {
+	shipped := o.ShippedQuantities()
+	for _, item := range o.OrderItems {
+		if shipped[item.ID] < item.Quantity {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolveShipmentItems ตรวจสอบสินค้าที่จะใส่ในพัสดุกับ OrderItem และจำนวนที่ส่งไปแล้ว
+// ถ้าไม่ระบุ requested จะคืนสินค้าทุกรายการที่ยังไม่ได้ส่ง
+func ResolveShipmentItems(orderItems []OrderItem, shipped map[uuid.UUID]int, requested []ShipmentItemRequest) ([]ShipmentItem, error) {
+	var items []ShipmentItem
+
+	if len(requested) == 0 {
+		for _, orderItem := range orderItems {
+			if quantity := orderItem.Quantity - shipped[orderItem.ID]; quantity > 0 {
+				items = append(items, ShipmentItem{OrderItemID: orderItem.ID, ProductID: orderItem.ProductID, Quantity: quantity})
+			}
+		}
+		if len(items) == 0 {
+			return nil, fmt.Errorf("%w: ส่งสินค้าครบทุกรายการแล้ว", ErrInvalidShipmentItem)
+		}
+		return items, nil
+	}
+
+	orderItemsByID := make(map[uuid.UUID]*OrderItem, len(orderItems))
+	for i := range orderItems {
+		orderItemsByID[orderItems[i].ID] = &orderItems[i]
+	}
+
+	seen := make(map[uuid.UUID]bool, len(requested))
+	for _, request := range requested {
+		orderItem, ok := orderItemsByID[request.OrderItemID]
+		if !ok {
+			return nil, fmt.Errorf("%w: ไม่พบสินค้า %s ในคำสั่งซื้อนี้", ErrInvalidShipmentItem, request.OrderItemID)
+		}
+		if seen[request.OrderItemID] {
+			return nil, fmt.Errorf("%w: สินค้า %s ถูกระบุซ้ำ", ErrInvalidShipmentItem, request.OrderItemID)
+		}
+		seen[request.OrderItemID] = true
+
+		available := orderItem.Quantity - shipped[orderItem.ID]
+		if request.Quantity > available {
+			return nil, fmt.Errorf("%w: สินค้า %s ขอส่ง %d ชิ้น ส่งได้อีก %d ชิ้น", ErrInvalidShipmentItem, request.OrderItemID, request.Quantity, available)
+		}
+
+		items = append(items, ShipmentItem{OrderItemID: orderItem.ID, ProductID: orderItem.ProductID, Quantity: request.Quantity})
+	}
+
+	return items, nil
+}
+
+// AggregateShippingStatus สรุปสถานะการจัดส่งของคำสั่งซื้อจากทุกพัสดุ
+// คำสั่งซื้อจะเป็น delivered เมื่อส่งสินค้าครบแล้วและพัสดุที่ไม่ถูกตีกลับถึงปลายทางทั้งหมด
+// และเป็น returned เมื่อพัสดุทุกกล่องถูกตีกลับ
+func AggregateShippingStatus(shipments []Shipment, fullyShipped bool) ShippingStatus {
+	if len(shipments) == 0 {
+		return ShippingStatusPending
+	}
+
+	var returned, delivered, moving int
+	for _, shipment := range shipments {
+		switch shipment.Status {
+		case ShippingStatusReturned:
+			returned++
+		case ShippingStatusDelivered:
+			delivered++
+		case ShippingStatusInTransit:
+			moving++
+		}
+	}
+
+	switch {
+	case returned == len(shipments):
+		return ShippingStatusReturned
+	case fullyShipped && delivered > 0 && delivered+returned == len(shipments):
+		return ShippingStatusDelivered
+	case delivered > 0 || moving > 0:
+		return ShippingStatusInTransit
+	}
+	return ShippingStatusShipped
+}
@@ -0,0 +1,150 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrShippingMethodNotFound = errors.New("ไม่พบวิธีจัดส่ง")
+	ErrShippingNotAvailable   = errors.New("วิธีจัดส่งนี้ไม่ให้บริการในพื้นที่ที่เลือก")
+	ErrInvalidShippingMethod  = errors.New("ข้อมูลวิธีจัดส่งไม่ถูกต้อง")
+	ErrShippingCodeExists     = errors.New("รหัสวิธีจัดส่งนี้ถูกใช้แล้ว")
+)
+
+// ShippingRateType วิธีคิดค่าจัดส่ง
+type ShippingRateType string
+
+const (
+	// ShippingRateFlat ค่าจัดส่งคงที่ต่อคำสั่งซื้อ
+	ShippingRateFlat ShippingRateType = "flat"
+	// ShippingRateWeight ค่าจัดส่งพื้นฐานบวกค่าน้ำหนักต่อกิโลกรัม (เศษของกิโลกรัมปัดขึ้น)
+	ShippingRateWeight ShippingRateType = "weight_based"
+)
+
+// IsValid ตรวจสอบว่าเป็นวิธีคิดค่าจัดส่งที่รองรับ
+func (t ShippingRateType) IsValid() bool {
+	return t == ShippingRateFlat || t == ShippingRateWeight
+}
+
+// ShippingMethod วิธีจัดส่งพร้อมกฎการคิดค่าจัดส่ง จำนวนเงินทั้งหมดเป็นสกุลเงินของสินค้า
+// Fee คือค่าจัดส่งพื้นฐาน ถ้าจังหวัดปลายทางอยู่ใน Regions จะใช้ค่าจัดส่งของจังหวัดนั้นแทน
+// FreeOver มากกว่าศูนย์คือส่งฟรีเมื่อยอดสินค้าหลังหักส่วนลดถึงจำนวนนี้
+type ShippingMethod struct {
+	ID            uuid.UUID            `json:"id"`
+	Code          string               `json:"code"`
+	Name          string               `json:"name"`
+	Description   string               `json:"description"`
+	RateType      ShippingRateType     `json:"rate_type"`
+	Fee           Money                `json:"fee"`
+	FeePerKg      Money                `json:"fee_per_kg"`
+	FreeOver      Money                `json:"free_over"`
+	Regions       []ShippingRegionRate `json:"regions,omitempty"`
+	RegionsOnly   bool                 `json:"regions_only"`
+	EstimatedDays int                  `json:"estimated_days"`
+	Active        bool                 `json:"active"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+// ShippingRegionRate ค่าจัดส่งพื้นฐานเฉพาะจังหวัด
+type ShippingRegionRate struct {
+	Province string `json:"province" validate:"required"`
+	Fee      Money  `json:"fee" validate:"min=0"`
+}
+
+type CreateShippingMethodRequest struct {
+	Code          string               `json:"code" validate:"required,max=50"`
+	Name          string               `json:"name" validate:"required"`
+	Description   string               `json:"description"`
+	RateType      ShippingRateType     `json:"rate_type" validate:"required"`
+	Fee           Money                `json:"fee" validate:"min=0"`
+	FeePerKg      Money                `json:"fee_per_kg" validate:"min=0"`
+	FreeOver      Money                `json:"free_over" validate:"min=0"`
+	Regions       []ShippingRegionRate `json:"regions" validate:"dive"`
+	RegionsOnly   bool                 `json:"regions_only"`
+	EstimatedDays int                  `json:"estimated_days" validate:"min=0"`
+	Active        *bool                `json:"active"`
+}
+
+// UpdateShippingMethodRequest ฟิลด์ที่เป็น nil จะไม่ถูกแก้ไข ส่วน Regions ถ้าส่งมาจะแทนที่รายการเดิมทั้งหมด
+type UpdateShippingMethodRequest struct {
+	Name          string                `json:"name"`
+	Description   *string               `json:"description"`
+	RateType      *ShippingRateType     `json:"rate_type"`
+	Fee           *Money                `json:"fee" validate:"omitempty,min=0"`
+	FeePerKg      *Money                `json:"fee_per_kg" validate:"omitempty,min=0"`
+	FreeOver      *Money                `json:"free_over" validate:"omitempty,min=0"`
+	Regions       *[]ShippingRegionRate `json:"regions" validate:"omitempty,dive"`
+	RegionsOnly   *bool                 `json:"regions_only"`
+	EstimatedDays *int                  `json:"estimated_days" validate:"omitempty,min=0"`
+	Active        *bool                 `json:"active"`
+}
+
+// ShippingQuoteRequest ข้อมูลปลายทางสำหรับคำนวณค่าจัดส่งของตะกร้า
+// ระบุ AddressID จากสมุดที่อยู่หรือระบุ Province ก็ได้ ถ้าระบุ Method จะคำนวณเฉพาะวิธีจัดส่งนั้น
+type ShippingQuoteRequest struct {
+	AddressID  *uuid.UUID `json:"address_id"`
+	Province   string     `json:"province" validate:"required_without=AddressID"`
+	PostalCode string     `json:"postal_code"`
+	Method     string     `json:"method"`
+}
+
+// ShippingQuote ค่าจัดส่งของตะกร้าสำหรับวิธีจัดส่งหนึ่งวิธี
+type ShippingQuote struct {
+	MethodID      uuid.UUID `json:"method_id"`
+	Code          string    `json:"code"`
+	Name          string    `json:"name"`
+	EstimatedDays int       `json:"estimated_days"`
+	Fee           Money     `json:"fee"`
+	FreeShipping  bool      `json:"free_shipping"`
+}
+
+// ShippingQuoteInput ข้อมูลของคำสั่งซื้อที่ใช้คิดค่าจัดส่ง Subtotal คือยอดสินค้าหลังหักส่วนลด Weight เป็นกรัม
+type ShippingQuoteInput struct {
+	Subtotal Money
+	Weight   int
+	Province string
+}
+
+// Quote คำนวณค่าจัดส่งตามกฎของวิธีจัดส่งนี้
+// ถ้า RegionsOnly และจังหวัดปลายทางไม่อยู่ใน Regions จะคืน ErrShippingNotAvailable
+func (m *ShippingMethod) Quote(in ShippingQuoteInput) (Money, error) {
+	fee := m.Fee
+	if region, ok := m.region(in.Province); ok {
+		fee = region.Fee
+	} else if m.RegionsOnly {
+		return Money{}, fmt.Errorf("%w: %s ไม่จัดส่งไปที่ %s", ErrShippingNotAvailable, m.Name, in.Province)
+	}
+
+	if m.RateType == ShippingRateWeight && in.Weight > 0 {
+		kg := int64((in.Weight + 999) / 1000)
+		fee.Amount += m.FeePerKg.Amount * kg
+	}
+
+	if m.FreeOver.IsPositive() {
+		if in.Subtotal.Currency != m.FreeOver.Currency {
+			return Money{}, fmt.Errorf("%w: %s กับ %s", ErrCurrencyMismatch, m.FreeOver.Currency, in.Subtotal.Currency)
+		}
+		if in.Subtotal.Amount >= m.FreeOver.Amount {
+			fee.Amount = 0
+		}
+	}
+
+	return fee, nil
+}
+
+// region หาค่าจัดส่งของจังหวัดปลายทาง โดยไม่สนใจตัวพิมพ์เล็กใหญ่และช่องว่างหัวท้าย
+func (m *ShippingMethod) region(province string) (ShippingRegionRate, bool) {
+	province = strings.TrimSpace(province)
+	for _, region := range m.Regions {
+		if strings.EqualFold(strings.TrimSpace(region.Province), province) {
+			return region, true
+		}
+	}
+	return ShippingRegionRate{}, false
+}
@@ -0,0 +1,81 @@
+package entities_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+func TestShippingMethodQuote(t *testing.T) {
+	flat := entities.ShippingMethod{
+		Name:     "Standard",
+		RateType: entities.ShippingRateFlat,
+		Fee:      thb(5000),
+		FeePerKg: thb(1000),
+		Regions:  []entities.ShippingRegionRate{{Province: " Chiang Mai ", Fee: thb(8000)}},
+	}
+	regionsOnly := flat
+	regionsOnly.RegionsOnly = true
+	byWeight := entities.ShippingMethod{
+		Name:     "Parcel",
+		RateType: entities.ShippingRateWeight,
+		Fee:      thb(3000),
+		FeePerKg: thb(1000),
+		Regions:  []entities.ShippingRegionRate{{Province: "Phuket", Fee: thb(6000)}},
+	}
+	freeOver := flat
+	freeOver.FreeOver = thb(100000)
+
+	tests := []struct {
+		name   string
+		method entities.ShippingMethod
+		in     entities.ShippingQuoteInput
+		want   int64
+	}{
+		{"base fee", flat, entities.ShippingQuoteInput{Subtotal: thb(10000), Province: "Bangkok"}, 5000},
+		{"region overrides base fee", flat, entities.ShippingQuoteInput{Subtotal: thb(10000), Province: "chiang mai"}, 8000},
+		{"regions only ships to listed region", regionsOnly, entities.ShippingQuoteInput{Subtotal: thb(10000), Province: "Chiang Mai"}, 8000},
+		{"flat rate ignores weight", flat, entities.ShippingQuoteInput{Subtotal: thb(10000), Weight: 5000, Province: "Bangkok"}, 5000},
+		{"no weight adds nothing", byWeight, entities.ShippingQuoteInput{Subtotal: thb(10000), Province: "Bangkok"}, 3000},
+		{"one gram rounds up to a kg", byWeight, entities.ShippingQuoteInput{Subtotal: thb(10000), Weight: 1, Province: "Bangkok"}, 4000},
+		{"exact kg", byWeight, entities.ShippingQuoteInput{Subtotal: thb(10000), Weight: 1000, Province: "Bangkok"}, 4000},
+		{"just over a kg rounds up", byWeight, entities.ShippingQuoteInput{Subtotal: thb(10000), Weight: 1001, Province: "Bangkok"}, 5000},
+		{"weight adds to region fee", byWeight, entities.ShippingQuoteInput{Subtotal: thb(10000), Weight: 2500, Province: "Phuket"}, 9000},
+		{"below free threshold", freeOver, entities.ShippingQuoteInput{Subtotal: thb(99999), Province: "Bangkok"}, 5000},
+		{"at free threshold", freeOver, entities.ShippingQuoteInput{Subtotal: thb(100000), Province: "Chiang Mai"}, 0},
+		// ไม่มีเงื่อนไขส่งฟรี จึงไม่ต้องเทียบสกุลเงินของยอดสินค้า
+		{"no free threshold accepts any currency", flat, entities.ShippingQuoteInput{Subtotal: entities.NewMoney(10000, "USD"), Province: "Bangkok"}, 5000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fee, err := tt.method.Quote(tt.in)
+			if err != nil {
+				t.Fatalf("Quote: %v", err)
+			}
+			if fee.Amount != tt.want || fee.Currency != "THB" {
+				t.Errorf("Quote = %d %s, want %d THB", fee.Amount, fee.Currency, tt.want)
+			}
+		})
+	}
+}
+
+func TestShippingMethodQuote_Errors(t *testing.T) {
+	regionsOnly := entities.ShippingMethod{
+		Name:        "Local",
+		RateType:    entities.ShippingRateFlat,
+		Fee:         thb(5000),
+		Regions:     []entities.ShippingRegionRate{{Province: "Bangkok", Fee: thb(3000)}},
+		RegionsOnly: true,
+	}
+	if fee, err := regionsOnly.Quote(entities.ShippingQuoteInput{Subtotal: thb(10000), Province: "Phuket"}); !errors.Is(err, entities.ErrShippingNotAvailable) {
+		t.Errorf("Quote outside regions = %v, %v, want ErrShippingNotAvailable", fee, err)
+	}
+
+	// ยอดสินค้าต่างสกุลกับเงื่อนไขส่งฟรีเทียบกันไม่ได้ จึงต้องเป็นข้อผิดพลาด ไม่ใช่คิดค่าส่งเต็มหรือส่งฟรี
+	freeOver := entities.ShippingMethod{Name: "Standard", RateType: entities.ShippingRateFlat, Fee: thb(5000), FreeOver: thb(100000)}
+	if fee, err := freeOver.Quote(entities.ShippingQuoteInput{Subtotal: entities.NewMoney(500000, "USD"), Province: "Bangkok"}); !errors.Is(err, entities.ErrCurrencyMismatch) {
+		t.Errorf("Quote with USD subtotal = %v, %v, want ErrCurrencyMismatch", fee, err)
+	}
+}
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTaxRuleNotFound = errors.New("ไม่พบกฎภาษี")
+	ErrTaxRuleInUse    = errors.New("กฎภาษีนี้ยังถูกใช้กับสินค้าหรือหมวดหมู่อยู่")
+)
+
+// TaxRule กฎภาษีมูลค่าเพิ่มที่ผูกกับสินค้าหรือหมวดหมู่
+// Rate เป็น basis point (700 คือ 7%) Inclusive คือราคาสินค้ารวมภาษีแล้ว
+// Exempt คือสินค้าที่ได้รับยกเว้นภาษี ซึ่งต่างจากอัตรา 0% ในใบกำกับภาษี
+type TaxRule struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Rate      int       `json:"rate"`
+	Inclusive bool      `json:"inclusive"`
+	Exempt    bool      `json:"exempt"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultTaxRule VAT 7% แบบราคารวมภาษี ใช้เมื่อทั้งสินค้าและหมวดหมู่ไม่ได้กำหนดกฎภาษีไว้
+// ลำดับการเลือกใช้คือ Product.TaxRuleID → Category.TaxRuleID → ค่านี้
+var DefaultTaxRule = TaxRule{Name: "VAT 7%", Rate: 700, Inclusive: true}
+
+type CreateTaxRuleRequest struct {
+	Name      string `json:"name" validate:"required"`
+	Rate      int    `json:"rate" validate:"min=0,max=10000"`
+	Inclusive bool   `json:"inclusive"`
+	Exempt    bool   `json:"exempt"`
+}
+
+type UpdateTaxRuleRequest struct {
+	Name      string `json:"name"`
+	Rate      *int   `json:"rate" validate:"omitempty,min=0,max=10000"`
+	Inclusive *bool  `json:"inclusive"`
+	Exempt    *bool  `json:"exempt"`
+}
+
+// TaxBreakdown ยอดก่อนภาษี ภาษี และยอดรวมของรายการสินค้าหรือคำสั่งซื้อ
+type TaxBreakdown struct {
+	Subtotal Money `json:"subtotal"`
+	Tax      Money `json:"tax"`
+	Total    Money `json:"total"`
+}
+
+// Calculate คำนวณภาษีของยอด line (ราคาต่อชิ้น × จำนวน) ตามกฎนี้ ปัดเศษหน่วยย่อยแบบครึ่งหนึ่งปัดขึ้น
+// ราคารวมภาษี: ภาษี = line × rate / (100% + rate) และยอดรวมเท่ากับ line
+// ราคาไม่รวมภาษี: ภาษี = line × rate และยอดรวมเท่ากับ line + ภาษี
+func (r TaxRule) Calculate(line Money) TaxBreakdown {
+	tax := NewMoney(0, line.Currency)
+
+	switch {
+	case r.Exempt || r.Rate == 0:
+		return TaxBreakdown{Subtotal: line, Tax: tax, Total: line}
+	case r.Inclusive:
+		tax.Amount = divRound(line.Amount*int64(r.Rate), 10000+int64(r.Rate))
+		subtotal, _ := line.Sub(tax)
+		return TaxBreakdown{Subtotal: subtotal, Tax: tax, Total: line}
+	default:
+		tax.Amount = divRound(line.Amount*int64(r.Rate), 10000)
+		total, _ := line.Add(tax)
+		return TaxBreakdown{Subtotal: line, Tax: tax, Total: total}
+	}
+}
+
+// divRound หาร n ด้วย d แล้วปัดเศษครึ่งหนึ่งออกจากศูนย์
+func divRound(n, d int64) int64 {
+	if n < 0 {
+		return -divRound(-n, d)
+	}
+	return (2*n + d) / (2 * d)
+}
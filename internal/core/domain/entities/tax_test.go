@@ -0,0 +1,47 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+func TestTaxRuleCalculate(t *testing.T) {
+	exclusive := entities.TaxRule{Rate: 700}
+	inclusive := entities.TaxRule{Rate: 700, Inclusive: true}
+
+	tests := []struct {
+		name                 string
+		rule                 entities.TaxRule
+		line                 int64
+		subtotal, tax, total int64
+	}{
+		{"exclusive", exclusive, 10000, 10000, 700, 10700},
+		{"exclusive rounds half up", exclusive, 50, 50, 4, 54},
+		{"exclusive rounds down below half", exclusive, 49, 49, 3, 52},
+		{"inclusive", inclusive, 10700, 10000, 700, 10700},
+		{"inclusive rounds to nearest", inclusive, 100, 93, 7, 100},
+		{"exempt ignores rate", entities.TaxRule{Rate: 700, Exempt: true}, 10000, 10000, 0, 10000},
+		{"exempt inclusive ignores rate", entities.TaxRule{Rate: 700, Inclusive: true, Exempt: true}, 10700, 10700, 0, 10700},
+		{"zero rate", entities.TaxRule{}, 10000, 10000, 0, 10000},
+		// ยอดติดลบ เช่น ส่วนลดหรือการคืนเงิน ปัดเศษออกจากศูนย์เท่ากับยอดบวก
+		{"exclusive negative rounds away from zero", exclusive, -50, -50, -4, -54},
+		{"inclusive negative", inclusive, -100, -93, -7, -100},
+		{"zero line", inclusive, 0, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rule.Calculate(entities.NewMoney(tt.line, "THB"))
+
+			if got.Subtotal.Amount != tt.subtotal || got.Tax.Amount != tt.tax || got.Total.Amount != tt.total {
+				t.Errorf("Calculate(%d) = subtotal %d, tax %d, total %d, want %d, %d, %d",
+					tt.line, got.Subtotal.Amount, got.Tax.Amount, got.Total.Amount, tt.subtotal, tt.tax, tt.total)
+			}
+			if got.Subtotal.Currency != "THB" || got.Tax.Currency != "THB" || got.Total.Currency != "THB" {
+				t.Errorf("Calculate(%d) currencies = %s, %s, %s, want THB",
+					tt.line, got.Subtotal.Currency, got.Tax.Currency, got.Total.Currency)
+			}
+		})
+	}
+}
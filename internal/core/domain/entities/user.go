@@ -79,6 +79,12 @@ type UpdateUserRequest struct {
 	Address   string `json:"address"`
 }
 
+// ชื่อ Role พื้นฐานของระบบ ใช้ตรวจสอบสิทธิ์ใน RoleMiddleware และใน seeder
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
 // Role Entity
 type Role struct {
 	ID          uuid.UUID    `json:"id"`
@@ -99,40 +105,71 @@ type Permission struct {
 }
 
 // Category Entity
+// หมวดหมู่ซ้อนกันได้ไม่จำกัดระดับผ่าน ParentID (nil คือหมวดหมู่บนสุด)
+// Breadcrumbs คือเส้นทางจากหมวดหมู่บนสุดถึงหมวดหมู่นี้ และ Children ใช้เมื่อดึงหมวดหมู่เป็นโครงสร้างต้นไม้
+// ProductCount คือจำนวนสินค้าในหมวดหมู่รวมหมวดหมู่ย่อยทุกระดับ มีค่าเฉพาะเมื่อดึงหมวดหมู่โดยตรง
 type Category struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Image       string    `json:"image"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-}
-
+	ID               uuid.UUID            `json:"id"`
+	ParentID         *uuid.UUID           `json:"parent_id,omitempty"`
+	Name             string               `json:"name"`
+	Slug             string               `json:"slug"`
+	Description      string               `json:"description"`
+	Image            string               `json:"image"`
+	SortOrder        int                  `json:"sort_order"`
+	ProductCount     *int                 `json:"product_count,omitempty"`
+	ReorderThreshold *int                 `json:"reorder_threshold,omitempty"`
+	TaxRuleID        *uuid.UUID           `json:"tax_rule_id,omitempty"`
+	Breadcrumbs      []CategoryBreadcrumb `json:"breadcrumbs,omitempty"`
+	Children         []*Category          `json:"children,omitempty"`
+	CreatedAt        time.Time            `json:"created_at"`
+	UpdatedAt        time.Time            `json:"updated_at"`
+}
+
+// CreateCategoryRequest ถ้าไม่ระบุ Slug จะสร้างจากชื่อและเติมเลขต่อท้ายเมื่อซ้ำ
 type CreateCategoryRequest struct {
-	Name        string `json:"name" validate:"required"`
-	Description string `json:"description"`
-	Image       string `json:"image"`
-}
-
+	ParentID         *uuid.UUID `json:"parent_id"`
+	Name             string     `json:"name" validate:"required"`
+	Slug             string     `json:"slug"`
+	Description      string     `json:"description"`
+	Image            string     `json:"image"`
+	SortOrder        int        `json:"sort_order"`
+	ReorderThreshold *int       `json:"reorder_threshold" validate:"omitempty,min=0"`
+	TaxRuleID        *uuid.UUID `json:"tax_rule_id"`
+}
+
+// UpdateCategoryRequest ส่ง parent_id เป็น uuid ว่างเพื่อย้ายหมวดหมู่ขึ้นไปเป็นหมวดหมู่บนสุด
+// หมวดหมู่ย่อยและสินค้าทั้งหมดย้ายตามไปด้วย
 type UpdateCategoryRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Image       string `json:"image"`
+	ParentID         *uuid.UUID `json:"parent_id"`
+	Name             string     `json:"name"`
+	Slug             string     `json:"slug"`
+	Description      string     `json:"description"`
+	Image            string     `json:"image"`
+	SortOrder        *int       `json:"sort_order"`
+	ReorderThreshold *int       `json:"reorder_threshold" validate:"omitempty,min=0"`
+	TaxRuleID        *uuid.UUID `json:"tax_rule_id"`
 }
 
 // Product Entity
+// Weight คือน้ำหนักต่อชิ้นเป็นกรัม ใช้คิดค่าจัดส่งแบบตามน้ำหนัก
 type Product struct {
-	ID          uuid.UUID      `json:"id"`
-	Name        string         `json:"name"`
-	Description string         `json:"description"`
-	Price       float64        `json:"price"`
-	Stock       int            `json:"stock"`
-	Image       string         `json:"image"`
-	Images      []ProductImage `json:"images,omitempty"`
-	CategoryID  uuid.UUID      `json:"category_id"`
-	Category    *Category      `json:"category,omitempty"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
+	ID               uuid.UUID        `json:"id"`
+	Name             string           `json:"name"`
+	Description      string           `json:"description"`
+	Price            Money            `json:"price"`
+	DisplayPrice     *Money           `json:"display_price,omitempty"`
+	Stock            int              `json:"stock"`
+	Weight           int              `json:"weight"`
+	ReorderThreshold *int             `json:"reorder_threshold,omitempty"`
+	TaxRuleID        *uuid.UUID       `json:"tax_rule_id,omitempty"`
+	Image            string           `json:"image"`
+	Images           []ProductImage   `json:"images,omitempty"`
+	Options          []ProductOption  `json:"options,omitempty"`
+	Variants         []ProductVariant `json:"variants,omitempty"`
+	CategoryID       uuid.UUID        `json:"category_id"`
+	Category         *Category        `json:"category,omitempty"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
 }
 
 type ProductImage struct {
@@ -144,32 +181,48 @@ type ProductImage struct {
 }
 
 type CreateProductRequest struct {
-	Name        string    `json:"name" validate:"required"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price" validate:"required,min=0"`
-	Stock       int       `json:"stock" validate:"min=0"`
-	Image       string    `json:"image"`
-	CategoryID  uuid.UUID `json:"category_id" validate:"required"`
-	Images      []string  `json:"images"`
+	Name             string     `json:"name" validate:"required"`
+	Description      string     `json:"description"`
+	Price            Money      `json:"price" validate:"required,gt=0"`
+	Stock            int        `json:"stock" validate:"min=0"`
+	Weight           int        `json:"weight" validate:"min=0"`
+	ReorderThreshold *int       `json:"reorder_threshold" validate:"omitempty,min=0"`
+	TaxRuleID        *uuid.UUID `json:"tax_rule_id"`
+	Image            string     `json:"image"`
+	CategoryID       uuid.UUID  `json:"category_id" validate:"required"`
+	Images           []string   `json:"images"`
 }
 
 type UpdateProductRequest struct {
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price" validate:"min=0"`
-	Stock       int       `json:"stock" validate:"min=0"`
-	Image       string    `json:"image"`
-	CategoryID  uuid.UUID `json:"category_id"`
-	Images      []string  `json:"images"`
+	Name             string     `json:"name"`
+	Description      string     `json:"description"`
+	Price            *Money     `json:"price" validate:"omitempty,gt=0"`
+	Stock            *int       `json:"stock" validate:"omitempty,min=0"`
+	Weight           *int       `json:"weight" validate:"omitempty,min=0"`
+	ReorderThreshold *int       `json:"reorder_threshold" validate:"omitempty,min=0"`
+	TaxRuleID        *uuid.UUID `json:"tax_rule_id"`
+	Image            string     `json:"image"`
+	CategoryID       uuid.UUID  `json:"category_id"`
+	Images           []string   `json:"images"`
+}
+
+// ProductSearchRequest เงื่อนไขการค้นหาสินค้า InStock กรองเฉพาะสินค้าที่ยังมีสต็อก
+type ProductSearchRequest struct {
+	Query      string      `json:"query"`
+	CategoryID uuid.UUID   `json:"category_id"`
+	MinPrice   *Money      `json:"min_price"`
+	MaxPrice   *Money      `json:"max_price"`
+	InStock    bool        `json:"in_stock"`
+	Sort       ProductSort `json:"sort"`
+	Page       int         `json:"page"`
+	Limit      int         `json:"limit"`
+	// ProductIDs ผลจาก search index เรียงตามความเกี่ยวข้อง ถ้าไม่เป็น nil จะใช้แทนการจับคู่ Query ในฐานข้อมูล
+	ProductIDs []uuid.UUID `json:"-"`
 }
 
-type ProductSearchRequest struct {
-	Query      string    `json:"query"`
-	CategoryID uuid.UUID `json:"category_id"`
-	MinPrice   float64   `json:"min_price"`
-	MaxPrice   float64   `json:"max_price"`
-	Page       int       `json:"page"`
-	Limit      int       `json:"limit"`
+// HasFilters ตรวจสอบว่ามีตัวกรองนอกจากคำค้นหรือไม่
+func (r *ProductSearchRequest) HasFilters() bool {
+	return r.CategoryID != uuid.Nil || r.MinPrice != nil || r.MaxPrice != nil || r.InStock
 }
 
 // Cart Entity
@@ -177,25 +230,37 @@ type Cart struct {
 	ID         uuid.UUID  `json:"id"`
 	UserID     uuid.UUID  `json:"user_id"`
 	CartItems  []CartItem `json:"cart_items"`
-	TotalPrice float64    `json:"total_price"`
+	TotalPrice Money      `json:"total_price"`
 	CreatedAt  time.Time  `json:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at"`
-}
 
-type CartItem struct {
-	ID        uuid.UUID `json:"id"`
-	CartID    uuid.UUID `json:"cart_id"`
-	ProductID uuid.UUID `json:"product_id"`
-	Product   *Product  `json:"product,omitempty"`
-	Quantity  int       `json:"quantity"`
-	Price     float64   `json:"price"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	// คูปองที่ใช้กับตะกร้า ส่วนลดคำนวณใหม่ทุกครั้งที่ดูตะกร้า และตรวจอีกครั้งตอน checkout
+	// ถ้าคูปองใช้ไม่ได้แล้ว CouponError จะบอกสาเหตุและส่วนลดเป็นศูนย์
+	CouponCode      string `json:"coupon_code,omitempty"`
+	CouponError     string `json:"coupon_error,omitempty"`
+	Discount        Money  `json:"discount"`
+	DiscountedTotal Money  `json:"discounted_total"`
+	FreeShipping    bool   `json:"free_shipping"`
 }
 
+type CartItem struct {
+	ID        uuid.UUID       `json:"id"`
+	CartID    uuid.UUID       `json:"cart_id"`
+	ProductID uuid.UUID       `json:"product_id"`
+	Product   *Product        `json:"product,omitempty"`
+	VariantID *uuid.UUID      `json:"variant_id,omitempty"`
+	Variant   *ProductVariant `json:"variant,omitempty"`
+	Quantity  int             `json:"quantity"`
+	Price     Money           `json:"price"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// AddToCartRequest ต้องระบุ VariantID เมื่อสินค้ามี variant
 type AddToCartRequest struct {
-	ProductID uuid.UUID `json:"product_id" validate:"required"`
-	Quantity  int       `json:"quantity" validate:"required,min=1"`
+	ProductID uuid.UUID  `json:"product_id" validate:"required"`
+	VariantID *uuid.UUID `json:"variant_id"`
+	Quantity  int        `json:"quantity" validate:"required,min=1"`
 }
 
 type UpdateCartItemRequest struct {
@@ -203,67 +268,127 @@ type UpdateCartItemRequest struct {
 }
 
 // Order Entity
+// ShippingFee คือค่าจัดส่งที่เก็บจริง (รวม VAT แล้ว) ซึ่งรวมอยู่ใน TotalPrice
+// ยอดก่อนภาษีและ VAT ของค่าจัดส่งรวมอยู่ใน Subtotal และ TaxTotal ตามลำดับ
+// TrackingNumber คือเลขพัสดุแรกของคำสั่งซื้อ ส่วนพัสดุทั้งหมดพร้อมจุดติดตามอยู่ใน Shipments
 type Order struct {
-	ID              uuid.UUID     `json:"id"`
-	UserID          uuid.UUID     `json:"user_id"`
-	User            *User         `json:"user,omitempty"`
-	OrderItems      []OrderItem   `json:"order_items"`
-	TotalPrice      float64       `json:"total_price"`
-	Status          string        `json:"status"`
-	PaymentMethod   string        `json:"payment_method"`
-	PaymentStatus   string        `json:"payment_status"`
-	ShippingMethod  string        `json:"shipping_method"`
-	ShippingStatus  string        `json:"shipping_status"`
-	ShippingAddress string        `json:"shipping_address"`
-	TrackingNumber  string        `json:"tracking_number"`
-	Notes           string        `json:"notes"`
-	Transactions    []Transaction `json:"transactions,omitempty"`
-	CreatedAt       time.Time     `json:"created_at"`
-	UpdatedAt       time.Time     `json:"updated_at"`
+	ID                    uuid.UUID          `json:"id"`
+	UserID                uuid.UUID          `json:"user_id"`
+	User                  *User              `json:"user,omitempty"`
+	OrderItems            []OrderItem        `json:"order_items"`
+	Subtotal              Money              `json:"subtotal"`
+	DiscountTotal         Money              `json:"discount_total"`
+	Discounts             []OrderDiscount    `json:"discounts,omitempty"`
+	TaxTotal              Money              `json:"tax_total"`
+	ShippingFee           Money              `json:"shipping_fee"`
+	TotalPrice            Money              `json:"total_price"`
+	BaseCurrency          string             `json:"base_currency"`
+	ExchangeRate          float64            `json:"exchange_rate"`
+	Status                OrderStatus        `json:"status"`
+	PaymentMethod         string             `json:"payment_method"`
+	PaymentStatus         PaymentStatus      `json:"payment_status"`
+	ShippingMethod        string             `json:"shipping_method"`
+	ShippingStatus        ShippingStatus     `json:"shipping_status"`
+	ShippingAddress       string             `json:"shipping_address"`
+	ShippingAddressDetail *PostalAddress     `json:"shipping_address_detail,omitempty"`
+	TrackingNumber        string             `json:"tracking_number"`
+	Shipments             []Shipment         `json:"shipments,omitempty"`
+	Notes                 string             `json:"notes"`
+	Transactions          []Transaction      `json:"transactions,omitempty"`
+	Timeline              []OrderStatusEvent `json:"timeline,omitempty"`
+	CreatedAt             time.Time          `json:"created_at"`
+	UpdatedAt             time.Time          `json:"updated_at"`
+}
+
+// OrderStatusEvent บันทึกการเปลี่ยนสถานะหนึ่งครั้งของคำสั่งซื้อ ใช้แสดงเป็น timeline
+type OrderStatusEvent struct {
+	ID         uuid.UUID  `json:"id"`
+	OrderID    uuid.UUID  `json:"order_id"`
+	Axis       StatusAxis `json:"axis"`
+	FromStatus string     `json:"from_status"`
+	ToStatus   string     `json:"to_status"`
+	ActorID    *uuid.UUID `json:"actor_id,omitempty"`
+	Actor      *User      `json:"actor,omitempty"`
+	Note       string     `json:"note"`
+	CreatedAt  time.Time  `json:"created_at"`
 }
 
 type OrderItem struct {
-	ID        uuid.UUID `json:"id"`
-	OrderID   uuid.UUID `json:"order_id"`
-	ProductID uuid.UUID `json:"product_id"`
-	Product   *Product  `json:"product,omitempty"`
-	Quantity  int       `json:"quantity"`
-	Price     float64   `json:"price"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-}
-
+	ID           uuid.UUID  `json:"id"`
+	OrderID      uuid.UUID  `json:"order_id"`
+	ProductID    uuid.UUID  `json:"product_id"`
+	Product      *Product   `json:"product,omitempty"`
+	VariantID    *uuid.UUID `json:"variant_id,omitempty"`
+	SKU          string     `json:"sku,omitempty"`
+	VariantName  string     `json:"variant_name,omitempty"`
+	Quantity     int        `json:"quantity"`
+	Price        Money      `json:"price"`
+	Discount     Money      `json:"discount"`
+	Subtotal     Money      `json:"subtotal"`
+	Tax          Money      `json:"tax"`
+	Total        Money      `json:"total"`
+	TaxRate      int        `json:"tax_rate"`
+	TaxInclusive bool       `json:"tax_inclusive"`
+	TaxExempt    bool       `json:"tax_exempt"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// CreateOrderRequest ShippingMethod คือรหัสของวิธีจัดส่ง
+// ถ้าระบุ AddressID ที่อยู่จากสมุดที่อยู่จะถูกคัดลอกไว้กับคำสั่งซื้อ และใช้แทน ShippingAddress กับ ShippingProvince
 type CreateOrderRequest struct {
-	PaymentMethod   string `json:"payment_method" validate:"required"`
-	ShippingMethod  string `json:"shipping_method" validate:"required"`
-	ShippingAddress string `json:"shipping_address" validate:"required"`
-	Notes           string `json:"notes"`
+	PaymentMethod    string     `json:"payment_method" validate:"required"`
+	ShippingMethod   string     `json:"shipping_method" validate:"required"`
+	AddressID        *uuid.UUID `json:"address_id"`
+	ShippingAddress  string     `json:"shipping_address" validate:"required_without=AddressID"`
+	ShippingProvince string     `json:"shipping_province"`
+	Notes            string     `json:"notes"`
+	Currency         string     `json:"currency" validate:"omitempty,len=3"`
+	CouponCode       string     `json:"coupon_code"`
+}
+
+// OrderFilter ใช้กรองรายการคำสั่งซื้อในหน้าจัดการของผู้ดูแลระบบ
+// ฟิลด์ที่เป็นค่าว่างหรือ zero value จะไม่ถูกนำมากรอง
+type OrderFilter struct {
+	Status         OrderStatus    `json:"status"`
+	PaymentStatus  PaymentStatus  `json:"payment_status"`
+	ShippingStatus ShippingStatus `json:"shipping_status"`
+	UserID         uuid.UUID      `json:"user_id"`
+	DateFrom       time.Time      `json:"date_from"`
+	DateTo         time.Time      `json:"date_to"`
+	Page           int            `json:"page"`
+	Limit          int            `json:"limit"`
 }
 
 type UpdateOrderStatusRequest struct {
-	Status string `json:"status" validate:"required"`
+	Status OrderStatus `json:"status" validate:"required"`
+	Note   string      `json:"note"`
 }
 
 type UpdatePaymentStatusRequest struct {
-	PaymentStatus string `json:"payment_status" validate:"required"`
+	PaymentStatus PaymentStatus `json:"payment_status" validate:"required"`
+	Note          string        `json:"note"`
 }
 
 type UpdateShippingStatusRequest struct {
-	ShippingStatus string `json:"shipping_status" validate:"required"`
-	TrackingNumber string `json:"tracking_number"`
+	ShippingStatus ShippingStatus `json:"shipping_status" validate:"required"`
+	TrackingNumber string         `json:"tracking_number"`
+	Note           string         `json:"note"`
 }
 
 // Transaction Entity
 type Transaction struct {
-	ID            uuid.UUID `json:"id"`
-	OrderID       uuid.UUID `json:"order_id"`
-	Amount        float64   `json:"amount"`
-	PaymentMethod string    `json:"payment_method"`
-	Status        string    `json:"status"`
-	TransactionID string    `json:"transaction_id"`
-	PaymentData   string    `json:"payment_data"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID             uuid.UUID         `json:"id"`
+	OrderID        uuid.UUID         `json:"order_id"`
+	Amount         Money             `json:"amount"`
+	PaymentMethod  string            `json:"payment_method"`
+	Status         TransactionStatus `json:"status"`
+	TransactionID  string            `json:"transaction_id"`
+	ProviderRef    string            `json:"provider_ref"`
+	PaymentPayload string            `json:"payment_payload,omitempty"`
+	PaymentData    string            `json:"payment_data"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
 }
 
 type CreatePaymentRequest struct {
@@ -278,15 +403,21 @@ type VerifyPaymentRequest struct {
 }
 
 // Stats Entity
+
+// SalesStats ยอดขาย *Sales เป็นยอดรวมภาษี (gross) และ *NetSales เป็นยอดก่อนภาษี (net)
 type SalesStats struct {
-	TotalSales    float64 `json:"total_sales"`
-	TotalOrders   int     `json:"total_orders"`
-	TodaySales    float64 `json:"today_sales"`
-	TodayOrders   int     `json:"today_orders"`
-	MonthlySales  float64 `json:"monthly_sales"`
-	MonthlyOrders int     `json:"monthly_orders"`
-	YearlySales   float64 `json:"yearly_sales"`
-	YearlyOrders  int     `json:"yearly_orders"`
+	TotalSales      Money `json:"total_sales"`
+	TotalNetSales   Money `json:"total_net_sales"`
+	TotalOrders     int   `json:"total_orders"`
+	TodaySales      Money `json:"today_sales"`
+	TodayNetSales   Money `json:"today_net_sales"`
+	TodayOrders     int   `json:"today_orders"`
+	MonthlySales    Money `json:"monthly_sales"`
+	MonthlyNetSales Money `json:"monthly_net_sales"`
+	MonthlyOrders   int   `json:"monthly_orders"`
+	YearlySales     Money `json:"yearly_sales"`
+	YearlyNetSales  Money `json:"yearly_net_sales"`
+	YearlyOrders    int   `json:"yearly_orders"`
 }
 
 type ProductStats struct {
@@ -311,11 +442,15 @@ type PaginationResponse struct {
 	TotalItems int `json:"total_items"`
 }
 
+// ApiResponse รูปแบบ response มาตรฐาน โดย Facets มีเฉพาะในผลการค้นหาสินค้า
 type ApiResponse struct {
 	Success    bool                `json:"success"`
 	Message    string              `json:"message"`
 	Data       interface{}         `json:"data,omitempty"`
 	Pagination *PaginationResponse `json:"pagination,omitempty"`
+	Facets     *SearchFacets       `json:"facets,omitempty"`
+	// Breadcrumbs เส้นทางของหมวดหมู่เมื่อแสดงสินค้าตามหมวดหมู่
+	Breadcrumbs []CategoryBreadcrumb `json:"breadcrumbs,omitempty"`
 }
 
 type ErrorResponse struct {
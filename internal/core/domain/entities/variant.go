@@ -0,0 +1,96 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductOption ประเภทตัวเลือกของสินค้า เช่น ขนาด หรือ สี พร้อมค่าที่เลือกได้ตามลำดับการแสดงผล
+type ProductOption struct {
+	ID        uuid.UUID            `json:"id"`
+	ProductID uuid.UUID            `json:"product_id"`
+	Name      string               `json:"name"`
+	Position  int                  `json:"position"`
+	Values    []ProductOptionValue `json:"values"`
+}
+
+// ProductOptionValue ค่าหนึ่งค่าของตัวเลือก เช่น M ของตัวเลือกขนาด
+type ProductOptionValue struct {
+	ID       uuid.UUID `json:"id"`
+	OptionID uuid.UUID `json:"option_id"`
+	Value    string    `json:"value"`
+	Position int       `json:"position"`
+}
+
+// ProductVariant สินค้าย่อยตามชุดค่าตัวเลือก (ค่าละหนึ่งตัวเลือก) ที่มี SKU และสต็อกของตัวเอง
+// Price คือราคาที่ใช้ขายจริง ส่วน PriceOverride เป็น nil เมื่อใช้ราคาของสินค้าหลัก
+type ProductVariant struct {
+	ID            uuid.UUID       `json:"id"`
+	ProductID     uuid.UUID       `json:"product_id"`
+	SKU           string          `json:"sku"`
+	Name          string          `json:"name"`
+	Price         Money           `json:"price"`
+	PriceOverride *Money          `json:"price_override,omitempty"`
+	Stock         int             `json:"stock"`
+	Image         string          `json:"image"`
+	Options       []VariantOption `json:"options"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// VariantOption ค่าตัวเลือกของ variant ในรูปชื่อตัวเลือกกับค่า เช่น ขนาด: M
+type VariantOption struct {
+	OptionID uuid.UUID `json:"option_id"`
+	Name     string    `json:"name"`
+	ValueID  uuid.UUID `json:"value_id"`
+	Value    string    `json:"value"`
+}
+
+// VariantName ชื่อของ variant จากค่าตัวเลือกเรียงตามลำดับตัวเลือก เช่น "M / แดง"
+func VariantName(options []VariantOption) string {
+	values := make([]string, len(options))
+	for i, option := range options {
+		values[i] = option.Value
+	}
+	return strings.Join(values, " / ")
+}
+
+// CreateProductOptionRequest คำขอเพิ่มประเภทตัวเลือกพร้อมค่าที่เลือกได้
+type CreateProductOptionRequest struct {
+	Name   string   `json:"name" validate:"required,max=50"`
+	Values []string `json:"values" validate:"required,min=1,dive,required,max=50"`
+}
+
+// CreateVariantRequest คำขอเพิ่ม variant ต้องเลือกค่าครบทุกตัวเลือกของสินค้า ตัวเลือกละหนึ่งค่า
+// Price ไม่ระบุเมื่อใช้ราคาของสินค้าหลัก ถ้าระบุต้องเป็นสกุลเงินเดียวกับสินค้า
+type CreateVariantRequest struct {
+	SKU            string      `json:"sku" validate:"required,max=64"`
+	Price          *Money      `json:"price"`
+	Stock          int         `json:"stock" validate:"min=0"`
+	Image          string      `json:"image"`
+	OptionValueIDs []uuid.UUID `json:"option_value_ids" validate:"required,min=1"`
+}
+
+// UpdateVariantRequest คำขอแก้ไข variant ส่ง ClearPrice เพื่อกลับไปใช้ราคาของสินค้าหลัก
+// สต็อกของ variant เปลี่ยนผ่านการปรับสต็อกใน inventory เท่านั้น
+type UpdateVariantRequest struct {
+	SKU        *string `json:"sku" validate:"omitempty,max=64"`
+	Price      *Money  `json:"price"`
+	ClearPrice bool    `json:"clear_price"`
+	Image      *string `json:"image"`
+}
+
+// Variant errors
+var (
+	ErrVariantNotFound       = errors.New("ไม่พบ variant ของสินค้า")
+	ErrProductOptionNotFound = errors.New("ไม่พบตัวเลือกของสินค้า")
+	ErrVariantRequired       = errors.New("สินค้านี้มีหลายตัวเลือก ต้องระบุ variant")
+	ErrInvalidVariantOptions = errors.New("ค่าตัวเลือกของ variant ไม่ถูกต้อง")
+	ErrDuplicateSKU          = errors.New("SKU นี้ถูกใช้แล้ว")
+	ErrVariantStockConflict  = errors.New("ต้องปรับสต็อกของสินค้าหลักเป็น 0 ก่อนเพิ่ม variant แรก")
+	ErrProductOptionInUse    = errors.New("ตัวเลือกนี้ถูกใช้โดย variant อยู่")
+	ErrProductHasVariants    = errors.New("ต้องลบ variant ทั้งหมดก่อนเพิ่มตัวเลือกใหม่")
+)
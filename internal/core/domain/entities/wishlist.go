@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WishlistItem สินค้าหนึ่งรายการในรายการสินค้าที่อยากได้ของผู้ใช้
+// NotifyBackInStock และ NotifyPriceDrop คือการแจ้งเตือนที่ผู้ใช้เลือกรับสำหรับสินค้านี้
+type WishlistItem struct {
+	ProductID         uuid.UUID `json:"product_id"`
+	Product           *Product  `json:"product,omitempty"`
+	NotifyBackInStock bool      `json:"notify_back_in_stock"`
+	NotifyPriceDrop   bool      `json:"notify_price_drop"`
+	AddedAt           time.Time `json:"added_at"`
+}
+
+// AddToWishlistRequest เพิ่มสินค้าลงรายการที่อยากได้ ถ้ามีอยู่แล้วจะอัพเดทการแจ้งเตือนแทน
+type AddToWishlistRequest struct {
+	ProductID         uuid.UUID `json:"product_id" validate:"required"`
+	NotifyBackInStock bool      `json:"notify_back_in_stock"`
+	NotifyPriceDrop   bool      `json:"notify_price_drop"`
+}
+
+// MoveToCartRequest จำนวนสินค้าที่ย้ายจากรายการที่อยากได้ไปตะกร้า ไม่ระบุคือ 1 ชิ้น
+// สินค้าที่มี variant ต้องระบุ VariantID ที่จะใส่ตะกร้า
+type MoveToCartRequest struct {
+	VariantID *uuid.UUID `json:"variant_id"`
+	Quantity  int        `json:"quantity" validate:"omitempty,min=1"`
+}
+
+// WishlistNotificationKind ประเภทของการแจ้งเตือนสินค้าในรายการที่อยากได้
+type WishlistNotificationKind string
+
+const (
+	WishlistNotificationBackInStock WishlistNotificationKind = "back_in_stock"
+	WishlistNotificationPriceDrop   WishlistNotificationKind = "price_drop"
+)
+
+// WishlistNotification การแจ้งเตือนหนึ่งรายการถึงผู้ใช้ เมื่อสินค้าที่อยากได้กลับมามีสต็อกหรือลดราคา
+// PreviousPrice คือราคาล่าสุดที่ระบบเห็นก่อนลดราคา ใช้เฉพาะ price_drop
+type WishlistNotification struct {
+	Kind          WishlistNotificationKind `json:"kind"`
+	UserID        uuid.UUID                `json:"user_id"`
+	Email         string                   `json:"email"`
+	FirstName     string                   `json:"first_name"`
+	ProductID     uuid.UUID                `json:"product_id"`
+	ProductName   string                   `json:"product_name"`
+	Stock         int                      `json:"stock"`
+	Price         Money                    `json:"price"`
+	PreviousPrice *Money                   `json:"previous_price,omitempty"`
+}
+
+// Wishlist errors
+var (
+	ErrWishlistItemNotFound = errors.New("ไม่พบสินค้าในรายการที่อยากได้")
+)
@@ -0,0 +1,14 @@
+package gateways
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// CarrierClient interface สำหรับเชื่อมต่อผู้ให้บริการขนส่ง
+// Track คืนเหตุการณ์ทั้งหมดของพัสดุตั้งแต่ต้น ผู้เรียกต้องตัดเหตุการณ์ที่เคยบันทึกแล้วออกเองด้วย EventID
+type CarrierClient interface {
+	CreateShipment(ctx context.Context, req *entities.CarrierShipmentRequest) (*entities.CarrierShipment, error)
+	Track(ctx context.Context, trackingNumber string) ([]entities.CarrierEvent, error)
+}
@@ -0,0 +1,16 @@
+package gateways
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// PaymentGateway interface สำหรับเชื่อมต่อผู้ให้บริการรับชำระเงิน
+// providerRef คือเลขอ้างอิงที่ gateway คืนมาจาก CreateIntent
+type PaymentGateway interface {
+	CreateIntent(ctx context.Context, req *entities.PaymentIntentRequest) (*entities.PaymentIntent, error)
+	Capture(ctx context.Context, providerRef string, amount entities.Money) (*entities.PaymentResult, error)
+	Refund(ctx context.Context, providerRef string, amount entities.Money) (*entities.PaymentResult, error)
+	Verify(ctx context.Context, providerRef string) (*entities.PaymentResult, error)
+}
@@ -0,0 +1,13 @@
+package notifiers
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// LowStockNotifier interface สำหรับส่งการแจ้งเตือนเมื่อสินค้าสต็อกต่ำ
+// แต่ละ adapter (log, webhook, email) ต้อง implement interface นี้
+type LowStockNotifier interface {
+	NotifyLowStock(ctx context.Context, products []*entities.LowStockProduct) error
+}
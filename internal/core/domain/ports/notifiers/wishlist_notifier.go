@@ -0,0 +1,12 @@
+package notifiers
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// WishlistNotifier interface สำหรับแจ้งผู้ใช้เมื่อสินค้าในรายการที่อยากได้กลับมามีสต็อกหรือลดราคา
+type WishlistNotifier interface {
+	NotifyWishlist(ctx context.Context, notifications []*entities.WishlistNotification) error
+}
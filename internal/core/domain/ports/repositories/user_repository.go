@@ -51,22 +51,42 @@ type PermissionRepository interface {
 type CategoryRepository interface {
 	Create(ctx context.Context, category *entities.CreateCategoryRequest) (*entities.Category, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Category, error)
+	GetBySlug(ctx context.Context, slug string) (*entities.Category, error)
 	GetAll(ctx context.Context, page, limit int) ([]*entities.Category, int, error)
+	GetTree(ctx context.Context) ([]*entities.Category, error)
 	Update(ctx context.Context, id uuid.UUID, category *entities.UpdateCategoryRequest) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID) error
 }
 
 // ProductRepository interface สำหรับการจัดการสินค้า
 type ProductRepository interface {
-	Create(ctx context.Context, product *entities.CreateProductRequest) (*entities.Product, error)
+	Create(ctx context.Context, product *entities.CreateProductRequest, actorID uuid.UUID) (*entities.Product, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Product, error)
 	GetAll(ctx context.Context, page, limit int) ([]*entities.Product, int, error)
 	GetByCategory(ctx context.Context, categoryID uuid.UUID, page, limit int) ([]*entities.Product, int, error)
-	Search(ctx context.Context, req *entities.ProductSearchRequest) ([]*entities.Product, int, error)
-	Update(ctx context.Context, id uuid.UUID, product *entities.UpdateProductRequest) error
+	Search(ctx context.Context, req *entities.ProductSearchRequest) ([]*entities.Product, *entities.SearchFacets, int, error)
+	Update(ctx context.Context, id uuid.UUID, product *entities.UpdateProductRequest, actorID uuid.UUID) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	UpdateStock(ctx context.Context, id uuid.UUID, stock int) error
-	GetLowStockProducts(ctx context.Context, threshold int) ([]*entities.Product, error)
+	GetLowStockProducts(ctx context.Context, filter *entities.LowStockFilter) ([]*entities.LowStockProduct, int, error)
+}
+
+// ProductVariantRepository interface สำหรับการจัดการตัวเลือกและ variant ของสินค้า
+type ProductVariantRepository interface {
+	CreateOption(ctx context.Context, productID uuid.UUID, req *entities.CreateProductOptionRequest) (*entities.ProductOption, error)
+	DeleteOption(ctx context.Context, productID, optionID uuid.UUID) error
+	GetByProductID(ctx context.Context, productID uuid.UUID) ([]*entities.ProductVariant, error)
+	GetByID(ctx context.Context, productID, variantID uuid.UUID) (*entities.ProductVariant, error)
+	Create(ctx context.Context, productID, actorID uuid.UUID, req *entities.CreateVariantRequest) (*entities.ProductVariant, error)
+	Update(ctx context.Context, productID, variantID uuid.UUID, req *entities.UpdateVariantRequest) (*entities.ProductVariant, error)
+	Delete(ctx context.Context, productID, variantID, actorID uuid.UUID) error
+}
+
+// InventoryRepository interface สำหรับการจัดการ inventory ledger
+// การเปลี่ยนสต็อกทุกครั้งจะถูกบันทึกเป็น InventoryMovement
+type InventoryRepository interface {
+	Adjust(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, delta int, reason entities.InventoryReason, referenceID *uuid.UUID, actorID uuid.UUID, note string) (*entities.InventoryMovement, error)
+	GetByProductID(ctx context.Context, productID uuid.UUID, page, limit int) ([]*entities.InventoryMovement, int, error)
+	RebuildStock(ctx context.Context, productID uuid.UUID) (*entities.StockRebuildResult, error)
 }
 
 // CartRepository interface สำหรับการจัดการตะกร้าสินค้า
@@ -77,6 +97,7 @@ type CartRepository interface {
 	RemoveItem(ctx context.Context, cartItemID uuid.UUID) error
 	ClearCart(ctx context.Context, userID uuid.UUID) error
 	GetCartItem(ctx context.Context, cartItemID uuid.UUID) (*entities.CartItem, error)
+	SetCouponCode(ctx context.Context, userID uuid.UUID, code string) error
 }
 
 // OrderRepository interface สำหรับการจัดการคำสั่งซื้อ
@@ -84,11 +105,11 @@ type OrderRepository interface {
 	Create(ctx context.Context, userID uuid.UUID, order *entities.CreateOrderRequest) (*entities.Order, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Order, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entities.Order, int, error)
-	GetAll(ctx context.Context, page, limit int) ([]*entities.Order, int, error)
-	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
-	UpdatePaymentStatus(ctx context.Context, id uuid.UUID, paymentStatus string) error
-	UpdateShippingStatus(ctx context.Context, id uuid.UUID, shippingStatus, trackingNumber string) error
-	Cancel(ctx context.Context, id uuid.UUID) error
+	GetAll(ctx context.Context, filter *entities.OrderFilter) ([]*entities.Order, int, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, status entities.OrderStatus, actorID uuid.UUID, note string) error
+	UpdatePaymentStatus(ctx context.Context, id uuid.UUID, paymentStatus entities.PaymentStatus, actorID uuid.UUID, note string) error
+	UpdateShippingStatus(ctx context.Context, id uuid.UUID, shippingStatus entities.ShippingStatus, trackingNumber string, actorID uuid.UUID, note string) error
+	Cancel(ctx context.Context, id uuid.UUID, actorID uuid.UUID, note string) error
 }
 
 // TransactionRepository interface สำหรับการจัดการธุรกรรม
@@ -97,13 +118,109 @@ type TransactionRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error)
 	GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entities.Transaction, error)
 	GetByTransactionID(ctx context.Context, transactionID string) (*entities.Transaction, error)
-	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
+	UpdateStatus(ctx context.Context, id uuid.UUID, status entities.TransactionStatus, event *entities.PaymentEvent) error
+	AttachIntent(ctx context.Context, id uuid.UUID, providerRef, payload string) error
 	Cancel(ctx context.Context, id uuid.UUID) error
 }
 
+// RefundRepository interface สำหรับการจัดการการคืนเงิน
+// Create จองยอดคืนเงินไว้เป็น pending ก่อนเรียก payment gateway แล้วจึง Complete หรือ Fail ตามผลลัพธ์
+type RefundRepository interface {
+	Create(ctx context.Context, transactionID uuid.UUID, req *entities.CreateRefundRequest, actorID uuid.UUID) (*entities.Refund, error)
+	Complete(ctx context.Context, id uuid.UUID, providerRef string, actorID uuid.UUID) (*entities.Refund, error)
+	Fail(ctx context.Context, id uuid.UUID, reason string) error
+	GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*entities.Refund, error)
+}
+
+// PromotionRepository interface สำหรับการจัดการโปรโมชันและคูปองส่วนลด
+type PromotionRepository interface {
+	Create(ctx context.Context, req *entities.CreatePromotionRequest) (*entities.Promotion, error)
+	GetAll(ctx context.Context, page, limit int) ([]*entities.Promotion, int, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Promotion, error)
+	GetByCode(ctx context.Context, code string) (*entities.Promotion, error)
+	Update(ctx context.Context, id uuid.UUID, req *entities.UpdatePromotionRequest) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	CountRedemptions(ctx context.Context, promotionID, userID uuid.UUID) (total int, byUser int, err error)
+}
+
+// TaxRuleRepository interface สำหรับการจัดการกฎภาษี
+type TaxRuleRepository interface {
+	Create(ctx context.Context, req *entities.CreateTaxRuleRequest) (*entities.TaxRule, error)
+	GetAll(ctx context.Context) ([]*entities.TaxRule, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.TaxRule, error)
+	Update(ctx context.Context, id uuid.UUID, req *entities.UpdateTaxRuleRequest) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// ExchangeRateRepository interface สำหรับการจัดการอัตราแลกเปลี่ยน
+type ExchangeRateRepository interface {
+	List(ctx context.Context) ([]*entities.ExchangeRate, error)
+	Upsert(ctx context.Context, rate *entities.ExchangeRate) (*entities.ExchangeRate, error)
+	Delete(ctx context.Context, currency string) error
+}
+
 // StatsRepository interface สำหรับสถิติ
 type StatsRepository interface {
 	GetSalesStats(ctx context.Context) (*entities.SalesStats, error)
 	GetProductStats(ctx context.Context) (*entities.ProductStats, error)
 	GetUserStats(ctx context.Context) (*entities.UserStats, error)
 }
+
+// ShippingMethodRepository interface สำหรับการจัดการวิธีจัดส่ง
+// GetAll ส่ง activeOnly เป็น true เพื่อดึงเฉพาะวิธีจัดส่งที่เปิดให้ลูกค้าเลือก
+type ShippingMethodRepository interface {
+	Create(ctx context.Context, req *entities.CreateShippingMethodRequest) (*entities.ShippingMethod, error)
+	GetAll(ctx context.Context, activeOnly bool) ([]*entities.ShippingMethod, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.ShippingMethod, error)
+	Update(ctx context.Context, id uuid.UUID, req *entities.UpdateShippingMethodRequest) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// AddressRepository interface สำหรับการจัดการสมุดที่อยู่ ทุก method จำกัดเฉพาะที่อยู่ของ userID ที่ระบุ
+type AddressRepository interface {
+	Create(ctx context.Context, userID uuid.UUID, req *entities.CreateAddressRequest) (*entities.Address, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Address, error)
+	GetByID(ctx context.Context, userID, id uuid.UUID) (*entities.Address, error)
+	Update(ctx context.Context, userID, id uuid.UUID, req *entities.UpdateAddressRequest) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	SetDefault(ctx context.Context, userID, id uuid.UUID) error
+}
+
+// ShipmentRepository interface สำหรับการจัดการพัสดุของคำสั่งซื้อ
+// GetActive คืนพัสดุที่ยังไม่ถึงปลายทางหรือถูกตีกลับ เพื่อใช้ติดตามสถานะกับผู้ให้บริการขนส่ง
+// AddCheckpoints ข้ามจุดติดตามที่มี EventID ซ้ำกับที่บันทึกไว้แล้ว
+type ShipmentRepository interface {
+	Create(ctx context.Context, shipment *entities.Shipment) (*entities.Shipment, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Shipment, error)
+	GetActive(ctx context.Context) ([]*entities.Shipment, error)
+	AddCheckpoints(ctx context.Context, id uuid.UUID, status entities.ShippingStatus, checkpoints []entities.TrackingCheckpoint) error
+}
+
+// ReturnRepository interface สำหรับการจัดการคำขอคืนสินค้า
+// การเปลี่ยนสถานะระบุสถานะเดิม (from) ด้วยเสมอ ถ้าสถานะในฐานข้อมูลเปลี่ยนไปแล้วจะคืน ErrReturnStatusConflict
+// ClaimRefund จองการคืนเงินของคำขอก่อนเรียก payment gateway และคืน false ถ้าคำขอผูกการคืนเงินไว้แล้ว
+// AttachRefund หรือ ReleaseRefund ปล่อยการจองเมื่อคืนเงินสำเร็จหรือไม่สำเร็จ
+// Complete คืนสต็อกของสินค้าที่ผ่านการตรวจและนำกลับเข้าสต็อกได้ใน transaction เดียวกับการปิดคำขอ
+type ReturnRepository interface {
+	Create(ctx context.Context, userID uuid.UUID, req *entities.CreateReturnRequest) (*entities.Return, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Return, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entities.Return, int, error)
+	GetAll(ctx context.Context, status entities.ReturnStatus, page, limit int) ([]*entities.Return, int, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, from, to entities.ReturnStatus, actorID uuid.UUID, note string) error
+	Inspect(ctx context.Context, id uuid.UUID, req *entities.InspectReturnRequest, actorID uuid.UUID) error
+	ClaimRefund(ctx context.Context, id uuid.UUID) (bool, error)
+	AttachRefund(ctx context.Context, id, refundID uuid.UUID) error
+	ReleaseRefund(ctx context.Context, id uuid.UUID) error
+	Complete(ctx context.Context, id, actorID uuid.UUID, note string) error
+}
+
+// WishlistRepository interface สำหรับรายการสินค้าที่อยากได้ของผู้ใช้
+// PendingNotifications หาการแจ้งเตือนที่ต้องส่งของสินค้าที่ระบุ และ MarkNotified บันทึกว่าส่งแล้วเพื่อไม่ให้แจ้งซ้ำ
+type WishlistRepository interface {
+	Add(ctx context.Context, userID uuid.UUID, req *entities.AddToWishlistRequest) (*entities.WishlistItem, error)
+	Get(ctx context.Context, userID, productID uuid.UUID) (*entities.WishlistItem, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entities.WishlistItem, int, error)
+	Remove(ctx context.Context, userID, productID uuid.UUID) error
+	PendingNotifications(ctx context.Context, productIDs []uuid.UUID) ([]*entities.WishlistNotification, error)
+	MarkNotified(ctx context.Context, notifications []*entities.WishlistNotification) error
+}
@@ -0,0 +1,16 @@
+package search
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+// ProductSearchIndex interface สำหรับ search engine ที่จับคู่คำค้นกับสินค้า
+// ProductService ส่งสินค้าเข้ามาทุกครั้งที่สร้าง แก้ไข หรือลบ ส่วนตัวกรอง facet และการแบ่งหน้ายังทำในฐานข้อมูล
+type ProductSearchIndex interface {
+	Index(ctx context.Context, product *entities.Product) error
+	Delete(ctx context.Context, productID uuid.UUID) error
+	Query(ctx context.Context, query *entities.ProductSearchQuery) (*entities.ProductSearchResult, error)
+}
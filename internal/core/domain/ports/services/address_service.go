@@ -0,0 +1,18 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+// AddressService interface สำหรับการจัดการสมุดที่อยู่ของผู้ใช้
+type AddressService interface {
+	CreateAddress(ctx context.Context, userID uuid.UUID, req *entities.CreateAddressRequest) (*entities.Address, error)
+	GetAddresses(ctx context.Context, userID uuid.UUID) ([]*entities.Address, error)
+	GetAddress(ctx context.Context, userID, id uuid.UUID) (*entities.Address, error)
+	UpdateAddress(ctx context.Context, userID, id uuid.UUID, req *entities.UpdateAddressRequest) (*entities.Address, error)
+	DeleteAddress(ctx context.Context, userID, id uuid.UUID) error
+	SetDefaultAddress(ctx context.Context, userID, id uuid.UUID) (*entities.Address, error)
+}
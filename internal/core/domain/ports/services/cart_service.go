@@ -11,7 +11,9 @@ import (
 type CartService interface {
 	GetCart(ctx context.Context, userID uuid.UUID) (*entities.Cart, error)
 	AddToCart(ctx context.Context, userID uuid.UUID, req *entities.AddToCartRequest) error
-	UpdateCartItem(ctx context.Context, cartItemID uuid.UUID, req *entities.UpdateCartItemRequest) error
-	RemoveFromCart(ctx context.Context, cartItemID uuid.UUID) error
+	UpdateCartItem(ctx context.Context, userID, cartItemID uuid.UUID, req *entities.UpdateCartItemRequest) error
+	RemoveFromCart(ctx context.Context, userID, cartItemID uuid.UUID) error
 	ClearCart(ctx context.Context, userID uuid.UUID) error
+	ApplyCoupon(ctx context.Context, userID uuid.UUID, code string) (*entities.Cart, error)
+	RemoveCoupon(ctx context.Context, userID uuid.UUID) (*entities.Cart, error)
 }
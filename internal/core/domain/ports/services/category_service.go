@@ -11,7 +11,14 @@ import (
 type CategoryService interface {
 	CreateCategory(ctx context.Context, req *entities.CreateCategoryRequest) (*entities.Category, error)
 	GetCategories(ctx context.Context, page, limit int) ([]*entities.Category, *entities.PaginationResponse, error)
+	GetCategoryTree(ctx context.Context) ([]*entities.Category, error)
 	GetCategoryByID(ctx context.Context, id uuid.UUID) (*entities.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*entities.Category, error)
 	UpdateCategory(ctx context.Context, id uuid.UUID, req *entities.UpdateCategoryRequest) error
-	DeleteCategory(ctx context.Context, id uuid.UUID) error
+	DeleteCategory(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID) error
+}
+
+// CategoryIndexer ส่งสินค้าในหมวดหมู่เข้า search index ใหม่ เมื่อข้อมูลหมวดหมู่ที่ index เก็บไว้เปลี่ยน
+type CategoryIndexer interface {
+	ReindexCategory(ctx context.Context, categoryID uuid.UUID)
 }
@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+)
+
+// CurrencyService interface สำหรับการจัดการอัตราแลกเปลี่ยนและการแสดงราคาหลายสกุลเงิน
+type CurrencyService interface {
+	ListRates(ctx context.Context) ([]*entities.ExchangeRate, error)
+	SetRate(ctx context.Context, currency string, req *entities.SetExchangeRateRequest) (*entities.ExchangeRate, error)
+	DeleteRate(ctx context.Context, currency string) error
+	GetRateTable(ctx context.Context) (entities.ExchangeRateTable, error)
+	LocalizeProducts(ctx context.Context, currency string, products ...*entities.Product) error
+}
@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+// InventoryService interface สำหรับการจัดการสต็อกสินค้าผ่าน inventory ledger
+type InventoryService interface {
+	AdjustStock(ctx context.Context, productID, actorID uuid.UUID, req *entities.AdjustStockRequest) (*entities.InventoryMovement, error)
+	GetMovements(ctx context.Context, productID uuid.UUID, page, limit int) ([]*entities.InventoryMovement, *entities.PaginationResponse, error)
+	RebuildStock(ctx context.Context, productID uuid.UUID) (*entities.StockRebuildResult, error)
+	GetLowStockProducts(ctx context.Context, filter *entities.LowStockFilter) ([]*entities.LowStockProduct, *entities.PaginationResponse, error)
+}
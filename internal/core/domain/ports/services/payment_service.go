@@ -13,4 +13,5 @@ type PaymentService interface {
 	GetPaymentByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error)
 	VerifyPayment(ctx context.Context, id uuid.UUID, req *entities.VerifyPaymentRequest) error
 	CancelPayment(ctx context.Context, id uuid.UUID) error
+	HandleWebhook(ctx context.Context, provider string, payload []byte, signature string) error
 }
@@ -9,11 +9,14 @@ import (
 
 // ProductService interface สำหรับการจัดการสินค้า
 type ProductService interface {
-	CreateProduct(ctx context.Context, req *entities.CreateProductRequest) (*entities.Product, error)
+	CreateProduct(ctx context.Context, actorID uuid.UUID, req *entities.CreateProductRequest) (*entities.Product, error)
 	GetProducts(ctx context.Context, page, limit int) ([]*entities.Product, *entities.PaginationResponse, error)
 	GetProductByID(ctx context.Context, id uuid.UUID) (*entities.Product, error)
-	GetProductsByCategory(ctx context.Context, categoryID uuid.UUID, page, limit int) ([]*entities.Product, *entities.PaginationResponse, error)
-	SearchProducts(ctx context.Context, req *entities.ProductSearchRequest) ([]*entities.Product, *entities.PaginationResponse, error)
-	UpdateProduct(ctx context.Context, id uuid.UUID, req *entities.UpdateProductRequest) error
+	GetProductsByCategory(ctx context.Context, categoryID uuid.UUID, page, limit int) ([]*entities.Product, *entities.Category, *entities.PaginationResponse, error)
+	SearchProducts(ctx context.Context, req *entities.ProductSearchRequest) ([]*entities.Product, *entities.SearchFacets, *entities.PaginationResponse, error)
+	UpdateProduct(ctx context.Context, id, actorID uuid.UUID, req *entities.UpdateProductRequest) error
 	DeleteProduct(ctx context.Context, id uuid.UUID) error
+	SuggestProducts(ctx context.Context, text string, limit int) ([]string, error)
+	RebuildSearchIndex(ctx context.Context) (int, error)
+	ReindexCategory(ctx context.Context, categoryID uuid.UUID)
 }
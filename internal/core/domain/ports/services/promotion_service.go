@@ -0,0 +1,17 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+// PromotionService interface สำหรับการจัดการโปรโมชันและคูปองส่วนลด
+type PromotionService interface {
+	CreatePromotion(ctx context.Context, req *entities.CreatePromotionRequest) (*entities.Promotion, error)
+	GetPromotions(ctx context.Context, page, limit int) ([]*entities.Promotion, *entities.PaginationResponse, error)
+	GetPromotionByID(ctx context.Context, id uuid.UUID) (*entities.Promotion, error)
+	UpdatePromotion(ctx context.Context, id uuid.UUID, req *entities.UpdatePromotionRequest) (*entities.Promotion, error)
+	DeletePromotion(ctx context.Context, id uuid.UUID) error
+}
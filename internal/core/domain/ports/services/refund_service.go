@@ -0,0 +1,14 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+// RefundService interface สำหรับการคืนเงินของธุรกรรมที่ชำระเงินสำเร็จแล้ว
+type RefundService interface {
+	CreateRefund(ctx context.Context, transactionID, actorID uuid.UUID, req *entities.CreateRefundRequest) (*entities.Refund, error)
+	GetRefunds(ctx context.Context, transactionID uuid.UUID) ([]*entities.Refund, error)
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+// ReturnService interface สำหรับคำขอคืนสินค้า (RMA) ตั้งแต่ลูกค้ายื่นคำขอจนถึงคืนสต็อกและคืนเงิน
+// InspectReturn จะคืนเงินและปิดคำขอต่อทันที ถ้าคืนเงินไม่สำเร็จคำขอจะค้างอยู่ที่ inspected
+// และลองใหม่ได้ด้วย RefundReturn
+type ReturnService interface {
+	CreateReturn(ctx context.Context, userID uuid.UUID, req *entities.CreateReturnRequest) (*entities.Return, error)
+	GetUserReturns(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entities.Return, *entities.PaginationResponse, error)
+	GetUserReturn(ctx context.Context, userID, id uuid.UUID) (*entities.Return, error)
+	CancelReturn(ctx context.Context, userID, id uuid.UUID) (*entities.Return, error)
+
+	GetReturns(ctx context.Context, status entities.ReturnStatus, page, limit int) ([]*entities.Return, *entities.PaginationResponse, error)
+	GetReturn(ctx context.Context, id uuid.UUID) (*entities.Return, error)
+	ApproveReturn(ctx context.Context, id, actorID uuid.UUID, note string) (*entities.Return, error)
+	RejectReturn(ctx context.Context, id, actorID uuid.UUID, note string) (*entities.Return, error)
+	ReceiveReturn(ctx context.Context, id, actorID uuid.UUID, note string) (*entities.Return, error)
+	InspectReturn(ctx context.Context, id, actorID uuid.UUID, req *entities.InspectReturnRequest) (*entities.Return, error)
+	RefundReturn(ctx context.Context, id, actorID uuid.UUID) (*entities.Return, error)
+}
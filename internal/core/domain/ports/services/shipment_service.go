@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+// ShipmentService interface สำหรับการจัดส่งพัสดุของคำสั่งซื้อและติดตามสถานะกับผู้ให้บริการขนส่ง
+// SyncShipments ดึงเหตุการณ์ของพัสดุทุกกล่องที่ยังไม่ถึงปลายทาง ใช้โดย ShipmentTracker
+type ShipmentService interface {
+	CreateShipment(ctx context.Context, orderID, actorID uuid.UUID, req *entities.CreateShipmentRequest) (*entities.Shipment, error)
+	SyncShipment(ctx context.Context, id uuid.UUID) (*entities.Shipment, error)
+	SyncShipments(ctx context.Context) error
+}
@@ -0,0 +1,8 @@
+package services
+
+import "context"
+
+// ShipmentTracker interface สำหรับงานเบื้องหลังที่ติดตามสถานะพัสดุกับผู้ให้บริการขนส่งเป็นระยะ
+type ShipmentTracker interface {
+	Start(ctx context.Context)
+}
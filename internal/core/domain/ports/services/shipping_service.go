@@ -0,0 +1,18 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+// ShippingService interface สำหรับการจัดการวิธีจัดส่งและคำนวณค่าจัดส่ง
+type ShippingService interface {
+	CreateMethod(ctx context.Context, req *entities.CreateShippingMethodRequest) (*entities.ShippingMethod, error)
+	GetMethods(ctx context.Context, activeOnly bool) ([]*entities.ShippingMethod, error)
+	GetMethodByID(ctx context.Context, id uuid.UUID) (*entities.ShippingMethod, error)
+	UpdateMethod(ctx context.Context, id uuid.UUID, req *entities.UpdateShippingMethodRequest) (*entities.ShippingMethod, error)
+	DeleteMethod(ctx context.Context, id uuid.UUID) error
+	QuoteCart(ctx context.Context, userID uuid.UUID, req *entities.ShippingQuoteRequest) ([]*entities.ShippingQuote, error)
+}
@@ -0,0 +1,14 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// StockMonitor interface สำหรับงานเบื้องหลังที่ตรวจจุดสั่งซื้อเพิ่มหลังจากสต็อกเปลี่ยน
+// service ที่ทำให้สต็อกเปลี่ยนต้องเรียก StockChanged ทุกครั้ง
+type StockMonitor interface {
+	Start(ctx context.Context)
+	StockChanged(productIDs ...uuid.UUID)
+}
@@ -0,0 +1,17 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+// TaxService interface สำหรับการจัดการกฎภาษีมูลค่าเพิ่ม
+type TaxService interface {
+	CreateTaxRule(ctx context.Context, req *entities.CreateTaxRuleRequest) (*entities.TaxRule, error)
+	GetTaxRules(ctx context.Context) ([]*entities.TaxRule, error)
+	GetTaxRuleByID(ctx context.Context, id uuid.UUID) (*entities.TaxRule, error)
+	UpdateTaxRule(ctx context.Context, id uuid.UUID, req *entities.UpdateTaxRuleRequest) (*entities.TaxRule, error)
+	DeleteTaxRule(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+// VariantService interface สำหรับการจัดการตัวเลือก (ขนาด สี) และ variant ของสินค้า
+// สต็อกของ variant ปรับผ่าน InventoryService โดยระบุ VariantID
+type VariantService interface {
+	CreateOption(ctx context.Context, productID uuid.UUID, req *entities.CreateProductOptionRequest) (*entities.ProductOption, error)
+	DeleteOption(ctx context.Context, productID, optionID uuid.UUID) error
+	GetVariants(ctx context.Context, productID uuid.UUID) ([]*entities.ProductVariant, error)
+	CreateVariant(ctx context.Context, productID, actorID uuid.UUID, req *entities.CreateVariantRequest) (*entities.ProductVariant, error)
+	UpdateVariant(ctx context.Context, productID, variantID uuid.UUID, req *entities.UpdateVariantRequest) (*entities.ProductVariant, error)
+	DeleteVariant(ctx context.Context, productID, variantID, actorID uuid.UUID) error
+}
@@ -0,0 +1,10 @@
+package services
+
+import "github.com/google/uuid"
+
+// WishlistMonitor interface สำหรับงานเบื้องหลังที่แจ้งผู้ใช้เมื่อสินค้าในรายการที่อยากได้กลับมามีสต็อกหรือลดราคา
+// รับการเปลี่ยนสต็อกแบบเดียวกับ StockMonitor และ service ที่เปลี่ยนราคาสินค้าต้องเรียก PriceChanged
+type WishlistMonitor interface {
+	StockMonitor
+	PriceChanged(productIDs ...uuid.UUID)
+}
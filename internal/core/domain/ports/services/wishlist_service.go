@@ -0,0 +1,17 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/google/uuid"
+)
+
+// WishlistService interface สำหรับรายการสินค้าที่อยากได้ของผู้ใช้
+// MoveToCart เพิ่มสินค้าลงตะกร้าผ่าน CartService แล้วจึงเอาออกจากรายการที่อยากได้
+type WishlistService interface {
+	GetWishlist(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entities.WishlistItem, *entities.PaginationResponse, error)
+	AddToWishlist(ctx context.Context, userID uuid.UUID, req *entities.AddToWishlistRequest) (*entities.WishlistItem, error)
+	RemoveFromWishlist(ctx context.Context, userID, productID uuid.UUID) error
+	MoveToCart(ctx context.Context, userID, productID uuid.UUID, req *entities.MoveToCartRequest) (*entities.Cart, error)
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/google/uuid"
+)
+
+type addressService struct {
+	addressRepo repositories.AddressRepository
+}
+
+func NewAddressService(addressRepo repositories.AddressRepository) services.AddressService {
+	return &addressService{
+		addressRepo: addressRepo,
+	}
+}
+
+func (s *addressService) CreateAddress(ctx context.Context, userID uuid.UUID, req *entities.CreateAddressRequest) (*entities.Address, error) {
+	return s.addressRepo.Create(ctx, userID, req)
+}
+
+func (s *addressService) GetAddresses(ctx context.Context, userID uuid.UUID) ([]*entities.Address, error) {
+	return s.addressRepo.GetByUserID(ctx, userID)
+}
+
+func (s *addressService) GetAddress(ctx context.Context, userID, id uuid.UUID) (*entities.Address, error) {
+	return s.addressRepo.GetByID(ctx, userID, id)
+}
+
+// UpdateAddress แก้ไขที่อยู่ คำสั่งซื้อที่ใช้ที่อยู่นี้ไปแล้วยังคงเก็บที่อยู่เดิมไว้
+func (s *addressService) UpdateAddress(ctx context.Context, userID, id uuid.UUID, req *entities.UpdateAddressRequest) (*entities.Address, error) {
+	if err := s.addressRepo.Update(ctx, userID, id, req); err != nil {
+		return nil, err
+	}
+	return s.addressRepo.GetByID(ctx, userID, id)
+}
+
+func (s *addressService) DeleteAddress(ctx context.Context, userID, id uuid.UUID) error {
+	return s.addressRepo.Delete(ctx, userID, id)
+}
+
+func (s *addressService) SetDefaultAddress(ctx context.Context, userID, id uuid.UUID) (*entities.Address, error) {
+	if err := s.addressRepo.SetDefault(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	return s.addressRepo.GetByID(ctx, userID, id)
+}
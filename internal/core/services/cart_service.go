@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
@@ -10,31 +12,138 @@ import (
 )
 
 type cartService struct {
-	cartRepo repositories.CartRepository
+	cartRepo      repositories.CartRepository
+	promotionRepo repositories.PromotionRepository
 }
 
-func NewCartService(cartRepo repositories.CartRepository) services.CartService {
+func NewCartService(cartRepo repositories.CartRepository, promotionRepo repositories.PromotionRepository) services.CartService {
 	return &cartService{
-		cartRepo: cartRepo,
+		cartRepo:      cartRepo,
+		promotionRepo: promotionRepo,
 	}
 }
 
+// GetCart ดึงตะกร้าพร้อมคำนวณส่วนลดจากคูปองที่ใช้อยู่
+// ถ้าคูปองใช้ไม่ได้แล้ว (หมดอายุ ครบจำนวน หรือยอดไม่ถึง) ตะกร้ายังแสดงได้ตามปกติแต่ไม่มีส่วนลด
 func (s *cartService) GetCart(ctx context.Context, userID uuid.UUID) (*entities.Cart, error) {
-	return s.cartRepo.GetByUserID(ctx, userID)
+	cart, err := s.cartRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cart.Discount = entities.NewMoney(0, cart.TotalPrice.Currency)
+	if cart.CouponCode != "" {
+		result, err := s.applyCoupon(ctx, userID, cart)
+		switch {
+		case err == nil:
+			cart.Discount = result.Discount
+			cart.FreeShipping = result.FreeShipping
+		case errors.Is(err, entities.ErrPromotionNotFound), errors.Is(err, entities.ErrPromotionNotApplicable):
+			cart.CouponError = err.Error()
+		default:
+			return nil, err
+		}
+	}
+
+	cart.DiscountedTotal, err = cart.TotalPrice.Sub(cart.Discount)
+	if err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// ApplyCoupon ตรวจสอบคูปองกับสินค้าในตะกร้าตอนนี้ แล้วบันทึกไว้กับตะกร้าถ้าใช้ได้
+func (s *cartService) ApplyCoupon(ctx context.Context, userID uuid.UUID, code string) (*entities.Cart, error) {
+	cart, err := s.cartRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cart.CouponCode = normalizeCouponCode(code)
+	if _, err := s.applyCoupon(ctx, userID, cart); err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepo.SetCouponCode(ctx, userID, cart.CouponCode); err != nil {
+		return nil, err
+	}
+	return s.GetCart(ctx, userID)
+}
+
+func (s *cartService) RemoveCoupon(ctx context.Context, userID uuid.UUID) (*entities.Cart, error) {
+	if err := s.cartRepo.SetCouponCode(ctx, userID, ""); err != nil {
+		return nil, err
+	}
+	return s.GetCart(ctx, userID)
 }
 
 func (s *cartService) AddToCart(ctx context.Context, userID uuid.UUID, req *entities.AddToCartRequest) error {
 	return s.cartRepo.AddItem(ctx, userID, req)
 }
 
-func (s *cartService) UpdateCartItem(ctx context.Context, cartItemID uuid.UUID, req *entities.UpdateCartItemRequest) error {
+func (s *cartService) UpdateCartItem(ctx context.Context, userID, cartItemID uuid.UUID, req *entities.UpdateCartItemRequest) error {
+	if err := s.checkCartItemOwner(ctx, userID, cartItemID); err != nil {
+		return err
+	}
 	return s.cartRepo.UpdateItem(ctx, cartItemID, req.Quantity)
 }
 
-func (s *cartService) RemoveFromCart(ctx context.Context, cartItemID uuid.UUID) error {
+func (s *cartService) RemoveFromCart(ctx context.Context, userID, cartItemID uuid.UUID) error {
+	if err := s.checkCartItemOwner(ctx, userID, cartItemID); err != nil {
+		return err
+	}
 	return s.cartRepo.RemoveItem(ctx, cartItemID)
 }
 
 func (s *cartService) ClearCart(ctx context.Context, userID uuid.UUID) error {
 	return s.cartRepo.ClearCart(ctx, userID)
 }
+
+// applyCoupon คำนวณส่วนลดของคูปองในตะกร้า ณ เวลานี้ ตอน checkout จะถูกตรวจซ้ำอีกครั้งใน transaction
+func (s *cartService) applyCoupon(ctx context.Context, userID uuid.UUID, cart *entities.Cart) (*entities.PromotionResult, error) {
+	promotion, err := s.promotionRepo.GetByCode(ctx, cart.CouponCode)
+	if err != nil {
+		return nil, err
+	}
+
+	totalUses, userUses, err := s.promotionRepo.CountRedemptions(ctx, promotion.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := promotion.CheckAvailability(time.Now(), totalUses, userUses); err != nil {
+		return nil, err
+	}
+
+	lines := make([]entities.PromotionLine, 0, len(cart.CartItems))
+	for _, item := range cart.CartItems {
+		line := entities.PromotionLine{
+			ProductID: item.ProductID,
+			Amount:    item.Price.Mul(item.Quantity),
+		}
+		if item.Product != nil {
+			line.CategoryID = item.Product.CategoryID
+		}
+		lines = append(lines, line)
+	}
+
+	return promotion.Apply(lines)
+}
+
+// checkCartItemOwner ตรวจสอบว่า cart item อยู่ในตะกร้าของผู้ใช้คนนี้จริง
+// ถ้าไม่ใช่จะคืนค่า ErrCartItemNotFound เพื่อไม่ให้รู้ว่ามี item นี้อยู่ในตะกร้าของคนอื่น
+func (s *cartService) checkCartItemOwner(ctx context.Context, userID, cartItemID uuid.UUID) error {
+	item, err := s.cartRepo.GetCartItem(ctx, cartItemID)
+	if err != nil {
+		return entities.ErrCartItemNotFound
+	}
+
+	cart, err := s.cartRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if item.CartID != cart.ID {
+		return entities.ErrCartItemNotFound
+	}
+	return nil
+}
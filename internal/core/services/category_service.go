@@ -11,12 +11,16 @@ import (
 )
 
 type categoryService struct {
-	categoryRepo repositories.CategoryRepository
+	categoryRepo    repositories.CategoryRepository
+	categoryIndexer services.CategoryIndexer
 }
 
-func NewCategoryService(categoryRepo repositories.CategoryRepository) services.CategoryService {
+// NewCategoryService สร้าง CategoryService ใหม่
+// categoryIndexer ใช้ส่งสินค้าในหมวดหมู่เข้า search index ใหม่เมื่อชื่อหมวดหมู่เปลี่ยน
+func NewCategoryService(categoryRepo repositories.CategoryRepository, categoryIndexer services.CategoryIndexer) services.CategoryService {
 	return &categoryService{
-		categoryRepo: categoryRepo,
+		categoryRepo:    categoryRepo,
+		categoryIndexer: categoryIndexer,
 	}
 }
 
@@ -42,14 +46,39 @@ func (s *categoryService) GetCategories(ctx context.Context, page, limit int) ([
 	return categories, pagination, nil
 }
 
+func (s *categoryService) GetCategoryTree(ctx context.Context) ([]*entities.Category, error) {
+	return s.categoryRepo.GetTree(ctx)
+}
+
 func (s *categoryService) GetCategoryByID(ctx context.Context, id uuid.UUID) (*entities.Category, error) {
 	return s.categoryRepo.GetByID(ctx, id)
 }
 
+func (s *categoryService) GetCategoryBySlug(ctx context.Context, slug string) (*entities.Category, error) {
+	return s.categoryRepo.GetBySlug(ctx, slug)
+}
+
 func (s *categoryService) UpdateCategory(ctx context.Context, id uuid.UUID, req *entities.UpdateCategoryRequest) error {
-	return s.categoryRepo.Update(ctx, id, req)
+	if err := s.categoryRepo.Update(ctx, id, req); err != nil {
+		return err
+	}
+
+	// search index เก็บชื่อหมวดหมู่ไว้กับสินค้า จึงต้องส่งสินค้าในหมวดหมู่นี้เข้าไปใหม่
+	if req.Name != "" {
+		s.categoryIndexer.ReindexCategory(ctx, id)
+	}
+	return nil
 }
 
-func (s *categoryService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
-	return s.categoryRepo.Delete(ctx, id)
+// DeleteCategory ลบหมวดหมู่ ถ้ายังมีสินค้าอยู่ต้องระบุ reassignTo เพื่อย้ายสินค้าไปหมวดหมู่อื่นก่อน
+func (s *categoryService) DeleteCategory(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID) error {
+	if err := s.categoryRepo.Delete(ctx, id, reassignTo); err != nil {
+		return err
+	}
+
+	// สินค้าที่ย้ายมายังมีชื่อหมวดหมู่เดิมอยู่ใน search index
+	if reassignTo != nil {
+		s.categoryIndexer.ReindexCategory(ctx, *reassignTo)
+	}
+	return nil
 }
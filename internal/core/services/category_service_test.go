@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/google/uuid"
+)
+
+// stubCategoryRepo ยอมรับการแก้ไขและการลบหมวดหมู่ทุกครั้ง
+type stubCategoryRepo struct {
+	repositories.CategoryRepository
+}
+
+func (stubCategoryRepo) Update(ctx context.Context, id uuid.UUID, req *entities.UpdateCategoryRequest) error {
+	return nil
+}
+
+func (stubCategoryRepo) Delete(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID) error {
+	return nil
+}
+
+// recordingCategoryIndexer เก็บหมวดหมู่ที่ถูกขอให้ index ใหม่
+type recordingCategoryIndexer struct {
+	reindexed []uuid.UUID
+}
+
+func (r *recordingCategoryIndexer) ReindexCategory(ctx context.Context, categoryID uuid.UUID) {
+	r.reindexed = append(r.reindexed, categoryID)
+}
+
+func TestUpdateCategory_ReindexesProductsOnlyWhenRenamed(t *testing.T) {
+	id := uuid.New()
+	indexer := &recordingCategoryIndexer{}
+	service := NewCategoryService(stubCategoryRepo{}, indexer)
+
+	if err := service.UpdateCategory(context.Background(), id, &entities.UpdateCategoryRequest{Description: "new"}); err != nil {
+		t.Fatalf("UpdateCategory: %v", err)
+	}
+	if len(indexer.reindexed) != 0 {
+		t.Fatalf("reindexed %v after a description change, want none", indexer.reindexed)
+	}
+
+	if err := service.UpdateCategory(context.Background(), id, &entities.UpdateCategoryRequest{Name: "Renamed"}); err != nil {
+		t.Fatalf("UpdateCategory: %v", err)
+	}
+	if len(indexer.reindexed) != 1 || indexer.reindexed[0] != id {
+		t.Fatalf("reindexed %v after a rename, want [%s]", indexer.reindexed, id)
+	}
+}
+
+func TestDeleteCategory_ReindexesReassignedProducts(t *testing.T) {
+	indexer := &recordingCategoryIndexer{}
+	service := NewCategoryService(stubCategoryRepo{}, indexer)
+
+	if err := service.DeleteCategory(context.Background(), uuid.New(), nil); err != nil {
+		t.Fatalf("DeleteCategory: %v", err)
+	}
+	if len(indexer.reindexed) != 0 {
+		t.Fatalf("reindexed %v after deleting an empty category, want none", indexer.reindexed)
+	}
+
+	target := uuid.New()
+	if err := service.DeleteCategory(context.Background(), uuid.New(), &target); err != nil {
+		t.Fatalf("DeleteCategory: %v", err)
+	}
+	if len(indexer.reindexed) != 1 || indexer.reindexed[0] != target {
+		t.Fatalf("reindexed %v after reassigning products, want [%s]", indexer.reindexed, target)
+	}
+}
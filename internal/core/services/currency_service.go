@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+)
+
+// currencyCodePattern รหัสสกุลเงิน ISO 4217 เป็นตัวอักษรภาษาอังกฤษพิมพ์ใหญ่ 3 ตัว
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+type currencyService struct {
+	exchangeRateRepo repositories.ExchangeRateRepository
+}
+
+func NewCurrencyService(exchangeRateRepo repositories.ExchangeRateRepository) services.CurrencyService {
+	return &currencyService{
+		exchangeRateRepo: exchangeRateRepo,
+	}
+}
+
+func (s *currencyService) ListRates(ctx context.Context) ([]*entities.ExchangeRate, error) {
+	return s.exchangeRateRepo.List(ctx)
+}
+
+// SetRate กำหนดอัตราแลกเปลี่ยนของสกุลเงิน สกุลเงินหลักมีอัตรา 1 เสมอจึงแก้ไม่ได้
+func (s *currencyService) SetRate(ctx context.Context, currency string, req *entities.SetExchangeRateRequest) (*entities.ExchangeRate, error) {
+	currency = strings.ToUpper(currency)
+	if !currencyCodePattern.MatchString(currency) {
+		return nil, fmt.Errorf("%w: รหัสสกุลเงิน %q ไม่ถูกต้อง", entities.ErrUnsupportedCurrency, currency)
+	}
+	if currency == entities.DefaultCurrency {
+		return nil, errors.New("ไม่สามารถกำหนดอัตราแลกเปลี่ยนของสกุลเงินหลักได้")
+	}
+
+	return s.exchangeRateRepo.Upsert(ctx, &entities.ExchangeRate{
+		Currency: currency,
+		Rate:     req.Rate,
+	})
+}
+
+func (s *currencyService) DeleteRate(ctx context.Context, currency string) error {
+	return s.exchangeRateRepo.Delete(ctx, strings.ToUpper(currency))
+}
+
+func (s *currencyService) GetRateTable(ctx context.Context) (entities.ExchangeRateTable, error) {
+	rates, err := s.exchangeRateRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return entities.NewExchangeRateTable(rates), nil
+}
+
+// LocalizeProducts ใส่ DisplayPrice ของสินค้าเป็นสกุลเงินที่ลูกค้าเลือก
+// ถ้าไม่ได้เลือกสกุลเงินจะไม่แก้ไขอะไร
+func (s *currencyService) LocalizeProducts(ctx context.Context, currency string, products ...*entities.Product) error {
+	if currency == "" {
+		return nil
+	}
+
+	rates, err := s.GetRateTable(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		price, err := rates.Convert(product.Price, currency)
+		if err != nil {
+			return err
+		}
+		product.DisplayPrice = &price
+	}
+
+	return nil
+}
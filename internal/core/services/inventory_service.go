@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/google/uuid"
+)
+
+type inventoryService struct {
+	inventoryRepo repositories.InventoryRepository
+	productRepo   repositories.ProductRepository
+	stockMonitor  services.StockMonitor
+}
+
+func NewInventoryService(inventoryRepo repositories.InventoryRepository, productRepo repositories.ProductRepository, stockMonitor services.StockMonitor) services.InventoryService {
+	return &inventoryService{
+		inventoryRepo: inventoryRepo,
+		productRepo:   productRepo,
+		stockMonitor:  stockMonitor,
+	}
+}
+
+// AdjustStock ปรับสต็อกด้วยมือ ใช้ได้เฉพาะเหตุผล adjustment และ return
+// เพราะการขายและการยกเลิกต้องเกิดจากคำสั่งซื้อเท่านั้น
+func (s *inventoryService) AdjustStock(ctx context.Context, productID, actorID uuid.UUID, req *entities.AdjustStockRequest) (*entities.InventoryMovement, error) {
+	reason := req.Reason
+	if reason == "" {
+		reason = entities.InventoryReasonAdjustment
+	}
+
+	if reason != entities.InventoryReasonAdjustment && reason != entities.InventoryReasonReturn {
+		return nil, errors.New("เหตุผลการปรับสต็อกต้องเป็น adjustment หรือ return")
+	}
+	if req.Delta == 0 {
+		return nil, errors.New("จำนวนที่ปรับต้องไม่เป็น 0")
+	}
+
+	movement, err := s.inventoryRepo.Adjust(ctx, productID, req.VariantID, req.Delta, reason, req.ReferenceID, actorID, req.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	s.stockMonitor.StockChanged(productID)
+	return movement, nil
+}
+
+func (s *inventoryService) GetMovements(ctx context.Context, productID uuid.UUID, page, limit int) ([]*entities.InventoryMovement, *entities.PaginationResponse, error) {
+	movements, total, err := s.inventoryRepo.GetByProductID(ctx, productID, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	pagination := &entities.PaginationResponse{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+		TotalItems: total,
+	}
+
+	return movements, pagination, nil
+}
+
+func (s *inventoryService) RebuildStock(ctx context.Context, productID uuid.UUID) (*entities.StockRebuildResult, error) {
+	result, err := s.inventoryRepo.RebuildStock(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.stockMonitor.StockChanged(productID)
+	return result, nil
+}
+
+func (s *inventoryService) GetLowStockProducts(ctx context.Context, filter *entities.LowStockFilter) ([]*entities.LowStockProduct, *entities.PaginationResponse, error) {
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.Limit == 0 {
+		filter.Limit = 10
+	}
+
+	products, total, err := s.productRepo.GetLowStockProducts(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(filter.Limit)))
+
+	pagination := &entities.PaginationResponse{
+		Page:       filter.Page,
+		Limit:      filter.Limit,
+		TotalPages: totalPages,
+		TotalItems: total,
+	}
+
+	return products, pagination, nil
+}
@@ -11,17 +11,25 @@ import (
 )
 
 type orderService struct {
-	orderRepo repositories.OrderRepository
+	orderRepo    repositories.OrderRepository
+	stockMonitor services.StockMonitor
 }
 
-func NewOrderService(orderRepo repositories.OrderRepository) services.OrderService {
+func NewOrderService(orderRepo repositories.OrderRepository, stockMonitor services.StockMonitor) services.OrderService {
 	return &orderService{
-		orderRepo: orderRepo,
+		orderRepo:    orderRepo,
+		stockMonitor: stockMonitor,
 	}
 }
 
 func (s *orderService) CreateOrder(ctx context.Context, userID uuid.UUID, req *entities.CreateOrderRequest) (*entities.Order, error) {
-	return s.orderRepo.Create(ctx, userID, req)
+	order, err := s.orderRepo.Create(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.stockMonitor.StockChanged(orderProductIDs(order)...)
+	return order, nil
 }
 
 func (s *orderService) GetOrders(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entities.Order, *entities.PaginationResponse, error) {
@@ -46,21 +54,60 @@ func (s *orderService) GetOrderByID(ctx context.Context, id uuid.UUID) (*entitie
 	return s.orderRepo.GetByID(ctx, id)
 }
 
-func (s *orderService) CancelOrder(ctx context.Context, id uuid.UUID) error {
-	return s.orderRepo.Cancel(ctx, id)
+// GetUserOrderByID ดึงคำสั่งซื้อโดยตรวจสอบว่าเป็นของผู้ใช้คนนี้
+func (s *orderService) GetUserOrderByID(ctx context.Context, userID, id uuid.UUID) (*entities.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, entities.ErrOrderNotFound
+	}
+
+	if order.UserID != userID {
+		return nil, entities.ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// CancelOrder ให้ลูกค้ายกเลิกคำสั่งซื้อของตัวเอง ทำได้เฉพาะตอนที่คำสั่งซื้อยังเป็น pending
+func (s *orderService) CancelOrder(ctx context.Context, userID, id uuid.UUID) error {
+	order, err := s.GetUserOrderByID(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if order.Status != entities.OrderStatusPending {
+		return &entities.StatusTransitionError{
+			Axis:   entities.StatusAxisOrder,
+			From:   string(order.Status),
+			To:     string(entities.OrderStatusCancelled),
+			Reason: "ลูกค้ายกเลิกได้เฉพาะคำสั่งซื้อที่ยังไม่ได้รับการยืนยัน",
+		}
+	}
+	if err := s.orderRepo.Cancel(ctx, id, userID, "ยกเลิกโดยลูกค้า"); err != nil {
+		return err
+	}
+
+	s.stockMonitor.StockChanged(orderProductIDs(order)...)
+	return nil
 }
 
-func (s *orderService) GetAllOrders(ctx context.Context, page, limit int) ([]*entities.Order, *entities.PaginationResponse, error) {
-	orders, total, err := s.orderRepo.GetAll(ctx, page, limit)
+func (s *orderService) GetAllOrders(ctx context.Context, filter *entities.OrderFilter) ([]*entities.Order, *entities.PaginationResponse, error) {
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.Limit == 0 {
+		filter.Limit = 10
+	}
+
+	orders, total, err := s.orderRepo.GetAll(ctx, filter)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	totalPages := int(math.Ceil(float64(total) / float64(filter.Limit)))
 
 	pagination := &entities.PaginationResponse{
-		Page:       page,
-		Limit:      limit,
+		Page:       filter.Page,
+		Limit:      filter.Limit,
 		TotalPages: totalPages,
 		TotalItems: total,
 	}
@@ -68,14 +115,50 @@ func (s *orderService) GetAllOrders(ctx context.Context, page, limit int) ([]*en
 	return orders, pagination, nil
 }
 
-func (s *orderService) UpdateOrderStatus(ctx context.Context, id uuid.UUID, req *entities.UpdateOrderStatusRequest) error {
-	return s.orderRepo.UpdateStatus(ctx, id, req.Status)
+// UpdateOrderStatus เปลี่ยนสถานะหลักของคำสั่งซื้อ
+// repository ตรวจการเปลี่ยนสถานะหลังล็อคแถวของคำสั่งซื้อแล้ว จึงไม่ตรวจซ้ำจากข้อมูลที่อ่านมาก่อนล็อค
+func (s *orderService) UpdateOrderStatus(ctx context.Context, id, actorID uuid.UUID, req *entities.UpdateOrderStatusRequest) error {
+	// การยกเลิกต้องคืนสต็อกด้วย จึงใช้ Cancel แทนการอัพเดทสถานะตรงๆ
+	if req.Status == entities.OrderStatusCancelled {
+		order, err := s.orderRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.orderRepo.Cancel(ctx, id, actorID, req.Note); err != nil {
+			return err
+		}
+
+		s.stockMonitor.StockChanged(orderProductIDs(order)...)
+		return nil
+	}
+
+	return s.orderRepo.UpdateStatus(ctx, id, req.Status, actorID, req.Note)
 }
 
-func (s *orderService) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, req *entities.UpdatePaymentStatusRequest) error {
-	return s.orderRepo.UpdatePaymentStatus(ctx, id, req.PaymentStatus)
+// UpdatePaymentStatus ให้ผู้ดูแลระบบเปลี่ยนสถานะการชำระเงินเอง ยกเว้นสถานะที่ต้องมาจากการคืนเงิน
+// repository ยังตรวจตารางการเปลี่ยนสถานะซ้ำกับแถวที่ล็อคไว้
+func (s *orderService) UpdatePaymentStatus(ctx context.Context, id, actorID uuid.UUID, req *entities.UpdatePaymentStatusRequest) error {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := entities.CheckManualPaymentStatusTransition(order, req.PaymentStatus); err != nil {
+		return err
+	}
+
+	return s.orderRepo.UpdatePaymentStatus(ctx, id, req.PaymentStatus, actorID, req.Note)
 }
 
-func (s *orderService) UpdateShippingStatus(ctx context.Context, id uuid.UUID, req *entities.UpdateShippingStatusRequest) error {
-	return s.orderRepo.UpdateShippingStatus(ctx, id, req.ShippingStatus, req.TrackingNumber)
+func (s *orderService) UpdateShippingStatus(ctx context.Context, id, actorID uuid.UUID, req *entities.UpdateShippingStatusRequest) error {
+	return s.orderRepo.UpdateShippingStatus(ctx, id, req.ShippingStatus, req.TrackingNumber, actorID, req.Note)
+}
+
+// orderProductIDs คืนรายการ product ID ของสินค้าในคำสั่งซื้อ
+func orderProductIDs(order *entities.Order) []uuid.UUID {
+	productIDs := make([]uuid.UUID, 0, len(order.OrderItems))
+	for _, item := range order.OrderItems {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	return productIDs
 }
@@ -2,49 +2,224 @@ package services
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/gateways"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/Sup-Film/fiber-ecommerce-api/pkg/utils"
 	"github.com/google/uuid"
 )
 
 type paymentService struct {
 	transactionRepo repositories.TransactionRepository
+	// paymentGateways จับคู่ PaymentMethod กับ gateway ที่ใช้ประมวลผล
+	paymentGateways map[string]gateways.PaymentGateway
+	// webhookProviders จับคู่ชื่อผู้ให้บริการใน URL ของ webhook กับ secret และช่องทางการชำระเงินที่ดูแล
+	webhookProviders map[string]entities.PaymentWebhookProvider
 }
 
-func NewPaymentService(transactionRepo repositories.TransactionRepository) services.PaymentService {
+func NewPaymentService(transactionRepo repositories.TransactionRepository, paymentGateways map[string]gateways.PaymentGateway, webhookProviders map[string]entities.PaymentWebhookProvider) services.PaymentService {
 	return &paymentService{
-		transactionRepo: transactionRepo,
+		transactionRepo:  transactionRepo,
+		paymentGateways:  paymentGateways,
+		webhookProviders: webhookProviders,
 	}
 }
 
+// gatewayFor เลือก gateway ตามช่องทางการชำระเงิน
+func (s *paymentService) gatewayFor(paymentMethod string) (gateways.PaymentGateway, error) {
+	gateway, ok := s.paymentGateways[paymentMethod]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", entities.ErrUnsupportedPaymentMethod, paymentMethod)
+	}
+	return gateway, nil
+}
+
 func (s *paymentService) CreatePayment(ctx context.Context, req *entities.CreatePaymentRequest) (*entities.Transaction, error) {
-	return s.transactionRepo.Create(ctx, req)
+	gateway, err := s.gatewayFor(req.PaymentMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.transactionRepo.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	intent, err := gateway.CreateIntent(ctx, &entities.PaymentIntentRequest{
+		Reference:   transaction.TransactionID,
+		Amount:      transaction.Amount,
+		Description: fmt.Sprintf("Order %s", transaction.OrderID),
+	})
+	if err != nil {
+		// สร้างรายการที่ gateway ไม่สำเร็จ ถือว่าธุรกรรมนี้ล้มเหลว
+		if updateErr := s.transactionRepo.UpdateStatus(ctx, transaction.ID, entities.TransactionStatusFailed, nil); updateErr != nil {
+			return nil, errors.Join(err, updateErr)
+		}
+		return nil, err
+	}
+
+	if err := s.transactionRepo.AttachIntent(ctx, transaction.ID, intent.ProviderRef, intent.Payload); err != nil {
+		return nil, err
+	}
+
+	return s.transactionRepo.GetByID(ctx, transaction.ID)
 }
 
 func (s *paymentService) GetPaymentByID(ctx context.Context, id uuid.UUID) (*entities.Transaction, error) {
 	return s.transactionRepo.GetByID(ctx, id)
 }
 
+// VerifyPayment ตรวจสอบสถานะการชำระเงินกับ gateway แล้วอัพเดทธุรกรรมและคำสั่งซื้อตามผลลัพธ์
+// ถ้า gateway ยังไม่ได้รับเงินจะคืน ErrPaymentPending โดยไม่เปลี่ยนสถานะ
 func (s *paymentService) VerifyPayment(ctx context.Context, id uuid.UUID, req *entities.VerifyPaymentRequest) error {
-	// ตรวจสอบข้อมูลการชำระเงิน
 	transaction, err := s.transactionRepo.GetByID(ctx, id)
 	if err != nil {
+		return entities.ErrPaymentNotFound
+	}
+
+	if transaction.TransactionID != req.TransactionID {
+		return entities.ErrPaymentNotFound
+	}
+
+	// ธุรกรรมที่จบไปแล้วไม่ต้องตรวจซ้ำ
+	switch transaction.Status {
+	case entities.TransactionStatusCompleted:
+		return nil
+	case entities.TransactionStatusFailed, entities.TransactionStatusCancelled:
+		return fmt.Errorf("ธุรกรรมนี้อยู่ในสถานะ %s แล้ว", transaction.Status)
+	}
+
+	gateway, err := s.gatewayFor(transaction.PaymentMethod)
+	if err != nil {
+		return err
+	}
+
+	result, err := gateway.Verify(ctx, transaction.ProviderRef)
+	if err != nil {
+		return err
+	}
+
+	switch result.Status {
+	case entities.GatewayStatusAuthorized:
+		if !sameAmount(result.Amount, transaction.Amount) {
+			return s.failPayment(ctx, transaction, result.Amount)
+		}
+		if result, err = gateway.Capture(ctx, transaction.ProviderRef, transaction.Amount); err != nil {
+			return err
+		}
+		if result.Status != entities.GatewayStatusCaptured {
+			return entities.ErrPaymentPending
+		}
+		return s.transactionRepo.UpdateStatus(ctx, id, entities.TransactionStatusCompleted, nil)
+	case entities.GatewayStatusCaptured:
+		if !sameAmount(result.Amount, transaction.Amount) {
+			return s.failPayment(ctx, transaction, result.Amount)
+		}
+		return s.transactionRepo.UpdateStatus(ctx, id, entities.TransactionStatusCompleted, nil)
+	case entities.GatewayStatusFailed:
+		return s.transactionRepo.UpdateStatus(ctx, id, entities.TransactionStatusFailed, nil)
+	case entities.GatewayStatusCancelled:
+		return s.transactionRepo.UpdateStatus(ctx, id, entities.TransactionStatusCancelled, nil)
+	}
+
+	return entities.ErrPaymentPending
+}
+
+// failPayment บันทึกธุรกรรมเป็น failed เมื่อยอดเงินที่ gateway ได้รับไม่ตรงกับยอดที่ต้องชำระ
+func (s *paymentService) failPayment(ctx context.Context, transaction *entities.Transaction, received entities.Money) error {
+	if err := s.transactionRepo.UpdateStatus(ctx, transaction.ID, entities.TransactionStatusFailed, nil); err != nil {
 		return err
 	}
+	return fmt.Errorf("ยอดเงินที่ได้รับ %s ไม่ตรงกับยอดที่ต้องชำระ %s", received, transaction.Amount)
+}
+
+// HandleWebhook ตรวจลายเซ็น HMAC-SHA256 ของ payload แล้วนำผลการชำระเงินไปอัพเดทธุรกรรม
+// event ที่เคยประมวลผลแล้ว หรือมาช้ากว่าสถานะปัจจุบันของธุรกรรม จะถูกข้ามโดยไม่ถือเป็น error
+// เพื่อให้ gateway ไม่ส่ง event เดิมซ้ำอีก
+// ผู้ให้บริการแจ้งผลได้เฉพาะธุรกรรมของช่องทางการชำระเงินที่ตัวเองดูแล ถ้าไม่ตรงจะคืน ErrWebhookProviderMismatch
+func (s *paymentService) HandleWebhook(ctx context.Context, provider string, payload []byte, signature string) error {
+	webhookProvider, ok := s.webhookProviders[provider]
+	if !ok || webhookProvider.Secret == "" {
+		return fmt.Errorf("%w: %s", entities.ErrUnknownWebhookProvider, provider)
+	}
+
+	if !validWebhookSignature(webhookProvider.Secret, payload, signature) {
+		return entities.ErrInvalidWebhookSignature
+	}
+
+	var event entities.PaymentWebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("%w: %v", entities.ErrInvalidWebhookPayload, err)
+	}
+	if err := utils.ValidateStruct(event); err != nil {
+		return fmt.Errorf("%w: %v", entities.ErrInvalidWebhookPayload, err)
+	}
 
-	// ในการใช้งานจริง ควรมีการตรวจสอบกับ payment gateway
-	// ที่นี่เป็นการจำลองการตรวจสอบ
-	if transaction.TransactionID == req.TransactionID {
-		// อัพเดทสถานะเป็น completed
-		return s.transactionRepo.UpdateStatus(ctx, id, "completed")
+	transaction, err := s.transactionRepo.GetByTransactionID(ctx, event.Reference)
+	if err != nil {
+		return entities.ErrPaymentNotFound
+	}
+	if !webhookProvider.Handles(transaction.PaymentMethod) {
+		return fmt.Errorf("%w: %s ไม่ได้ดูแล %s", entities.ErrWebhookProviderMismatch, provider, transaction.PaymentMethod)
 	}
 
-	// หากการตรวจสอบไม่ผ่าน อัพเดทสถานะเป็น failed
-	return s.transactionRepo.UpdateStatus(ctx, id, "failed")
+	var status entities.TransactionStatus
+	switch event.Status {
+	case entities.GatewayStatusCaptured:
+		status = entities.TransactionStatusCompleted
+		// ยอดเงินไม่ตรงถือว่าการชำระเงินล้มเหลว เหมือนกับตอน VerifyPayment
+		if !sameAmount(event.Amount, transaction.Amount) {
+			status = entities.TransactionStatusFailed
+		}
+	case entities.GatewayStatusFailed:
+		status = entities.TransactionStatusFailed
+	case entities.GatewayStatusCancelled:
+		status = entities.TransactionStatusCancelled
+	default:
+		// pending, authorized และสถานะอื่นยังไม่ใช่ผลสุดท้าย บันทึก event ไว้อย่างเดียว
+		status = entities.TransactionStatusPending
+	}
+
+	err = s.transactionRepo.UpdateStatus(ctx, transaction.ID, status, &entities.PaymentEvent{
+		Provider:      provider,
+		EventID:       event.EventID,
+		TransactionID: transaction.ID,
+		Status:        event.Status,
+		Payload:       string(payload),
+	})
+	if errors.Is(err, entities.ErrDuplicatePaymentEvent) || errors.Is(err, entities.ErrTransactionFinalized) {
+		return nil
+	}
+	return err
+}
+
+// validWebhookSignature เปรียบเทียบลายเซ็นแบบ constant time
+// รับได้ทั้งค่า hex ตรงๆ และแบบมี prefix "sha256="
+func validWebhookSignature(secret string, payload []byte, signature string) bool {
+	received, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(received, mac.Sum(nil))
 }
 
 func (s *paymentService) CancelPayment(ctx context.Context, id uuid.UUID) error {
 	return s.transactionRepo.Cancel(ctx, id)
 }
+
+// sameAmount เปรียบเทียบจำนวนเงินและสกุลเงิน
+func sameAmount(a, b entities.Money) bool {
+	return a.Amount == b.Amount && a.Currency == b.Currency
+}
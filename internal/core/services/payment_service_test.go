@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/google/uuid"
+)
+
+// unusedTransactionRepo ทำให้การทดสอบล้มถ้า service เรียก repository
+// ใช้ยืนยันว่า webhook ที่ไม่ผ่านการตรวจสอบจะไม่ไปแตะข้อมูลธุรกรรม
+type unusedTransactionRepo struct {
+	repositories.TransactionRepository
+}
+
+func TestHandleWebhook_RejectsBeforeTouchingTransactions(t *testing.T) {
+	const secret = "webhook-secret"
+	service := NewPaymentService(unusedTransactionRepo{}, nil, map[string]entities.PaymentWebhookProvider{
+		"fake": {Secret: secret, PaymentMethods: []string{entities.PaymentMethodBankTransfer}},
+	})
+
+	body := []byte(`{"event_id":"evt_1","reference":"TXN_1","status":"captured","amount":{"amount":10000,"currency":"THB"}}`)
+	signature := hexHMAC(secret, body)
+
+	cases := []struct {
+		name      string
+		provider  string
+		body      []byte
+		signature string
+		want      error
+	}{
+		{"unknown provider", "stripe", body, signature, entities.ErrUnknownWebhookProvider},
+		{"bad signature", "fake", body, signature[:len(signature)-2] + "00", entities.ErrInvalidWebhookSignature},
+		{"signature of other body", "fake", []byte(`{"event_id":"evt_2"}`), signature, entities.ErrInvalidWebhookSignature},
+		{"invalid json", "fake", []byte(`not json`), hexHMAC(secret, []byte(`not json`)), entities.ErrInvalidWebhookPayload},
+		{"missing event id", "fake", []byte(`{"reference":"TXN_1","status":"captured"}`), hexHMAC(secret, []byte(`{"reference":"TXN_1","status":"captured"}`)), entities.ErrInvalidWebhookPayload},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := service.HandleWebhook(context.Background(), tc.provider, tc.body, tc.signature)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+// stubTransactionRepo คืนธุรกรรมที่กำหนดไว้ และบันทึกสถานะที่ service ขอเปลี่ยน
+type stubTransactionRepo struct {
+	repositories.TransactionRepository
+	transaction *entities.Transaction
+	updateErr   error
+	updates     []entities.TransactionStatus
+}
+
+func (r *stubTransactionRepo) GetByTransactionID(ctx context.Context, transactionID string) (*entities.Transaction, error) {
+	if r.transaction == nil || r.transaction.TransactionID != transactionID {
+		return nil, errors.New("not found")
+	}
+	return r.transaction, nil
+}
+
+func (r *stubTransactionRepo) UpdateStatus(ctx context.Context, id uuid.UUID, status entities.TransactionStatus, event *entities.PaymentEvent) error {
+	r.updates = append(r.updates, status)
+	return r.updateErr
+}
+
+func TestHandleWebhook_RejectsProviderOfAnotherPaymentMethod(t *testing.T) {
+	repo := &stubTransactionRepo{transaction: &entities.Transaction{
+		ID:            uuid.New(),
+		TransactionID: "TXN_1",
+		PaymentMethod: entities.PaymentMethodPromptPay,
+		Amount:        entities.NewMoney(10000, entities.DefaultCurrency),
+	}}
+	service := NewPaymentService(repo, nil, map[string]entities.PaymentWebhookProvider{
+		"fake":      {Secret: "fake-secret", PaymentMethods: []string{entities.PaymentMethodBankTransfer}},
+		"promptpay": {Secret: "promptpay-secret", PaymentMethods: []string{entities.PaymentMethodPromptPay}},
+	})
+
+	body := []byte(`{"event_id":"evt_1","reference":"TXN_1","status":"captured","amount":{"amount":10000,"currency":"THB"}}`)
+
+	// ลายเซ็นถูกต้องตาม secret ของ fake แต่ธุรกรรมชำระผ่าน PromptPay
+	err := service.HandleWebhook(context.Background(), "fake", body, hexHMAC("fake-secret", body))
+	if !errors.Is(err, entities.ErrWebhookProviderMismatch) {
+		t.Fatalf("expected ErrWebhookProviderMismatch, got %v", err)
+	}
+	if len(repo.updates) != 0 {
+		t.Fatalf("expected the transaction to be left untouched, got %v", repo.updates)
+	}
+
+	if err := service.HandleWebhook(context.Background(), "promptpay", body, hexHMAC("promptpay-secret", body)); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.updates) != 1 || repo.updates[0] != entities.TransactionStatusCompleted {
+		t.Errorf("expected the owning provider to capture the transaction, got %v", repo.updates)
+	}
+}
+
+func TestHandleWebhook_AcknowledgesRedeliveredAndStaleEvents(t *testing.T) {
+	body := []byte(`{"event_id":"evt_1","reference":"TXN_1","status":"failed"}`)
+
+	for _, updateErr := range []error{entities.ErrDuplicatePaymentEvent, entities.ErrTransactionFinalized} {
+		repo := &stubTransactionRepo{
+			transaction: &entities.Transaction{ID: uuid.New(), TransactionID: "TXN_1", PaymentMethod: entities.PaymentMethodBankTransfer},
+			updateErr:   updateErr,
+		}
+		service := NewPaymentService(repo, nil, map[string]entities.PaymentWebhookProvider{
+			"fake": {Secret: "secret", PaymentMethods: []string{entities.PaymentMethodBankTransfer}},
+		})
+
+		if err := service.HandleWebhook(context.Background(), "fake", body, hexHMAC("secret", body)); err != nil {
+			t.Errorf("expected %v to be acknowledged, got %v", updateErr, err)
+		}
+	}
+}
+
+func TestValidWebhookSignature_AcceptsPrefixedDigest(t *testing.T) {
+	body := []byte(`{"event_id":"evt_1"}`)
+	digest := hexHMAC("secret", body)
+
+	if !validWebhookSignature("secret", body, digest) {
+		t.Error("expected plain hex digest to be accepted")
+	}
+	if !validWebhookSignature("secret", body, "sha256="+digest) {
+		t.Error("expected sha256= prefixed digest to be accepted")
+	}
+}
+
+func hexHMAC(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -2,26 +2,53 @@ package services
 
 import (
 	"context"
+	"log"
 	"math"
+	"strings"
 
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/search"
 	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
 	"github.com/google/uuid"
 )
 
+// maxIndexedSearchResults จำนวนสินค้าสูงสุดที่ขอจาก search index ต่อการค้นหาที่ไม่มีตัวกรอง
+// ถ้ามีตัวกรอง จะขอทุกรายการที่ตรงกับคำค้น เพราะตัวกรองและการแบ่งหน้าทำในฐานข้อมูลจากรายการนี้
+const maxIndexedSearchResults = 1000
+
+// reindexBatchSize จำนวนสินค้าที่อ่านจากฐานข้อมูลต่อรอบเมื่อสร้าง search index ใหม่
+const reindexBatchSize = 200
+
 type productService struct {
-	productRepo repositories.ProductRepository
+	productRepo     repositories.ProductRepository
+	categoryRepo    repositories.CategoryRepository
+	stockMonitor    services.StockMonitor
+	wishlistMonitor services.WishlistMonitor
+	searchIndex     search.ProductSearchIndex
 }
 
-func NewProductService(productRepo repositories.ProductRepository) services.ProductService {
+// NewProductService สร้าง ProductService ใหม่
+// searchIndex เป็น nil ได้ เมื่อนั้นการค้นหาใช้ full-text search ของฐานข้อมูลอย่างเดียว
+func NewProductService(productRepo repositories.ProductRepository, categoryRepo repositories.CategoryRepository, stockMonitor services.StockMonitor, wishlistMonitor services.WishlistMonitor, searchIndex search.ProductSearchIndex) services.ProductService {
 	return &productService{
-		productRepo: productRepo,
+		productRepo:     productRepo,
+		categoryRepo:    categoryRepo,
+		stockMonitor:    stockMonitor,
+		wishlistMonitor: wishlistMonitor,
+		searchIndex:     searchIndex,
 	}
 }
 
-func (s *productService) CreateProduct(ctx context.Context, req *entities.CreateProductRequest) (*entities.Product, error) {
-	return s.productRepo.Create(ctx, req)
+func (s *productService) CreateProduct(ctx context.Context, actorID uuid.UUID, req *entities.CreateProductRequest) (*entities.Product, error) {
+	product, err := s.productRepo.Create(ctx, req, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.stockMonitor.StockChanged(product.ID)
+	s.indexProduct(ctx, product.ID)
+	return product, nil
 }
 
 func (s *productService) GetProducts(ctx context.Context, page, limit int) ([]*entities.Product, *entities.PaginationResponse, error) {
@@ -46,10 +73,16 @@ func (s *productService) GetProductByID(ctx context.Context, id uuid.UUID) (*ent
 	return s.productRepo.GetByID(ctx, id)
 }
 
-func (s *productService) GetProductsByCategory(ctx context.Context, categoryID uuid.UUID, page, limit int) ([]*entities.Product, *entities.PaginationResponse, error) {
+// GetProductsByCategory คืนสินค้าในหมวดหมู่และหมวดหมู่ย่อยทุกระดับ พร้อมหมวดหมู่ที่มี breadcrumb สำหรับแสดงเส้นทาง
+func (s *productService) GetProductsByCategory(ctx context.Context, categoryID uuid.UUID, page, limit int) ([]*entities.Product, *entities.Category, *entities.PaginationResponse, error) {
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	products, total, err := s.productRepo.GetByCategory(ctx, categoryID, page, limit)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	totalPages := int(math.Ceil(float64(total) / float64(limit)))
@@ -61,13 +94,26 @@ func (s *productService) GetProductsByCategory(ctx context.Context, categoryID u
 		TotalItems: total,
 	}
 
-	return products, pagination, nil
+	return products, category, pagination, nil
 }
 
-func (s *productService) SearchProducts(ctx context.Context, req *entities.ProductSearchRequest) ([]*entities.Product, *entities.PaginationResponse, error) {
-	products, total, err := s.productRepo.Search(ctx, req)
+func (s *productService) SearchProducts(ctx context.Context, req *entities.ProductSearchRequest) ([]*entities.Product, *entities.SearchFacets, *entities.PaginationResponse, error) {
+	// ให้ search index จับคู่คำค้น แล้วกรองและแบ่งหน้าจากรายการสินค้าที่ได้ในฐานข้อมูล
+	if s.searchIndex != nil && strings.TrimSpace(req.Query) != "" {
+		limit := maxIndexedSearchResults
+		if req.HasFilters() {
+			limit = 0
+		}
+		result, err := s.searchIndex.Query(ctx, &entities.ProductSearchQuery{Text: req.Query, Limit: limit})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		req.ProductIDs = result.ProductIDs
+	}
+
+	products, facets, total, err := s.productRepo.Search(ctx, req)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	page := req.Page
@@ -88,13 +134,115 @@ func (s *productService) SearchProducts(ctx context.Context, req *entities.Produ
 		TotalItems: total,
 	}
 
-	return products, pagination, nil
+	return products, facets, pagination, nil
 }
 
-func (s *productService) UpdateProduct(ctx context.Context, id uuid.UUID, req *entities.UpdateProductRequest) error {
-	return s.productRepo.Update(ctx, id, req)
+func (s *productService) UpdateProduct(ctx context.Context, id, actorID uuid.UUID, req *entities.UpdateProductRequest) error {
+	if err := s.productRepo.Update(ctx, id, req, actorID); err != nil {
+		return err
+	}
+
+	// สต็อกหรือจุดสั่งซื้อเพิ่มอาจเปลี่ยน จึงให้ตรวจใหม่
+	if req.Stock != nil || req.ReorderThreshold != nil {
+		s.stockMonitor.StockChanged(id)
+	}
+	if req.Price != nil {
+		s.wishlistMonitor.PriceChanged(id)
+	}
+	if req.Name != "" || req.Description != "" || req.CategoryID != uuid.Nil {
+		s.indexProduct(ctx, id)
+	}
+	return nil
 }
 
 func (s *productService) DeleteProduct(ctx context.Context, id uuid.UUID) error {
-	return s.productRepo.Delete(ctx, id)
+	if err := s.productRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if s.searchIndex != nil {
+		if err := s.searchIndex.Delete(ctx, id); err != nil {
+			log.Printf("Error removing product %s from search index: %v\n", id, err)
+		}
+	}
+	return nil
+}
+
+// SuggestProducts คืนคำค้นแนะนำสำหรับ autocomplete จาก search index
+// ถ้าไม่ได้ตั้งค่า search index จะคืนรายการว่าง
+func (s *productService) SuggestProducts(ctx context.Context, text string, limit int) ([]string, error) {
+	if s.searchIndex == nil || strings.TrimSpace(text) == "" {
+		return []string{}, nil
+	}
+
+	result, err := s.searchIndex.Query(ctx, &entities.ProductSearchQuery{Text: text, SuggestionLimit: limit})
+	if err != nil {
+		return nil, err
+	}
+	if result.Suggestions == nil {
+		return []string{}, nil
+	}
+	return result.Suggestions, nil
+}
+
+// RebuildSearchIndex ส่งสินค้าทั้งหมดในฐานข้อมูลเข้า search index ใช้ตอนเริ่มระบบสำหรับ index ที่ไม่เก็บข้อมูลถาวร
+// คืนจำนวนสินค้าที่ส่งเข้า index
+func (s *productService) RebuildSearchIndex(ctx context.Context) (int, error) {
+	if s.searchIndex == nil {
+		return 0, nil
+	}
+
+	return s.indexPages(ctx, func(page int) ([]*entities.Product, int, error) {
+		return s.productRepo.GetAll(ctx, page, reindexBatchSize)
+	})
+}
+
+// ReindexCategory ส่งสินค้าในหมวดหมู่และหมวดหมู่ย่อยเข้า search index ใหม่ เช่น หลังเปลี่ยนชื่อหมวดหมู่
+// ข้อมูลในฐานข้อมูลบันทึกแล้ว จึงแค่ log ไว้ถ้าส่งไม่สำเร็จ เหมือน indexProduct
+func (s *productService) ReindexCategory(ctx context.Context, categoryID uuid.UUID) {
+	if s.searchIndex == nil {
+		return
+	}
+
+	if _, err := s.indexPages(ctx, func(page int) ([]*entities.Product, int, error) {
+		return s.productRepo.GetByCategory(ctx, categoryID, page, reindexBatchSize)
+	}); err != nil {
+		log.Printf("Error indexing products of category %s: %v\n", categoryID, err)
+	}
+}
+
+// indexPages ส่งสินค้าทุกหน้าที่ fetch คืนมาเข้า search index และคืนจำนวนสินค้าที่ส่งเข้า index
+func (s *productService) indexPages(ctx context.Context, fetch func(page int) ([]*entities.Product, int, error)) (int, error) {
+	indexed := 0
+	for page := 1; ; page++ {
+		products, total, err := fetch(page)
+		if err != nil {
+			return indexed, err
+		}
+		for _, product := range products {
+			if err := s.searchIndex.Index(ctx, product); err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+		if len(products) < reindexBatchSize || page*reindexBatchSize >= total {
+			return indexed, nil
+		}
+	}
+}
+
+// indexProduct ส่งข้อมูลล่าสุดของสินค้าเข้า search index
+// ข้อมูลในฐานข้อมูลบันทึกแล้ว จึงแค่ log ไว้ถ้าส่งไม่สำเร็จ และจะถูกแก้เมื่อสร้าง index ใหม่
+func (s *productService) indexProduct(ctx context.Context, id uuid.UUID) {
+	if s.searchIndex == nil {
+		return
+	}
+
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err == nil {
+		err = s.searchIndex.Index(ctx, product)
+	}
+	if err != nil {
+		log.Printf("Error indexing product %s: %v\n", id, err)
+	}
 }
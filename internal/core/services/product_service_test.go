@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/search"
+	"github.com/google/uuid"
+)
+
+// recordingSearchIndex เก็บคำค้นและสินค้าที่ service ส่งมา
+type recordingSearchIndex struct {
+	search.ProductSearchIndex
+	queries []entities.ProductSearchQuery
+	indexed []uuid.UUID
+}
+
+func (idx *recordingSearchIndex) Query(ctx context.Context, query *entities.ProductSearchQuery) (*entities.ProductSearchResult, error) {
+	idx.queries = append(idx.queries, *query)
+	return &entities.ProductSearchResult{}, nil
+}
+
+func (idx *recordingSearchIndex) Index(ctx context.Context, product *entities.Product) error {
+	idx.indexed = append(idx.indexed, product.ID)
+	return nil
+}
+
+// categoryProductRepo คืนสินค้าของหมวดหมู่ที่กำหนดไว้ทีละหน้า
+type categoryProductRepo struct {
+	repositories.ProductRepository
+	categoryID uuid.UUID
+	products   []*entities.Product
+}
+
+func (r categoryProductRepo) Search(ctx context.Context, req *entities.ProductSearchRequest) ([]*entities.Product, *entities.SearchFacets, int, error) {
+	return nil, &entities.SearchFacets{}, 0, nil
+}
+
+func (r categoryProductRepo) GetByCategory(ctx context.Context, categoryID uuid.UUID, page, limit int) ([]*entities.Product, int, error) {
+	if categoryID != r.categoryID {
+		return nil, 0, nil
+	}
+	start := min((page-1)*limit, len(r.products))
+	end := min(start+limit, len(r.products))
+	return r.products[start:end], len(r.products), nil
+}
+
+func TestSearchProducts_DoesNotTruncateIndexResultsWhenFiltering(t *testing.T) {
+	minPrice := entities.NewMoney(10000, "THB")
+	tests := []struct {
+		name string
+		req  entities.ProductSearchRequest
+		want int
+	}{
+		{"query only", entities.ProductSearchRequest{Query: "shirt"}, maxIndexedSearchResults},
+		{"category filter", entities.ProductSearchRequest{Query: "shirt", CategoryID: uuid.New()}, 0},
+		{"price filter", entities.ProductSearchRequest{Query: "shirt", MinPrice: &minPrice}, 0},
+		{"in stock filter", entities.ProductSearchRequest{Query: "shirt", InStock: true}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := &recordingSearchIndex{}
+			service := NewProductService(categoryProductRepo{}, nil, nil, nil, idx)
+
+			if _, _, _, err := service.SearchProducts(context.Background(), &tt.req); err != nil {
+				t.Fatalf("SearchProducts: %v", err)
+			}
+			if len(idx.queries) != 1 || idx.queries[0].Limit != tt.want {
+				t.Fatalf("index queries = %+v, want one query with limit %d", idx.queries, tt.want)
+			}
+		})
+	}
+}
+
+func TestReindexCategory_IndexesEveryPage(t *testing.T) {
+	categoryID := uuid.New()
+	repo := categoryProductRepo{categoryID: categoryID}
+	for i := 0; i < reindexBatchSize+1; i++ {
+		repo.products = append(repo.products, &entities.Product{ID: uuid.New(), CategoryID: categoryID})
+	}
+	idx := &recordingSearchIndex{}
+
+	NewProductService(repo, nil, nil, nil, idx).ReindexCategory(context.Background(), categoryID)
+
+	if len(idx.indexed) != len(repo.products) {
+		t.Fatalf("indexed %d products, want %d", len(idx.indexed), len(repo.products))
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/google/uuid"
+)
+
+type promotionService struct {
+	promotionRepo repositories.PromotionRepository
+}
+
+func NewPromotionService(promotionRepo repositories.PromotionRepository) services.PromotionService {
+	return &promotionService{
+		promotionRepo: promotionRepo,
+	}
+}
+
+func (s *promotionService) CreatePromotion(ctx context.Context, req *entities.CreatePromotionRequest) (*entities.Promotion, error) {
+	switch {
+	case !req.Type.IsValid():
+		return nil, fmt.Errorf("%w: ประเภทส่วนลด %q ไม่รองรับ", entities.ErrInvalidPromotion, req.Type)
+	case req.Type == entities.PromotionTypePercentage && req.Rate <= 0:
+		return nil, fmt.Errorf("%w: ส่วนลดแบบ percentage ต้องระบุ rate", entities.ErrInvalidPromotion)
+	case req.Type == entities.PromotionTypeFixed && !req.Amount.IsPositive():
+		return nil, fmt.Errorf("%w: ส่วนลดแบบ fixed ต้องระบุ amount", entities.ErrInvalidPromotion)
+	case req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt):
+		return nil, fmt.Errorf("%w: ends_at ต้องอยู่หลัง starts_at", entities.ErrInvalidPromotion)
+	case req.Amount.Currency != "" && req.MinSpend.Currency != "" && req.Amount.Currency != req.MinSpend.Currency:
+		return nil, fmt.Errorf("%w: amount และ min_spend ต้องเป็นสกุลเงินเดียวกัน", entities.ErrInvalidPromotion)
+	}
+
+	req.Code = normalizeCouponCode(req.Code)
+	return s.promotionRepo.Create(ctx, req)
+}
+
+func (s *promotionService) GetPromotions(ctx context.Context, page, limit int) ([]*entities.Promotion, *entities.PaginationResponse, error) {
+	promotions, total, err := s.promotionRepo.GetAll(ctx, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	pagination := &entities.PaginationResponse{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+		TotalItems: total,
+	}
+
+	return promotions, pagination, nil
+}
+
+func (s *promotionService) GetPromotionByID(ctx context.Context, id uuid.UUID) (*entities.Promotion, error) {
+	return s.promotionRepo.GetByID(ctx, id)
+}
+
+// UpdatePromotion แก้ไขโปรโมชัน คำสั่งซื้อที่ใช้คูปองไปแล้วจะไม่ถูกคำนวณใหม่
+func (s *promotionService) UpdatePromotion(ctx context.Context, id uuid.UUID, req *entities.UpdatePromotionRequest) (*entities.Promotion, error) {
+	if err := s.promotionRepo.Update(ctx, id, req); err != nil {
+		return nil, err
+	}
+	return s.promotionRepo.GetByID(ctx, id)
+}
+
+func (s *promotionService) DeletePromotion(ctx context.Context, id uuid.UUID) error {
+	return s.promotionRepo.Delete(ctx, id)
+}
+
+// normalizeCouponCode รหัสคูปองไม่สนใจตัวพิมพ์เล็กใหญ่และช่องว่างหัวท้าย
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
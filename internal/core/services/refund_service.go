@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/google/uuid"
+)
+
+type refundService struct {
+	refundRepo      repositories.RefundRepository
+	transactionRepo repositories.TransactionRepository
+	paymentGateways map[string]gateways.PaymentGateway
+	stockMonitor    services.StockMonitor
+}
+
+func NewRefundService(refundRepo repositories.RefundRepository, transactionRepo repositories.TransactionRepository, paymentGateways map[string]gateways.PaymentGateway, stockMonitor services.StockMonitor) services.RefundService {
+	return &refundService{
+		refundRepo:      refundRepo,
+		transactionRepo: transactionRepo,
+		paymentGateways: paymentGateways,
+		stockMonitor:    stockMonitor,
+	}
+}
+
+// CreateRefund จองยอดคืนเงินไว้ก่อน แล้วจึงสั่งคืนเงินที่ payment gateway
+// ถ้า gateway คืนเงินไม่สำเร็จ การคืนเงินจะถูกบันทึกเป็น failed และยอดที่จองไว้จะถูกปล่อยคืน
+func (s *refundService) CreateRefund(ctx context.Context, transactionID, actorID uuid.UUID, req *entities.CreateRefundRequest) (*entities.Refund, error) {
+	transaction, err := s.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, entities.ErrPaymentNotFound
+	}
+
+	gateway, ok := s.paymentGateways[transaction.PaymentMethod]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", entities.ErrUnsupportedPaymentMethod, transaction.PaymentMethod)
+	}
+
+	refund, err := s.refundRepo.Create(ctx, transactionID, req, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := gateway.Refund(ctx, transaction.ProviderRef, refund.Amount)
+	if err == nil && result.Status != entities.GatewayStatusRefunded {
+		err = fmt.Errorf("gateway ตอบกลับสถานะ %s", result.Status)
+	}
+	if err != nil {
+		if failErr := s.refundRepo.Fail(ctx, refund.ID, err.Error()); failErr != nil {
+			return nil, failErr
+		}
+		return nil, fmt.Errorf("%w: %v", entities.ErrRefundFailed, err)
+	}
+
+	refund, err = s.refundRepo.Complete(ctx, refund.ID, result.ProviderRef, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if refund.Restock {
+		productIDs := make([]uuid.UUID, 0, len(refund.Items))
+		for _, item := range refund.Items {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		s.stockMonitor.StockChanged(productIDs...)
+	}
+
+	return refund, nil
+}
+
+func (s *refundService) GetRefunds(ctx context.Context, transactionID uuid.UUID) ([]*entities.Refund, error) {
+	if _, err := s.transactionRepo.GetByID(ctx, transactionID); err != nil {
+		return nil, entities.ErrPaymentNotFound
+	}
+
+	return s.refundRepo.GetByTransactionID(ctx, transactionID)
+}
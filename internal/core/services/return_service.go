@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/google/uuid"
+)
+
+// returnStatusTransitions การเปลี่ยนสถานะที่อนุญาตของคำขอคืนสินค้า
+// rejected completed และ cancelled เป็นสถานะสุดท้าย
+var returnStatusTransitions = map[entities.ReturnStatus][]entities.ReturnStatus{
+	entities.ReturnStatusRequested: {entities.ReturnStatusApproved, entities.ReturnStatusRejected, entities.ReturnStatusCancelled},
+	entities.ReturnStatusApproved:  {entities.ReturnStatusReceived, entities.ReturnStatusCancelled},
+	entities.ReturnStatusReceived:  {entities.ReturnStatusInspected},
+	entities.ReturnStatusInspected: {entities.ReturnStatusCompleted},
+	entities.ReturnStatusRejected:  {},
+	entities.ReturnStatusCompleted: {},
+	entities.ReturnStatusCancelled: {},
+}
+
+// checkReturnStatusTransition ตรวจสอบการเปลี่ยนสถานะของคำขอคืนสินค้า
+func checkReturnStatusTransition(ret *entities.Return, to entities.ReturnStatus) error {
+	if !entities.CanTransition(returnStatusTransitions, ret.Status, to) {
+		return fmt.Errorf("%w: เปลี่ยนจาก %s เป็น %s ไม่ได้", entities.ErrReturnStatusConflict, ret.Status, to)
+	}
+	return nil
+}
+
+type returnService struct {
+	returnRepo      repositories.ReturnRepository
+	orderRepo       repositories.OrderRepository
+	transactionRepo repositories.TransactionRepository
+	refundService   services.RefundService
+	stockMonitor    services.StockMonitor
+}
+
+func NewReturnService(returnRepo repositories.ReturnRepository, orderRepo repositories.OrderRepository, transactionRepo repositories.TransactionRepository, refundService services.RefundService, stockMonitor services.StockMonitor) services.ReturnService {
+	return &returnService{
+		returnRepo:      returnRepo,
+		orderRepo:       orderRepo,
+		transactionRepo: transactionRepo,
+		refundService:   refundService,
+		stockMonitor:    stockMonitor,
+	}
+}
+
+// CreateReturn สร้างคำขอคืนสินค้าของลูกค้า ขอคืนได้เฉพาะคำสั่งซื้อที่ชำระเงินแล้วและจัดส่งสำเร็จแล้ว
+// จำนวนที่ขอคืนต้องไม่เกินจำนวนที่ซื้อ หักจำนวนที่คืนเงินไปแล้วและที่อยู่ในคำขออื่นที่ยังไม่จบ
+func (s *returnService) CreateReturn(ctx context.Context, userID uuid.UUID, req *entities.CreateReturnRequest) (*entities.Return, error) {
+	order, err := s.orderRepo.GetByID(ctx, req.OrderID)
+	if err != nil || order.UserID != userID {
+		return nil, entities.ErrOrderNotFound
+	}
+
+	if order.ShippingStatus != entities.ShippingStatusDelivered {
+		return nil, fmt.Errorf("%w: คำสั่งซื้อยังจัดส่งไม่สำเร็จ", entities.ErrReturnNotAllowed)
+	}
+	if order.PaymentStatus != entities.PaymentStatusPaid && order.PaymentStatus != entities.PaymentStatusPartiallyRefunded {
+		return nil, fmt.Errorf("%w: คำสั่งซื้ออยู่ในสถานะการชำระเงิน %s", entities.ErrReturnNotAllowed, order.PaymentStatus)
+	}
+
+	return s.returnRepo.Create(ctx, userID, req)
+}
+
+func (s *returnService) GetUserReturns(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entities.Return, *entities.PaginationResponse, error) {
+	returns, total, err := s.returnRepo.GetByUserID(ctx, userID, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return returns, returnPagination(page, limit, total), nil
+}
+
+// GetUserReturn ดึงคำขอคืนสินค้าของลูกค้า คำขอของคนอื่นถือว่าไม่พบ
+func (s *returnService) GetUserReturn(ctx context.Context, userID, id uuid.UUID) (*entities.Return, error) {
+	ret, err := s.returnRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ret.UserID != userID {
+		return nil, entities.ErrReturnNotFound
+	}
+	return ret, nil
+}
+
+// CancelReturn ลูกค้ายกเลิกคำขอคืนสินค้าได้จนกว่าร้านจะได้รับสินค้าคืน
+func (s *returnService) CancelReturn(ctx context.Context, userID, id uuid.UUID) (*entities.Return, error) {
+	ret, err := s.GetUserReturn(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.transition(ctx, ret, entities.ReturnStatusCancelled, userID, "ลูกค้ายกเลิกคำขอคืนสินค้า")
+}
+
+func (s *returnService) GetReturns(ctx context.Context, status entities.ReturnStatus, page, limit int) ([]*entities.Return, *entities.PaginationResponse, error) {
+	returns, total, err := s.returnRepo.GetAll(ctx, status, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return returns, returnPagination(page, limit, total), nil
+}
+
+func returnPagination(page, limit, total int) *entities.PaginationResponse {
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &entities.PaginationResponse{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+		TotalItems: total,
+	}
+}
+
+func (s *returnService) GetReturn(ctx context.Context, id uuid.UUID) (*entities.Return, error) {
+	return s.returnRepo.GetByID(ctx, id)
+}
+
+func (s *returnService) ApproveReturn(ctx context.Context, id, actorID uuid.UUID, note string) (*entities.Return, error) {
+	return s.transitionByID(ctx, id, entities.ReturnStatusApproved, actorID, note)
+}
+
+func (s *returnService) RejectReturn(ctx context.Context, id, actorID uuid.UUID, note string) (*entities.Return, error) {
+	return s.transitionByID(ctx, id, entities.ReturnStatusRejected, actorID, note)
+}
+
+func (s *returnService) ReceiveReturn(ctx context.Context, id, actorID uuid.UUID, note string) (*entities.Return, error) {
+	return s.transitionByID(ctx, id, entities.ReturnStatusReceived, actorID, note)
+}
+
+// InspectReturn บันทึกผลการตรวจสินค้าที่ได้รับคืน แล้วคืนเงินและคืนสต็อกตามผลการตรวจทันที
+func (s *returnService) InspectReturn(ctx context.Context, id, actorID uuid.UUID, req *entities.InspectReturnRequest) (*entities.Return, error) {
+	ret, err := s.returnRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkReturnStatusTransition(ret, entities.ReturnStatusInspected); err != nil {
+		return nil, err
+	}
+
+	if err := s.returnRepo.Inspect(ctx, id, req, actorID); err != nil {
+		return nil, err
+	}
+
+	return s.settle(ctx, id, actorID)
+}
+
+// RefundReturn ลองคืนเงินและปิดคำขอคืนสินค้าที่ตรวจแล้วอีกครั้ง หลังจากการคืนเงินครั้งก่อนไม่สำเร็จ
+func (s *returnService) RefundReturn(ctx context.Context, id, actorID uuid.UUID) (*entities.Return, error) {
+	return s.settle(ctx, id, actorID)
+}
+
+// settle คืนเงินตามจำนวนที่รับคืน แล้วคืนสต็อกและปิดคำขอคืนสินค้า
+// สต็อกคืนผ่าน ReturnRepository.Complete ไม่ใช่ผ่าน Refund เพื่อให้คืนเฉพาะสินค้าที่ตรวจแล้วว่านำกลับเข้าสต็อกได้
+// การคืนเงินถูกจองไว้ภายใต้ล็อคของคำขอก่อนเรียก gateway คำขอที่ผูกการคืนเงินไว้แล้วหรือกำลังคืนเงินอยู่จะไม่คืนเงินซ้ำ
+func (s *returnService) settle(ctx context.Context, id, actorID uuid.UUID) (*entities.Return, error) {
+	ret, err := s.returnRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkReturnStatusTransition(ret, entities.ReturnStatusCompleted); err != nil {
+		return nil, err
+	}
+
+	var refundItems []entities.RefundItemRequest
+	var restocked []uuid.UUID
+	for _, item := range ret.Items {
+		if item.AcceptedQuantity == 0 {
+			continue
+		}
+		refundItems = append(refundItems, entities.RefundItemRequest{
+			OrderItemID: item.OrderItemID,
+			Quantity:    item.AcceptedQuantity,
+		})
+		if item.Restock {
+			restocked = append(restocked, item.ProductID)
+		}
+	}
+
+	if len(refundItems) > 0 {
+		claimed, err := s.returnRepo.ClaimRefund(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			if err := s.refund(ctx, ret, actorID, refundItems); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.returnRepo.Complete(ctx, id, actorID, "คืนเงินและคืนสต็อกตามผลการตรวจแล้ว"); err != nil {
+		return nil, err
+	}
+
+	if len(restocked) > 0 {
+		s.stockMonitor.StockChanged(restocked...)
+	}
+
+	return s.returnRepo.GetByID(ctx, id)
+}
+
+// refund คืนเงินของคำขอคืนสินค้าที่จองการคืนเงินไว้แล้ว ถ้าคืนเงินไม่สำเร็จจะปล่อยการจองเพื่อให้ลองใหม่ได้
+func (s *returnService) refund(ctx context.Context, ret *entities.Return, actorID uuid.UUID, items []entities.RefundItemRequest) error {
+	transactionID, err := s.completedTransactionID(ctx, ret.OrderID)
+	if err != nil {
+		return s.releaseRefund(ctx, ret.ID, err)
+	}
+
+	refund, err := s.refundService.CreateRefund(ctx, transactionID, actorID, &entities.CreateRefundRequest{
+		Reason: fmt.Sprintf("คืนสินค้า %s: %s", ret.ID, ret.Reason),
+		Items:  items,
+	})
+	if err != nil {
+		return s.releaseRefund(ctx, ret.ID, err)
+	}
+
+	return s.returnRepo.AttachRefund(ctx, ret.ID, refund.ID)
+}
+
+// releaseRefund ปล่อยการจองคืนเงินแล้วคืน err เดิม
+func (s *returnService) releaseRefund(ctx context.Context, id uuid.UUID, err error) error {
+	if releaseErr := s.returnRepo.ReleaseRefund(ctx, id); releaseErr != nil {
+		return releaseErr
+	}
+	return err
+}
+
+// completedTransactionID หาธุรกรรมที่ชำระเงินสำเร็จของคำสั่งซื้อ ซึ่งเป็นธุรกรรมที่ต้องคืนเงิน
+func (s *returnService) completedTransactionID(ctx context.Context, orderID uuid.UUID) (uuid.UUID, error) {
+	transactions, err := s.transactionRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	for _, transaction := range transactions {
+		if transaction.Status == entities.TransactionStatusCompleted {
+			return transaction.ID, nil
+		}
+	}
+
+	return uuid.Nil, fmt.Errorf("%w: ไม่พบธุรกรรมที่ชำระเงินสำเร็จของคำสั่งซื้อ %s", entities.ErrRefundNotAllowed, orderID)
+}
+
+func (s *returnService) transitionByID(ctx context.Context, id uuid.UUID, to entities.ReturnStatus, actorID uuid.UUID, note string) (*entities.Return, error) {
+	ret, err := s.returnRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, ret, to, actorID, note)
+}
+
+// transition เปลี่ยนสถานะคำขอคืนสินค้า repository จะตรวจซ้ำว่าสถานะยังเป็นค่าเดิมตอนบันทึก
+func (s *returnService) transition(ctx context.Context, ret *entities.Return, to entities.ReturnStatus, actorID uuid.UUID, note string) (*entities.Return, error) {
+	if err := checkReturnStatusTransition(ret, to); err != nil {
+		return nil, err
+	}
+
+	if err := s.returnRepo.UpdateStatus(ctx, ret.ID, ret.Status, to, actorID, note); err != nil {
+		return nil, err
+	}
+
+	return s.returnRepo.GetByID(ctx, ret.ID)
+}
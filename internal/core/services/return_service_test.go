@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/google/uuid"
+)
+
+var allReturnStatuses = []entities.ReturnStatus{
+	entities.ReturnStatusRequested,
+	entities.ReturnStatusApproved,
+	entities.ReturnStatusRejected,
+	entities.ReturnStatusReceived,
+	entities.ReturnStatusInspected,
+	entities.ReturnStatusCompleted,
+	entities.ReturnStatusCancelled,
+}
+
+// stubOrderRepo คืนคำสั่งซื้อที่กำหนดไว้จาก GetByID เท่านั้น
+type stubOrderRepo struct {
+	repositories.OrderRepository
+	order *entities.Order
+}
+
+func (r stubOrderRepo) GetByID(ctx context.Context, id uuid.UUID) (*entities.Order, error) {
+	if r.order == nil || r.order.ID != id {
+		return nil, errors.New("record not found")
+	}
+	return r.order, nil
+}
+
+// unusedReturnRepo ทำให้การทดสอบล้มถ้า service สร้างคำขอคืนสินค้าที่ควรถูกปฏิเสธ
+type unusedReturnRepo struct {
+	repositories.ReturnRepository
+}
+
+// inspectedReturnRepo เก็บคำขอคืนสินค้าที่ตรวจแล้วในหน่วยความจำ และจองการคืนเงินภายใต้ mutex แทนล็อคของแถว
+type inspectedReturnRepo struct {
+	repositories.ReturnRepository
+	mu      sync.Mutex
+	ret     entities.Return
+	claimed bool
+}
+
+func (r *inspectedReturnRepo) GetByID(ctx context.Context, id uuid.UUID) (*entities.Return, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ret := r.ret
+	return &ret, nil
+}
+
+func (r *inspectedReturnRepo) ClaimRefund(ctx context.Context, id uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ret.Status != entities.ReturnStatusInspected {
+		return false, entities.ErrReturnStatusConflict
+	}
+	if r.ret.RefundID != nil {
+		return false, nil
+	}
+	if r.claimed {
+		return false, entities.ErrReturnStatusConflict
+	}
+	r.claimed = true
+	return true, nil
+}
+
+func (r *inspectedReturnRepo) AttachRefund(ctx context.Context, id, refundID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ret.RefundID = &refundID
+	r.claimed = false
+	return nil
+}
+
+func (r *inspectedReturnRepo) ReleaseRefund(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.claimed = false
+	return nil
+}
+
+func (r *inspectedReturnRepo) Complete(ctx context.Context, id, actorID uuid.UUID, note string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ret.Status != entities.ReturnStatusInspected {
+		return entities.ErrReturnStatusConflict
+	}
+	r.ret.Status = entities.ReturnStatusCompleted
+	return nil
+}
+
+func (r *stubTransactionRepo) GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entities.Transaction, error) {
+	return []*entities.Transaction{r.transaction}, nil
+}
+
+// blockingRefundService นับจำนวนครั้งที่ถูกเรียกคืนเงิน และรอ release ก่อนตอบ เพื่อจำลอง gateway ที่ตอบช้า
+type blockingRefundService struct {
+	mu      sync.Mutex
+	calls   int
+	errs    []error
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingRefundService) CreateRefund(ctx context.Context, transactionID, actorID uuid.UUID, req *entities.CreateRefundRequest) (*entities.Refund, error) {
+	s.mu.Lock()
+	call := s.calls
+	s.calls++
+	s.mu.Unlock()
+
+	if s.started != nil {
+		s.started <- struct{}{}
+		<-s.release
+	}
+	if call < len(s.errs) && s.errs[call] != nil {
+		return nil, s.errs[call]
+	}
+	return &entities.Refund{ID: uuid.New()}, nil
+}
+
+func (s *blockingRefundService) GetRefunds(ctx context.Context, transactionID uuid.UUID) ([]*entities.Refund, error) {
+	return nil, nil
+}
+
+func newInspectedReturn() entities.Return {
+	return entities.Return{
+		ID:      uuid.New(),
+		OrderID: uuid.New(),
+		Status:  entities.ReturnStatusInspected,
+		Items:   []entities.ReturnItem{{OrderItemID: uuid.New(), ProductID: uuid.New(), Quantity: 1, AcceptedQuantity: 1}},
+	}
+}
+
+func TestRefundReturn_ConcurrentCallsRefundOnce(t *testing.T) {
+	ctx := context.Background()
+	returnRepo := &inspectedReturnRepo{ret: newInspectedReturn()}
+	transactionRepo := &stubTransactionRepo{transaction: &entities.Transaction{ID: uuid.New(), Status: entities.TransactionStatusCompleted}}
+	refundService := &blockingRefundService{started: make(chan struct{}), release: make(chan struct{})}
+	service := NewReturnService(returnRepo, nil, transactionRepo, refundService, nil)
+
+	// การเรียกแรกจองการคืนเงินแล้วค้างอยู่ที่ gateway
+	done := make(chan error)
+	go func() {
+		_, err := service.RefundReturn(ctx, returnRepo.ret.ID, uuid.Nil)
+		done <- err
+	}()
+	<-refundService.started
+
+	// การเรียกที่ซ้อนเข้ามาระหว่างนั้นต้องไม่คืนเงินซ้ำ
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = service.RefundReturn(ctx, returnRepo.ret.ID, uuid.Nil)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if !errors.Is(err, entities.ErrReturnStatusConflict) {
+			t.Errorf("expected ErrReturnStatusConflict while the refund is in progress, got %v", err)
+		}
+	}
+
+	close(refundService.release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if refundService.calls != 1 {
+		t.Errorf("expected exactly one gateway refund, got %d", refundService.calls)
+	}
+	if returnRepo.ret.Status != entities.ReturnStatusCompleted || returnRepo.ret.RefundID == nil {
+		t.Errorf("expected a completed return with its refund attached, got %s refund=%v", returnRepo.ret.Status, returnRepo.ret.RefundID)
+	}
+}
+
+func TestRefundReturn_FailedRefundReleasesClaim(t *testing.T) {
+	ctx := context.Background()
+	returnRepo := &inspectedReturnRepo{ret: newInspectedReturn()}
+	transactionRepo := &stubTransactionRepo{transaction: &entities.Transaction{ID: uuid.New(), Status: entities.TransactionStatusCompleted}}
+	refundService := &blockingRefundService{errs: []error{entities.ErrRefundFailed}}
+	service := NewReturnService(returnRepo, nil, transactionRepo, refundService, nil)
+
+	if _, err := service.RefundReturn(ctx, returnRepo.ret.ID, uuid.Nil); !errors.Is(err, entities.ErrRefundFailed) {
+		t.Fatalf("expected ErrRefundFailed, got %v", err)
+	}
+	if returnRepo.claimed {
+		t.Fatal("expected the failed refund to release its claim")
+	}
+
+	if _, err := service.RefundReturn(ctx, returnRepo.ret.ID, uuid.Nil); err != nil {
+		t.Fatal(err)
+	}
+	if refundService.calls != 2 || returnRepo.ret.Status != entities.ReturnStatusCompleted {
+		t.Errorf("expected the retry to refund and complete, got %d calls and status %s", refundService.calls, returnRepo.ret.Status)
+	}
+}
+
+func TestCheckReturnStatusTransition_Table(t *testing.T) {
+	allowed := map[[2]entities.ReturnStatus]bool{
+		{entities.ReturnStatusRequested, entities.ReturnStatusApproved}:  true,
+		{entities.ReturnStatusRequested, entities.ReturnStatusRejected}:  true,
+		{entities.ReturnStatusRequested, entities.ReturnStatusCancelled}: true,
+		{entities.ReturnStatusApproved, entities.ReturnStatusReceived}:   true,
+		{entities.ReturnStatusApproved, entities.ReturnStatusCancelled}:  true,
+		{entities.ReturnStatusReceived, entities.ReturnStatusInspected}:  true,
+		{entities.ReturnStatusInspected, entities.ReturnStatusCompleted}: true,
+	}
+
+	for _, from := range allReturnStatuses {
+		for _, to := range allReturnStatuses {
+			t.Run(string(from)+"->"+string(to), func(t *testing.T) {
+				err := checkReturnStatusTransition(&entities.Return{Status: from}, to)
+
+				if allowed[[2]entities.ReturnStatus{from, to}] {
+					if err != nil {
+						t.Fatalf("expected transition to be allowed, got %v", err)
+					}
+					return
+				}
+				if !errors.Is(err, entities.ErrReturnStatusConflict) {
+					t.Fatalf("expected ErrReturnStatusConflict, got %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestReturnStatus_IsOpen(t *testing.T) {
+	open := map[entities.ReturnStatus]bool{
+		entities.ReturnStatusRequested: true,
+		entities.ReturnStatusApproved:  true,
+		entities.ReturnStatusReceived:  true,
+		entities.ReturnStatusInspected: true,
+	}
+
+	for _, status := range allReturnStatuses {
+		if status.IsOpen() != open[status] {
+			t.Errorf("expected %s open=%v", status, open[status])
+		}
+		// สถานะที่ยังไม่จบต้องเปลี่ยนต่อได้ และสถานะสุดท้ายต้องเปลี่ยนต่อไม่ได้
+		if (len(returnStatusTransitions[status]) > 0) != open[status] {
+			t.Errorf("expected %s to have transitions only while open", status)
+		}
+	}
+}
+
+func TestCreateReturn_RejectsIneligibleOrders(t *testing.T) {
+	userID := uuid.New()
+	newOrder := func(userID uuid.UUID, payment entities.PaymentStatus, shipping entities.ShippingStatus) *entities.Order {
+		return &entities.Order{
+			ID:             uuid.New(),
+			UserID:         userID,
+			Status:         entities.OrderStatusProcessing,
+			PaymentStatus:  payment,
+			ShippingStatus: shipping,
+		}
+	}
+
+	cases := []struct {
+		name  string
+		order *entities.Order
+		want  error
+	}{
+		{"order of another user", newOrder(uuid.New(), entities.PaymentStatusPaid, entities.ShippingStatusDelivered), entities.ErrOrderNotFound},
+		{"not shipped", newOrder(userID, entities.PaymentStatusPaid, entities.ShippingStatusPending), entities.ErrReturnNotAllowed},
+		{"still in transit", newOrder(userID, entities.PaymentStatusPaid, entities.ShippingStatusInTransit), entities.ErrReturnNotAllowed},
+		{"returned to sender", newOrder(userID, entities.PaymentStatusPaid, entities.ShippingStatusReturned), entities.ErrReturnNotAllowed},
+		{"fully refunded", newOrder(userID, entities.PaymentStatusRefunded, entities.ShippingStatusDelivered), entities.ErrReturnNotAllowed},
+		{"unpaid", newOrder(userID, entities.PaymentStatusPending, entities.ShippingStatusDelivered), entities.ErrReturnNotAllowed},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := NewReturnService(unusedReturnRepo{}, stubOrderRepo{order: tc.order}, nil, nil, nil)
+
+			_, err := service.CreateReturn(context.Background(), userID, &entities.CreateReturnRequest{
+				OrderID: tc.order.ID,
+				Reason:  "damaged",
+				Items:   []entities.ReturnItemRequest{{OrderItemID: uuid.New(), Quantity: 1}},
+			})
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+
+	t.Run("unknown order", func(t *testing.T) {
+		service := NewReturnService(unusedReturnRepo{}, stubOrderRepo{}, nil, nil, nil)
+
+		_, err := service.CreateReturn(context.Background(), userID, &entities.CreateReturnRequest{OrderID: uuid.New()})
+		if !errors.Is(err, entities.ErrOrderNotFound) {
+			t.Fatalf("expected ErrOrderNotFound, got %v", err)
+		}
+	})
+}
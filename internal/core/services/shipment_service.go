@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/gateways"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/google/uuid"
+)
+
+// shipmentCreatedEventID EventID ของจุดติดตามแรกที่ระบบสร้างเองตอนส่งพัสดุ
+const shipmentCreatedEventID = "created"
+
+type shipmentService struct {
+	shipmentRepo repositories.ShipmentRepository
+	orderRepo    repositories.OrderRepository
+	// carriers จับคู่ชื่อผู้ให้บริการขนส่งกับ client ที่ใช้สร้างและติดตามพัสดุ
+	carriers map[string]gateways.CarrierClient
+}
+
+func NewShipmentService(shipmentRepo repositories.ShipmentRepository, orderRepo repositories.OrderRepository, carriers map[string]gateways.CarrierClient) services.ShipmentService {
+	return &shipmentService{
+		shipmentRepo: shipmentRepo,
+		orderRepo:    orderRepo,
+		carriers:     carriers,
+	}
+}
+
+// CreateShipment สร้างพัสดุของคำสั่งซื้อ แล้วอัพเดทสถานะการจัดส่งของคำสั่งซื้อตามพัสดุทั้งหมด
+// พัสดุแรกจะเปลี่ยนคำสั่งซื้อเป็น shipped จึงต้องผ่านเงื่อนไขเดียวกับการเปลี่ยนสถานะการจัดส่งด้วยมือ
+func (s *shipmentService) CreateShipment(ctx context.Context, orderID, actorID uuid.UUID, req *entities.CreateShipmentRequest) (*entities.Shipment, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, entities.ErrOrderNotFound
+	}
+
+	items, err := entities.ResolveShipmentItems(order.OrderItems, order.ShippedQuantities(), req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	shipment := &entities.Shipment{
+		OrderID:        orderID,
+		Carrier:        strings.ToLower(strings.TrimSpace(req.Carrier)),
+		TrackingNumber: strings.TrimSpace(req.TrackingNumber),
+		Status:         entities.ShippingStatusShipped,
+		Items:          items,
+		ShippedAt:      now,
+	}
+
+	// ตรวจก่อนขอเลขพัสดุจากผู้ให้บริการขนส่ง เพื่อไม่ให้สร้างพัสดุของคำสั่งซื้อที่ยังส่งไม่ได้
+	// (repository ตรวจซ้ำอีกครั้งหลังล็อคแถวของคำสั่งซื้อตอนบันทึกสถานะ)
+	next := entities.AggregateShippingStatus(append(order.Shipments, *shipment), fullyShippedWith(order, items))
+	if next != order.ShippingStatus {
+		if err := entities.CheckShippingStatusTransition(order, next); err != nil {
+			return nil, err
+		}
+	}
+
+	if shipment.TrackingNumber == "" {
+		carrier, ok := s.carriers[shipment.Carrier]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", entities.ErrUnknownCarrier, shipment.Carrier)
+		}
+
+		result, err := carrier.CreateShipment(ctx, &entities.CarrierShipmentRequest{
+			Reference: order.ID.String(),
+			Address:   order.ShippingAddress,
+			Weight:    shipmentWeight(order, items),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", entities.ErrCarrierFailed, err)
+		}
+		shipment.TrackingNumber = result.TrackingNumber
+	}
+
+	shipment.Checkpoints = []entities.TrackingCheckpoint{{
+		EventID:     shipmentCreatedEventID,
+		Status:      entities.ShippingStatusShipped,
+		Description: fmt.Sprintf("ร้านค้าส่งพัสดุให้ %s แล้ว", shipment.Carrier),
+		OccurredAt:  now,
+	}}
+
+	created, err := s.shipmentRepo.Create(ctx, shipment)
+	if err != nil {
+		return nil, err
+	}
+
+	note := req.Note
+	if note == "" {
+		note = fmt.Sprintf("ส่งพัสดุ %s %s", created.Carrier, created.TrackingNumber)
+	}
+	if err := s.updateOrderShippingStatus(ctx, orderID, actorID, note); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// SyncShipment ดึงเหตุการณ์ล่าสุดของพัสดุจากผู้ให้บริการขนส่งทันที แล้วคืนพัสดุที่อัพเดทแล้ว
+func (s *shipmentService) SyncShipment(ctx context.Context, id uuid.UUID) (*entities.Shipment, error) {
+	shipment, err := s.shipmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.syncShipment(ctx, shipment); err != nil {
+		return nil, err
+	}
+
+	return s.shipmentRepo.GetByID(ctx, id)
+}
+
+// SyncShipments ติดตามพัสดุทุกกล่องที่ยังไม่ถึงปลายทาง พัสดุที่ติดตามไม่สำเร็จจะไม่หยุดการติดตามกล่องอื่น
+// พัสดุของผู้ให้บริการที่ไม่มี CarrierClient ต้องอัพเดทสถานะด้วยมือจึงถูกข้ามไป
+func (s *shipmentService) SyncShipments(ctx context.Context) error {
+	shipments, err := s.shipmentRepo.GetActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, shipment := range shipments {
+		if _, ok := s.carriers[shipment.Carrier]; !ok {
+			continue
+		}
+		if err := s.syncShipment(ctx, shipment); err != nil {
+			errs = append(errs, fmt.Errorf("shipment %s: %w", shipment.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// syncShipment บันทึกเหตุการณ์ใหม่ของพัสดุเป็นจุดติดตาม และเลื่อนสถานะของพัสดุกับคำสั่งซื้อตาม
+// เหตุการณ์ที่มาช้ากว่าสถานะปัจจุบันจะถูกบันทึกเป็นจุดติดตามอย่างเดียว ไม่ย้อนสถานะของพัสดุ
+func (s *shipmentService) syncShipment(ctx context.Context, shipment *entities.Shipment) error {
+	carrier, ok := s.carriers[shipment.Carrier]
+	if !ok {
+		return fmt.Errorf("%w: %s", entities.ErrUnknownCarrier, shipment.Carrier)
+	}
+
+	events, err := carrier.Track(ctx, shipment.TrackingNumber)
+	if err != nil {
+		return fmt.Errorf("%w: %v", entities.ErrCarrierFailed, err)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+
+	recorded := make(map[string]bool, len(shipment.Checkpoints))
+	for _, checkpoint := range shipment.Checkpoints {
+		recorded[checkpoint.EventID] = true
+	}
+
+	status := shipment.Status
+	var checkpoints []entities.TrackingCheckpoint
+	for _, event := range events {
+		if recorded[event.EventID] {
+			continue
+		}
+		recorded[event.EventID] = true
+
+		checkpoints = append(checkpoints, entities.TrackingCheckpoint{
+			EventID:     event.EventID,
+			Status:      event.Status,
+			Location:    event.Location,
+			Description: event.Description,
+			OccurredAt:  event.OccurredAt,
+		})
+		if status.CanTransitionTo(event.Status) {
+			status = event.Status
+		}
+	}
+
+	if len(checkpoints) == 0 {
+		return nil
+	}
+
+	if err := s.shipmentRepo.AddCheckpoints(ctx, shipment.ID, status, checkpoints); err != nil {
+		return err
+	}
+	if status == shipment.Status {
+		return nil
+	}
+
+	note := fmt.Sprintf("อัพเดทจาก %s: %s", shipment.Carrier, shipment.TrackingNumber)
+	return s.updateOrderShippingStatus(ctx, shipment.OrderID, uuid.Nil, note)
+}
+
+// updateOrderShippingStatus สรุปสถานะการจัดส่งของคำสั่งซื้อจากพัสดุทั้งหมด แล้วบันทึกถ้าเปลี่ยนไป
+// เลขพัสดุแรกจะถูกเก็บไว้ใน Order.TrackingNumber ด้วย
+func (s *shipmentService) updateOrderShippingStatus(ctx context.Context, orderID, actorID uuid.UUID, note string) error {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return entities.ErrOrderNotFound
+	}
+
+	next := entities.AggregateShippingStatus(order.Shipments, order.FullyShipped())
+	if next == order.ShippingStatus {
+		return nil
+	}
+
+	var trackingNumber string
+	if order.TrackingNumber == "" && len(order.Shipments) > 0 {
+		trackingNumber = order.Shipments[0].TrackingNumber
+	}
+
+	return s.orderRepo.UpdateShippingStatus(ctx, orderID, next, trackingNumber, actorID, note)
+}
+
+// fullyShippedWith ตรวจสอบว่าคำสั่งซื้อจะส่งสินค้าครบทุกรายการหรือไม่ ถ้าส่งสินค้า items เพิ่ม
+func fullyShippedWith(order *entities.Order, items []entities.ShipmentItem) bool {
+	shipped := order.ShippedQuantities()
+	for _, item := range items {
+		shipped[item.OrderItemID] += item.Quantity
+	}
+	for _, orderItem := range order.OrderItems {
+		if shipped[orderItem.ID] < orderItem.Quantity {
+			return false
+		}
+	}
+	return true
+}
+
+// shipmentWeight น้ำหนักรวมของสินค้าในพัสดุเป็นกรัม
+func shipmentWeight(order *entities.Order, items []entities.ShipmentItem) int {
+	weights := make(map[uuid.UUID]int, len(order.OrderItems))
+	for _, orderItem := range order.OrderItems {
+		if orderItem.Product != nil {
+			weights[orderItem.ID] = orderItem.Product.Weight
+		}
+	}
+
+	var weight int
+	for _, item := range items {
+		weight += weights[item.OrderItemID] * item.Quantity
+	}
+	return weight
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+)
+
+type shipmentTracker struct {
+	shipmentService services.ShipmentService
+	interval        time.Duration
+}
+
+// NewShipmentTracker สร้างงานเบื้องหลังที่ดึงสถานะพัสดุจากผู้ให้บริการขนส่งทุก interval
+func NewShipmentTracker(shipmentService services.ShipmentService, interval time.Duration) services.ShipmentTracker {
+	return &shipmentTracker{
+		shipmentService: shipmentService,
+		interval:        interval,
+	}
+}
+
+// Start เริ่ม goroutine ที่ติดตามพัสดุทันทีหนึ่งครั้ง แล้วติดตามซ้ำทุก interval จนกว่า ctx จะถูกยกเลิก
+func (t *shipmentTracker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := t.shipmentService.SyncShipments(ctx); err != nil {
+				log.Printf("Error syncing shipments: %v\n", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
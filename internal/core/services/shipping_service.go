@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/google/uuid"
+)
+
+type shippingService struct {
+	shippingRepo repositories.ShippingMethodRepository
+	addressRepo  repositories.AddressRepository
+	cartService  services.CartService
+}
+
+func NewShippingService(shippingRepo repositories.ShippingMethodRepository, addressRepo repositories.AddressRepository, cartService services.CartService) services.ShippingService {
+	return &shippingService{
+		shippingRepo: shippingRepo,
+		addressRepo:  addressRepo,
+		cartService:  cartService,
+	}
+}
+
+func (s *shippingService) CreateMethod(ctx context.Context, req *entities.CreateShippingMethodRequest) (*entities.ShippingMethod, error) {
+	if !req.RateType.IsValid() {
+		return nil, fmt.Errorf("%w: วิธีคิดค่าจัดส่ง %q ไม่รองรับ", entities.ErrInvalidShippingMethod, req.RateType)
+	}
+
+	// จำนวนเงินทุกช่องต้องเป็นสกุลเงินเดียวกับค่าจัดส่งพื้นฐาน
+	currency := entities.NewMoney(0, req.Fee.Currency).Currency
+	amounts := []entities.Money{req.FeePerKg, req.FreeOver}
+	for _, region := range req.Regions {
+		amounts = append(amounts, region.Fee)
+	}
+	for _, amount := range amounts {
+		if amount.Currency != "" && amount.Currency != currency {
+			return nil, fmt.Errorf("%w: จำนวนเงินทั้งหมดต้องเป็นสกุลเงิน %s", entities.ErrInvalidShippingMethod, currency)
+		}
+	}
+
+	req.Code = strings.ToLower(strings.TrimSpace(req.Code))
+	return s.shippingRepo.Create(ctx, req)
+}
+
+func (s *shippingService) GetMethods(ctx context.Context, activeOnly bool) ([]*entities.ShippingMethod, error) {
+	return s.shippingRepo.GetAll(ctx, activeOnly)
+}
+
+func (s *shippingService) GetMethodByID(ctx context.Context, id uuid.UUID) (*entities.ShippingMethod, error) {
+	return s.shippingRepo.GetByID(ctx, id)
+}
+
+// UpdateMethod แก้ไขวิธีจัดส่ง คำสั่งซื้อที่สร้างไปแล้วยังคงใช้ค่าจัดส่งเดิม
+func (s *shippingService) UpdateMethod(ctx context.Context, id uuid.UUID, req *entities.UpdateShippingMethodRequest) (*entities.ShippingMethod, error) {
+	if req.RateType != nil && !req.RateType.IsValid() {
+		return nil, fmt.Errorf("%w: วิธีคิดค่าจัดส่ง %q ไม่รองรับ", entities.ErrInvalidShippingMethod, *req.RateType)
+	}
+
+	if err := s.shippingRepo.Update(ctx, id, req); err != nil {
+		return nil, err
+	}
+	return s.shippingRepo.GetByID(ctx, id)
+}
+
+func (s *shippingService) DeleteMethod(ctx context.Context, id uuid.UUID) error {
+	return s.shippingRepo.Delete(ctx, id)
+}
+
+// QuoteCart คำนวณค่าจัดส่งของตะกร้าปัจจุบันไปยังที่อยู่หรือจังหวัดที่ระบุ สำหรับทุกวิธีจัดส่งที่ให้บริการ
+// ยอดส่งฟรีคิดจากยอดหลังหักส่วนลด และคูปองส่งฟรีทำให้ค่าจัดส่งเป็นศูนย์
+func (s *shippingService) QuoteCart(ctx context.Context, userID uuid.UUID, req *entities.ShippingQuoteRequest) ([]*entities.ShippingQuote, error) {
+	cart, err := s.cartService.GetCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.CartItems) == 0 {
+		return nil, entities.ErrCartEmpty
+	}
+
+	input := entities.ShippingQuoteInput{
+		Subtotal: cart.DiscountedTotal,
+		Province: req.Province,
+	}
+	if req.AddressID != nil {
+		address, err := s.addressRepo.GetByID(ctx, userID, *req.AddressID)
+		if err != nil {
+			return nil, err
+		}
+		input.Province = address.Province
+	}
+	for _, item := range cart.CartItems {
+		if item.Product != nil {
+			input.Weight += item.Product.Weight * item.Quantity
+		}
+	}
+
+	methods, err := s.shippingRepo.GetAll(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	code := strings.ToLower(strings.TrimSpace(req.Method))
+	quotes := []*entities.ShippingQuote{}
+	for _, method := range methods {
+		if code != "" && method.Code != code {
+			continue
+		}
+
+		fee, err := method.Quote(input)
+		if errors.Is(err, entities.ErrShippingNotAvailable) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		quote := &entities.ShippingQuote{
+			MethodID:      method.ID,
+			Code:          method.Code,
+			Name:          method.Name,
+			EstimatedDays: method.EstimatedDays,
+			Fee:           fee,
+		}
+		if cart.FreeShipping || fee.IsZero() {
+			quote.Fee.Amount = 0
+			quote.FreeShipping = true
+		}
+		quotes = append(quotes, quote)
+	}
+
+	if code != "" && len(quotes) == 0 {
+		return nil, fmt.Errorf("%w: %s", entities.ErrShippingNotAvailable, code)
+	}
+	return quotes, nil
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/notifiers"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/google/uuid"
+)
+
+// stockMonitorQueueSize จำนวนการเปลี่ยนสต็อกที่รอตรวจได้ก่อนจะเริ่มทิ้ง
+const stockMonitorQueueSize = 256
+
+type stockMonitor struct {
+	productRepo repositories.ProductRepository
+	notifier    notifiers.LowStockNotifier
+	changes     chan []uuid.UUID
+
+	// alerted เก็บสินค้าที่แจ้งเตือนไปแล้ว เพื่อไม่ให้แจ้งซ้ำทุกครั้งที่ขายได้
+	// จะถูกล้างเมื่อสต็อกกลับมาสูงกว่าจุดสั่งซื้อ ใช้เฉพาะใน goroutine ของ Start
+	alerted map[uuid.UUID]bool
+}
+
+func NewStockMonitor(productRepo repositories.ProductRepository, notifier notifiers.LowStockNotifier) services.StockMonitor {
+	return &stockMonitor{
+		productRepo: productRepo,
+		notifier:    notifier,
+		changes:     make(chan []uuid.UUID, stockMonitorQueueSize),
+		alerted:     make(map[uuid.UUID]bool),
+	}
+}
+
+// Start เริ่ม goroutine ที่คอยตรวจสินค้าที่สต็อกเปลี่ยน จนกว่า ctx จะถูกยกเลิก
+func (m *stockMonitor) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case productIDs := <-m.changes:
+				m.check(ctx, drainProductIDs(m.changes, productIDs))
+			}
+		}
+	}()
+}
+
+// StockChanged ส่งสินค้าที่สต็อกเปลี่ยนเข้าคิวโดยไม่บล็อกผู้เรียก
+func (m *stockMonitor) StockChanged(productIDs ...uuid.UUID) {
+	enqueueProductIDs(m.changes, "stock monitor", productIDs)
+}
+
+// enqueueProductIDs ส่งสินค้าเข้าคิวของงานเบื้องหลังโดยไม่บล็อกผู้เรียก ถ้าคิวเต็มจะทิ้งและเขียน log
+func enqueueProductIDs(changes chan<- []uuid.UUID, name string, productIDs []uuid.UUID) {
+	if len(productIDs) == 0 {
+		return
+	}
+
+	select {
+	case changes <- productIDs:
+	default:
+		log.Printf("%s queue is full, skipping %d products\n", name, len(productIDs))
+	}
+}
+
+// drainProductIDs รวมสินค้าทั้งหมดที่รออยู่ในคิวเป็นชุดเดียว และตัดตัวซ้ำออก
+func drainProductIDs(changes <-chan []uuid.UUID, first []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool)
+	var productIDs []uuid.UUID
+
+	add := func(ids []uuid.UUID) {
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				productIDs = append(productIDs, id)
+			}
+		}
+	}
+
+	add(first)
+	for {
+		select {
+		case ids := <-changes:
+			add(ids)
+		default:
+			return productIDs
+		}
+	}
+}
+
+// check ตรวจจุดสั่งซื้อเพิ่มของสินค้าที่ระบุ และแจ้งเตือนเฉพาะสินค้าที่เพิ่งต่ำกว่าจุดสั่งซื้อ
+func (m *stockMonitor) check(ctx context.Context, productIDs []uuid.UUID) {
+	lowStock, _, err := m.productRepo.GetLowStockProducts(ctx, &entities.LowStockFilter{ProductIDs: productIDs})
+	if err != nil {
+		log.Printf("Error checking low stock: %v\n", err)
+		return
+	}
+
+	isLow := make(map[uuid.UUID]bool, len(lowStock))
+	var newlyLow []*entities.LowStockProduct
+	for _, product := range lowStock {
+		isLow[product.ProductID] = true
+		if !m.alerted[product.ProductID] {
+			newlyLow = append(newlyLow, product)
+		}
+	}
+
+	for _, id := range productIDs {
+		if !isLow[id] {
+			delete(m.alerted, id)
+		}
+	}
+
+	if len(newlyLow) == 0 {
+		return
+	}
+
+	if err := m.notifier.NotifyLowStock(ctx, newlyLow); err != nil {
+		log.Printf("Error sending low stock notification: %v\n", err)
+		return
+	}
+
+	for _, product := range newlyLow {
+		m.alerted[product.ProductID] = true
+	}
+}
+
+type stockMonitorGroup struct {
+	monitors []services.StockMonitor
+}
+
+// NewStockMonitorGroup รวมงานเบื้องหลังหลายตัวที่ต้องรู้เมื่อสต็อกเปลี่ยน ให้ service เรียกผ่าน StockMonitor ตัวเดียว
+func NewStockMonitorGroup(monitors ...services.StockMonitor) services.StockMonitor {
+	return &stockMonitorGroup{monitors: monitors}
+}
+
+func (g *stockMonitorGroup) Start(ctx context.Context) {
+	for _, monitor := range g.monitors {
+		monitor.Start(ctx)
+	}
+}
+
+func (g *stockMonitorGroup) StockChanged(productIDs ...uuid.UUID) {
+	for _, monitor := range g.monitors {
+		monitor.StockChanged(productIDs...)
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/google/uuid"
+)
+
+type taxService struct {
+	taxRuleRepo repositories.TaxRuleRepository
+}
+
+func NewTaxService(taxRuleRepo repositories.TaxRuleRepository) services.TaxService {
+	return &taxService{
+		taxRuleRepo: taxRuleRepo,
+	}
+}
+
+func (s *taxService) CreateTaxRule(ctx context.Context, req *entities.CreateTaxRuleRequest) (*entities.TaxRule, error) {
+	return s.taxRuleRepo.Create(ctx, req)
+}
+
+func (s *taxService) GetTaxRules(ctx context.Context) ([]*entities.TaxRule, error) {
+	return s.taxRuleRepo.GetAll(ctx)
+}
+
+func (s *taxService) GetTaxRuleByID(ctx context.Context, id uuid.UUID) (*entities.TaxRule, error) {
+	return s.taxRuleRepo.GetByID(ctx, id)
+}
+
+// UpdateTaxRule แก้ไขกฎภาษี มีผลกับคำสั่งซื้อใหม่เท่านั้น
+func (s *taxService) UpdateTaxRule(ctx context.Context, id uuid.UUID, req *entities.UpdateTaxRuleRequest) (*entities.TaxRule, error) {
+	if err := s.taxRuleRepo.Update(ctx, id, req); err != nil {
+		return nil, err
+	}
+	return s.taxRuleRepo.GetByID(ctx, id)
+}
+
+func (s *taxService) DeleteTaxRule(ctx context.Context, id uuid.UUID) error {
+	return s.taxRuleRepo.Delete(ctx, id)
+}
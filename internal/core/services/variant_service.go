@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/google/uuid"
+)
+
+type variantService struct {
+	variantRepo     repositories.ProductVariantRepository
+	stockMonitor    services.StockMonitor
+	wishlistMonitor services.WishlistMonitor
+}
+
+func NewVariantService(variantRepo repositories.ProductVariantRepository, stockMonitor services.StockMonitor, wishlistMonitor services.WishlistMonitor) services.VariantService {
+	return &variantService{
+		variantRepo:     variantRepo,
+		stockMonitor:    stockMonitor,
+		wishlistMonitor: wishlistMonitor,
+	}
+}
+
+func (s *variantService) CreateOption(ctx context.Context, productID uuid.UUID, req *entities.CreateProductOptionRequest) (*entities.ProductOption, error) {
+	return s.variantRepo.CreateOption(ctx, productID, req)
+}
+
+func (s *variantService) DeleteOption(ctx context.Context, productID, optionID uuid.UUID) error {
+	return s.variantRepo.DeleteOption(ctx, productID, optionID)
+}
+
+func (s *variantService) GetVariants(ctx context.Context, productID uuid.UUID) ([]*entities.ProductVariant, error) {
+	return s.variantRepo.GetByProductID(ctx, productID)
+}
+
+func (s *variantService) CreateVariant(ctx context.Context, productID, actorID uuid.UUID, req *entities.CreateVariantRequest) (*entities.ProductVariant, error) {
+	variant, err := s.variantRepo.Create(ctx, productID, actorID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Stock > 0 {
+		s.stockMonitor.StockChanged(productID)
+	}
+	return variant, nil
+}
+
+func (s *variantService) UpdateVariant(ctx context.Context, productID, variantID uuid.UUID, req *entities.UpdateVariantRequest) (*entities.ProductVariant, error) {
+	variant, err := s.variantRepo.Update(ctx, productID, variantID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// การล้างราคาของ variant ทำให้กลับไปใช้ราคาของสินค้าหลัก ราคาจึงเปลี่ยนเหมือนกัน
+	if req.Price != nil || req.ClearPrice {
+		s.wishlistMonitor.PriceChanged(productID)
+	}
+	return variant, nil
+}
+
+// DeleteVariant ลบ variant พร้อมตัดสต็อกที่เหลือออก สต็อกรวมของสินค้าจึงอาจลดลงจนต่ำกว่าจุดสั่งซื้อเพิ่ม
+func (s *variantService) DeleteVariant(ctx context.Context, productID, variantID, actorID uuid.UUID) error {
+	if err := s.variantRepo.Delete(ctx, productID, variantID, actorID); err != nil {
+		return err
+	}
+
+	s.stockMonitor.StockChanged(productID)
+	return nil
+}
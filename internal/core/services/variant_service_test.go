@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/google/uuid"
+)
+
+// stubVariantRepo ยอมรับการแก้ไข variant ทุกครั้ง
+type stubVariantRepo struct {
+	repositories.ProductVariantRepository
+}
+
+func (stubVariantRepo) Update(ctx context.Context, productID, variantID uuid.UUID, req *entities.UpdateVariantRequest) (*entities.ProductVariant, error) {
+	return &entities.ProductVariant{ID: variantID, ProductID: productID}, nil
+}
+
+// recordingWishlistMonitor เก็บสินค้าที่ถูกแจ้งว่าราคาเปลี่ยน
+type recordingWishlistMonitor struct {
+	services.WishlistMonitor
+	priceChanged []uuid.UUID
+}
+
+func (m *recordingWishlistMonitor) PriceChanged(productIDs ...uuid.UUID) {
+	m.priceChanged = append(m.priceChanged, productIDs...)
+}
+
+func TestUpdateVariant_NotifiesWishlistWhenPriceChanges(t *testing.T) {
+	price := entities.NewMoney(19900, "THB")
+	sku := "SKU-NEW"
+	tests := []struct {
+		name   string
+		req    entities.UpdateVariantRequest
+		notify bool
+	}{
+		{"price set", entities.UpdateVariantRequest{Price: &price}, true},
+		{"price cleared", entities.UpdateVariantRequest{ClearPrice: true}, true},
+		{"sku only", entities.UpdateVariantRequest{SKU: &sku}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productID := uuid.New()
+			monitor := &recordingWishlistMonitor{}
+			service := NewVariantService(stubVariantRepo{}, nil, monitor)
+
+			if _, err := service.UpdateVariant(context.Background(), productID, uuid.New(), &tt.req); err != nil {
+				t.Fatalf("UpdateVariant: %v", err)
+			}
+
+			notified := len(monitor.priceChanged) == 1 && monitor.priceChanged[0] == productID
+			if notified != tt.notify || (!tt.notify && len(monitor.priceChanged) != 0) {
+				t.Errorf("PriceChanged calls = %v, want notify %v for product %s", monitor.priceChanged, tt.notify, productID)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/notifiers"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/google/uuid"
+)
+
+type wishlistMonitor struct {
+	wishlistRepo repositories.WishlistRepository
+	notifier     notifiers.WishlistNotifier
+	changes      chan []uuid.UUID
+}
+
+func NewWishlistMonitor(wishlistRepo repositories.WishlistRepository, notifier notifiers.WishlistNotifier) services.WishlistMonitor {
+	return &wishlistMonitor{
+		wishlistRepo: wishlistRepo,
+		notifier:     notifier,
+		changes:      make(chan []uuid.UUID, stockMonitorQueueSize),
+	}
+}
+
+// Start เริ่ม goroutine ที่คอยตรวจรายการที่อยากได้ของสินค้าที่สต็อกหรือราคาเปลี่ยน จนกว่า ctx จะถูกยกเลิก
+func (m *wishlistMonitor) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case productIDs := <-m.changes:
+				m.check(ctx, drainProductIDs(m.changes, productIDs))
+			}
+		}
+	}()
+}
+
+func (m *wishlistMonitor) StockChanged(productIDs ...uuid.UUID) {
+	enqueueProductIDs(m.changes, "wishlist monitor", productIDs)
+}
+
+func (m *wishlistMonitor) PriceChanged(productIDs ...uuid.UUID) {
+	enqueueProductIDs(m.changes, "wishlist monitor", productIDs)
+}
+
+// check ส่งการแจ้งเตือนที่ยังไม่ได้ส่งของสินค้าที่ระบุ ถ้าส่งไม่สำเร็จจะไม่บันทึกว่าส่งแล้ว
+// การแจ้งเตือนจึงจะถูกส่งอีกครั้งเมื่อสินค้านั้นเปลี่ยนครั้งถัดไป
+func (m *wishlistMonitor) check(ctx context.Context, productIDs []uuid.UUID) {
+	notifications, err := m.wishlistRepo.PendingNotifications(ctx, productIDs)
+	if err != nil {
+		log.Printf("Error checking wishlist notifications: %v\n", err)
+		return
+	}
+
+	if len(notifications) == 0 {
+		return
+	}
+
+	if err := m.notifier.NotifyWishlist(ctx, notifications); err != nil {
+		log.Printf("Error sending wishlist notifications: %v\n", err)
+		return
+	}
+
+	if err := m.wishlistRepo.MarkNotified(ctx, notifications); err != nil {
+		log.Printf("Error marking wishlist notifications as sent: %v\n", err)
+	}
+}
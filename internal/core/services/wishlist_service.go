@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"math"
+
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/entities"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/repositories"
+	"github.com/Sup-Film/fiber-ecommerce-api/internal/core/domain/ports/services"
+	"github.com/google/uuid"
+)
+
+type wishlistService struct {
+	wishlistRepo repositories.WishlistRepository
+	cartService  services.CartService
+}
+
+func NewWishlistService(wishlistRepo repositories.WishlistRepository, cartService services.CartService) services.WishlistService {
+	return &wishlistService{
+		wishlistRepo: wishlistRepo,
+		cartService:  cartService,
+	}
+}
+
+func (s *wishlistService) GetWishlist(ctx context.Context, userID uuid.UUID, page, limit int) ([]*entities.WishlistItem, *entities.PaginationResponse, error) {
+	items, total, err := s.wishlistRepo.GetByUserID(ctx, userID, page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	pagination := &entities.PaginationResponse{
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+		TotalItems: total,
+	}
+
+	return items, pagination, nil
+}
+
+func (s *wishlistService) AddToWishlist(ctx context.Context, userID uuid.UUID, req *entities.AddToWishlistRequest) (*entities.WishlistItem, error) {
+	return s.wishlistRepo.Add(ctx, userID, req)
+}
+
+func (s *wishlistService) RemoveFromWishlist(ctx context.Context, userID, productID uuid.UUID) error {
+	return s.wishlistRepo.Remove(ctx, userID, productID)
+}
+
+// MoveToCart เพิ่มสินค้าลงตะกร้าด้วยเงื่อนไขเดียวกับการเพิ่มสินค้าตามปกติ (เช่นตรวจสต็อก)
+// สินค้าจะออกจากรายการที่อยากได้เมื่อเพิ่มลงตะกร้าสำเร็จแล้วเท่านั้น
+func (s *wishlistService) MoveToCart(ctx context.Context, userID, productID uuid.UUID, req *entities.MoveToCartRequest) (*entities.Cart, error) {
+	if _, err := s.wishlistRepo.Get(ctx, userID, productID); err != nil {
+		return nil, err
+	}
+
+	quantity := req.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+
+	if err := s.cartService.AddToCart(ctx, userID, &entities.AddToCartRequest{
+		ProductID: productID,
+		VariantID: req.VariantID,
+		Quantity:  quantity,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.wishlistRepo.Remove(ctx, userID, productID); err != nil {
+		return nil, err
+	}
+
+	return s.cartService.GetCart(ctx, userID)
+}
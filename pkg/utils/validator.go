@@ -12,6 +12,12 @@ func ValidateStruct(s interface{}) error {
 	return validate.Struct(s)
 }
 
+// RegisterCustomType ให้ validator ตรวจฟิลด์ของชนิดที่ระบุด้วยค่าที่ fn คืนมาแทนตัว struct
+// ใช้กับ value type อย่างจำนวนเงิน ที่ต้องการให้ tag เช่น gt=0 ตรวจกับค่าข้างใน
+func RegisterCustomType(fn validator.CustomTypeFunc, types ...interface{}) {
+	validate.RegisterCustomTypeFunc(fn, types...)
+}
+
 // init ฟังก์ชันจะทำงานเมื่อ package ถูกโหลด
 func init() {
 	// ลงทะเบียน custom validator สำหรับรหัสผ่านที่ซับซ้อน